@@ -43,11 +43,33 @@ func main() {
 	}
 
 	// Initialize repositories
-	repos := repository.NewRepositories(db, cfg.Database.Driver)
+	secrets, err := repository.NewSecretBox(cfg.App.EncryptionKeyVersion, cfg.App.EncryptionKeys())
+	if err != nil {
+		log.Fatalf("Failed to set up secret encryption: %v", err)
+	}
+	repos := repository.NewRepositories(db, cfg.Database.Driver, secrets)
+
+	// Seal tokens stored before encryption and re-seal any under a retired key
+	resealed, err := repos.ResealSecrets(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to encrypt stored connection secrets: %v", err)
+	}
+	if resealed > 0 {
+		log.Printf("Encrypted secrets on %d connection(s) with key version %d", resealed, cfg.App.EncryptionKeyVersion)
+	}
 
 	// Initialize services
 	svc := services.New(cfg, repos)
 
+	// Move templates' availability rules from before schedules onto schedules
+	converted, err := svc.Schedule.ConvertAvailabilityRules(context.Background())
+	if err != nil {
+		log.Printf("Failed to convert template availability rules: %v", err)
+	}
+	if converted > 0 {
+		log.Printf("Converted availability rules of %d template(s) to schedules", converted)
+	}
+
 	// Start background services
 	svc.Reminder.Start()
 	defer svc.Reminder.Stop()
@@ -55,6 +77,12 @@ func main() {
 	svc.CalendarSync.Start()
 	defer svc.CalendarSync.Stop()
 
+	svc.Outbox.Start()
+	defer svc.Outbox.Stop()
+
+	svc.Webhook.Start()
+	defer svc.Webhook.Stop()
+
 	// Initialize handlers
 	h := handlers.New(cfg, svc, repos)
 
@@ -103,8 +131,13 @@ func main() {
 
 	// OAuth callbacks (calendar/conferencing)
 	mux.HandleFunc("GET /auth/google/callback", h.Auth.GoogleCallback)
+	mux.Handle("GET /auth/microsoft/callback", middleware.RequireAuth(svc.Session)(http.HandlerFunc(h.Auth.OutlookCallback)))
 	mux.HandleFunc("GET /auth/zoom/callback", h.Auth.ZoomCallback)
 
+	// Provider push notifications (authenticated by per-channel token or signature)
+	mux.HandleFunc("POST /webhooks/google/calendar", h.Webhooks.GoogleCalendar)
+	mux.HandleFunc("POST /webhooks/mailgun", h.Webhooks.Mailgun)
+
 	// Protected dashboard routes
 	dashboard := http.NewServeMux()
 	dashboard.HandleFunc("GET /dashboard", h.Dashboard.Home)
@@ -114,13 +147,16 @@ func main() {
 	// provider_calendars.id (an individual calendar within a connection).
 	dashboard.HandleFunc("GET /dashboard/calendars", h.Dashboard.Calendars)
 	dashboard.HandleFunc("POST /dashboard/calendars/connect/google", h.Dashboard.ConnectGoogle)
+	dashboard.HandleFunc("GET /dashboard/calendars/connect/outlook", h.Dashboard.ConnectOutlook)
 	dashboard.HandleFunc("POST /dashboard/calendars/connect/caldav", h.Dashboard.ConnectCalDAV)
+	dashboard.HandleFunc("POST /dashboard/calendars/connect/ics-feed", h.Dashboard.ConnectICSFeed)
 	dashboard.HandleFunc("POST /dashboard/calendars/{id}/disconnect", h.Dashboard.DisconnectCalendar)
 	dashboard.HandleFunc("POST /dashboard/conferencing/{provider}/disconnect", h.Dashboard.DisconnectConferencing)
 	dashboard.HandleFunc("POST /dashboard/calendars/{id}/default", h.Dashboard.SetDefaultCalendar)
 	dashboard.HandleFunc("POST /dashboard/calendars/{id}/refresh", h.Dashboard.RefreshCalendarSync)
 	dashboard.HandleFunc("POST /dashboard/calendars/{id}/color", h.Dashboard.UpdateCalendarColor)
 	dashboard.HandleFunc("POST /dashboard/calendars/sub/{id}/poll", h.Dashboard.ToggleSubCalendarPoll)
+	dashboard.HandleFunc("POST /dashboard/calendars/sub/{id}/busy-policy", h.Dashboard.UpdateSubCalendarBusyPolicy)
 	dashboard.HandleFunc("POST /dashboard/calendars/sub/{id}/color", h.Dashboard.UpdateSubCalendarColor)
 	dashboard.HandleFunc("POST /dashboard/calendars/sub/{id}/default", h.Dashboard.SetDefaultSubCalendar)
 	dashboard.HandleFunc("GET /dashboard/agenda/day-detail", h.Dashboard.AgendaDayPartial)
@@ -155,6 +191,7 @@ func main() {
 	dashboard.HandleFunc("POST /dashboard/bookings/archive-all", h.Dashboard.BulkArchiveBookings)
 	dashboard.HandleFunc("POST /dashboard/bookings/archive-all-past", h.Dashboard.BulkArchivePastBookings)
 	dashboard.HandleFunc("POST /dashboard/bookings/{id}/retry-calendar", h.Dashboard.RetryCalendarEvent)
+	dashboard.HandleFunc("POST /dashboard/bookings/{id}/restore-calendar", h.Dashboard.RestoreCalendarEvents)
 	dashboard.HandleFunc("POST /dashboard/bookings/retry-calendar-all", h.Dashboard.BulkRetryCalendarEvents)
 
 	// Contacts
@@ -169,6 +206,7 @@ func main() {
 	dashboard.HandleFunc("POST /dashboard/events", h.DashboardEvents.Create)
 	dashboard.HandleFunc("GET /dashboard/events/check-conflicts", h.DashboardEvents.CheckConflicts)
 	dashboard.HandleFunc("GET /dashboard/events/attendee-search", h.DashboardEvents.AttendeeSearch)
+	dashboard.HandleFunc("GET /dashboard/events/find-times", h.DashboardEvents.FindTimes)
 	dashboard.HandleFunc("GET /dashboard/events/{id}/details", h.DashboardEvents.Details)
 	dashboard.HandleFunc("GET /dashboard/events/{id}/edit", h.DashboardEvents.EditForm)
 	dashboard.HandleFunc("POST /dashboard/events/{id}/edit", h.DashboardEvents.Update)
@@ -181,6 +219,30 @@ func main() {
 	dashboard.HandleFunc("GET /dashboard/settings", h.Dashboard.Settings)
 	dashboard.HandleFunc("PUT /dashboard/settings", h.Dashboard.UpdateSettings)
 	dashboard.HandleFunc("PUT /dashboard/settings/working-hours", h.Dashboard.UpdateWorkingHours)
+	dashboard.HandleFunc("POST /dashboard/settings/overrides", h.Dashboard.CreateAvailabilityOverride)
+	dashboard.HandleFunc("DELETE /dashboard/settings/overrides/{id}", h.Dashboard.DeleteAvailabilityOverride)
+	dashboard.HandleFunc("PUT /dashboard/settings/booking-limits", h.Dashboard.UpdateBookingLimits)
+	dashboard.HandleFunc("PUT /dashboard/settings/meeting-spacing", h.Dashboard.UpdateMeetingSpacing)
+	dashboard.HandleFunc("PUT /dashboard/settings/holidays", h.Dashboard.UpdateHolidaySet)
+	dashboard.HandleFunc("PUT /dashboard/settings/holidays/{date}", h.Dashboard.UpdateHoliday)
+	dashboard.HandleFunc("PUT /dashboard/settings/email-branding", h.Dashboard.UpdateEmailBranding)
+	dashboard.HandleFunc("GET /dashboard/settings/emails", h.Dashboard.EmailPreviews)
+	dashboard.HandleFunc("GET /dashboard/settings/emails/{type}", h.Dashboard.EmailPreview)
+	dashboard.HandleFunc("GET /dashboard/emails/failed", h.Dashboard.FailedEmails)
+	dashboard.HandleFunc("POST /dashboard/emails/failed/{id}/resend", h.Dashboard.ResendEmail)
+	dashboard.HandleFunc("GET /dashboard/settings/webhooks", h.Dashboard.Webhooks)
+	dashboard.HandleFunc("POST /dashboard/settings/webhooks", h.Dashboard.CreateWebhook)
+	dashboard.HandleFunc("GET /dashboard/settings/webhooks/{id}", h.Dashboard.Webhook)
+	dashboard.HandleFunc("PUT /dashboard/settings/webhooks/{id}", h.Dashboard.UpdateWebhook)
+	dashboard.HandleFunc("DELETE /dashboard/settings/webhooks/{id}", h.Dashboard.DeleteWebhook)
+	dashboard.HandleFunc("POST /dashboard/settings/webhooks/deliveries/{id}/redeliver", h.Dashboard.RedeliverWebhook)
+	dashboard.HandleFunc("GET /dashboard/settings/schedules/new", h.Dashboard.NewSchedulePage)
+	dashboard.HandleFunc("POST /dashboard/settings/schedules", h.Dashboard.CreateSchedule)
+	dashboard.HandleFunc("GET /dashboard/settings/schedules/{id}", h.Dashboard.EditSchedulePage)
+	dashboard.HandleFunc("PUT /dashboard/settings/schedules/{id}", h.Dashboard.UpdateSchedule)
+	dashboard.HandleFunc("DELETE /dashboard/settings/schedules/{id}", h.Dashboard.DeleteSchedule)
+	dashboard.HandleFunc("POST /dashboard/settings/schedules/{id}/overrides", h.Dashboard.CreateScheduleOverride)
+	dashboard.HandleFunc("DELETE /dashboard/settings/schedules/{id}/overrides/{overrideId}", h.Dashboard.DeleteScheduleOverride)
 
 	// Audit logs (admin only)
 	dashboard.HandleFunc("GET /dashboard/audit-logs", h.Dashboard.AuditLogs)
@@ -222,6 +284,16 @@ func main() {
 
 	mux.Handle("/api/v1/", middleware.RequireAuth(svc.Session)(apiv1))
 
+	// Public API v1 endpoints (no session, rate limited per client IP).
+	// Booking UIs on other sites read slots and book through these, so
+	// they can be called cross-origin.
+	publicAPI := http.NewServeMux()
+	publicAPI.HandleFunc("GET /api/v1/public/{tenant}/{host}/{template}/slots", h.PublicAPI.GetSlots)
+	publicAPI.HandleFunc("POST /api/v1/public/{tenant}/{host}/{template}/bookings", h.PublicAPI.CreateBooking)
+
+	limiter := middleware.NewRateLimiter(cfg.App.PublicAPIRateLimit, cfg.App.TrustProxy)
+	mux.Handle("/api/v1/public/", middleware.AllowAnyOrigin(limiter.Middleware(publicAPI)))
+
 	// Health check
 	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -36,8 +37,9 @@ type DatabaseConfig struct {
 
 // OAuthConfig holds OAuth provider configurations
 type OAuthConfig struct {
-	Google GoogleOAuthConfig
-	Zoom   ZoomOAuthConfig
+	Google    GoogleOAuthConfig
+	Microsoft MicrosoftOAuthConfig
+	Zoom      ZoomOAuthConfig
 }
 
 // GoogleOAuthConfig holds Google OAuth configuration
@@ -47,6 +49,15 @@ type GoogleOAuthConfig struct {
 	RedirectURL  string
 }
 
+// MicrosoftOAuthConfig holds Microsoft identity platform OAuth configuration
+// (Outlook / Microsoft 365 calendars)
+type MicrosoftOAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Tenant       string // "common", "organizations", or a directory (tenant) ID
+}
+
 // ZoomOAuthConfig holds Zoom OAuth configuration
 type ZoomOAuthConfig struct {
 	ClientID     string
@@ -61,8 +72,10 @@ type EmailConfig struct {
 	FromName    string
 
 	// Mailgun specific
-	MailgunDomain string
-	MailgunAPIKey string
+	MailgunDomain     string
+	MailgunAPIKey     string
+	MailgunAPIBase    string // https://api.eu.mailgun.net/v3 for EU domains
+	MailgunSigningKey string // HTTP webhook signing key
 
 	// SMTP specific
 	SMTPHost     string
@@ -75,11 +88,25 @@ type EmailConfig struct {
 type AppConfig struct {
 	Environment       string
 	MaxSchedulingDays int
+	BusyCacheMaxAge   time.Duration // how old cached calendar busy times may be before availability fetches live
 	SessionDuration   time.Duration
 	DefaultTimezone   string
+	HolidaysPath      string // directory of bundled public holiday sets
 	EncryptionKey     string
 	BaseURL           string // APP_BASE_URL - used for OAuth callback URLs
 
+	// The public JSON API takes PublicAPIRateLimit requests a minute from
+	// each client IP. Behind a reverse proxy, set TrustProxy so the client
+	// IP is read from X-Forwarded-For rather than the proxy's address.
+	PublicAPIRateLimit int  // PUBLIC_API_RATE_LIMIT
+	TrustProxy         bool // TRUST_PROXY
+
+	// Connection secrets are sealed with EncryptionKey under
+	// EncryptionKeyVersion. After rotating, list the retired keys in
+	// PreviousEncryptionKeys until the next start has re-sealed everything.
+	EncryptionKeyVersion   int            // ENCRYPTION_KEY_VERSION
+	PreviousEncryptionKeys map[int]string // ENCRYPTION_PREVIOUS_KEYS - "version:key,version:key"
+
 	// SEO & Analytics
 	GoogleSiteVerification string // GOOGLE_SITE_VERIFICATION - Search Console verification code
 	GoogleAnalyticsID      string // GOOGLE_ANALYTICS_ID - GA4 measurement ID (e.g., "G-XXXXXXXXXX")
@@ -119,6 +146,12 @@ func Load() (*Config, error) {
 				ClientSecret: getEnv("GOOGLE_CLIENT_SECRET", ""),
 				RedirectURL:  getEnv("GOOGLE_REDIRECT_URL", ""),
 			},
+			Microsoft: MicrosoftOAuthConfig{
+				ClientID:     getEnv("MICROSOFT_CLIENT_ID", ""),
+				ClientSecret: getEnv("MICROSOFT_CLIENT_SECRET", ""),
+				RedirectURL:  getEnv("MICROSOFT_REDIRECT_URL", ""),
+				Tenant:       getEnv("MICROSOFT_TENANT", "common"),
+			},
 			Zoom: ZoomOAuthConfig{
 				ClientID:     getEnv("ZOOM_CLIENT_ID", ""),
 				ClientSecret: getEnv("ZOOM_CLIENT_SECRET", ""),
@@ -126,23 +159,30 @@ func Load() (*Config, error) {
 			},
 		},
 		Email: EmailConfig{
-			Provider:      getEnv("EMAIL_PROVIDER", "smtp"),
-			FromAddress:   getEnv("EMAIL_FROM_ADDRESS", "noreply@localhost"),
-			FromName:      getEnv("EMAIL_FROM_NAME", "Meet When"),
-			MailgunDomain: getEnv("MAILGUN_DOMAIN", ""),
-			MailgunAPIKey: getEnv("MAILGUN_API_KEY", ""),
-			SMTPHost:      getEnv("SMTP_HOST", "localhost"),
-			SMTPPort:      getEnvInt("SMTP_PORT", 587),
-			SMTPUser:      getEnv("SMTP_USER", ""),
-			SMTPPassword:  getEnv("SMTP_PASSWORD", ""),
+			Provider:          getEnv("EMAIL_PROVIDER", "smtp"),
+			FromAddress:       getEnv("EMAIL_FROM_ADDRESS", "noreply@localhost"),
+			FromName:          getEnv("EMAIL_FROM_NAME", "Meet When"),
+			MailgunDomain:     getEnv("MAILGUN_DOMAIN", ""),
+			MailgunAPIKey:     getEnv("MAILGUN_API_KEY", ""),
+			MailgunAPIBase:    getEnv("MAILGUN_API_BASE", "https://api.mailgun.net/v3"),
+			MailgunSigningKey: getEnv("MAILGUN_WEBHOOK_SIGNING_KEY", ""),
+			SMTPHost:          getEnv("SMTP_HOST", "localhost"),
+			SMTPPort:          getEnvInt("SMTP_PORT", 587),
+			SMTPUser:          getEnv("SMTP_USER", ""),
+			SMTPPassword:      getEnv("SMTP_PASSWORD", ""),
 		},
 		App: AppConfig{
 			Environment:            getEnv("APP_ENV", "development"),
 			MaxSchedulingDays:      getEnvInt("MAX_SCHEDULING_DAYS", 90),
+			BusyCacheMaxAge:        time.Duration(getEnvInt("BUSY_CACHE_MAX_AGE_MINUTES", 30)) * time.Minute,
 			SessionDuration:        time.Duration(getEnvInt("SESSION_DURATION_HOURS", 168)) * time.Hour,
 			DefaultTimezone:        getEnv("DEFAULT_TIMEZONE", "UTC"),
+			HolidaysPath:           getEnv("HOLIDAYS_PATH", "holidays"),
 			EncryptionKey:          getEnv("ENCRYPTION_KEY", ""),
+			EncryptionKeyVersion:   getEnvInt("ENCRYPTION_KEY_VERSION", 1),
 			BaseURL:                getEnv("APP_BASE_URL", "http://localhost:8080"),
+			PublicAPIRateLimit:     getEnvInt("PUBLIC_API_RATE_LIMIT", 60),
+			TrustProxy:             getEnv("TRUST_PROXY", "false") == "true",
 			GoogleSiteVerification: getEnv("GOOGLE_SITE_VERIFICATION", ""),
 			GoogleAnalyticsID:      getEnv("GOOGLE_ANALYTICS_ID", ""),
 		},
@@ -158,9 +198,47 @@ func Load() (*Config, error) {
 		cfg.App.EncryptionKey = "development-key-32-bytes-long!!"
 	}
 
+	previous, err := parseEncryptionKeys(getEnv("ENCRYPTION_PREVIOUS_KEYS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("ENCRYPTION_PREVIOUS_KEYS: %w", err)
+	}
+	if _, clash := previous[cfg.App.EncryptionKeyVersion]; clash {
+		return nil, fmt.Errorf("ENCRYPTION_PREVIOUS_KEYS reuses the current version %d", cfg.App.EncryptionKeyVersion)
+	}
+	cfg.App.PreviousEncryptionKeys = previous
+
 	return cfg, nil
 }
 
+// EncryptionKeys returns every configured key by version, current included.
+func (a AppConfig) EncryptionKeys() map[int]string {
+	keys := map[int]string{a.EncryptionKeyVersion: a.EncryptionKey}
+	for v, k := range a.PreviousEncryptionKeys {
+		keys[v] = k
+	}
+	return keys
+}
+
+// parseEncryptionKeys parses "1:oldkey,2:otherkey". Keys may contain ':'
+// but not ','.
+func parseEncryptionKeys(value string) (map[int]string, error) {
+	keys := map[int]string{}
+	for i, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		v, key, ok := strings.Cut(entry, ":")
+		version, err := strconv.Atoi(v)
+		if !ok || err != nil || version < 1 || key == "" {
+			// Don't echo the entry back; it holds a key.
+			return nil, fmt.Errorf("entry %d: expected version:key", i+1)
+		}
+		keys[version] = key
+	}
+	return keys, nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -3,6 +3,7 @@ package handlers
 import (
 	"crypto/hmac"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -12,6 +13,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/meet-when/meet-when/internal/middleware"
 	"github.com/meet-when/meet-when/internal/models"
 	"github.com/meet-when/meet-when/internal/services"
 )
@@ -222,6 +224,58 @@ func (h *AuthHandler) GoogleCallback(w http.ResponseWriter, r *http.Request) {
 	h.handlers.error(w, r, http.StatusBadRequest, "Invalid state")
 }
 
+// outlookStateCookie holds the state of the Outlook OAuth flow this browser
+// started, for OutlookCallback to check
+const outlookStateCookie = "outlook_auth_state"
+
+// OutlookCallback handles the Microsoft OAuth callback for Outlook calendar
+// connections. It runs behind RequireAuth: the calendar is connected to the
+// signed-in host, and only when state matches the one ConnectOutlook set.
+func (h *AuthHandler) OutlookCallback(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	stateCookie, err := r.Cookie(outlookStateCookie)
+	http.SetCookie(w, &http.Cookie{
+		Name:     outlookStateCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+	state := r.URL.Query().Get("state")
+	if err != nil || stateCookie.Value == "" || subtle.ConstantTimeCompare([]byte(state), []byte(stateCookie.Value)) != 1 {
+		h.handlers.error(w, r, http.StatusBadRequest, "Invalid state")
+		return
+	}
+
+	code := r.URL.Query().Get("code")
+	if code == "" {
+		// Microsoft reports consent refusals as error=access_denied.
+		if r.URL.Query().Get("error") != "" {
+			h.handlers.redirect(w, r, "/dashboard/calendars?error=connection_failed")
+			return
+		}
+		h.handlers.error(w, r, http.StatusBadRequest, "Missing authorization code")
+		return
+	}
+
+	_, err = h.handlers.services.Calendar.ConnectOutlookCalendar(r.Context(), services.OutlookCalendarConnectInput{
+		HostID:      host.Host.ID,
+		AuthCode:    code,
+		RedirectURI: h.handlers.cfg.OAuth.Microsoft.RedirectURL,
+	})
+	if err != nil {
+		log.Printf("[CALENDAR] Outlook connect failed for host %s: %v", host.Host.ID, err)
+		h.handlers.redirect(w, r, "/dashboard/calendars?error=connection_failed")
+		return
+	}
+	h.handlers.redirect(w, r, "/dashboard/calendars?success=calendar_connected")
+}
+
 // ZoomCallback handles Zoom OAuth callback
 func (h *AuthHandler) ZoomCallback(w http.ResponseWriter, r *http.Request) {
 	code := r.URL.Query().Get("code")
@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/meet-when/meet-when/internal/middleware"
 	"github.com/meet-when/meet-when/internal/models"
+	"github.com/meet-when/meet-when/internal/repository"
 	"github.com/meet-when/meet-when/internal/services"
 )
 
@@ -44,6 +46,9 @@ func (h *DashboardHandler) Home(w http.ResponseWriter, r *http.Request) {
 	// Get templates count
 	templates, _ := h.handlers.services.Template.GetTemplates(r.Context(), host.Host.ID)
 
+	// Emails that went dead, so they don't go unnoticed
+	failedEmails, _ := h.handlers.services.Outbox.CountFailed(r.Context(), host.Tenant.ID, hostScope(host))
+
 	h.handlers.render(w, "dashboard_home.html", PageData{
 		Title:        "Dashboard",
 		Host:         host.Host,
@@ -55,6 +60,7 @@ func (h *DashboardHandler) Home(w http.ResponseWriter, r *http.Request) {
 			"Bookings":     bookings,
 			"PendingCount": len(pending),
 			"Templates":    templates,
+			"FailedEmails": failedEmails,
 		},
 	})
 }
@@ -126,6 +132,33 @@ func (h *DashboardHandler) ConnectGoogle(w http.ResponseWriter, r *http.Request)
 	http.Redirect(w, r, authURL, http.StatusSeeOther)
 }
 
+// ConnectOutlook initiates the Microsoft 365 / Outlook calendar OAuth flow.
+// The state it sends is kept in an HttpOnly cookie, so the callback only
+// completes a consent this browser started.
+func (h *DashboardHandler) ConnectOutlook(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	authURL, state, err := h.handlers.services.Calendar.GetOutlookAuthURL()
+	if err != nil {
+		log.Printf("Error generating Outlook auth URL: %v", err)
+		h.handlers.redirect(w, r, "/dashboard/calendars?error=connection_failed")
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     outlookStateCookie,
+		Value:    state,
+		Path:     "/",
+		MaxAge:   600, // 10 minutes
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, authURL, http.StatusSeeOther)
+}
+
 // ConnectCalDAV handles CalDAV connection form
 func (h *DashboardHandler) ConnectCalDAV(w http.ResponseWriter, r *http.Request) {
 	host := middleware.GetHost(r.Context())
@@ -163,6 +196,33 @@ func (h *DashboardHandler) ConnectCalDAV(w http.ResponseWriter, r *http.Request)
 	h.handlers.redirect(w, r, "/dashboard/calendars?success=calendar_connected")
 }
 
+// ConnectICSFeed handles the ICS feed subscription form
+func (h *DashboardHandler) ConnectICSFeed(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.handlers.redirect(w, r, "/dashboard/calendars?error=invalid_form")
+		return
+	}
+
+	_, err := h.handlers.services.Calendar.ConnectICSFeed(r.Context(), services.ICSFeedConnectInput{
+		HostID: host.Host.ID,
+		Name:   r.FormValue("name"),
+		URL:    r.FormValue("url"),
+	})
+	if err != nil {
+		log.Printf("[CALENDAR] ICS feed subscription failed for host %s: %v", host.Host.ID, err)
+		h.handlers.redirect(w, r, "/dashboard/calendars?error=connection_failed")
+		return
+	}
+
+	h.handlers.redirect(w, r, "/dashboard/calendars?success=calendar_connected")
+}
+
 // DisconnectCalendar removes a calendar connection
 func (h *DashboardHandler) DisconnectCalendar(w http.ResponseWriter, r *http.Request) {
 	host := middleware.GetHost(r.Context())
@@ -298,6 +358,57 @@ func (h *DashboardHandler) ToggleSubCalendarPoll(w http.ResponseWriter, r *http.
 	h.handlers.redirect(w, r, "/dashboard/calendars")
 }
 
+// UpdateSubCalendarBusyPolicy saves which tentative, free and declined events
+// on a provider calendar count as busy, and the buffers kept free around its
+// events. Each policy is a checkbox; an absent value means "off". Returns the
+// updated sub-calendar row partial for HTMX.
+func (h *DashboardHandler) UpdateSubCalendarBusyPolicy(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	pcID := r.PathValue("id")
+	onTentative := r.FormValue("busy_on_tentative") == "on"
+	onFree := r.FormValue("busy_on_free") == "on"
+	onDeclined := r.FormValue("busy_on_declined") == "on"
+
+	if err := h.handlers.services.Calendar.SetProviderCalendarBusyPolicy(r.Context(), host.Host.ID, pcID, onTentative, onFree, onDeclined); err != nil {
+		log.Printf("[CALENDAR] update busy policy failed for %s: %v", pcID, err)
+		http.Error(w, "Calendar not found", http.StatusNotFound)
+		return
+	}
+	before := parseIntOrDefault(r.FormValue("buffer_before"), 0)
+	after := parseIntOrDefault(r.FormValue("buffer_after"), 0)
+	if err := h.handlers.services.Calendar.SetProviderCalendarBuffers(r.Context(), host.Host.ID, pcID, before, after); err != nil {
+		log.Printf("[CALENDAR] update buffers failed for %s: %v", pcID, err)
+		http.Error(w, "Calendar not found", http.StatusNotFound)
+		return
+	}
+
+	pc, _ := h.handlers.services.Calendar.GetProviderCalendar(r.Context(), host.Host.ID, pcID)
+	if pc == nil {
+		http.Error(w, "Calendar not found", http.StatusNotFound)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		fresh, _ := h.handlers.repos.Host.GetByID(r.Context(), host.Host.ID)
+		def := ""
+		if fresh != nil {
+			def = derefString(fresh.DefaultCalendarID)
+		}
+		h.handlers.renderPartial(w, "sub_calendar_row.html", map[string]interface{}{
+			"Calendar":          pc,
+			"Palette":           paletteData,
+			"DefaultCalendarID": def,
+		})
+		return
+	}
+	h.handlers.redirect(w, r, "/dashboard/calendars")
+}
+
 // SetDefaultSubCalendar promotes a provider calendar to be the host's
 // fallback default for booking events that don't pick a calendar explicitly
 // (and as the fallback target for pooled-host bookings).
@@ -581,6 +692,7 @@ func (h *DashboardHandler) NewTemplatePage(w http.ResponseWriter, r *http.Reques
 	}
 
 	calendarOptions, _ := h.handlers.services.Calendar.GetCalendarTree(r.Context(), host.Host.ID)
+	schedules, _ := h.handlers.services.Schedule.GetSchedules(r.Context(), host.Host.ID)
 
 	h.handlers.render(w, "dashboard_template_form.html", PageData{
 		Title:        "New Meeting Template",
@@ -591,6 +703,7 @@ func (h *DashboardHandler) NewTemplatePage(w http.ResponseWriter, r *http.Reques
 		Data: map[string]interface{}{
 			"Template":        nil,
 			"CalendarOptions": calendarOptions,
+			"Schedules":       schedules,
 			"IsNew":           true,
 		},
 	})
@@ -628,39 +741,41 @@ func (h *DashboardHandler) CreateTemplate(w http.ResponseWriter, r *http.Request
 		}
 	}
 
-	// Parse availability rules
-	var availabilityRules models.JSONMap
-	if rulesJSON := r.FormValue("availability_rules"); rulesJSON != "" {
-		if err := json.Unmarshal([]byte(rulesJSON), &availabilityRules); err != nil {
-			log.Printf("[TEMPLATE] Failed to parse availability_rules: %v", err)
-		}
-	}
-
 	input := services.CreateTemplateInput{
-		HostID:            host.Host.ID,
-		TenantID:          host.Tenant.ID,
-		Slug:              r.FormValue("slug"),
-		Name:              r.FormValue("name"),
-		Description:       r.FormValue("description"),
-		Durations:         durations,
-		LocationType:      models.ConferencingProvider(r.FormValue("location_type")),
-		CustomLocation:    r.FormValue("custom_location"),
-		CalendarID:        r.FormValue("calendar_id"),
-		RequiresApproval:  r.FormValue("requires_approval") == "on",
-		MinNoticeMinutes:  parseIntOrDefault(r.FormValue("min_notice_minutes"), 60),
-		MaxScheduleDays:   parseIntOrDefault(r.FormValue("max_schedule_days"), 14),
-		PreBufferMinutes:  parseIntOrDefault(r.FormValue("pre_buffer_minutes"), 0),
-		PostBufferMinutes: parseIntOrDefault(r.FormValue("post_buffer_minutes"), 0),
-		AvailabilityRules: availabilityRules,
-		InviteeQuestions:  inviteeQuestions,
-		ConfirmationEmail: r.FormValue("confirmation_email"),
-		ReminderEmail:     r.FormValue("reminder_email"),
-		IsPrivate:         r.FormValue("is_private") == "on",
+		HostID:             host.Host.ID,
+		TenantID:           host.Tenant.ID,
+		Slug:               r.FormValue("slug"),
+		Name:               r.FormValue("name"),
+		Description:        r.FormValue("description"),
+		Durations:          durations,
+		LocationType:       models.ConferencingProvider(r.FormValue("location_type")),
+		CustomLocation:     r.FormValue("custom_location"),
+		CalendarID:         r.FormValue("calendar_id"),
+		RequiresApproval:   r.FormValue("requires_approval") == "on",
+		MinNoticeMinutes:   parseIntOrDefault(r.FormValue("min_notice_minutes"), 60),
+		MaxScheduleDays:    parseIntOrDefault(r.FormValue("max_schedule_days"), 14),
+		MaxBookingsPerDay:  parseIntOrDefault(r.FormValue("max_bookings_per_day"), 0),
+		MaxBookingsPerWeek: parseIntOrDefault(r.FormValue("max_bookings_per_week"), 0),
+		SchedulingType:     models.SchedulingType(r.FormValue("scheduling_type")),
+		RoundRobinStrategy: models.RoundRobinStrategy(r.FormValue("round_robin_strategy")),
+		SlotInterval:       parseIntOrDefault(r.FormValue("slot_interval"), 0),
+		AlignSlots:         r.FormValue("align_slots") == "on",
+		StartMinutes:       parseIntValues(r.Form["start_minutes"]),
+		Seats:              parseIntOrDefault(r.FormValue("seats"), 0),
+		Reminders:          parseReminders(r.FormValue("reminders")),
+		PreBufferMinutes:   parseIntOrDefault(r.FormValue("pre_buffer_minutes"), 0),
+		PostBufferMinutes:  parseIntOrDefault(r.FormValue("post_buffer_minutes"), 0),
+		ScheduleID:         r.FormValue("schedule_id"),
+		InviteeQuestions:   inviteeQuestions,
+		ConfirmationEmail:  r.FormValue("confirmation_email"),
+		ReminderEmail:      r.FormValue("reminder_email"),
+		IsPrivate:          r.FormValue("is_private") == "on",
 	}
 
 	_, err := h.handlers.services.Template.CreateTemplate(r.Context(), input)
 	if err != nil {
 		calendarOptions, _ := h.handlers.services.Calendar.GetCalendarTree(r.Context(), host.Host.ID)
+		schedules, _ := h.handlers.services.Schedule.GetSchedules(r.Context(), host.Host.ID)
 		h.handlers.render(w, "dashboard_template_form.html", PageData{
 			Title:        "New Meeting Template",
 			Host:         host.Host,
@@ -671,6 +786,7 @@ func (h *DashboardHandler) CreateTemplate(w http.ResponseWriter, r *http.Request
 			Data: map[string]interface{}{
 				"Template":        nil,
 				"CalendarOptions": calendarOptions,
+				"Schedules":       schedules,
 				"IsNew":           true,
 			},
 		})
@@ -696,13 +812,13 @@ func (h *DashboardHandler) EditTemplatePage(w http.ResponseWriter, r *http.Reque
 	}
 
 	calendarOptions, _ := h.handlers.services.Calendar.GetCalendarTree(r.Context(), host.Host.ID)
+	schedules, _ := h.handlers.services.Schedule.GetSchedules(r.Context(), host.Host.ID)
 
-	// Load pooled hosts for this template
-	pooledHosts, _ := h.handlers.services.Template.GetPooledHosts(r.Context(), templateID)
-
-	// Load all hosts in the tenant for the dropdown, excluding already-pooled hosts
-	allTenantHosts, _ := h.handlers.repos.Host.GetByTenantID(r.Context(), host.Tenant.ID)
-	tenantHosts := filterAvailableHosts(allTenantHosts, pooledHosts)
+	// Pooled hosts, tenant hosts for the dropdown and round-robin assignments
+	data := h.pooledHostsData(r, host.Tenant.ID, template)
+	data["CalendarOptions"] = calendarOptions
+	data["Schedules"] = schedules
+	data["IsNew"] = false
 
 	h.handlers.render(w, "dashboard_template_form.html", PageData{
 		Title:        "Edit Template",
@@ -710,16 +826,37 @@ func (h *DashboardHandler) EditTemplatePage(w http.ResponseWriter, r *http.Reque
 		Tenant:       host.Tenant,
 		ActiveNav:    "templates",
 		PendingCount: h.getPendingCount(r, host.Host.ID),
-		Data: map[string]interface{}{
-			"Template":        template,
-			"CalendarOptions": calendarOptions,
-			"IsNew":           false,
-			"PooledHosts":     pooledHosts,
-			"TenantHosts":     tenantHosts,
-		},
+		Data:         data,
 	})
 }
 
+// pooledHostsData is what pooled_hosts_partial.html renders: the template's
+// pooled hosts, the tenant's other hosts that can be added, and how
+// round-robin bookings have been assigned
+func (h *DashboardHandler) pooledHostsData(r *http.Request, tenantID string, template *models.MeetingTemplate) map[string]interface{} {
+	pooledHosts, _ := h.handlers.services.Template.GetPooledHosts(r.Context(), template.ID)
+
+	// Load all hosts in the tenant for the dropdown, excluding already-pooled hosts
+	allTenantHosts, _ := h.handlers.repos.Host.GetByTenantID(r.Context(), tenantID)
+	tenantHosts := filterAvailableHosts(allTenantHosts, pooledHosts)
+
+	var assignments map[string]*repository.AssignmentStat
+	if template.SchedulingType == models.SchedulingTypeRoundRobin {
+		var err error
+		assignments, err = h.handlers.services.Template.GetAssignmentStats(r.Context(), template.ID)
+		if err != nil {
+			log.Printf("[DASHBOARD] Failed to load round-robin assignments for template %s: %v", template.ID, err)
+		}
+	}
+
+	return map[string]interface{}{
+		"Template":    template,
+		"PooledHosts": pooledHosts,
+		"TenantHosts": tenantHosts,
+		"Assignments": assignments,
+	}
+}
+
 // UpdateTemplate handles template updates
 func (h *DashboardHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
 	host := middleware.GetHost(r.Context())
@@ -754,36 +891,37 @@ func (h *DashboardHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request
 		}
 	}
 
-	// Parse availability rules
-	var availabilityRules models.JSONMap
-	if rulesJSON := r.FormValue("availability_rules"); rulesJSON != "" {
-		if err := json.Unmarshal([]byte(rulesJSON), &availabilityRules); err != nil {
-			log.Printf("[TEMPLATE] Failed to parse availability_rules: %v", err)
-		}
-	}
-
 	input := services.UpdateTemplateInput{
-		ID:                templateID,
-		HostID:            host.Host.ID,
-		TenantID:          host.Tenant.ID,
-		Slug:              r.FormValue("slug"),
-		Name:              r.FormValue("name"),
-		Description:       r.FormValue("description"),
-		Durations:         durations,
-		LocationType:      models.ConferencingProvider(r.FormValue("location_type")),
-		CustomLocation:    r.FormValue("custom_location"),
-		CalendarID:        r.FormValue("calendar_id"),
-		RequiresApproval:  r.FormValue("requires_approval") == "on",
-		MinNoticeMinutes:  parseIntOrDefault(r.FormValue("min_notice_minutes"), 60),
-		MaxScheduleDays:   parseIntOrDefault(r.FormValue("max_schedule_days"), 14),
-		PreBufferMinutes:  parseIntOrDefault(r.FormValue("pre_buffer_minutes"), 0),
-		PostBufferMinutes: parseIntOrDefault(r.FormValue("post_buffer_minutes"), 0),
-		AvailabilityRules: availabilityRules,
-		InviteeQuestions:  inviteeQuestions,
-		ConfirmationEmail: r.FormValue("confirmation_email"),
-		ReminderEmail:     r.FormValue("reminder_email"),
-		IsActive:          r.FormValue("is_active") == "on",
-		IsPrivate:         r.FormValue("is_private") == "on",
+		ID:                 templateID,
+		HostID:             host.Host.ID,
+		TenantID:           host.Tenant.ID,
+		Slug:               r.FormValue("slug"),
+		Name:               r.FormValue("name"),
+		Description:        r.FormValue("description"),
+		Durations:          durations,
+		LocationType:       models.ConferencingProvider(r.FormValue("location_type")),
+		CustomLocation:     r.FormValue("custom_location"),
+		CalendarID:         r.FormValue("calendar_id"),
+		RequiresApproval:   r.FormValue("requires_approval") == "on",
+		MinNoticeMinutes:   parseIntOrDefault(r.FormValue("min_notice_minutes"), 60),
+		MaxScheduleDays:    parseIntOrDefault(r.FormValue("max_schedule_days"), 14),
+		MaxBookingsPerDay:  parseIntOrDefault(r.FormValue("max_bookings_per_day"), 0),
+		MaxBookingsPerWeek: parseIntOrDefault(r.FormValue("max_bookings_per_week"), 0),
+		SchedulingType:     models.SchedulingType(r.FormValue("scheduling_type")),
+		RoundRobinStrategy: models.RoundRobinStrategy(r.FormValue("round_robin_strategy")),
+		SlotInterval:       parseIntOrDefault(r.FormValue("slot_interval"), 0),
+		AlignSlots:         r.FormValue("align_slots") == "on",
+		StartMinutes:       parseIntValues(r.Form["start_minutes"]),
+		Seats:              parseIntOrDefault(r.FormValue("seats"), 0),
+		Reminders:          parseReminders(r.FormValue("reminders")),
+		PreBufferMinutes:   parseIntOrDefault(r.FormValue("pre_buffer_minutes"), 0),
+		PostBufferMinutes:  parseIntOrDefault(r.FormValue("post_buffer_minutes"), 0),
+		ScheduleID:         r.FormValue("schedule_id"),
+		InviteeQuestions:   inviteeQuestions,
+		ConfirmationEmail:  r.FormValue("confirmation_email"),
+		ReminderEmail:      r.FormValue("reminder_email"),
+		IsActive:           r.FormValue("is_active") == "on",
+		IsPrivate:          r.FormValue("is_private") == "on",
 	}
 
 	_, err := h.handlers.services.Template.UpdateTemplate(r.Context(), input)
@@ -873,14 +1011,7 @@ func (h *DashboardHandler) AddPooledHost(w http.ResponseWriter, r *http.Request)
 
 	// For HTMX requests, return the updated pooled hosts partial
 	if r.Header.Get("HX-Request") == "true" {
-		pooledHosts, _ := h.handlers.services.Template.GetPooledHosts(r.Context(), templateID)
-		allTenantHosts, _ := h.handlers.repos.Host.GetByTenantID(r.Context(), host.Tenant.ID)
-		tenantHosts := filterAvailableHosts(allTenantHosts, pooledHosts)
-		h.handlers.renderPartial(w, "pooled_hosts_partial.html", map[string]interface{}{
-			"Template":    template,
-			"PooledHosts": pooledHosts,
-			"TenantHosts": tenantHosts,
-		})
+		h.handlers.renderPartial(w, "pooled_hosts_partial.html", h.pooledHostsData(r, host.Tenant.ID, template))
 		return
 	}
 
@@ -921,14 +1052,7 @@ func (h *DashboardHandler) RemovePooledHost(w http.ResponseWriter, r *http.Reque
 
 	// For HTMX requests, return the updated pooled hosts partial
 	if r.Header.Get("HX-Request") == "true" {
-		pooledHosts, _ := h.handlers.services.Template.GetPooledHosts(r.Context(), templateID)
-		allTenantHosts, _ := h.handlers.repos.Host.GetByTenantID(r.Context(), host.Tenant.ID)
-		tenantHosts := filterAvailableHosts(allTenantHosts, pooledHosts)
-		h.handlers.renderPartial(w, "pooled_hosts_partial.html", map[string]interface{}{
-			"Template":    template,
-			"PooledHosts": pooledHosts,
-			"TenantHosts": tenantHosts,
-		})
+		h.handlers.renderPartial(w, "pooled_hosts_partial.html", h.pooledHostsData(r, host.Tenant.ID, template))
 		return
 	}
 
@@ -966,9 +1090,13 @@ func (h *DashboardHandler) UpdatePooledHost(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	isOptional := r.FormValue("is_optional") == "on"
-
-	err = h.handlers.services.Template.UpdatePooledHost(r.Context(), host.Tenant.ID, templateID, hostIDToUpdate, isOptional)
+	// The form either sets the host's round-robin weight or toggles optional
+	if weight := r.FormValue("weight"); weight != "" {
+		err = h.handlers.services.Template.SetPooledHostWeight(r.Context(), host.Tenant.ID, templateID, hostIDToUpdate, parseIntOrDefault(weight, 0))
+	} else {
+		isOptional := r.FormValue("is_optional") == "on"
+		err = h.handlers.services.Template.UpdatePooledHost(r.Context(), host.Tenant.ID, templateID, hostIDToUpdate, isOptional)
+	}
 	if err != nil {
 		if r.Header.Get("HX-Request") == "true" {
 			http.Error(w, err.Error(), http.StatusBadRequest)
@@ -980,14 +1108,7 @@ func (h *DashboardHandler) UpdatePooledHost(w http.ResponseWriter, r *http.Reque
 
 	// For HTMX requests, return the updated pooled hosts partial
 	if r.Header.Get("HX-Request") == "true" {
-		pooledHosts, _ := h.handlers.services.Template.GetPooledHosts(r.Context(), templateID)
-		allTenantHosts, _ := h.handlers.repos.Host.GetByTenantID(r.Context(), host.Tenant.ID)
-		tenantHosts := filterAvailableHosts(allTenantHosts, pooledHosts)
-		h.handlers.renderPartial(w, "pooled_hosts_partial.html", map[string]interface{}{
-			"Template":    template,
-			"PooledHosts": pooledHosts,
-			"TenantHosts": tenantHosts,
-		})
+		h.handlers.renderPartial(w, "pooled_hosts_partial.html", h.pooledHostsData(r, host.Tenant.ID, template))
 		return
 	}
 
@@ -1059,10 +1180,14 @@ func (h *DashboardHandler) Bookings(w http.ResponseWriter, r *http.Request) {
 		flash = &FlashMessage{Type: "success", Message: "Calendar event created successfully"}
 	} else if success == "calendar_bulk_synced" {
 		flash = &FlashMessage{Type: "success", Message: "Calendar events synced successfully"}
+	} else if success == "calendar_restored" {
+		flash = &FlashMessage{Type: "success", Message: "Calendar events restored at the booked time"}
 	} else if errType := r.URL.Query().Get("error"); errType == "calendar_retry_failed" {
 		flash = &FlashMessage{Type: "error", Message: "Failed to create calendar event. Please check your calendar connection."}
 	} else if errType == "calendar_bulk_retry_failed" {
 		flash = &FlashMessage{Type: "error", Message: "Some calendar events could not be synced. Please check your calendar connection."}
+	} else if errType == "calendar_restore_failed" {
+		flash = &FlashMessage{Type: "error", Message: "Failed to restore calendar events. Please check your calendar connection."}
 	}
 
 	h.handlers.render(w, "dashboard_bookings.html", PageData{
@@ -1300,6 +1425,26 @@ func (h *DashboardHandler) RetryCalendarEvent(w http.ResponseWriter, r *http.Req
 	h.handlers.redirect(w, r, "/dashboard/bookings?success=calendar_synced")
 }
 
+// RestoreCalendarEvents rewrites the calendar events of a booking flagged for
+// review, putting every host's copy back at the booked time
+func (h *DashboardHandler) RestoreCalendarEvents(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	bookingID := r.PathValue("id")
+	err := h.handlers.services.Booking.RestoreCalendarEvents(r.Context(), host.Host.ID, host.Tenant.ID, bookingID)
+	if err != nil {
+		log.Printf("[DASHBOARD] Calendar restore failed for booking %s: %v", bookingID, err)
+		h.handlers.redirect(w, r, "/dashboard/bookings?error=calendar_restore_failed")
+		return
+	}
+
+	h.handlers.redirect(w, r, "/dashboard/bookings?success=calendar_restored")
+}
+
 // BulkRetryCalendarEvents retries calendar event creation for all eligible bookings
 func (h *DashboardHandler) BulkRetryCalendarEvents(w http.ResponseWriter, r *http.Request) {
 	host := middleware.GetHost(r.Context())
@@ -1346,11 +1491,62 @@ func (h *DashboardHandler) Settings(w http.ResponseWriter, r *http.Request) {
 		hoursByDay[wh.DayOfWeek] = append(hoursByDay[wh.DayOfWeek], wh)
 	}
 
+	overrideCalendar := h.buildOverrideCalendar(r, host.Host)
+	upcomingOverrides, _ := h.handlers.services.Availability.GetUpcomingOverrides(r.Context(), host.Host)
+	// Schedules' own overrides are listed on their pages
+	upcomingOverrides = slices.DeleteFunc(upcomingOverrides, func(o *models.AvailabilityOverride) bool {
+		return o.ScheduleID != nil
+	})
+	schedules, _ := h.handlers.services.Schedule.GetSchedules(r.Context(), host.Host.ID)
+	templates, _ := h.handlers.services.Template.GetTemplates(r.Context(), host.Host.ID)
+	templateNames := make(map[string]string, len(templates))
+	for _, t := range templates {
+		templateNames[t.ID] = t.Name
+	}
+	// Label each override with the meeting type it applies to
+	overrideScopes := make(map[string]string, len(upcomingOverrides))
+	for _, o := range upcomingOverrides {
+		switch {
+		case o.TemplateID == nil:
+			overrideScopes[o.ID] = "All meeting types"
+		case templateNames[*o.TemplateID] != "":
+			overrideScopes[o.ID] = templateNames[*o.TemplateID]
+		default:
+			overrideScopes[o.ID] = "One meeting type"
+		}
+	}
+
+	holidaySet, _ := h.handlers.services.Holiday.GetHostSet(r.Context(), host.Host.ID)
+	// Holidays for the coming year, by the host's calendar
+	hostLoc, err := time.LoadLocation(host.Host.Timezone)
+	if err != nil {
+		hostLoc = time.UTC
+	}
+	today := time.Now().In(hostLoc)
+	holidays, _ := h.handlers.services.Holiday.GetHostHolidays(r.Context(), host.Host.ID,
+		today.Format("2006-01-02"), today.AddDate(1, 0, 0).Format("2006-01-02"))
+
 	// Check for flash messages from query params
 	var flash *FlashMessage
-	if r.URL.Query().Get("success") == "updated" {
+	switch r.URL.Query().Get("success") {
+	case "updated":
 		flash = &FlashMessage{Type: "success", Message: "Settings saved successfully"}
-	} else if errType := r.URL.Query().Get("error"); errType != "" {
+	case "override_added":
+		flash = &FlashMessage{Type: "success", Message: "Date override added"}
+	case "override_deleted":
+		flash = &FlashMessage{Type: "success", Message: "Date override removed"}
+	case "holidays_updated":
+		flash = &FlashMessage{Type: "success", Message: "Public holidays updated"}
+	case "limits_updated":
+		flash = &FlashMessage{Type: "success", Message: "Booking limits saved"}
+	case "spacing_updated":
+		flash = &FlashMessage{Type: "success", Message: "Meeting spacing saved"}
+	case "schedule_deleted":
+		flash = &FlashMessage{Type: "success", Message: "Schedule deleted"}
+	case "branding_updated":
+		flash = &FlashMessage{Type: "success", Message: "Email branding saved"}
+	}
+	if errType := r.URL.Query().Get("error"); errType != "" {
 		switch errType {
 		case "slug_taken":
 			flash = &FlashMessage{Type: "error", Message: "That URL slug is already taken"}
@@ -1358,6 +1554,10 @@ func (h *DashboardHandler) Settings(w http.ResponseWriter, r *http.Request) {
 			flash = &FlashMessage{Type: "error", Message: "Failed to save settings"}
 		case "invalid_form":
 			flash = &FlashMessage{Type: "error", Message: "Invalid form data"}
+		case "invalid_override":
+			flash = &FlashMessage{Type: "error", Message: "Check the override's dates and hours: each interval must end after it starts"}
+		case "invalid_branding":
+			flash = &FlashMessage{Type: "error", Message: "The logo must be an http or https URL and the accent color a hex color like #E85D40"}
 		default:
 			flash = &FlashMessage{Type: "error", Message: "An error occurred"}
 		}
@@ -1371,12 +1571,96 @@ func (h *DashboardHandler) Settings(w http.ResponseWriter, r *http.Request) {
 		PendingCount: h.getPendingCount(r, host.Host.ID),
 		Flash:        flash,
 		Data: map[string]interface{}{
-			"WorkingHours": hoursByDay,
-			"DayNames":     []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+			"WorkingHours":      hoursByDay,
+			"DayNames":          []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+			"OverrideCalendar":  overrideCalendar,
+			"UpcomingOverrides": upcomingOverrides,
+			"Templates":         templates,
+			"OverrideScopes":    overrideScopes,
+			"Schedules":         schedules,
+			"HolidaySets":       h.handlers.services.Holiday.Sets(),
+			"HolidaySet":        holidaySet,
+			"Holidays":          holidays,
 		},
 	})
 }
 
+// overrideCalendarDay is one cell of the date-override editor's month grid.
+type overrideCalendarDay struct {
+	Date      string // YYYY-MM-DD
+	DayNum    int
+	IsInMonth bool
+	IsToday   bool
+	IsPast    bool
+	IsOff     bool // a host-wide override blocks the whole day
+	HasCustom bool // custom hours, or an override for one meeting type
+}
+
+// buildOverrideCalendar lays out the month named by ?month= (YYYY-MM,
+// default the host's current month) and marks the days that have overrides.
+func (h *DashboardHandler) buildOverrideCalendar(r *http.Request, host *models.Host) map[string]interface{} {
+	loc, err := time.LoadLocation(host.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	now := time.Now().In(loc)
+	today := now.Format("2006-01-02")
+
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if parsed, err := time.Parse("2006-01", r.URL.Query().Get("month")); err == nil {
+		month = parsed
+	}
+	monthEnd := month.AddDate(0, 1, -1)
+
+	overrides, _ := h.handlers.services.Availability.GetOverridesInRange(r.Context(), host.ID,
+		month.Format("2006-01-02"), monthEnd.Format("2006-01-02"))
+
+	var weeks [][]overrideCalendarDay
+	var week []overrideCalendarDay
+	for i := 0; i < int(month.Weekday()); i++ {
+		week = append(week, overrideCalendarDay{})
+	}
+	for d := month; !d.After(monthEnd); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		day := overrideCalendarDay{
+			Date:      date,
+			DayNum:    d.Day(),
+			IsInMonth: true,
+			IsToday:   date == today,
+			IsPast:    date < today,
+		}
+		for _, o := range overrides {
+			if !o.Covers(date) || o.ScheduleID != nil {
+				continue
+			}
+			if o.TemplateID == nil && o.IsTimeOff() {
+				day.IsOff = true
+			} else {
+				day.HasCustom = true
+			}
+		}
+		week = append(week, day)
+		if len(week) == 7 {
+			weeks = append(weeks, week)
+			week = nil
+		}
+	}
+	for len(week) > 0 && len(week) < 7 {
+		week = append(week, overrideCalendarDay{})
+	}
+	if len(week) > 0 {
+		weeks = append(weeks, week)
+	}
+
+	return map[string]interface{}{
+		"Weeks":        weeks,
+		"MonthDisplay": month.Format("January 2006"),
+		"MonthValue":   month.Format("2006-01"),
+		"PrevMonth":    month.AddDate(0, -1, 0).Format("2006-01"),
+		"NextMonth":    month.AddDate(0, 1, 0).Format("2006-01"),
+	}
+}
+
 // UpdateSettings updates host settings
 func (h *DashboardHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
 	host := middleware.GetHost(r.Context())
@@ -1466,6 +1750,517 @@ func (h *DashboardHandler) UpdateWorkingHours(w http.ResponseWriter, r *http.Req
 	h.handlers.redirect(w, r, "/dashboard/settings?success=hours_updated")
 }
 
+// UpdateBookingLimits sets the host's booking caps across all meeting types
+func (h *DashboardHandler) UpdateBookingLimits(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.handlers.redirect(w, r, "/dashboard/settings?error=invalid_form#booking-limits")
+		return
+	}
+
+	perDay := parseIntOrDefault(r.FormValue("max_bookings_per_day"), 0)
+	perWeek := parseIntOrDefault(r.FormValue("max_bookings_per_week"), 0)
+	minutesPerDay := parseIntOrDefault(r.FormValue("max_minutes_per_day"), 0)
+	if err := h.handlers.services.Availability.SetBookingCaps(r.Context(), host.Host.ID, perDay, perWeek, minutesPerDay); err != nil {
+		log.Printf("[DASHBOARD] Failed to update booking limits: %v", err)
+		h.handlers.redirect(w, r, "/dashboard/settings?error=update_failed#booking-limits")
+		return
+	}
+
+	h.handlers.services.AuditLog.Log(r.Context(), host.Tenant.ID, &host.Host.ID, "booking_limits.updated", "host", host.Host.ID, models.JSONMap{
+		"max_bookings_per_day":  perDay,
+		"max_bookings_per_week": perWeek,
+		"max_minutes_per_day":   minutesPerDay,
+	}, r.RemoteAddr)
+
+	h.handlers.redirect(w, r, "/dashboard/settings?success=limits_updated#booking-limits")
+}
+
+// UpdateMeetingSpacing saves the time the host keeps free around calendar
+// events and between meetings, and their back-to-back limit
+func (h *DashboardHandler) UpdateMeetingSpacing(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.handlers.redirect(w, r, "/dashboard/settings?error=invalid_form#meeting-spacing")
+		return
+	}
+
+	eventBefore := parseIntOrDefault(r.FormValue("event_buffer_before"), 0)
+	eventAfter := parseIntOrDefault(r.FormValue("event_buffer_after"), 0)
+	minGap := parseIntOrDefault(r.FormValue("min_gap_minutes"), 0)
+	maxBackToBack := parseIntOrDefault(r.FormValue("max_back_to_back"), 0)
+	if err := h.handlers.services.Availability.SetBusyPadding(r.Context(), host.Host.ID, eventBefore, eventAfter, minGap, maxBackToBack); err != nil {
+		log.Printf("[DASHBOARD] Failed to update meeting spacing: %v", err)
+		h.handlers.redirect(w, r, "/dashboard/settings?error=update_failed#meeting-spacing")
+		return
+	}
+
+	h.handlers.services.AuditLog.Log(r.Context(), host.Tenant.ID, &host.Host.ID, "meeting_spacing.updated", "host", host.Host.ID, models.JSONMap{
+		"event_buffer_before": eventBefore,
+		"event_buffer_after":  eventAfter,
+		"min_gap_minutes":     minGap,
+		"max_back_to_back":    maxBackToBack,
+	}, r.RemoteAddr)
+
+	h.handlers.redirect(w, r, "/dashboard/settings?success=spacing_updated#meeting-spacing")
+}
+
+// CreateAvailabilityOverride adds a date override or time-off block
+func (h *DashboardHandler) CreateAvailabilityOverride(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.handlers.redirect(w, r, "/dashboard/settings?error=invalid_form")
+		return
+	}
+
+	back := overrideSettingsURL(r.FormValue("month"))
+	input, ok := overrideInputFromForm(r)
+	if !ok {
+		h.handlers.redirect(w, r, back+"error=invalid_override#date-overrides")
+		return
+	}
+
+	override, err := h.handlers.services.Availability.CreateOverride(r.Context(), host.Host.ID, input)
+	if err != nil {
+		log.Printf("[DASHBOARD] Failed to create availability override: %v", err)
+		if errors.Is(err, services.ErrInvalidOverride) {
+			h.handlers.redirect(w, r, back+"error=invalid_override#date-overrides")
+		} else {
+			h.handlers.redirect(w, r, back+"error=update_failed#date-overrides")
+		}
+		return
+	}
+
+	h.handlers.services.AuditLog.Log(r.Context(), host.Tenant.ID, &host.Host.ID, "availability_override.created", "availability_override", override.ID, models.JSONMap{
+		"start_date": override.StartDate,
+		"end_date":   override.EndDate,
+		"time_off":   override.IsTimeOff(),
+	}, r.RemoteAddr)
+
+	h.handlers.redirect(w, r, back+"success=override_added#date-overrides")
+}
+
+// DeleteAvailabilityOverride removes a date override
+func (h *DashboardHandler) DeleteAvailabilityOverride(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	id := r.PathValue("id")
+	back := overrideSettingsURL(r.FormValue("month"))
+	if err := h.handlers.services.Availability.DeleteOverride(r.Context(), host.Host.ID, id); err != nil {
+		log.Printf("[DASHBOARD] Failed to delete availability override %s: %v", id, err)
+		h.handlers.redirect(w, r, back+"error=update_failed#date-overrides")
+		return
+	}
+
+	h.handlers.services.AuditLog.Log(r.Context(), host.Tenant.ID, &host.Host.ID, "availability_override.deleted", "availability_override", id, nil, r.RemoteAddr)
+
+	h.handlers.redirect(w, r, back+"success=override_deleted#date-overrides")
+}
+
+// NewSchedulePage renders the form for a new availability schedule
+func (h *DashboardHandler) NewSchedulePage(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	h.handlers.render(w, "dashboard_schedule_form.html", PageData{
+		Title:        "New Schedule",
+		Host:         host.Host,
+		Tenant:       host.Tenant,
+		ActiveNav:    "settings",
+		PendingCount: h.getPendingCount(r, host.Host.ID),
+		Flash:        scheduleFlash(r),
+		Data: map[string]interface{}{
+			"Schedule": nil,
+			"IsNew":    true,
+			"DayNames": []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		},
+	})
+}
+
+// EditSchedulePage renders an availability schedule's hours and date overrides
+func (h *DashboardHandler) EditSchedulePage(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	schedule, err := h.handlers.services.Schedule.GetSchedule(r.Context(), host.Host.ID, r.PathValue("id"))
+	if err != nil {
+		h.handlers.error(w, r, http.StatusNotFound, "Schedule not found")
+		return
+	}
+	overrides, _ := h.handlers.services.Schedule.GetUpcomingOverrides(r.Context(), schedule)
+
+	// Meeting types on this schedule
+	templates, _ := h.handlers.services.Template.GetTemplates(r.Context(), host.Host.ID)
+	var usedBy []*models.MeetingTemplate
+	for _, t := range templates {
+		if t.ScheduleID == schedule.ID {
+			usedBy = append(usedBy, t)
+		}
+	}
+
+	h.handlers.render(w, "dashboard_schedule_form.html", PageData{
+		Title:        schedule.Name,
+		Host:         host.Host,
+		Tenant:       host.Tenant,
+		ActiveNav:    "settings",
+		PendingCount: h.getPendingCount(r, host.Host.ID),
+		Flash:        scheduleFlash(r),
+		Data: map[string]interface{}{
+			"Schedule":          schedule,
+			"IsNew":             false,
+			"DayNames":          []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+			"UpcomingOverrides": overrides,
+			"UsedBy":            usedBy,
+		},
+	})
+}
+
+// scheduleFlash returns the flash message for the schedule form's query params
+func scheduleFlash(r *http.Request) *FlashMessage {
+	switch r.URL.Query().Get("success") {
+	case "created":
+		return &FlashMessage{Type: "success", Message: "Schedule created"}
+	case "updated":
+		return &FlashMessage{Type: "success", Message: "Schedule saved"}
+	case "override_added":
+		return &FlashMessage{Type: "success", Message: "Date override added"}
+	case "override_deleted":
+		return &FlashMessage{Type: "success", Message: "Date override removed"}
+	}
+	switch r.URL.Query().Get("error") {
+	case "":
+		return nil
+	case "invalid_schedule":
+		return &FlashMessage{Type: "error", Message: "Give the schedule a name and a valid timezone: each interval must end after it starts"}
+	case "invalid_override":
+		return &FlashMessage{Type: "error", Message: "Check the override's dates and hours: each interval must end after it starts"}
+	default:
+		return &FlashMessage{Type: "error", Message: "An error occurred"}
+	}
+}
+
+// scheduleInputFromForm reads the schedule form. The weekly hours come from
+// the editor as JSON.
+func scheduleInputFromForm(r *http.Request) (services.ScheduleInput, error) {
+	input := services.ScheduleInput{
+		Name:     r.FormValue("name"),
+		Timezone: r.FormValue("timezone"),
+	}
+	if hoursJSON := r.FormValue("weekly_hours"); hoursJSON != "" {
+		if err := json.Unmarshal([]byte(hoursJSON), &input.WeeklyHours); err != nil {
+			return input, err
+		}
+	}
+	return input, nil
+}
+
+// CreateSchedule adds an availability schedule
+func (h *DashboardHandler) CreateSchedule(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.handlers.redirect(w, r, "/dashboard/settings/schedules/new?error=invalid_form")
+		return
+	}
+	input, err := scheduleInputFromForm(r)
+	if err != nil {
+		log.Printf("[DASHBOARD] Failed to parse weekly_hours: %v", err)
+		h.handlers.redirect(w, r, "/dashboard/settings/schedules/new?error=invalid_schedule")
+		return
+	}
+
+	schedule, err := h.handlers.services.Schedule.CreateSchedule(r.Context(), host.Host.ID, input)
+	if err != nil {
+		log.Printf("[DASHBOARD] Failed to create schedule: %v", err)
+		if errors.Is(err, services.ErrInvalidSchedule) {
+			h.handlers.redirect(w, r, "/dashboard/settings/schedules/new?error=invalid_schedule")
+		} else {
+			h.handlers.redirect(w, r, "/dashboard/settings/schedules/new?error=update_failed")
+		}
+		return
+	}
+
+	h.handlers.services.AuditLog.Log(r.Context(), host.Tenant.ID, &host.Host.ID, "schedule.created", "availability_schedule", schedule.ID, models.JSONMap{
+		"name":     schedule.Name,
+		"timezone": schedule.Timezone,
+	}, r.RemoteAddr)
+
+	h.handlers.redirect(w, r, "/dashboard/settings/schedules/"+schedule.ID+"?success=created")
+}
+
+// UpdateSchedule saves an availability schedule's name, timezone and hours
+func (h *DashboardHandler) UpdateSchedule(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	id := r.PathValue("id")
+	back := "/dashboard/settings/schedules/" + id
+	if err := r.ParseForm(); err != nil {
+		h.handlers.redirect(w, r, back+"?error=invalid_form")
+		return
+	}
+	input, err := scheduleInputFromForm(r)
+	if err != nil {
+		log.Printf("[DASHBOARD] Failed to parse weekly_hours: %v", err)
+		h.handlers.redirect(w, r, back+"?error=invalid_schedule")
+		return
+	}
+
+	schedule, err := h.handlers.services.Schedule.UpdateSchedule(r.Context(), host.Host.ID, id, input)
+	if err != nil {
+		log.Printf("[DASHBOARD] Failed to update schedule %s: %v", id, err)
+		switch {
+		case errors.Is(err, services.ErrScheduleNotFound):
+			h.handlers.error(w, r, http.StatusNotFound, "Schedule not found")
+		case errors.Is(err, services.ErrInvalidSchedule):
+			h.handlers.redirect(w, r, back+"?error=invalid_schedule")
+		default:
+			h.handlers.redirect(w, r, back+"?error=update_failed")
+		}
+		return
+	}
+
+	h.handlers.services.AuditLog.Log(r.Context(), host.Tenant.ID, &host.Host.ID, "schedule.updated", "availability_schedule", schedule.ID, models.JSONMap{
+		"name":     schedule.Name,
+		"timezone": schedule.Timezone,
+	}, r.RemoteAddr)
+
+	h.handlers.redirect(w, r, back+"?success=updated")
+}
+
+// DeleteSchedule removes an availability schedule. Meeting types on it go
+// back to the host's working hours.
+func (h *DashboardHandler) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	id := r.PathValue("id")
+	if err := h.handlers.services.Schedule.DeleteSchedule(r.Context(), host.Host.ID, id); err != nil {
+		log.Printf("[DASHBOARD] Failed to delete schedule %s: %v", id, err)
+		h.handlers.redirect(w, r, "/dashboard/settings?error=update_failed#schedules")
+		return
+	}
+
+	h.handlers.services.AuditLog.Log(r.Context(), host.Tenant.ID, &host.Host.ID, "schedule.deleted", "availability_schedule", id, nil, r.RemoteAddr)
+
+	h.handlers.redirect(w, r, "/dashboard/settings?success=schedule_deleted#schedules")
+}
+
+// CreateScheduleOverride adds a date override to an availability schedule
+func (h *DashboardHandler) CreateScheduleOverride(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	id := r.PathValue("id")
+	back := "/dashboard/settings/schedules/" + id + "?"
+	if err := r.ParseForm(); err != nil {
+		h.handlers.redirect(w, r, back+"error=invalid_form#date-overrides")
+		return
+	}
+	input, ok := overrideInputFromForm(r)
+	if !ok {
+		h.handlers.redirect(w, r, back+"error=invalid_override#date-overrides")
+		return
+	}
+	input.TemplateID = ""
+	input.ScheduleID = id
+
+	override, err := h.handlers.services.Availability.CreateOverride(r.Context(), host.Host.ID, input)
+	if err != nil {
+		log.Printf("[DASHBOARD] Failed to create schedule override: %v", err)
+		if errors.Is(err, services.ErrInvalidOverride) {
+			h.handlers.redirect(w, r, back+"error=invalid_override#date-overrides")
+		} else {
+			h.handlers.redirect(w, r, back+"error=update_failed#date-overrides")
+		}
+		return
+	}
+
+	h.handlers.services.AuditLog.Log(r.Context(), host.Tenant.ID, &host.Host.ID, "availability_override.created", "availability_override", override.ID, models.JSONMap{
+		"schedule_id": id,
+		"start_date":  override.StartDate,
+		"end_date":    override.EndDate,
+		"time_off":    override.IsTimeOff(),
+	}, r.RemoteAddr)
+
+	h.handlers.redirect(w, r, back+"success=override_added#date-overrides")
+}
+
+// DeleteScheduleOverride removes a date override from an availability schedule
+func (h *DashboardHandler) DeleteScheduleOverride(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	id := r.PathValue("overrideId")
+	back := "/dashboard/settings/schedules/" + r.PathValue("id") + "?"
+	if err := h.handlers.services.Availability.DeleteOverride(r.Context(), host.Host.ID, id); err != nil {
+		log.Printf("[DASHBOARD] Failed to delete availability override %s: %v", id, err)
+		h.handlers.redirect(w, r, back+"error=update_failed#date-overrides")
+		return
+	}
+
+	h.handlers.services.AuditLog.Log(r.Context(), host.Tenant.ID, &host.Host.ID, "availability_override.deleted", "availability_override", id, nil, r.RemoteAddr)
+
+	h.handlers.redirect(w, r, back+"success=override_deleted#date-overrides")
+}
+
+// UpdateHolidaySet sets which public holiday set blocks the host's
+// availability
+func (h *DashboardHandler) UpdateHolidaySet(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	code := r.FormValue("holiday_set")
+	if err := h.handlers.services.Holiday.SetHostSet(r.Context(), host.Host.ID, code); err != nil {
+		log.Printf("[DASHBOARD] Failed to set holiday set %q: %v", code, err)
+		if errors.Is(err, services.ErrUnknownHolidaySet) {
+			h.handlers.redirect(w, r, "/dashboard/settings?error=invalid_form#holidays")
+		} else {
+			h.handlers.redirect(w, r, "/dashboard/settings?error=update_failed#holidays")
+		}
+		return
+	}
+
+	h.handlers.services.AuditLog.Log(r.Context(), host.Tenant.ID, &host.Host.ID, "holiday_set.updated", "host", host.Host.ID, models.JSONMap{
+		"holiday_set": code,
+	}, r.RemoteAddr)
+
+	h.handlers.redirect(w, r, "/dashboard/settings?success=holidays_updated#holidays")
+}
+
+// UpdateHoliday blocks or un-blocks one holiday in the host's set
+func (h *DashboardHandler) UpdateHoliday(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	date := r.PathValue("date")
+	blocked := r.FormValue("blocked") == "true"
+	if err := h.handlers.services.Holiday.SetHolidayBlocked(r.Context(), host.Host.ID, date, blocked); err != nil {
+		log.Printf("[DASHBOARD] Failed to update holiday %s: %v", date, err)
+		if errors.Is(err, services.ErrNotAHoliday) {
+			h.handlers.redirect(w, r, "/dashboard/settings?error=invalid_form#holidays")
+		} else {
+			h.handlers.redirect(w, r, "/dashboard/settings?error=update_failed#holidays")
+		}
+		return
+	}
+
+	action := "holiday.unblocked"
+	if blocked {
+		action = "holiday.blocked"
+	}
+	h.handlers.services.AuditLog.Log(r.Context(), host.Tenant.ID, &host.Host.ID, action, "host", host.Host.ID, models.JSONMap{
+		"date": date,
+	}, r.RemoteAddr)
+
+	h.handlers.redirect(w, r, "/dashboard/settings?success=holidays_updated#holidays")
+}
+
+// overrideSettingsURL returns the settings page URL, keeping the override
+// calendar on month, ready for a query parameter to be appended.
+func overrideSettingsURL(month string) string {
+	if month == "" {
+		return "/dashboard/settings?"
+	}
+	return "/dashboard/settings?month=" + url.QueryEscape(month) + "&"
+}
+
+// overrideInputFromForm reads a date override form. ok is false when custom
+// hours were picked without any, which would silently mean time off.
+func overrideInputFromForm(r *http.Request) (input services.CreateOverrideInput, ok bool) {
+	input = services.CreateOverrideInput{
+		TemplateID: r.FormValue("template_id"),
+		StartDate:  r.FormValue("start_date"),
+		EndDate:    r.FormValue("end_date"),
+		Note:       strings.TrimSpace(r.FormValue("note")),
+	}
+	if r.FormValue("kind") == "custom" {
+		starts, ends := r.Form["interval_start"], r.Form["interval_end"]
+		for i := range starts {
+			if i < len(ends) && (starts[i] != "" || ends[i] != "") {
+				input.Intervals = append(input.Intervals, models.TimeRange{Start: starts[i], End: ends[i]})
+			}
+		}
+		if len(input.Intervals) == 0 {
+			return input, false
+		}
+	}
+	return input, true
+}
+
+// parseIntValues returns the values of a repeated form field that parse as
+// integers
+func parseIntValues(values []string) []int {
+	var ints []int
+	for _, v := range values {
+		if i, err := strconv.Atoi(v); err == nil {
+			ints = append(ints, i)
+		}
+	}
+	return ints
+}
+
+// parseReminders returns the reminders posted as JSON by the template form,
+// or nil if there are none to save
+func parseReminders(value string) []models.Reminder {
+	if value == "" {
+		return nil
+	}
+	reminders := []models.Reminder{}
+	if err := json.Unmarshal([]byte(value), &reminders); err != nil {
+		log.Printf("[TEMPLATE] Failed to parse reminders: %v", err)
+		return nil
+	}
+	return reminders
+}
+
 func parseIntOrDefault(s string, defaultValue int) int {
 	if v, err := strconv.Atoi(s); err == nil {
 		return v
@@ -1491,9 +2286,16 @@ func (h *DashboardHandler) BookingDetails(w http.ResponseWriter, r *http.Request
 	// Get template for meeting name
 	template, _ := h.handlers.services.Template.GetTemplate(r.Context(), host.Host.ID, booking.TemplateID)
 
+	// Everyone booked into the same slot of a group template
+	roster, err := h.handlers.services.Booking.GetSeats(r.Context(), booking)
+	if err != nil {
+		log.Printf("[DASHBOARD] Error loading seats of booking %s: %v", booking.ID, err)
+	}
+
 	h.handlers.renderPartial(w, "booking_details_partial.html", map[string]interface{}{
 		"Booking":      booking,
 		"Template":     template,
+		"Roster":       roster,
 		"HostTimezone": host.Host.Timezone,
 	})
 }
@@ -1696,6 +2498,7 @@ func (h *DashboardHandler) Agenda(w http.ResponseWriter, r *http.Request) {
 	var lanes []services.CalendarLane
 	var windowStart, windowEnd time.Time
 	var hourLabels []services.HourLabel
+	var holiday *services.HostHoliday
 
 	// week-view fields
 	var weekDays []services.WeekDayView
@@ -1732,6 +2535,7 @@ func (h *DashboardHandler) Agenda(w http.ResponseWriter, r *http.Request) {
 		} else {
 			agendaEvents = agendaView.Events
 			agendaCalendars = agendaView.Calendars
+			holiday = agendaView.Holiday
 			lanes = services.LanesByCalendar(agendaView)
 			windowStart, windowEnd = services.ComputeVisibleWindow(agendaView.Events, agendaView.DayStart, agendaView.DayEnd)
 			hourLabels = services.GenerateHourLabels(windowStart, windowEnd)
@@ -1756,6 +2560,7 @@ func (h *DashboardHandler) Agenda(w http.ResponseWriter, r *http.Request) {
 			"WindowStart": windowStart,
 			"WindowEnd":   windowEnd,
 			"HourLabels":  hourLabels,
+			"Holiday":     holiday,
 		},
 	})
 }
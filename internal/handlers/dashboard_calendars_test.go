@@ -403,3 +403,53 @@ func TestUpdateSubCalendarColor_ValidatesPalette(t *testing.T) {
 		t.Errorf("color not persisted: got %s want %s", pc.Color, services.CalendarPalette[0])
 	}
 }
+
+// TestOutlookConnect_RejectsMismatchedState checks the Outlook callback
+// only completes a consent the signed-in browser started: state must match
+// the cookie ConnectOutlook set.
+func TestOutlookConnect_RejectsMismatchedState(t *testing.T) {
+	_, repos, cleanup := setupTestDatabase(t)
+	defer cleanup()
+
+	host, _ := seedDashboardCalendarFixture(t, repos)
+	h := createTestHandlers(t, repos)
+
+	w := httptest.NewRecorder()
+	h.Dashboard.ConnectOutlook(w, requestWithHost(http.MethodGet, "/dashboard/calendars/connect/outlook", "", host))
+	var stateCookie *http.Cookie
+	for _, c := range w.Result().Cookies() {
+		if c.Name == outlookStateCookie {
+			stateCookie = c
+		}
+	}
+	if stateCookie == nil || stateCookie.Value == "" || !stateCookie.HttpOnly {
+		t.Fatalf("state cookie = %+v", stateCookie)
+	}
+	authURL, err := url.Parse(w.Header().Get("Location"))
+	if err != nil || authURL.Query().Get("state") != stateCookie.Value {
+		t.Fatalf("auth URL %q doesn't carry the cookie's state", w.Header().Get("Location"))
+	}
+	if authURL.Query().Get("state") == host.Host.ID {
+		t.Fatal("state is the host ID")
+	}
+
+	before, _ := repos.Calendar.GetByHostID(context.Background(), host.Host.ID)
+	for name, tc := range map[string]struct{ cookie, state string }{
+		"no cookie":     {"", stateCookie.Value},
+		"other cookie":  {"someone-elses-state", stateCookie.Value},
+		"host ID state": {stateCookie.Value, host.Host.ID},
+	} {
+		r := requestWithHost(http.MethodGet, "/auth/microsoft/callback?code=attacker-code&state="+url.QueryEscape(tc.state), "", host)
+		if tc.cookie != "" {
+			r.AddCookie(&http.Cookie{Name: outlookStateCookie, Value: tc.cookie})
+		}
+		w := httptest.NewRecorder()
+		h.Auth.OutlookCallback(w, r)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: %d, want 400", name, w.Code)
+		}
+	}
+	if after, _ := repos.Calendar.GetByHostID(context.Background(), host.Host.ID); len(after) != len(before) {
+		t.Errorf("calendars went from %d to %d", len(before), len(after))
+	}
+}
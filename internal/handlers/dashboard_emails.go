@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/meet-when/meet-when/internal/middleware"
+	"github.com/meet-when/meet-when/internal/models"
+	"github.com/meet-when/meet-when/internal/services"
+)
+
+// UpdateEmailBranding saves the tenant's email logo, accent color and
+// footer. Admins only, since every host's emails carry them.
+func (h *DashboardHandler) UpdateEmailBranding(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+	if !host.Host.IsAdmin {
+		h.handlers.error(w, r, http.StatusForbidden, "Access denied. Admin privileges required.")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		h.handlers.redirect(w, r, "/dashboard/settings?error=invalid_form#email-branding")
+		return
+	}
+
+	err := h.handlers.services.Email.UpdateBranding(r.Context(), host.Tenant,
+		r.FormValue("logo_url"), r.FormValue("accent_color"), r.FormValue("email_footer"))
+	if err != nil {
+		log.Printf("[DASHBOARD] Failed to update email branding: %v", err)
+		if errors.Is(err, services.ErrInvalidBranding) {
+			h.handlers.redirect(w, r, "/dashboard/settings?error=invalid_branding#email-branding")
+		} else {
+			h.handlers.redirect(w, r, "/dashboard/settings?error=update_failed#email-branding")
+		}
+		return
+	}
+
+	h.handlers.services.AuditLog.Log(r.Context(), host.Tenant.ID, &host.Host.ID, "email_branding.updated", "tenant", host.Tenant.ID, models.JSONMap{
+		"logo_url":     host.Tenant.LogoURL,
+		"accent_color": host.Tenant.AccentColor,
+	}, r.RemoteAddr)
+
+	h.handlers.redirect(w, r, "/dashboard/settings?success=branding_updated#email-branding")
+}
+
+// EmailPreviews renders the email preview page: the list of email types
+// and the selected one (?type=, default the first) in a frame
+func (h *DashboardHandler) EmailPreviews(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	selected := services.EmailPreviews[0]
+	for _, p := range services.EmailPreviews {
+		if p.Type == r.URL.Query().Get("type") {
+			selected = p
+		}
+	}
+
+	h.handlers.render(w, "dashboard_email_preview.html", PageData{
+		Title:        "Email Preview",
+		Host:         host.Host,
+		Tenant:       host.Tenant,
+		ActiveNav:    "settings",
+		PendingCount: h.getPendingCount(r, host.Host.ID),
+		Data: map[string]interface{}{
+			"Previews": services.EmailPreviews,
+			"Selected": selected,
+		},
+	})
+}
+
+// EmailPreview returns one email type rendered with sample data in the
+// tenant's branding, as a standalone document for the preview frame
+func (h *DashboardHandler) EmailPreview(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	email, err := h.handlers.services.Email.PreviewEmail(host.Host, host.Tenant, r.PathValue("type"))
+	if err != nil {
+		log.Printf("[DASHBOARD] Failed to render email preview %s: %v", r.PathValue("type"), err)
+		http.Error(w, "Email not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(email.Body))
+}
+
+// FailedEmails lists the emails that failed every attempt to send them:
+// the whole tenant's for admins, the host's own for everyone else
+func (h *DashboardHandler) FailedEmails(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	emails, err := h.handlers.services.Outbox.GetFailed(r.Context(), host.Tenant.ID, hostScope(host))
+	if err != nil {
+		log.Printf("[DASHBOARD] Failed to load failed emails: %v", err)
+	}
+
+	var flash *FlashMessage
+	if r.URL.Query().Get("success") == "resent" {
+		flash = &FlashMessage{Type: "success", Message: "Email queued to send again"}
+	}
+	switch r.URL.Query().Get("error") {
+	case "":
+	case "not_found":
+		flash = &FlashMessage{Type: "error", Message: "That email has already been resent or removed"}
+	default:
+		flash = &FlashMessage{Type: "error", Message: "An error occurred"}
+	}
+
+	h.handlers.render(w, "dashboard_failed_emails.html", PageData{
+		Title:        "Failed Emails",
+		Host:         host.Host,
+		Tenant:       host.Tenant,
+		ActiveNav:    "settings",
+		PendingCount: h.getPendingCount(r, host.Host.ID),
+		Flash:        flash,
+		Data: map[string]interface{}{
+			"Emails": emails,
+		},
+	})
+}
+
+// ResendEmail puts a failed email back in the outbox to be sent now
+func (h *DashboardHandler) ResendEmail(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	id := r.PathValue("id")
+	email, err := h.handlers.services.Outbox.Resend(r.Context(), host.Tenant.ID, hostScope(host), id)
+	if err != nil {
+		if errors.Is(err, services.ErrOutboxEmailNotFound) {
+			h.handlers.redirect(w, r, "/dashboard/emails/failed?error=not_found")
+		} else {
+			log.Printf("[DASHBOARD] Failed to resend email %s: %v", id, err)
+			h.handlers.redirect(w, r, "/dashboard/emails/failed?error=resend_failed")
+		}
+		return
+	}
+
+	h.handlers.services.AuditLog.Log(r.Context(), host.Tenant.ID, &host.Host.ID, "email.resent", "email", id, models.JSONMap{
+		"recipient": email.Recipient,
+		"subject":   email.Subject,
+	}, r.RemoteAddr)
+
+	h.handlers.redirect(w, r, "/dashboard/emails/failed?success=resent")
+}
+
+// hostScope returns the host whose failed emails and webhooks the
+// signed-in host may see and manage, or "" for admins, who may manage the
+// whole tenant's
+func hostScope(host *services.HostWithTenant) string {
+	if host.Host.IsAdmin {
+		return ""
+	}
+	return host.Host.ID
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+func TestUpdateEmailBranding_AdminOnly(t *testing.T) {
+	_, repos, cleanup := setupTestDatabase(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	host, _ := seedDashboardCalendarFixture(t, repos)
+	h := createTestHandlers(t, repos)
+
+	form := url.Values{}
+	form.Set("logo_url", "https://cdn.example.com/logo.png")
+	form.Set("accent_color", "#0a7cff")
+	form.Set("email_footer", "Acme Ltd")
+
+	w := httptest.NewRecorder()
+	h.Dashboard.UpdateEmailBranding(w, requestWithHost(http.MethodPost, "/dashboard/settings/email-branding", form.Encode(), host))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("non-admin: status = %d, want 403", w.Code)
+	}
+
+	host.Host.IsAdmin = true
+	w = httptest.NewRecorder()
+	h.Dashboard.UpdateEmailBranding(w, requestWithHost(http.MethodPost, "/dashboard/settings/email-branding", form.Encode(), host))
+	if loc := w.Header().Get("Location"); loc != "/dashboard/settings?success=branding_updated#email-branding" {
+		t.Fatalf("admin: redirected to %q", loc)
+	}
+	tenant, err := repos.Tenant.GetByID(ctx, host.Tenant.ID)
+	if err != nil || tenant == nil {
+		t.Fatalf("get tenant: %v", err)
+	}
+	if tenant.LogoURL != "https://cdn.example.com/logo.png" || tenant.AccentColor != "#0A7CFF" || tenant.EmailFooter != "Acme Ltd" {
+		t.Errorf("saved branding = %q %q %q", tenant.LogoURL, tenant.AccentColor, tenant.EmailFooter)
+	}
+
+	form.Set("accent_color", "blue")
+	w = httptest.NewRecorder()
+	h.Dashboard.UpdateEmailBranding(w, requestWithHost(http.MethodPost, "/dashboard/settings/email-branding", form.Encode(), host))
+	if loc := w.Header().Get("Location"); loc != "/dashboard/settings?error=invalid_branding#email-branding" {
+		t.Errorf("invalid color: redirected to %q", loc)
+	}
+}
+
+func TestResendEmail_ScopedToHostUnlessAdmin(t *testing.T) {
+	_, repos, cleanup := setupTestDatabase(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	host, _ := seedDashboardCalendarFixture(t, repos)
+	h := createTestHandlers(t, repos)
+
+	// A dead email sent for a colleague in the same tenant
+	colleague := &models.Host{
+		ID: uuid.New().String(), TenantID: host.Tenant.ID,
+		Email: "c-" + uuid.New().String()[:8] + "@x", PasswordHash: "x", Name: "C",
+		Slug: "c-" + uuid.New().String()[:8], Timezone: "UTC",
+		CreatedAt: models.Now(), UpdatedAt: models.Now(),
+	}
+	if err := repos.Host.Create(ctx, colleague); err != nil {
+		t.Fatalf("host: %v", err)
+	}
+	email := &models.OutboxEmail{
+		ID: uuid.New().String(), TenantID: &host.Tenant.ID, HostID: &colleague.ID,
+		Recipient: "ann@example.com", Subject: "Confirmed", TextBody: "Hello",
+		Status: models.OutboxEmailStatusPending, NextAttemptAt: models.Now(),
+		CreatedAt: models.Now(), UpdatedAt: models.Now(),
+	}
+	if err := repos.EmailOutbox.Create(ctx, email); err != nil {
+		t.Fatalf("create email: %v", err)
+	}
+	if err := repos.EmailOutbox.MarkDead(ctx, email.ID, 8, "550 mailbox unavailable"); err != nil {
+		t.Fatalf("mark dead: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	h.Dashboard.ResendEmail(w, pathValueRequest(http.MethodPost, "/dashboard/emails/failed/"+email.ID+"/resend", "id", email.ID, "", host))
+	if loc := w.Header().Get("Location"); loc != "/dashboard/emails/failed?error=not_found" {
+		t.Fatalf("non-admin: redirected to %q", loc)
+	}
+	if got, _ := repos.EmailOutbox.GetByID(ctx, email.ID); got.Status != models.OutboxEmailStatusDead {
+		t.Fatalf("non-admin resent a colleague's email: status %q", got.Status)
+	}
+
+	host.Host.IsAdmin = true
+	w = httptest.NewRecorder()
+	h.Dashboard.ResendEmail(w, pathValueRequest(http.MethodPost, "/dashboard/emails/failed/"+email.ID+"/resend", "id", email.ID, "", host))
+	if loc := w.Header().Get("Location"); loc != "/dashboard/emails/failed?success=resent" {
+		t.Fatalf("admin: redirected to %q", loc)
+	}
+	if got, _ := repos.EmailOutbox.GetByID(ctx, email.ID); got.Status != models.OutboxEmailStatusPending || got.Attempts != 0 {
+		t.Errorf("after resend: status %q, %d attempts", got.Status, got.Attempts)
+	}
+}
@@ -336,6 +336,59 @@ func (h *DashboardEventsHandler) CheckConflicts(w http.ResponseWriter, r *http.R
 	})
 }
 
+// FindTimes returns the find-a-time suggestions partial: times in the week
+// from start_date when the host and the attendees who are colleagues in
+// the tenant are all working, least disruptive first.
+//
+// Query params: start_date (YYYY-MM-DD, optional), duration (min),
+// timezone (IANA), attendee_email (repeated), exclude_event_id (optional).
+func (h *DashboardEventsHandler) FindTimes(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	tz := query.Get("timezone")
+	loc, err := time.LoadLocation(tz)
+	if tz == "" || err != nil {
+		tz = host.Host.Timezone
+		if loc, err = time.LoadLocation(tz); err != nil {
+			tz, loc = "UTC", time.UTC
+		}
+	}
+	from := time.Now().In(loc)
+	if day, err := time.ParseInLocation("2006-01-02", query.Get("start_date"), loc); err == nil && day.After(from) {
+		from = day
+	}
+	to := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 7)
+	duration, _ := strconv.Atoi(query.Get("duration"))
+	if duration <= 0 {
+		duration = 30
+	}
+
+	result, err := h.handlers.services.HostedEvent.FindTimes(r.Context(), services.FindTimesInput{
+		HostID:         host.Host.ID,
+		TenantID:       host.Tenant.ID,
+		AttendeeEmails: query["attendee_email"],
+		Duration:       duration,
+		From:           from.UTC(),
+		To:             to.UTC(),
+		Timezone:       tz,
+		ExcludeEventID: query.Get("exclude_event_id"),
+	})
+	if err != nil {
+		log.Printf("[EVENTS] FindTimes: %v", err)
+	}
+
+	h.handlers.renderPartial(w, "event_time_suggestions.html", map[string]interface{}{
+		"Result":   result,
+		"HostID":   host.Host.ID,
+		"Timezone": tz,
+	})
+}
+
 // AttendeeSearch returns matching contacts for the attendee picker. Empty
 // or single-character queries return an empty result.
 func (h *DashboardEventsHandler) AttendeeSearch(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/meet-when/meet-when/internal/middleware"
+	"github.com/meet-when/meet-when/internal/models"
+	"github.com/meet-when/meet-when/internal/services"
+)
+
+// webhookEventChoice is an event type checkbox on the webhook forms
+type webhookEventChoice struct {
+	Type    models.WebhookEventType
+	Label   string
+	Checked bool
+}
+
+// webhookEventChoices returns a checkbox for every event type, checked if
+// the webhook subscribes to it by name
+func webhookEventChoices(sub *models.WebhookSubscription) []webhookEventChoice {
+	choices := make([]webhookEventChoice, 0, len(services.WebhookEventOptions))
+	for _, opt := range services.WebhookEventOptions {
+		checked := false
+		if sub != nil {
+			for _, t := range sub.EventTypes {
+				checked = checked || t == string(opt.Type)
+			}
+		}
+		choices = append(choices, webhookEventChoice{Type: opt.Type, Label: opt.Label, Checked: checked})
+	}
+	return choices
+}
+
+// webhookFlash returns the flash message for a webhook page's ?success= or
+// ?error= code
+func webhookFlash(r *http.Request) *FlashMessage {
+	switch r.URL.Query().Get("success") {
+	case "created":
+		return &FlashMessage{Type: "success", Message: "Webhook added. Use its signing secret to check our posts."}
+	case "updated":
+		return &FlashMessage{Type: "success", Message: "Webhook saved"}
+	case "deleted":
+		return &FlashMessage{Type: "success", Message: "Webhook deleted"}
+	case "redelivered":
+		return &FlashMessage{Type: "success", Message: "Event queued to post again"}
+	}
+	switch r.URL.Query().Get("error") {
+	case "":
+		return nil
+	case "invalid_url":
+		return &FlashMessage{Type: "error", Message: "The webhook URL must be an http or https URL on the public internet"}
+	case "not_found":
+		return &FlashMessage{Type: "error", Message: "That webhook or delivery no longer exists"}
+	default:
+		return &FlashMessage{Type: "error", Message: "An error occurred"}
+	}
+}
+
+// webhookInput reads the URL, event types and active switch of a webhook
+// form
+func webhookInput(r *http.Request) services.WebhookInput {
+	return services.WebhookInput{
+		URL:        r.FormValue("url"),
+		EventTypes: r.Form["event_types"],
+		IsActive:   r.FormValue("is_active") != "",
+	}
+}
+
+// Webhooks lists the webhooks the signed-in host may manage, with a form
+// to add one: the whole tenant's for admins, the host's own for everyone
+// else
+func (h *DashboardHandler) Webhooks(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	webhooks, err := h.handlers.services.Webhook.ListWebhooks(r.Context(), host.Tenant.ID, hostScope(host))
+	if err != nil {
+		log.Printf("[DASHBOARD] Failed to load webhooks: %v", err)
+	}
+
+	// Admins see everyone's webhooks, so say whose each one is
+	owners := make(map[string]string, len(webhooks))
+	if host.Host.IsAdmin {
+		names := make(map[string]string)
+		tenantHosts, _ := h.handlers.repos.Host.GetByTenantID(r.Context(), host.Tenant.ID)
+		for _, th := range tenantHosts {
+			names[th.ID] = th.Name
+		}
+		for _, sub := range webhooks {
+			switch {
+			case sub.HostID == nil:
+				owners[sub.ID] = "Everyone"
+			case *sub.HostID == host.Host.ID:
+				owners[sub.ID] = "You"
+			default:
+				owners[sub.ID] = names[*sub.HostID]
+			}
+		}
+	}
+
+	h.handlers.render(w, "dashboard_webhooks.html", PageData{
+		Title:        "Webhooks",
+		Host:         host.Host,
+		Tenant:       host.Tenant,
+		ActiveNav:    "settings",
+		PendingCount: h.getPendingCount(r, host.Host.ID),
+		Flash:        webhookFlash(r),
+		Data: map[string]interface{}{
+			"Webhooks": webhooks,
+			"Owners":   owners,
+			"Events":   webhookEventChoices(nil),
+		},
+	})
+}
+
+// CreateWebhook adds a webhook. Admins may add one for the whole tenant;
+// everyone else's only hear about their own bookings and events.
+func (h *DashboardHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		h.handlers.redirect(w, r, "/dashboard/settings/webhooks?error=invalid_form")
+		return
+	}
+
+	hostID := &host.Host.ID
+	if host.Host.IsAdmin && r.FormValue("scope") == "tenant" {
+		hostID = nil
+	}
+	input := webhookInput(r)
+	input.IsActive = true
+
+	sub, err := h.handlers.services.Webhook.CreateWebhook(r.Context(), host.Tenant.ID, hostID, input)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidWebhookURL) {
+			h.handlers.redirect(w, r, "/dashboard/settings/webhooks?error=invalid_url")
+		} else {
+			log.Printf("[DASHBOARD] Failed to create webhook: %v", err)
+			h.handlers.redirect(w, r, "/dashboard/settings/webhooks?error=create_failed")
+		}
+		return
+	}
+
+	h.handlers.services.AuditLog.Log(r.Context(), host.Tenant.ID, &host.Host.ID, "webhook.created", "webhook", sub.ID, models.JSONMap{
+		"url":         sub.URL,
+		"event_types": []string(sub.EventTypes),
+		"tenant_wide": sub.HostID == nil,
+	}, r.RemoteAddr)
+
+	h.handlers.redirect(w, r, "/dashboard/settings/webhooks/"+sub.ID+"?success=created")
+}
+
+// Webhook shows one webhook: its settings, signing secret and delivery log
+func (h *DashboardHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	sub, err := h.handlers.services.Webhook.GetWebhook(r.Context(), host.Tenant.ID, hostScope(host), r.PathValue("id"))
+	if err != nil {
+		if !errors.Is(err, services.ErrWebhookNotFound) {
+			log.Printf("[DASHBOARD] Failed to load webhook %s: %v", r.PathValue("id"), err)
+		}
+		h.handlers.redirect(w, r, "/dashboard/settings/webhooks?error=not_found")
+		return
+	}
+
+	deliveries, err := h.handlers.services.Webhook.ListDeliveries(r.Context(), sub.ID)
+	if err != nil {
+		log.Printf("[DASHBOARD] Failed to load deliveries of webhook %s: %v", sub.ID, err)
+	}
+
+	h.handlers.render(w, "dashboard_webhook.html", PageData{
+		Title:        "Webhook",
+		Host:         host.Host,
+		Tenant:       host.Tenant,
+		ActiveNav:    "settings",
+		PendingCount: h.getPendingCount(r, host.Host.ID),
+		Flash:        webhookFlash(r),
+		Data: map[string]interface{}{
+			"Webhook":    sub,
+			"Events":     webhookEventChoices(sub),
+			"Deliveries": deliveries,
+		},
+	})
+}
+
+// UpdateWebhook saves a webhook's URL, event types and active switch
+func (h *DashboardHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	id := r.PathValue("id")
+	if err := r.ParseForm(); err != nil {
+		h.handlers.redirect(w, r, "/dashboard/settings/webhooks/"+id+"?error=invalid_form")
+		return
+	}
+
+	sub, err := h.handlers.services.Webhook.UpdateWebhook(r.Context(), host.Tenant.ID, hostScope(host), id, webhookInput(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrWebhookNotFound):
+			h.handlers.redirect(w, r, "/dashboard/settings/webhooks?error=not_found")
+		case errors.Is(err, services.ErrInvalidWebhookURL):
+			h.handlers.redirect(w, r, "/dashboard/settings/webhooks/"+id+"?error=invalid_url")
+		default:
+			log.Printf("[DASHBOARD] Failed to update webhook %s: %v", id, err)
+			h.handlers.redirect(w, r, "/dashboard/settings/webhooks/"+id+"?error=update_failed")
+		}
+		return
+	}
+
+	h.handlers.services.AuditLog.Log(r.Context(), host.Tenant.ID, &host.Host.ID, "webhook.updated", "webhook", sub.ID, models.JSONMap{
+		"url":         sub.URL,
+		"event_types": []string(sub.EventTypes),
+		"is_active":   sub.IsActive,
+	}, r.RemoteAddr)
+
+	h.handlers.redirect(w, r, "/dashboard/settings/webhooks/"+sub.ID+"?success=updated")
+}
+
+// DeleteWebhook removes a webhook and its delivery log
+func (h *DashboardHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	id := r.PathValue("id")
+	sub, err := h.handlers.services.Webhook.DeleteWebhook(r.Context(), host.Tenant.ID, hostScope(host), id)
+	if err != nil {
+		if errors.Is(err, services.ErrWebhookNotFound) {
+			h.handlers.redirect(w, r, "/dashboard/settings/webhooks?error=not_found")
+		} else {
+			log.Printf("[DASHBOARD] Failed to delete webhook %s: %v", id, err)
+			h.handlers.redirect(w, r, "/dashboard/settings/webhooks?error=delete_failed")
+		}
+		return
+	}
+
+	h.handlers.services.AuditLog.Log(r.Context(), host.Tenant.ID, &host.Host.ID, "webhook.deleted", "webhook", id, models.JSONMap{
+		"url": sub.URL,
+	}, r.RemoteAddr)
+
+	h.handlers.redirect(w, r, "/dashboard/settings/webhooks?success=deleted")
+}
+
+// RedeliverWebhook posts a finished delivery's event to its webhook again
+func (h *DashboardHandler) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	host := middleware.GetHost(r.Context())
+	if host == nil {
+		h.handlers.redirect(w, r, "/auth/login")
+		return
+	}
+
+	id := r.PathValue("id")
+	d, err := h.handlers.services.Webhook.Redeliver(r.Context(), host.Tenant.ID, hostScope(host), id)
+	if err != nil {
+		if errors.Is(err, services.ErrWebhookDeliveryNotFound) {
+			h.handlers.redirect(w, r, "/dashboard/settings/webhooks?error=not_found")
+		} else {
+			log.Printf("[DASHBOARD] Failed to redeliver webhook delivery %s: %v", id, err)
+			h.handlers.redirect(w, r, "/dashboard/settings/webhooks?error=redeliver_failed")
+		}
+		return
+	}
+
+	h.handlers.services.AuditLog.Log(r.Context(), host.Tenant.ID, &host.Host.ID, "webhook.redelivered", "webhook", d.SubscriptionID, models.JSONMap{
+		"event_id":   d.EventID,
+		"event_type": string(d.EventType),
+	}, r.RemoteAddr)
+
+	h.handlers.redirect(w, r, "/dashboard/settings/webhooks/"+d.SubscriptionID+"?success=redelivered")
+}
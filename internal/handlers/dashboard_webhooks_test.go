@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/meet-when/meet-when/internal/models"
+	"github.com/meet-when/meet-when/internal/services"
+)
+
+func TestWebhooks_ScopedToHostUnlessAdmin(t *testing.T) {
+	_, repos, cleanup := setupTestDatabase(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	host, _ := seedDashboardCalendarFixture(t, repos)
+	h := createTestHandlers(t, repos)
+
+	// A non-admin asking for a tenant-wide webhook gets one of their own
+	form := url.Values{}
+	form.Set("url", "https://hooks.example.com/mine")
+	form.Add("event_types", "booking.created")
+	form.Set("scope", "tenant")
+	w := httptest.NewRecorder()
+	h.Dashboard.CreateWebhook(w, requestWithHost(http.MethodPost, "/dashboard/settings/webhooks", form.Encode(), host))
+	subs, _ := repos.WebhookSubscription.GetByTenantID(ctx, host.Tenant.ID, "")
+	if len(subs) != 1 || subs[0].HostID == nil || *subs[0].HostID != host.Host.ID || !subs[0].IsActive {
+		t.Fatalf("created webhooks = %+v", subs)
+	}
+	if loc := w.Header().Get("Location"); loc != "/dashboard/settings/webhooks/"+subs[0].ID+"?success=created" {
+		t.Errorf("create: redirected to %q", loc)
+	}
+
+	form.Set("url", "not a url")
+	w = httptest.NewRecorder()
+	h.Dashboard.CreateWebhook(w, requestWithHost(http.MethodPost, "/dashboard/settings/webhooks", form.Encode(), host))
+	if loc := w.Header().Get("Location"); loc != "/dashboard/settings/webhooks?error=invalid_url" {
+		t.Errorf("invalid URL: redirected to %q", loc)
+	}
+
+	// A failed delivery to a colleague's webhook in the same tenant
+	colleague := &models.Host{
+		ID: uuid.New().String(), TenantID: host.Tenant.ID,
+		Email: "c-" + uuid.New().String()[:8] + "@x", PasswordHash: "x", Name: "C",
+		Slug: "c-" + uuid.New().String()[:8], Timezone: "UTC",
+		CreatedAt: models.Now(), UpdatedAt: models.Now(),
+	}
+	if err := repos.Host.Create(ctx, colleague); err != nil {
+		t.Fatalf("host: %v", err)
+	}
+	theirs, err := h.Dashboard.handlers.services.Webhook.CreateWebhook(ctx, host.Tenant.ID, &colleague.ID, services.WebhookInput{
+		URL: "https://hooks.example.com/theirs", IsActive: true,
+	})
+	if err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	delivery := &models.WebhookDelivery{
+		ID: uuid.New().String(), SubscriptionID: theirs.ID, EventID: uuid.New().String(),
+		EventType: models.WebhookEventBookingCreated, Payload: `{}`,
+		Status: models.WebhookDeliveryStatusPending, NextAttemptAt: models.Now(),
+		CreatedAt: models.Now(), UpdatedAt: models.Now(),
+	}
+	if err := repos.WebhookDelivery.Create(ctx, delivery); err != nil {
+		t.Fatalf("create delivery: %v", err)
+	}
+	if err := repos.WebhookDelivery.Finish(ctx, delivery.ID, models.WebhookDeliveryStatusFailed, 8, 500, "500 Internal Server Error"); err != nil {
+		t.Fatalf("finish delivery: %v", err)
+	}
+
+	form = url.Values{}
+	form.Set("url", "https://evil.example.com")
+	w = httptest.NewRecorder()
+	h.Dashboard.UpdateWebhook(w, pathValueRequest(http.MethodPut, "/dashboard/settings/webhooks/"+theirs.ID, "id", theirs.ID, form.Encode(), host))
+	if loc := w.Header().Get("Location"); loc != "/dashboard/settings/webhooks?error=not_found" {
+		t.Fatalf("non-admin update: redirected to %q", loc)
+	}
+	if got, _ := repos.WebhookSubscription.GetByID(ctx, theirs.ID); got.URL != theirs.URL || !got.IsActive {
+		t.Fatalf("non-admin changed a colleague's webhook: %+v", got)
+	}
+
+	redeliver := "/dashboard/settings/webhooks/deliveries/" + delivery.ID + "/redeliver"
+	w = httptest.NewRecorder()
+	h.Dashboard.RedeliverWebhook(w, pathValueRequest(http.MethodPost, redeliver, "id", delivery.ID, "", host))
+	if loc := w.Header().Get("Location"); loc != "/dashboard/settings/webhooks?error=not_found" {
+		t.Fatalf("non-admin redeliver: redirected to %q", loc)
+	}
+
+	host.Host.IsAdmin = true
+	w = httptest.NewRecorder()
+	h.Dashboard.RedeliverWebhook(w, pathValueRequest(http.MethodPost, redeliver, "id", delivery.ID, "", host))
+	if loc := w.Header().Get("Location"); loc != "/dashboard/settings/webhooks/"+theirs.ID+"?success=redelivered" {
+		t.Fatalf("admin redeliver: redirected to %q", loc)
+	}
+	deliveries, _ := repos.WebhookDelivery.GetBySubscriptionID(ctx, theirs.ID, 10)
+	pending := 0
+	for _, d := range deliveries {
+		if d.Status == models.WebhookDeliveryStatusPending {
+			pending++
+		}
+	}
+	if len(deliveries) != 2 || pending != 1 {
+		t.Errorf("after redeliver: %d deliveries, %d pending; want 2 and 1", len(deliveries), pending)
+	}
+
+	w = httptest.NewRecorder()
+	h.Dashboard.DeleteWebhook(w, pathValueRequest(http.MethodDelete, "/dashboard/settings/webhooks/"+theirs.ID, "id", theirs.ID, "", host))
+	if loc := w.Header().Get("Location"); loc != "/dashboard/settings/webhooks?success=deleted" {
+		t.Fatalf("admin delete: redirected to %q", loc)
+	}
+	if got, _ := repos.WebhookSubscription.GetByID(ctx, theirs.ID); got != nil {
+		t.Errorf("webhook still there after delete")
+	}
+}
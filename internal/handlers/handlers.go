@@ -25,11 +25,13 @@ type Handlers struct {
 
 	Auth            *AuthHandler
 	Public          *PublicHandler
+	PublicAPI       *PublicAPIHandler
 	Dashboard       *DashboardHandler
 	DashboardEvents *DashboardEventsHandler
 	Onboarding      *OnboardingHandler
 	API             *APIHandler
 	APIV1           *APIV1Handler
+	Webhooks        *WebhookHandler
 }
 
 // New creates all handlers
@@ -46,11 +48,13 @@ func New(cfg *config.Config, svc *services.Services, repos *repository.Repositor
 
 	h.Auth = &AuthHandler{handlers: h}
 	h.Public = &PublicHandler{handlers: h}
+	h.PublicAPI = &PublicAPIHandler{handlers: h}
 	h.Dashboard = &DashboardHandler{handlers: h}
 	h.DashboardEvents = &DashboardEventsHandler{handlers: h}
 	h.Onboarding = &OnboardingHandler{handlers: h}
 	h.API = &APIHandler{handlers: h}
 	h.APIV1 = &APIV1Handler{handlers: h}
+	h.Webhooks = &WebhookHandler{handlers: h}
 
 	return h
 }
@@ -248,6 +252,7 @@ Meet When is a self-hosted scheduling platform. Hosts connect their calendars, d
 func templateFuncs() template.FuncMap {
 	return template.FuncMap{
 		"formatDate":     formatDate,
+		"formatDateKey":  formatDateKey,
 		"formatTime":     formatTime,
 		"formatDateTime": formatDateTime,
 		"formatDateInTZ": formatDateInTZ,
@@ -31,6 +31,16 @@ func formatDate(t interface{}) string {
 	return toTime(t).Format("Monday, January 2, 2006")
 }
 
+// formatDateKey formats a YYYY-MM-DD date as e.g. "Wed, Dec 23, 2026".
+// Anything else is returned unchanged.
+func formatDateKey(s string) string {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return s
+	}
+	return d.Format("Mon, Jan 2, 2006")
+}
+
 // formatTime formats a time as a time string
 func formatTime(t interface{}) string {
 	return toTime(t).Format("3:04 PM")
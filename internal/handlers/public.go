@@ -396,19 +396,30 @@ func (h *PublicHandler) CreateBooking(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		log.Printf("[BOOKING] Error creating booking: %v", err)
 		message := "Failed to create booking"
+		var alternatives []models.TimeSlot
 		switch err {
 		case services.ErrSlotNotAvailable:
-			message = "This time slot is no longer available"
+			message = "Sorry, someone just booked that time. Pick another slot below; your details are kept."
+			alternatives, err = h.handlers.services.Availability.NearbySlots(r.Context(), host.ID, template.ID, startTime, duration, input.InviteeTimezone, 6)
+			if err != nil {
+				log.Printf("[BOOKING] Error loading alternative slots: %v", err)
+			}
 		case services.ErrInvalidBookingTime:
 			message = "Invalid booking time"
+		case services.ErrInvalidInvitee:
+			message = "Please enter your name and a valid email address"
 		}
+		pooledHosts, _ := h.handlers.services.Template.GetPooledHosts(r.Context(), template.ID)
 		h.handlers.render(w, "public_template.html", PageData{
 			Title:  template.Name + " | " + host.Name,
 			Host:   host,
 			Tenant: tenant,
 			Flash:  &FlashMessage{Type: "error", Message: message},
 			Data: map[string]interface{}{
-				"Template": template,
+				"Template":     template,
+				"PooledHosts":  pooledHosts,
+				"Alternatives": alternatives,
+				"Form":         r.Form,
 			},
 		})
 		return
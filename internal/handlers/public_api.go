@@ -0,0 +1,377 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/models"
+	"github.com/meet-when/meet-when/internal/services"
+)
+
+// maxPublicSlotDays is the longest date range one slots request may cover
+const maxPublicSlotDays = 31
+
+// maxPublicBookingBytes caps the body of a booking request, which anyone
+// may send. Answers and guests fit in a fraction of it.
+const maxPublicBookingBytes = 64 << 10
+
+// PublicAPIHandler serves the booking pages' availability and booking form
+// as unauthenticated JSON, for booking UIs built outside the app.
+type PublicAPIHandler struct {
+	handlers *Handlers
+}
+
+// publicAPITemplate is what a booking UI needs to know about a template.
+type publicAPITemplate struct {
+	Name             string                      `json:"name"`
+	Description      string                      `json:"description"`
+	Durations        []int                       `json:"durations"`
+	LocationType     models.ConferencingProvider `json:"location_type"`
+	RequiresApproval bool                        `json:"requires_approval"`
+	Seats            int                         `json:"seats,omitempty"`
+	Questions        models.JSONArray            `json:"questions,omitempty"`
+}
+
+type publicAPISlot struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	SeatsLeft int       `json:"seats_left,omitempty"`
+}
+
+type publicAPIDurationSlots struct {
+	Duration int             `json:"duration"`
+	Slots    []publicAPISlot `json:"slots"`
+}
+
+// publicSlotsResponse is the JSON response for GET .../slots.
+type publicSlotsResponse struct {
+	Template  publicAPITemplate        `json:"template"`
+	Timezone  string                   `json:"timezone"`
+	Start     string                   `json:"start"`
+	End       string                   `json:"end"`
+	Durations []publicAPIDurationSlots `json:"durations"`
+}
+
+// publicBookingRequest is the JSON body for POST .../bookings. It carries
+// the booking page form's fields, with answers keyed by question field.
+type publicBookingRequest struct {
+	StartTime        string            `json:"start_time"`
+	Duration         int               `json:"duration"`
+	Name             string            `json:"name"`
+	Email            string            `json:"email"`
+	Timezone         string            `json:"timezone"`
+	Phone            string            `json:"phone"`
+	AdditionalGuests []string          `json:"additional_guests"`
+	Agenda           string            `json:"agenda"`
+	Answers          map[string]string `json:"answers"`
+}
+
+// publicAPIBooking is the JSON representation of a booking made through the
+// public API. The status URL is the page the invitee cancels or reschedules
+// from.
+type publicAPIBooking struct {
+	ID        string               `json:"id"`
+	Token     string               `json:"token"`
+	Status    models.BookingStatus `json:"status"`
+	StartTime time.Time            `json:"start_time"`
+	EndTime   time.Time            `json:"end_time"`
+	Duration  int                  `json:"duration"`
+	StatusURL string               `json:"status_url"`
+}
+
+func toPublicAPITemplate(t *models.MeetingTemplate) publicAPITemplate {
+	return publicAPITemplate{
+		Name:             t.Name,
+		Description:      t.Description,
+		Durations:        t.Durations,
+		LocationType:     t.LocationType,
+		RequiresApproval: t.RequiresApproval,
+		Seats:            t.Seats,
+		Questions:        t.InviteeQuestions,
+	}
+}
+
+func toPublicAPISlots(slots []models.TimeSlot) []publicAPISlot {
+	out := make([]publicAPISlot, 0, len(slots))
+	for _, s := range slots {
+		out = append(out, publicAPISlot{Start: s.Start, End: s.End, SeatsLeft: s.SeatsLeft})
+	}
+	return out
+}
+
+// lookupTemplate finds the active template a public API path names. Private
+// templates are included, as they are on their booking pages.
+func (h *PublicAPIHandler) lookupTemplate(r *http.Request) (*models.Tenant, *models.Host, *models.MeetingTemplate) {
+	ctx := r.Context()
+	tenant, _ := h.handlers.services.Auth.GetTenantBySlug(ctx, r.PathValue("tenant"))
+	if tenant == nil {
+		return nil, nil, nil
+	}
+	host, _ := h.handlers.services.Auth.GetHostBySlug(ctx, tenant.ID, r.PathValue("host"))
+	if host == nil {
+		return nil, nil, nil
+	}
+	template, _ := h.handlers.services.Template.GetTemplateBySlug(ctx, host.ID, r.PathValue("template"))
+	if template == nil || !template.IsActive {
+		return nil, nil, nil
+	}
+	return tenant, host, template
+}
+
+// parseDurations reads durations given as repeated or comma-separated
+// values. Each must be one of the template's; none means the first.
+func parseDurations(values []string, template *models.MeetingTemplate) ([]int, bool) {
+	var durations []int
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			d, err := strconv.Atoi(part)
+			if err != nil || !slices.Contains(template.Durations, d) {
+				return nil, false
+			}
+			if !slices.Contains(durations, d) {
+				durations = append(durations, d)
+			}
+		}
+	}
+	if len(durations) == 0 {
+		durations = []int{30}
+		if len(template.Durations) > 0 {
+			durations = []int{template.Durations[0]}
+		}
+	}
+	return durations, true
+}
+
+// GetSlots handles GET /api/v1/public/{tenant}/{host}/{template}/slots.
+// start and end are dates (YYYY-MM-DD, end inclusive) in timezone, which
+// defaults to UTC. They default to the coming week and may span at most
+// maxPublicSlotDays days.
+func (h *PublicAPIHandler) GetSlots(w http.ResponseWriter, r *http.Request) {
+	_, host, template := h.lookupTemplate(r)
+	if template == nil {
+		jsonError(w, "meeting type not found", http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	timezone := query.Get("timezone")
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		jsonError(w, "unknown timezone", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now().In(loc)
+	startDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if s := query.Get("start"); s != "" {
+		if startDate, err = time.ParseInLocation("2006-01-02", s, loc); err != nil {
+			jsonError(w, "start must be a date (YYYY-MM-DD)", http.StatusBadRequest)
+			return
+		}
+	}
+	endDate := startDate.AddDate(0, 0, 6)
+	if s := query.Get("end"); s != "" {
+		if endDate, err = time.ParseInLocation("2006-01-02", s, loc); err != nil {
+			jsonError(w, "end must be a date (YYYY-MM-DD)", http.StatusBadRequest)
+			return
+		}
+	}
+	if endDate.Before(startDate) || endDate.After(startDate.AddDate(0, 0, maxPublicSlotDays-1)) {
+		jsonError(w, "end must be on or after start and at most "+strconv.Itoa(maxPublicSlotDays)+" days later", http.StatusBadRequest)
+		return
+	}
+	rangeEnd := endDate.AddDate(0, 0, 1)
+
+	durations, ok := parseDurations(query["duration"], template)
+	if !ok {
+		jsonError(w, "duration is not offered for this meeting type", http.StatusBadRequest)
+		return
+	}
+
+	resp := publicSlotsResponse{
+		Template: toPublicAPITemplate(template),
+		Timezone: timezone,
+		Start:    startDate.Format("2006-01-02"),
+		End:      endDate.Format("2006-01-02"),
+	}
+	for _, duration := range durations {
+		slots, err := h.handlers.services.Availability.GetAvailableSlots(r.Context(), services.GetAvailableSlotsInput{
+			HostID:     host.ID,
+			TemplateID: template.ID,
+			StartDate:  startDate,
+			EndDate:    rangeEnd,
+			Duration:   duration,
+			Timezone:   timezone,
+		})
+		if err != nil {
+			log.Printf("[PUBLIC-API] Error loading slots for template %s: %v", template.ID, err)
+			jsonError(w, "failed to load availability", http.StatusInternalServerError)
+			return
+		}
+		// Slot generation works in whole UTC days, so trim to the range
+		var inRange []models.TimeSlot
+		for _, s := range slots {
+			if !s.Start.Before(startDate) && s.Start.Before(rangeEnd) {
+				inRange = append(inRange, s)
+			}
+		}
+		resp.Durations = append(resp.Durations, publicAPIDurationSlots{Duration: duration, Slots: toPublicAPISlots(inRange)})
+	}
+
+	jsonOK(w, resp)
+}
+
+// CreateBooking handles POST /api/v1/public/{tenant}/{host}/{template}/bookings.
+// A taken slot answers 409 with the nearest open slots as alternatives.
+func (h *PublicAPIHandler) CreateBooking(w http.ResponseWriter, r *http.Request) {
+	tenant, host, template := h.lookupTemplate(r)
+	if template == nil {
+		jsonError(w, "meeting type not found", http.StatusNotFound)
+		return
+	}
+
+	var req publicBookingRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPublicBookingBytes)).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			jsonError(w, "request body too large", http.StatusRequestEntityTooLarge)
+		} else {
+			jsonError(w, "invalid request body", http.StatusBadRequest)
+		}
+		return
+	}
+
+	startTime, err := time.Parse(time.RFC3339, req.StartTime)
+	if err != nil {
+		jsonError(w, "start_time must be an RFC 3339 time", http.StatusBadRequest)
+		return
+	}
+	var durationValues []string
+	if req.Duration != 0 {
+		durationValues = []string{strconv.Itoa(req.Duration)}
+	}
+	durations, ok := parseDurations(durationValues, template)
+	if !ok {
+		jsonError(w, "duration is not offered for this meeting type", http.StatusBadRequest)
+		return
+	}
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		jsonError(w, "unknown timezone", http.StatusBadRequest)
+		return
+	}
+
+	answers, missing := publicBookingAnswers(template, req)
+	if missing != "" {
+		jsonError(w, "an answer to "+missing+" is required", http.StatusBadRequest)
+		return
+	}
+
+	var guests []string
+	for _, g := range req.AdditionalGuests {
+		guests = append(guests, splitEmails(g)...)
+	}
+
+	input := services.CreateBookingInput{
+		TemplateID:       template.ID,
+		HostID:           host.ID,
+		TenantID:         tenant.ID,
+		StartTime:        startTime,
+		Duration:         durations[0],
+		InviteeName:      req.Name,
+		InviteeEmail:     req.Email,
+		InviteeTimezone:  req.Timezone,
+		InviteePhone:     req.Phone,
+		AdditionalGuests: guests,
+		Answers:          answers,
+	}
+
+	log.Printf("[PUBLIC-API] Creating booking: template=%s invitee=%s time=%s", input.TemplateID, input.InviteeEmail, input.StartTime)
+
+	booking, err := h.handlers.services.Booking.CreateBooking(r.Context(), input)
+	if err != nil {
+		h.bookingError(r.Context(), w, err, input)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{"booking": publicAPIBooking{
+		ID:        booking.Booking.ID,
+		Token:     booking.Booking.Token,
+		Status:    booking.Booking.Status,
+		StartTime: booking.Booking.StartTime.Time,
+		EndTime:   booking.Booking.EndTime.Time,
+		Duration:  booking.Booking.Duration,
+		StatusURL: h.handlers.cfg.Server.BaseURL + "/booking/" + booking.Booking.Token,
+	}})
+}
+
+// bookingError answers a failed CreateBooking the way the booking page
+// would explain it.
+func (h *PublicAPIHandler) bookingError(ctx context.Context, w http.ResponseWriter, err error, input services.CreateBookingInput) {
+	switch {
+	case errors.Is(err, services.ErrSlotNotAvailable):
+		alternatives, altErr := h.handlers.services.Availability.NearbySlots(ctx, input.HostID, input.TemplateID, input.StartTime, input.Duration, input.InviteeTimezone, 6)
+		if altErr != nil {
+			log.Printf("[PUBLIC-API] Error loading alternative slots: %v", altErr)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":        "time slot is no longer available",
+			"alternatives": toPublicAPISlots(alternatives),
+		})
+	case errors.Is(err, services.ErrInvalidBookingTime):
+		jsonError(w, "invalid booking time", http.StatusBadRequest)
+	case errors.Is(err, services.ErrInvalidInvitee):
+		jsonError(w, "name and a valid email are required", http.StatusBadRequest)
+	case errors.Is(err, services.ErrTemplateNotFound):
+		jsonError(w, "meeting type not found", http.StatusNotFound)
+	default:
+		log.Printf("[PUBLIC-API] Error creating booking: %v", err)
+		jsonError(w, "failed to create booking", http.StatusInternalServerError)
+	}
+}
+
+// publicBookingAnswers keeps the answers to the template's questions, and
+// the agenda, as the booking form does. It names the first required
+// question left unanswered.
+func publicBookingAnswers(template *models.MeetingTemplate, req publicBookingRequest) (models.JSONMap, string) {
+	answers := make(models.JSONMap)
+	for _, q := range template.InviteeQuestions {
+		qMap, ok := q.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		field, ok := qMap["field"].(string)
+		if !ok {
+			continue
+		}
+		answer := strings.TrimSpace(req.Answers[field])
+		if required, _ := qMap["required"].(bool); required && answer == "" {
+			return nil, field
+		}
+		answers[field] = answer
+	}
+	if agenda := strings.TrimSpace(req.Agenda); agenda != "" {
+		answers["agenda"] = agenda
+	}
+	return answers, ""
+}
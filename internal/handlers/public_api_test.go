@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// publicAPIFixture is a tenant, host and 30-minute template bookable
+// through the public API, the host working from 09:00 to closing UTC.
+type publicAPIFixture struct {
+	h        *Handlers
+	api      *PublicAPIHandler
+	tenant   *models.Tenant
+	host     *models.Host
+	template *models.MeetingTemplate
+}
+
+func setupPublicAPI(t *testing.T, closing string) (*publicAPIFixture, func()) {
+	t.Helper()
+	_, repos, cleanup := setupTestDatabase(t)
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	tenant := &models.Tenant{ID: uuid.New().String(), Slug: "acme-" + suffix, Name: "Acme", CreatedAt: models.Now(), UpdatedAt: models.Now()}
+	if err := repos.Tenant.Create(ctx, tenant); err != nil {
+		t.Fatalf("create tenant: %v", err)
+	}
+	host := &models.Host{
+		ID: uuid.New().String(), TenantID: tenant.ID, Email: "host-" + suffix + "@example.com", PasswordHash: "hash",
+		Name: "Jane", Slug: "jane-" + suffix, Timezone: "UTC", CreatedAt: models.Now(), UpdatedAt: models.Now(),
+	}
+	if err := repos.Host.Create(ctx, host); err != nil {
+		t.Fatalf("create host: %v", err)
+	}
+	template := &models.MeetingTemplate{
+		ID: uuid.New().String(), HostID: host.ID, Slug: "intro", Name: "Intro",
+		Durations: models.IntSlice{30}, LocationType: models.ConferencingProviderGoogleMeet,
+		MaxScheduleDays: 30, IsActive: true, CreatedAt: models.Now(), UpdatedAt: models.Now(),
+	}
+	if err := repos.Template.Create(ctx, template); err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+	var hours []*models.WorkingHours
+	for day := 0; day < 7; day++ {
+		hours = append(hours, &models.WorkingHours{ID: uuid.New().String(), HostID: host.ID, DayOfWeek: day, StartTime: "09:00", EndTime: closing, IsEnabled: true})
+	}
+	if err := repos.WorkingHours.SetForHost(ctx, host.ID, hours); err != nil {
+		t.Fatalf("set working hours: %v", err)
+	}
+
+	h := createTestHandlers(t, repos)
+	return &publicAPIFixture{h: h, api: &PublicAPIHandler{handlers: h}, tenant: tenant, host: host, template: template}, cleanup
+}
+
+func (f *publicAPIFixture) request(method, path string, body []byte) *http.Request {
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	req.SetPathValue("tenant", f.tenant.Slug)
+	req.SetPathValue("host", f.host.Slug)
+	req.SetPathValue("template", f.template.Slug)
+	return req
+}
+
+func TestPublicAPI_GetSlots(t *testing.T) {
+	f, cleanup := setupPublicAPI(t, "10:00")
+	defer cleanup()
+
+	day := time.Now().UTC().AddDate(0, 0, 2).Format("2006-01-02")
+	w := httptest.NewRecorder()
+	f.api.GetSlots(w, f.request("GET", "/slots?start="+day+"&end="+day+"&duration=30", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetSlots: %d %s", w.Code, w.Body.String())
+	}
+	var resp publicSlotsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Durations) != 1 || resp.Durations[0].Duration != 30 || len(resp.Durations[0].Slots) != 3 {
+		t.Fatalf("durations = %+v, want 30-minute slots at 09:00, 09:15 and 09:30", resp.Durations)
+	}
+	if got := resp.Durations[0].Slots[0].Start.UTC().Format("15:04"); got != "09:00" {
+		t.Errorf("first slot at %s, want 09:00", got)
+	}
+
+	for _, query := range []string{
+		"?duration=45",
+		"?timezone=Mars/Olympus",
+		"?start=" + day + "&end=2000-01-01",
+		"?start=tomorrow",
+	} {
+		w := httptest.NewRecorder()
+		f.api.GetSlots(w, f.request("GET", "/slots"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("GetSlots%s: %d, want 400", query, w.Code)
+		}
+	}
+}
+
+func TestPublicAPI_CreateBooking(t *testing.T) {
+	f, cleanup := setupPublicAPI(t, "17:00")
+	defer cleanup()
+
+	start := time.Now().UTC().AddDate(0, 0, 2).Truncate(24 * time.Hour).Add(10 * time.Hour)
+	book := func(body map[string]interface{}) *httptest.ResponseRecorder {
+		t.Helper()
+		data, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		f.api.CreateBooking(w, f.request("POST", "/bookings", data))
+		return w
+	}
+
+	for name, body := range map[string]map[string]interface{}{
+		"bad time":     {"start_time": "tomorrow", "name": "Ann", "email": "ann@example.com"},
+		"bad duration": {"start_time": start.Format(time.RFC3339), "duration": 45, "name": "Ann", "email": "ann@example.com"},
+		"no name":      {"start_time": start.Format(time.RFC3339), "email": "ann@example.com"},
+		"bad email":    {"start_time": start.Format(time.RFC3339), "name": "Ann", "email": "ann"},
+	} {
+		if w := book(body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: %d, want 400", name, w.Code)
+		}
+	}
+	if w := book(map[string]interface{}{"start_time": start.Format(time.RFC3339), "name": "Ann", "email": "ann@example.com", "agenda": strings.Repeat("a", maxPublicBookingBytes)}); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized body: %d, want 413", w.Code)
+	}
+
+	w := book(map[string]interface{}{"start_time": start.Format(time.RFC3339), "duration": 30, "name": "Ann", "email": "ann@example.com", "timezone": "Europe/Berlin"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("CreateBooking: %d %s", w.Code, w.Body.String())
+	}
+	var created struct {
+		Booking publicAPIBooking `json:"booking"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if created.Booking.Token == "" || !created.Booking.StartTime.Equal(start) {
+		t.Errorf("booking = %+v", created.Booking)
+	}
+
+	w = book(map[string]interface{}{"start_time": start.Format(time.RFC3339), "name": "Bob", "email": "bob@example.com"})
+	if w.Code != http.StatusConflict {
+		t.Fatalf("booking a taken slot: %d, want 409", w.Code)
+	}
+	var conflict struct {
+		Alternatives []publicAPISlot `json:"alternatives"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&conflict); err != nil || len(conflict.Alternatives) == 0 {
+		t.Errorf("conflict alternatives = %v, %v", conflict.Alternatives, err)
+	}
+}
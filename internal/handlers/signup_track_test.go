@@ -223,7 +223,7 @@ func setupTestDatabase(t *testing.T) (*sql.DB, *repository.Repositories, func())
 		t.Fatalf("Failed to run migrations: %v", err)
 	}
 
-	repos := repository.NewRepositories(db, "sqlite")
+	repos := repository.NewRepositories(db, "sqlite", nil)
 
 	cleanup := func() {
 		db.Close()
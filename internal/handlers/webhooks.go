@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/meet-when/meet-when/internal/services"
+)
+
+// WebhookHandler handles inbound notifications from third-party services
+type WebhookHandler struct {
+	handlers *Handlers
+}
+
+// GoogleCalendar receives Google Calendar push notifications. The request
+// body is empty; the channel id, token and resource state travel in headers.
+// Google retries anything but a 2xx, so once the channel checks out we
+// acknowledge straight away and run the sync in the background.
+func (h *WebhookHandler) GoogleCalendar(w http.ResponseWriter, r *http.Request) {
+	ch, err := h.handlers.services.Calendar.VerifyGoogleNotification(r.Context(),
+		r.Header.Get("X-Goog-Channel-ID"),
+		r.Header.Get("X-Goog-Channel-Token"),
+		r.Header.Get("X-Goog-Resource-ID"),
+	)
+	if errors.Is(err, services.ErrWatchChannelNotFound) {
+		http.Error(w, "Unknown channel", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("[WEBHOOK] google calendar: verify failed: %v", err)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+
+	// "sync" is the handshake sent when a channel is created; nothing changed.
+	if r.Header.Get("X-Goog-Resource-State") == "sync" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	go func() {
+		ctx := context.Background()
+		if err := h.handlers.services.Calendar.SyncWatchedCalendar(ctx, ch); err != nil {
+			log.Printf("[WEBHOOK] google calendar: sync of calendar %s failed: %v", ch.CalendarID, err)
+		}
+		// The change may have been the host moving or deleting a booking.
+		h.handlers.services.Reconciler.ReconcileCalendar(ctx, ch.CalendarID)
+	}()
+	w.WriteHeader(http.StatusOK)
+}
+
+// Mailgun receives Mailgun's delivered, permanent failure and complained
+// webhooks. A payload that fails verification gets 406, which tells Mailgun
+// not to retry it.
+func (h *WebhookHandler) Mailgun(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+	err = h.handlers.services.Email.HandleMailgunWebhook(r.Context(), payload)
+	if errors.Is(err, services.ErrInvalidMailgunWebhook) {
+		log.Printf("[WEBHOOK] mailgun: %v", err)
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+	if err != nil {
+		log.Printf("[WEBHOOK] mailgun: %v", err)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
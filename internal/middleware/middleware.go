@@ -88,6 +88,23 @@ func MethodOverride(next http.Handler) http.Handler {
 	})
 }
 
+// AllowAnyOrigin lets pages on any site call the wrapped endpoints from the
+// browser, answering CORS preflight requests itself. Only for endpoints that
+// take no cookies or other credentials.
+func AllowAnyOrigin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+			w.Header().Set("Access-Control-Max-Age", "86400")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // isAPIRequest returns true if the request targets the JSON API (Bearer token auth).
 func isAPIRequest(r *http.Request) bool {
 	return strings.HasPrefix(r.URL.Path, "/api/")
@@ -139,3 +139,26 @@ func TestGetHost(t *testing.T) {
 		t.Error("expected nil from empty context")
 	}
 }
+
+func TestAllowAnyOrigin(t *testing.T) {
+	called := false
+	handler := AllowAnyOrigin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	req := httptest.NewRequest("OPTIONS", "/api/v1/public/acme/jane/intro/bookings", nil)
+	req.Header.Set("Origin", "https://acme.example")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if called || rr.Code != http.StatusNoContent || rr.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("preflight: called %v, status %d, headers %v", called, rr.Code, rr.Header())
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/public/acme/jane/intro/slots", nil)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if !called || rr.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("GET: called %v, headers %v", called, rr.Header())
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimiter counts each client IP's requests in fixed one-minute windows.
+type RateLimiter struct {
+	limit      int
+	trustProxy bool
+	now        func() time.Time
+
+	mu      sync.Mutex
+	windows map[string]*rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// NewRateLimiter allows limit requests a minute from each client IP. With
+// trustProxy the client IP is the first address in X-Forwarded-For.
+func NewRateLimiter(limit int, trustProxy bool) *RateLimiter {
+	return &RateLimiter{
+		limit:      limit,
+		trustProxy: trustProxy,
+		now:        time.Now,
+		windows:    make(map[string]*rateWindow),
+	}
+}
+
+// Allow records a request from ip and reports whether it is within the
+// limit, and if not, how long until the client's window resets.
+func (l *RateLimiter) Allow(ip string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	w, ok := l.windows[ip]
+	if !ok || now.Sub(w.start) >= time.Minute {
+		if len(l.windows) >= 10000 {
+			l.sweep(now)
+		}
+		w = &rateWindow{start: now}
+		l.windows[ip] = w
+	}
+	if w.count >= l.limit {
+		return false, w.start.Add(time.Minute).Sub(now)
+	}
+	w.count++
+	return true, 0
+}
+
+// sweep drops the windows that have run out. Caller holds l.mu.
+func (l *RateLimiter) sweep(now time.Time) {
+	for ip, w := range l.windows {
+		if now.Sub(w.start) >= time.Minute {
+			delete(l.windows, ip)
+		}
+	}
+}
+
+// Middleware rejects requests over the limit with 429 Too Many Requests.
+// A limit of zero or less turns limiting off.
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if l.limit <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ok, retry := l.Allow(ClientIP(r, l.trustProxy))
+		if !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(retry.Seconds())+1))
+			if isAPIRequest(r) {
+				http.Error(w, `{"error":"rate limit exceeded"}`, http.StatusTooManyRequests)
+			} else {
+				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+			}
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ClientIP returns the IP the request came from. With trustProxy it is the
+// last address in X-Forwarded-For, when there is one: the one our proxy
+// appended. Anything before it came from the client and can't be trusted.
+func ClientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if fwd := r.Header.Values("X-Forwarded-For"); len(fwd) > 0 {
+			last := fwd[len(fwd)-1]
+			if i := strings.LastIndex(last, ","); i >= 0 {
+				last = last[i+1:]
+			}
+			if ip := strings.TrimSpace(last); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
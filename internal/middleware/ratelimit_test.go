@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(2, false)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Allow("10.0.0.1"); !ok {
+			t.Fatalf("request %d refused", i+1)
+		}
+	}
+	ok, retry := l.Allow("10.0.0.1")
+	if ok || retry != time.Minute {
+		t.Errorf("third request: ok = %v, retry = %v; want refused for a minute", ok, retry)
+	}
+	if ok, _ := l.Allow("10.0.0.2"); !ok {
+		t.Error("another client refused")
+	}
+
+	now = now.Add(time.Minute)
+	if ok, _ := l.Allow("10.0.0.1"); !ok {
+		t.Error("refused after the window reset")
+	}
+}
+
+func TestRateLimiter_Middleware(t *testing.T) {
+	l := NewRateLimiter(1, true)
+	handler := l.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	request := func(forwardedFor string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/v1/public/acme/jane/intro/slots", nil)
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	if rr := request("10.0.0.1, 203.0.113.7"); rr.Code != http.StatusOK {
+		t.Fatalf("first request: %d", rr.Code)
+	}
+	rr := request("203.0.113.7")
+	if rr.Code != http.StatusTooManyRequests || rr.Header().Get("Retry-After") == "" {
+		t.Errorf("second request: %d, Retry-After %q; want 429 with Retry-After", rr.Code, rr.Header().Get("Retry-After"))
+	}
+	// A client can't get a fresh allowance by making up addresses of its own
+	if rr := request("198.51.100.1, 203.0.113.7"); rr.Code != http.StatusTooManyRequests {
+		t.Errorf("spoofed forwarded address: %d; want 429", rr.Code)
+	}
+	if rr := request("203.0.113.8"); rr.Code != http.StatusOK {
+		t.Errorf("other forwarded client: %d", rr.Code)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "172.18.0.3:51234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+
+	if got := ClientIP(req, false); got != "172.18.0.3" {
+		t.Errorf("ClientIP without proxy = %q", got)
+	}
+	if got := ClientIP(req, true); got != "203.0.113.7" {
+		t.Errorf("ClientIP behind proxy = %q", got)
+	}
+
+	req.Header.Set("X-Forwarded-For", "198.51.100.1, 203.0.113.7")
+	if got := ClientIP(req, true); got != "203.0.113.7" {
+		t.Errorf("ClientIP with a client-supplied address = %q; want the one the proxy added", got)
+	}
+	req.Header.Add("X-Forwarded-For", "203.0.113.9")
+	if got := ClientIP(req, true); got != "203.0.113.9" {
+		t.Errorf("ClientIP over two headers = %q; want the last", got)
+	}
+}
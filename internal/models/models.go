@@ -5,6 +5,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 )
 
@@ -66,13 +67,17 @@ func NewSQLiteTime(t time.Time) SQLiteTime {
 	return SQLiteTime{Time: t.UTC()}
 }
 
-// Tenant represents a multi-tenant organization
+// Tenant represents a multi-tenant organization. The logo, accent color and
+// footer brand its emails; empty ones fall back to the Meet When defaults.
 type Tenant struct {
-	ID        string     `json:"id" db:"id"`
-	Slug      string     `json:"slug" db:"slug"`
-	Name      string     `json:"name" db:"name"`
-	CreatedAt SQLiteTime `json:"created_at" db:"created_at"`
-	UpdatedAt SQLiteTime `json:"updated_at" db:"updated_at"`
+	ID          string     `json:"id" db:"id"`
+	Slug        string     `json:"slug" db:"slug"`
+	Name        string     `json:"name" db:"name"`
+	LogoURL     string     `json:"logo_url" db:"logo_url"`
+	AccentColor string     `json:"accent_color" db:"accent_color"` // #rrggbb
+	EmailFooter string     `json:"email_footer" db:"email_footer"`
+	CreatedAt   SQLiteTime `json:"created_at" db:"created_at"`
+	UpdatedAt   SQLiteTime `json:"updated_at" db:"updated_at"`
 }
 
 // Host represents a user who can receive bookings
@@ -90,6 +95,13 @@ type Host struct {
 	GoogleID            *string    `json:"google_id,omitempty" db:"google_id"`
 	GoogleEmail         *string    `json:"google_email,omitempty" db:"google_email"`
 	SmartDurations      bool       `json:"smart_durations" db:"smart_durations"`
+	MaxBookingsPerDay   int        `json:"max_bookings_per_day" db:"max_bookings_per_day"`          // across all templates; 0 = no limit
+	MaxBookingsPerWeek  int        `json:"max_bookings_per_week" db:"max_bookings_per_week"`        // Monday to Sunday in the host's timezone
+	MaxMinutesPerDay    int        `json:"max_minutes_per_day" db:"max_minutes_per_day"`            // booked meeting minutes
+	EventBufferBefore   int        `json:"event_buffer_before" db:"calendar_buffer_before_minutes"` // minutes kept free before every calendar event
+	EventBufferAfter    int        `json:"event_buffer_after" db:"calendar_buffer_after_minutes"`   // minutes kept free after every calendar event
+	MinGapMinutes       int        `json:"min_gap_minutes" db:"min_gap_minutes"`                    // between any two meetings
+	MaxBackToBack       int        `json:"max_back_to_back" db:"max_back_to_back_minutes"`          // minutes of meetings in a row; 0 = no limit
 	CreatedAt           SQLiteTime `json:"created_at" db:"created_at"`
 	UpdatedAt           SQLiteTime `json:"updated_at" db:"updated_at"`
 }
@@ -106,13 +118,83 @@ type WorkingHours struct {
 	UpdatedAt SQLiteTime `json:"updated_at" db:"updated_at"`
 }
 
+// AvailabilityOverride replaces a host's regular hours on the dates from
+// StartDate to EndDate (inclusive, YYYY-MM-DD in the host's timezone). With
+// no intervals the host is off; otherwise only the intervals are bookable.
+// A non-nil TemplateID limits the override to one meeting type.
+type AvailabilityOverride struct {
+	ID         string     `json:"id" db:"id"`
+	HostID     string     `json:"host_id" db:"host_id"`
+	TemplateID *string    `json:"template_id,omitempty" db:"template_id"`
+	ScheduleID *string    `json:"schedule_id,omitempty" db:"schedule_id"`
+	StartDate  string     `json:"start_date" db:"start_date"`
+	EndDate    string     `json:"end_date" db:"end_date"`
+	Intervals  TimeRanges `json:"intervals" db:"intervals"`
+	Note       string     `json:"note" db:"note"`
+	CreatedAt  SQLiteTime `json:"created_at" db:"created_at"`
+	UpdatedAt  SQLiteTime `json:"updated_at" db:"updated_at"`
+}
+
+// IsTimeOff reports whether the override blocks its dates entirely.
+func (o *AvailabilityOverride) IsTimeOff() bool {
+	return len(o.Intervals) == 0
+}
+
+// Covers reports whether date (YYYY-MM-DD) falls within the override.
+func (o *AvailabilityOverride) Covers(date string) bool {
+	return o.StartDate <= date && date <= o.EndDate
+}
+
+// TimeRange is a wall-clock interval within a day, in HH:MM format.
+type TimeRange struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// AvailabilitySchedule is a named set of weekly hours a host can point
+// templates at instead of their working hours, such as "Summer hours". Its
+// hours and date overrides are read in its own timezone.
+type AvailabilitySchedule struct {
+	ID          string      `json:"id" db:"id"`
+	HostID      string      `json:"host_id" db:"host_id"`
+	Name        string      `json:"name" db:"name"`
+	Timezone    string      `json:"timezone" db:"timezone"`
+	WeeklyHours WeeklyHours `json:"weekly_hours" db:"weekly_hours"`
+	CreatedAt   SQLiteTime  `json:"created_at" db:"created_at"`
+	UpdatedAt   SQLiteTime  `json:"updated_at" db:"updated_at"`
+}
+
+// WeeklyInterval is a bookable HH:MM interval on a day of the week
+// (0=Sunday, 6=Saturday).
+type WeeklyInterval struct {
+	Day   int    `json:"day"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// WeeklyHours is a schedule's intervals, sorted by day and start time.
+type WeeklyHours []WeeklyInterval
+
+// Day returns the intervals on day of the week.
+func (h WeeklyHours) Day(day int) []WeeklyInterval {
+	var intervals []WeeklyInterval
+	for _, iv := range h {
+		if iv.Day == day {
+			intervals = append(intervals, iv)
+		}
+	}
+	return intervals
+}
+
 // CalendarProvider represents supported calendar providers
 type CalendarProvider string
 
 const (
-	CalendarProviderGoogle CalendarProvider = "google"
-	CalendarProviderICloud CalendarProvider = "icloud"
-	CalendarProviderCalDAV CalendarProvider = "caldav"
+	CalendarProviderGoogle  CalendarProvider = "google"
+	CalendarProviderICloud  CalendarProvider = "icloud"
+	CalendarProviderCalDAV  CalendarProvider = "caldav"
+	CalendarProviderOutlook CalendarProvider = "outlook"
+	CalendarProviderICSFeed CalendarProvider = "ics_feed" // read-only subscription to a published ICS URL
 )
 
 // CalendarSyncStatus represents the sync status of a calendar
@@ -168,6 +250,11 @@ type ProviderCalendar struct {
 	IsPrimary          bool               `json:"is_primary" db:"is_primary"`
 	IsWritable         bool               `json:"is_writable" db:"is_writable"`
 	PollBusy           bool               `json:"poll_busy" db:"poll_busy"`
+	BusyOnTentative    bool               `json:"busy_on_tentative" db:"busy_on_tentative"`
+	BusyOnFree         bool               `json:"busy_on_free" db:"busy_on_free"`
+	BusyOnDeclined     bool               `json:"busy_on_declined" db:"busy_on_declined"`
+	BufferBefore       int                `json:"buffer_before" db:"buffer_before_minutes"` // minutes kept free before each event
+	BufferAfter        int                `json:"buffer_after" db:"buffer_after_minutes"`   // minutes kept free after each event
 	LastSyncedAt       *SQLiteTime        `json:"last_synced_at" db:"last_synced_at"`
 	SyncStatus         CalendarSyncStatus `json:"sync_status" db:"sync_status"`
 	SyncError          string             `json:"sync_error" db:"sync_error"`
@@ -175,6 +262,43 @@ type ProviderCalendar struct {
 	UpdatedAt          SQLiteTime         `json:"updated_at" db:"updated_at"`
 }
 
+// BusyInterval is one cached event on a provider calendar. Availability is
+// the provider's classification (busy, tentative, free, declined); whether it
+// blocks time is decided by the calendar's busy policy when read.
+type BusyInterval struct {
+	ID           string     `json:"id" db:"id"`
+	CalendarID   string     `json:"calendar_id" db:"calendar_id"` // provider_calendars.id
+	Source       string     `json:"source" db:"source"`           // provider event id or CalDAV href
+	StartTime    SQLiteTime `json:"start_time" db:"start_time"`
+	EndTime      SQLiteTime `json:"end_time" db:"end_time"`
+	IsAllDay     bool       `json:"is_all_day" db:"is_all_day"`
+	Availability string     `json:"availability" db:"availability"`
+	CreatedAt    SQLiteTime `json:"created_at" db:"created_at"`
+}
+
+// BusySyncState records how far a calendar's busy_intervals can be trusted:
+// the window they cover, when they were last confirmed current, and the
+// provider cursor for the next incremental sync.
+type BusySyncState struct {
+	CalendarID  string     `json:"calendar_id" db:"calendar_id"`
+	SyncToken   string     `json:"-" db:"sync_token"`
+	CTag        string     `json:"-" db:"ctag"`
+	WindowStart SQLiteTime `json:"window_start" db:"window_start"`
+	WindowEnd   SQLiteTime `json:"window_end" db:"window_end"`
+	SyncedAt    SQLiteTime `json:"synced_at" db:"synced_at"`
+}
+
+// CalendarWatchChannel is a Google push-notification channel watching one
+// provider calendar's events.
+type CalendarWatchChannel struct {
+	ID         string     `json:"id" db:"id"` // channel id sent to Google
+	CalendarID string     `json:"calendar_id" db:"calendar_id"`
+	ResourceID string     `json:"-" db:"resource_id"` // Google's id for the watched resource, needed to stop the channel
+	Token      string     `json:"-" db:"token"`
+	ExpiresAt  SQLiteTime `json:"expires_at" db:"expires_at"`
+	CreatedAt  SQLiteTime `json:"created_at" db:"created_at"`
+}
+
 // ConferencingProvider represents supported conferencing providers
 type ConferencingProvider string
 
@@ -206,32 +330,141 @@ func (c *ConferencingConnection) NeedsReauth() bool {
 
 // MeetingTemplate represents a bookable meeting type
 type MeetingTemplate struct {
-	ID                string               `json:"id" db:"id"`
-	HostID            string               `json:"host_id" db:"host_id"`
-	Slug              string               `json:"slug" db:"slug"`
-	Name              string               `json:"name" db:"name"`
-	Description       string               `json:"description" db:"description"`
-	Durations         IntSlice             `json:"durations" db:"durations"` // Minutes, e.g., [30, 60]
-	LocationType      ConferencingProvider `json:"location_type" db:"location_type"`
-	CustomLocation    string               `json:"custom_location" db:"custom_location"`
-	CalendarID        string               `json:"calendar_id" db:"calendar_id"` // Which calendar to write to
-	RequiresApproval  bool                 `json:"requires_approval" db:"requires_approval"`
-	MinNoticeMinutes  int                  `json:"min_notice_minutes" db:"min_notice_minutes"`
-	MaxScheduleDays   int                  `json:"max_schedule_days" db:"max_schedule_days"`
-	PreBufferMinutes  int                  `json:"pre_buffer_minutes" db:"pre_buffer_minutes"`
-	PostBufferMinutes int                  `json:"post_buffer_minutes" db:"post_buffer_minutes"`
-	AvailabilityRules JSONMap              `json:"availability_rules" db:"availability_rules"`
-	InviteeQuestions  JSONArray            `json:"invitee_questions" db:"invitee_questions"`
-	ConfirmationEmail string               `json:"confirmation_email" db:"confirmation_email"`
-	ReminderEmail     string               `json:"reminder_email" db:"reminder_email"`
-	IsActive          bool                 `json:"is_active" db:"is_active"`
-	IsPrivate         bool                 `json:"is_private" db:"is_private"` // Hidden from public listing, still bookable via direct link
-	CreatedAt         SQLiteTime           `json:"created_at" db:"created_at"`
-	UpdatedAt         SQLiteTime           `json:"updated_at" db:"updated_at"`
+	ID                 string               `json:"id" db:"id"`
+	HostID             string               `json:"host_id" db:"host_id"`
+	Slug               string               `json:"slug" db:"slug"`
+	Name               string               `json:"name" db:"name"`
+	Description        string               `json:"description" db:"description"`
+	Durations          IntSlice             `json:"durations" db:"durations"` // Minutes, e.g., [30, 60]
+	LocationType       ConferencingProvider `json:"location_type" db:"location_type"`
+	CustomLocation     string               `json:"custom_location" db:"custom_location"`
+	CalendarID         string               `json:"calendar_id" db:"calendar_id"` // Which calendar to write to
+	RequiresApproval   bool                 `json:"requires_approval" db:"requires_approval"`
+	MinNoticeMinutes   int                  `json:"min_notice_minutes" db:"min_notice_minutes"`
+	MaxScheduleDays    int                  `json:"max_schedule_days" db:"max_schedule_days"`
+	PreBufferMinutes   int                  `json:"pre_buffer_minutes" db:"pre_buffer_minutes"`
+	PostBufferMinutes  int                  `json:"post_buffer_minutes" db:"post_buffer_minutes"`
+	AvailabilityRules  JSONMap              `json:"availability_rules" db:"availability_rules"`
+	ScheduleID         string               `json:"schedule_id" db:"schedule_id"` // Named schedule to take hours from, empty = working hours
+	InviteeQuestions   JSONArray            `json:"invitee_questions" db:"invitee_questions"`
+	ConfirmationEmail  string               `json:"confirmation_email" db:"confirmation_email"`
+	ReminderEmail      string               `json:"reminder_email" db:"reminder_email"`
+	IsActive           bool                 `json:"is_active" db:"is_active"`
+	IsPrivate          bool                 `json:"is_private" db:"is_private"`                       // Hidden from public listing, still bookable via direct link
+	MaxBookingsPerDay  int                  `json:"max_bookings_per_day" db:"max_bookings_per_day"`   // 0 = no limit
+	MaxBookingsPerWeek int                  `json:"max_bookings_per_week" db:"max_bookings_per_week"` // Monday to Sunday in the host's timezone
+	SchedulingType     SchedulingType       `json:"scheduling_type" db:"scheduling_type"`             // How pooled hosts are booked
+	RoundRobinStrategy RoundRobinStrategy   `json:"round_robin_strategy" db:"round_robin_strategy"`   // Used when SchedulingType is round_robin
+	SlotInterval       int                  `json:"slot_interval" db:"slot_interval"`                 // Minutes between start times, 0 = every 15
+	AlignSlots         bool                 `json:"align_slots" db:"align_slots"`                     // Start on multiples of SlotInterval from midnight
+	StartMinutes       IntSlice             `json:"start_minutes" db:"start_minutes"`                 // Minutes past the hour starts are limited to, empty = any
+	Seats              int                  `json:"seats" db:"seats"`                                 // Invitees per slot, 0 = one
+	Reminders          Reminders            `json:"reminders" db:"reminders"`                         // Sent before each confirmed booking
+	CreatedAt          SQLiteTime           `json:"created_at" db:"created_at"`
+	UpdatedAt          SQLiteTime           `json:"updated_at" db:"updated_at"`
 	// Populated by service layer, not persisted
 	PooledHosts []*TemplateHost `json:"pooled_hosts,omitempty" db:"-"`
 }
 
+// HasSeats reports whether several invitees can book each of the template's
+// slots
+func (t *MeetingTemplate) HasSeats() bool {
+	return t.Seats > 1
+}
+
+// ReminderChannel is how a reminder reaches its recipients
+type ReminderChannel string
+
+const (
+	ReminderChannelEmail ReminderChannel = "email"
+	ReminderChannelSMS   ReminderChannel = "sms" // handed to the reminder service's SMS sender
+)
+
+// ReminderRecipient is who a reminder goes to
+type ReminderRecipient string
+
+const (
+	ReminderRecipientInvitee ReminderRecipient = "invitee"
+	ReminderRecipientHost    ReminderRecipient = "host"
+	ReminderRecipientBoth    ReminderRecipient = "both"
+)
+
+// Reminder is one of a template's reminders, sent OffsetMinutes before each
+// of its bookings starts
+type Reminder struct {
+	OffsetMinutes int               `json:"offset_minutes"`
+	Channel       ReminderChannel   `json:"channel"`
+	Recipient     ReminderRecipient `json:"recipient"`
+}
+
+// Recipients returns who the reminder goes to, one at a time
+func (r Reminder) Recipients() []ReminderRecipient {
+	if r.Recipient == ReminderRecipientBoth {
+		return []ReminderRecipient{ReminderRecipientInvitee, ReminderRecipientHost}
+	}
+	return []ReminderRecipient{r.Recipient}
+}
+
+// Key names the reminder to one of its recipients in the sent-reminder
+// ledger, e.g. "invitee:email:1440"
+func (r Reminder) Key(to ReminderRecipient) string {
+	return fmt.Sprintf("%s:%s:%d", to, r.Channel, r.OffsetMinutes)
+}
+
+// DefaultReminders is what a template reminds with until it sets its own:
+// an email to the invitee a day before
+var DefaultReminders = Reminders{
+	{OffsetMinutes: 24 * 60, Channel: ReminderChannelEmail, Recipient: ReminderRecipientInvitee},
+}
+
+// Reminders is a template's list of reminders, stored as JSONB
+type Reminders []Reminder
+
+func (r Reminders) Value() (driver.Value, error) {
+	if r == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(r)
+}
+
+func (r *Reminders) Scan(value interface{}) error {
+	if value == nil {
+		*r = nil
+		return nil
+	}
+	return scanJSON(value, r)
+}
+
+// DefaultSlotInterval is the minutes between start times of a template that
+// doesn't set its own
+const DefaultSlotInterval = 15
+
+// EffectiveSlotInterval returns the minutes between the template's start times
+func (t *MeetingTemplate) EffectiveSlotInterval() int {
+	if t.SlotInterval > 0 {
+		return t.SlotInterval
+	}
+	return DefaultSlotInterval
+}
+
+// SchedulingType is how a template with pooled hosts books them
+type SchedulingType string
+
+const (
+	SchedulingTypeCollective SchedulingType = "collective"  // every required host attends
+	SchedulingTypeRoundRobin SchedulingType = "round_robin" // one host is assigned per booking
+)
+
+// RoundRobinStrategy picks the host a round-robin booking is assigned to
+// from those free at the booked time
+type RoundRobinStrategy string
+
+const (
+	RoundRobinLeastRecentlyBooked  RoundRobinStrategy = "least_recently_booked"
+	RoundRobinWeighted             RoundRobinStrategy = "weighted"              // by TemplateHost.Weight
+	RoundRobinMaximizeAvailability RoundRobinStrategy = "maximize_availability" // fewest booked minutes that day
+)
+
 // BookingStatus represents the status of a booking
 type BookingStatus string
 
@@ -262,12 +495,22 @@ type Booking struct {
 	CalendarEventID  string        `json:"calendar_event_id" db:"calendar_event_id"`
 	CancelledBy      string        `json:"cancelled_by" db:"cancelled_by"` // host or invitee
 	CancelReason     string        `json:"cancel_reason" db:"cancel_reason"`
-	ReminderSent     bool          `json:"reminder_sent" db:"reminder_sent"`
 	IsArchived       bool          `json:"is_archived" db:"is_archived"`
+	ReviewReason     string        `json:"review_reason" db:"review_reason"` // non-empty when the host's calendar drifted from the booking
 	CreatedAt        SQLiteTime    `json:"created_at" db:"created_at"`
 	UpdatedAt        SQLiteTime    `json:"updated_at" db:"updated_at"`
 }
 
+// BookingReminder records one reminder sent to one recipient of a booking,
+// for the start time it was sent for
+type BookingReminder struct {
+	ID          string     `json:"id" db:"id"`
+	BookingID   string     `json:"booking_id" db:"booking_id"`
+	ReminderKey string     `json:"reminder_key" db:"reminder_key"` // see Reminder.Key
+	StartTime   SQLiteTime `json:"start_time" db:"start_time"`
+	SentAt      SQLiteTime `json:"sent_at" db:"sent_at"`
+}
+
 // Session represents a user session
 type Session struct {
 	ID        string     `json:"id" db:"id"`
@@ -340,8 +583,9 @@ func (sc *SignupConversion) ConversionTime() *time.Duration {
 
 // TimeSlot represents an available time slot
 type TimeSlot struct {
-	Start time.Time `json:"start"`
-	End   time.Time `json:"end"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	SeatsLeft int       `json:"seats_left,omitempty"` // Set for templates with seats
 }
 
 // TemplateHostRole represents the role of a host in a pooled template
@@ -360,6 +604,7 @@ type TemplateHost struct {
 	Role         TemplateHostRole `json:"role" db:"role"`
 	IsOptional   bool             `json:"is_optional" db:"is_optional"`
 	DisplayOrder int              `json:"display_order" db:"display_order"`
+	Weight       int              `json:"weight" db:"weight"` // Share of round-robin bookings under the weighted strategy
 	CreatedAt    SQLiteTime       `json:"created_at" db:"created_at"`
 	UpdatedAt    SQLiteTime       `json:"updated_at" db:"updated_at"`
 	// Populated by joins, not persisted
@@ -430,8 +675,143 @@ type HostedEventCalendarEvent struct {
 	CreatedAt     SQLiteTime `json:"created_at" db:"created_at"`
 }
 
+// EmailMessageStatus is what the provider last reported for an email
+type EmailMessageStatus string
+
+const (
+	EmailMessageStatusSent       EmailMessageStatus = "sent"
+	EmailMessageStatusDelivered  EmailMessageStatus = "delivered"
+	EmailMessageStatusBounced    EmailMessageStatus = "bounced"
+	EmailMessageStatusComplained EmailMessageStatus = "complained"
+)
+
+// EmailMessage is an outgoing email handed to the provider
+type EmailMessage struct {
+	ID                string             `json:"id" db:"id"`
+	Provider          string             `json:"provider" db:"provider"`
+	ProviderMessageID string             `json:"provider_message_id" db:"provider_message_id"`
+	Recipient         string             `json:"recipient" db:"recipient"`
+	Subject           string             `json:"subject" db:"subject"`
+	Status            EmailMessageStatus `json:"status" db:"status"`
+	StatusDetail      string             `json:"status_detail" db:"status_detail"`
+	CreatedAt         SQLiteTime         `json:"created_at" db:"created_at"`
+	UpdatedAt         SQLiteTime         `json:"updated_at" db:"updated_at"`
+}
+
+// OutboxEmailStatus is where an email in the outbox stands. Sent emails
+// leave the outbox, so there is no sent status.
+type OutboxEmailStatus string
+
+const (
+	OutboxEmailStatusPending OutboxEmailStatus = "pending"
+	OutboxEmailStatusDead    OutboxEmailStatus = "dead"
+)
+
+// OutboxEmail is an email waiting to be sent, or one that failed every
+// attempt. TenantID and HostID are who it was sent for, when known.
+type OutboxEmail struct {
+	ID            string            `json:"id" db:"id"`
+	TenantID      *string           `json:"tenant_id,omitempty" db:"tenant_id"`
+	HostID        *string           `json:"host_id,omitempty" db:"host_id"`
+	Recipient     string            `json:"recipient" db:"recipient"`
+	Subject       string            `json:"subject" db:"subject"`
+	TextBody      string            `json:"text_body" db:"text_body"`
+	HTMLBody      string            `json:"html_body" db:"html_body"`
+	ICS           string            `json:"ics" db:"ics"`
+	Status        OutboxEmailStatus `json:"status" db:"status"`
+	Attempts      int               `json:"attempts" db:"attempts"`
+	NextAttemptAt SQLiteTime        `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError     string            `json:"last_error" db:"last_error"`
+	CreatedAt     SQLiteTime        `json:"created_at" db:"created_at"`
+	UpdatedAt     SQLiteTime        `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookEventType names something that happened to a booking or hosted
+// event that webhooks can subscribe to
+type WebhookEventType string
+
+const (
+	WebhookEventBookingCreated         WebhookEventType = "booking.created"
+	WebhookEventBookingApproved        WebhookEventType = "booking.approved"
+	WebhookEventBookingRejected        WebhookEventType = "booking.rejected"
+	WebhookEventBookingRescheduled     WebhookEventType = "booking.rescheduled"
+	WebhookEventBookingCancelled       WebhookEventType = "booking.cancelled"
+	WebhookEventBookingUpdated         WebhookEventType = "booking.updated"
+	WebhookEventHostedEventCreated     WebhookEventType = "hosted_event.created"
+	WebhookEventHostedEventRescheduled WebhookEventType = "hosted_event.rescheduled"
+	WebhookEventHostedEventCancelled   WebhookEventType = "hosted_event.cancelled"
+	WebhookEventHostedEventUpdated     WebhookEventType = "hosted_event.updated"
+)
+
+// WebhookSubscription is a URL that booking and hosted event events are
+// posted to. HostID limits it to one host's; nil means the whole tenant's.
+type WebhookSubscription struct {
+	ID         string      `json:"id" db:"id"`
+	TenantID   string      `json:"tenant_id" db:"tenant_id"`
+	HostID     *string     `json:"host_id,omitempty" db:"host_id"`
+	URL        string      `json:"url" db:"url"`
+	Secret     string      `json:"-" db:"secret"`
+	EventTypes StringSlice `json:"event_types" db:"event_types"` // Empty = every event
+	IsActive   bool        `json:"is_active" db:"is_active"`
+	CreatedAt  SQLiteTime  `json:"created_at" db:"created_at"`
+	UpdatedAt  SQLiteTime  `json:"updated_at" db:"updated_at"`
+}
+
+// Wants reports whether the subscription receives events of eventType
+func (s *WebhookSubscription) Wants(eventType WebhookEventType) bool {
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range s.EventTypes {
+		if t == string(eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus is where posting an event to a subscription stands
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is one event posted, or waiting to be posted, to one
+// subscription. ResponseStatus is the HTTP status of the last attempt, 0
+// if it got no response.
+type WebhookDelivery struct {
+	ID             string                `json:"id" db:"id"`
+	SubscriptionID string                `json:"subscription_id" db:"subscription_id"`
+	EventID        string                `json:"event_id" db:"event_id"`
+	EventType      WebhookEventType      `json:"event_type" db:"event_type"`
+	Payload        string                `json:"payload" db:"payload"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	NextAttemptAt  SQLiteTime            `json:"next_attempt_at" db:"next_attempt_at"`
+	ResponseStatus int                   `json:"response_status" db:"response_status"`
+	LastError      string                `json:"last_error" db:"last_error"`
+	CreatedAt      SQLiteTime            `json:"created_at" db:"created_at"`
+	UpdatedAt      SQLiteTime            `json:"updated_at" db:"updated_at"`
+}
+
 // Custom JSON types for PostgreSQL arrays and JSONB
 
+// scanJSON decodes a JSON column into dest. Postgres returns JSONB as
+// []byte; SQLite returns TEXT, and column defaults that rows from before
+// the column carry, as a string.
+func scanJSON(value interface{}, dest interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	}
+	return errors.New("unsupported type for JSON column")
+}
+
 // IntSlice is a slice of integers that can be stored as JSONB
 type IntSlice []int
 
@@ -444,11 +824,7 @@ func (s *IntSlice) Scan(value interface{}) error {
 		*s = nil
 		return nil
 	}
-	b, ok := value.([]byte)
-	if !ok {
-		return errors.New("type assertion to []byte failed")
-	}
-	return json.Unmarshal(b, s)
+	return scanJSON(value, s)
 }
 
 // StringSlice is a slice of strings that can be stored as JSONB
@@ -463,11 +839,40 @@ func (s *StringSlice) Scan(value interface{}) error {
 		*s = nil
 		return nil
 	}
-	b, ok := value.([]byte)
-	if !ok {
-		return errors.New("type assertion to []byte failed")
+	return scanJSON(value, s)
+}
+
+// TimeRanges is a list of time ranges that can be stored as JSONB
+type TimeRanges []TimeRange
+
+func (r TimeRanges) Value() (driver.Value, error) {
+	if r == nil {
+		return []byte("[]"), nil
 	}
-	return json.Unmarshal(b, s)
+	return json.Marshal(r)
+}
+
+func (r *TimeRanges) Scan(value interface{}) error {
+	if value == nil {
+		*r = nil
+		return nil
+	}
+	return scanJSON(value, r)
+}
+
+func (h WeeklyHours) Value() (driver.Value, error) {
+	if h == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(h)
+}
+
+func (h *WeeklyHours) Scan(value interface{}) error {
+	if value == nil {
+		*h = nil
+		return nil
+	}
+	return scanJSON(value, h)
 }
 
 // JSONMap is a map that can be stored as JSONB
@@ -485,11 +890,7 @@ func (m *JSONMap) Scan(value interface{}) error {
 		*m = nil
 		return nil
 	}
-	b, ok := value.([]byte)
-	if !ok {
-		return errors.New("type assertion to []byte failed")
-	}
-	return json.Unmarshal(b, m)
+	return scanJSON(value, m)
 }
 
 // JSONArray is an array that can be stored as JSONB
@@ -507,9 +908,5 @@ func (a *JSONArray) Scan(value interface{}) error {
 		*a = nil
 		return nil
 	}
-	b, ok := value.([]byte)
-	if !ok {
-		return errors.New("type assertion to []byte failed")
-	}
-	return json.Unmarshal(b, a)
+	return scanJSON(value, a)
 }
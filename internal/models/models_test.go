@@ -0,0 +1,37 @@
+package models
+
+import (
+	"database/sql"
+	"testing"
+)
+
+// TestJSONColumns_ScanBytesAndStrings checks every JSON column type reads
+// both Postgres' []byte and SQLite's string.
+func TestJSONColumns_ScanBytesAndStrings(t *testing.T) {
+	for _, value := range []interface{}{[]byte(`[]`), `[]`} {
+		for name, dest := range map[string]sql.Scanner{
+			"IntSlice":    &IntSlice{},
+			"StringSlice": &StringSlice{},
+			"TimeRanges":  &TimeRanges{},
+			"WeeklyHours": &WeeklyHours{},
+			"JSONArray":   &JSONArray{},
+			"Reminders":   &Reminders{},
+		} {
+			if err := dest.Scan(value); err != nil {
+				t.Errorf("%s.Scan(%T): %v", name, value, err)
+			}
+		}
+	}
+
+	for _, value := range []interface{}{[]byte(`{"a":1}`), `{"a":1}`} {
+		var m JSONMap
+		if err := m.Scan(value); err != nil || m["a"] != float64(1) {
+			t.Errorf("JSONMap.Scan(%T) = %v, %v", value, m, err)
+		}
+	}
+
+	var s IntSlice
+	if err := s.Scan(42); err == nil {
+		t.Error("IntSlice.Scan(int) succeeded")
+	}
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// AvailabilityOverrideRepository handles date-specific availability
+// overrides and time off.
+type AvailabilityOverrideRepository struct {
+	db     *sql.DB
+	driver string
+}
+
+const availabilityOverrideSelectColumns = `
+	id, host_id, template_id, schedule_id, start_date, end_date, intervals, note, created_at, updated_at`
+
+func scanAvailabilityOverride(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.AvailabilityOverride, error) {
+	o := &models.AvailabilityOverride{}
+	var templateID, scheduleID sql.NullString
+	err := scanner.Scan(
+		&o.ID, &o.HostID, &templateID, &scheduleID, &o.StartDate, &o.EndDate,
+		&o.Intervals, &o.Note, &o.CreatedAt, &o.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if templateID.Valid {
+		o.TemplateID = &templateID.String
+	}
+	if scheduleID.Valid {
+		o.ScheduleID = &scheduleID.String
+	}
+	return o, nil
+}
+
+func (r *AvailabilityOverrideRepository) Create(ctx context.Context, o *models.AvailabilityOverride) error {
+	query := q(r.driver, `
+		INSERT INTO availability_overrides (id, host_id, template_id, schedule_id, start_date, end_date, intervals, note, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`)
+	_, err := r.db.ExecContext(ctx, query,
+		o.ID, o.HostID, o.TemplateID, o.ScheduleID, o.StartDate, o.EndDate,
+		o.Intervals, o.Note, o.CreatedAt, o.UpdatedAt)
+	return err
+}
+
+// GetByID returns an override if it belongs to hostID.
+func (r *AvailabilityOverrideRepository) GetByID(ctx context.Context, hostID, id string) (*models.AvailabilityOverride, error) {
+	query := q(r.driver, `SELECT `+availabilityOverrideSelectColumns+`
+		FROM availability_overrides WHERE id = $1 AND host_id = $2`)
+	o, err := scanAvailabilityOverride(r.db.QueryRowContext(ctx, query, id, hostID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return o, err
+}
+
+// GetByHostIDAndDateRange returns the host's overrides that cover any date
+// from from to to (YYYY-MM-DD, inclusive), for every template and schedule
+// scope.
+func (r *AvailabilityOverrideRepository) GetByHostIDAndDateRange(ctx context.Context, hostID, from, to string) ([]*models.AvailabilityOverride, error) {
+	query := q(r.driver, `SELECT `+availabilityOverrideSelectColumns+`
+		FROM availability_overrides
+		WHERE host_id = $1 AND end_date >= $2 AND start_date <= $3
+		ORDER BY start_date ASC, created_at ASC`)
+	return r.list(ctx, query, hostID, from, to)
+}
+
+// GetUpcomingByHostID returns the host's overrides that end on or after
+// today (YYYY-MM-DD), earliest first.
+func (r *AvailabilityOverrideRepository) GetUpcomingByHostID(ctx context.Context, hostID, today string) ([]*models.AvailabilityOverride, error) {
+	query := q(r.driver, `SELECT `+availabilityOverrideSelectColumns+`
+		FROM availability_overrides
+		WHERE host_id = $1 AND end_date >= $2
+		ORDER BY start_date ASC, created_at ASC`)
+	return r.list(ctx, query, hostID, today)
+}
+
+// Delete removes an override if it belongs to hostID.
+func (r *AvailabilityOverrideRepository) Delete(ctx context.Context, hostID, id string) error {
+	query := q(r.driver, `DELETE FROM availability_overrides WHERE id = $1 AND host_id = $2`)
+	_, err := r.db.ExecContext(ctx, query, id, hostID)
+	return err
+}
+
+func (r *AvailabilityOverrideRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.AvailabilityOverride, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var overrides []*models.AvailabilityOverride
+	for rows.Next() {
+		o, err := scanAvailabilityOverride(rows)
+		if err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, o)
+	}
+	return overrides, rows.Err()
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// AvailabilityScheduleRepository handles hosts' named availability schedules.
+type AvailabilityScheduleRepository struct {
+	db     *sql.DB
+	driver string
+}
+
+const availabilityScheduleSelectColumns = `
+	id, host_id, name, timezone, weekly_hours, created_at, updated_at`
+
+func scanAvailabilitySchedule(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.AvailabilitySchedule, error) {
+	s := &models.AvailabilitySchedule{}
+	err := scanner.Scan(
+		&s.ID, &s.HostID, &s.Name, &s.Timezone, &s.WeeklyHours, &s.CreatedAt, &s.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *AvailabilityScheduleRepository) Create(ctx context.Context, s *models.AvailabilitySchedule) error {
+	return r.insert(ctx, r.db, s)
+}
+
+func (r *AvailabilityScheduleRepository) insert(ctx context.Context, exec interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}, s *models.AvailabilitySchedule) error {
+	query := q(r.driver, `
+		INSERT INTO availability_schedules (id, host_id, name, timezone, weekly_hours, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`)
+	_, err := exec.ExecContext(ctx, query,
+		s.ID, s.HostID, s.Name, s.Timezone, s.WeeklyHours, s.CreatedAt, s.UpdatedAt)
+	return err
+}
+
+// GetByID returns a schedule if it belongs to hostID.
+func (r *AvailabilityScheduleRepository) GetByID(ctx context.Context, hostID, id string) (*models.AvailabilitySchedule, error) {
+	query := q(r.driver, `SELECT `+availabilityScheduleSelectColumns+`
+		FROM availability_schedules WHERE id = $1 AND host_id = $2`)
+	s, err := scanAvailabilitySchedule(r.db.QueryRowContext(ctx, query, id, hostID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return s, err
+}
+
+// GetByHostID returns the host's schedules by name.
+func (r *AvailabilityScheduleRepository) GetByHostID(ctx context.Context, hostID string) ([]*models.AvailabilitySchedule, error) {
+	query := q(r.driver, `SELECT `+availabilityScheduleSelectColumns+`
+		FROM availability_schedules WHERE host_id = $1
+		ORDER BY name ASC, created_at ASC`)
+	rows, err := r.db.QueryContext(ctx, query, hostID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var schedules []*models.AvailabilitySchedule
+	for rows.Next() {
+		s, err := scanAvailabilitySchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, s)
+	}
+	return schedules, rows.Err()
+}
+
+// Update saves a schedule's name, timezone and weekly hours.
+func (r *AvailabilityScheduleRepository) Update(ctx context.Context, s *models.AvailabilitySchedule) error {
+	query := q(r.driver, `
+		UPDATE availability_schedules
+		SET name = $1, timezone = $2, weekly_hours = $3, updated_at = $4
+		WHERE id = $5 AND host_id = $6
+	`)
+	_, err := r.db.ExecContext(ctx, query, s.Name, s.Timezone, s.WeeklyHours, s.UpdatedAt, s.ID, s.HostID)
+	return err
+}
+
+// Delete removes a schedule if it belongs to hostID, along with its date
+// overrides. Templates using it go back to the host's working hours.
+func (r *AvailabilityScheduleRepository) Delete(ctx context.Context, hostID, id string) error {
+	query := q(r.driver, `DELETE FROM availability_schedules WHERE id = $1 AND host_id = $2`)
+	_, err := r.db.ExecContext(ctx, query, id, hostID)
+	return err
+}
+
+// GetTemplateIDsWithRules returns the templates that still carry
+// availability_rules from before schedules existed.
+func (r *AvailabilityScheduleRepository) GetTemplateIDsWithRules(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id FROM meeting_templates WHERE availability_rules IS NOT NULL`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ConvertTemplateRules clears a template's availability_rules and, when
+// schedule is set, creates it and points the template at it, in one
+// transaction so a template is never converted twice.
+func (r *AvailabilityScheduleRepository) ConvertTemplateRules(ctx context.Context, templateID string, schedule *models.AvailabilitySchedule) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("Error rolling back transaction: %v", err)
+		}
+	}()
+
+	if schedule == nil {
+		query := q(r.driver, `UPDATE meeting_templates SET availability_rules = NULL WHERE id = $1`)
+		if _, err := tx.ExecContext(ctx, query, templateID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	if err := r.insert(ctx, tx, schedule); err != nil {
+		return err
+	}
+	query := q(r.driver, `UPDATE meeting_templates SET availability_rules = NULL, schedule_id = $1 WHERE id = $2`)
+	if _, err := tx.ExecContext(ctx, query, schedule.ID, templateID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// BookingReminderRepository is the ledger of reminders sent for bookings.
+type BookingReminderRepository struct {
+	db     *sql.DB
+	driver string
+}
+
+// Claim records a reminder as sent before it goes out. It reports false if
+// the ledger already has it, for the same booking, key and start time, so
+// each reminder is sent once however many workers look at it.
+func (r *BookingReminderRepository) Claim(ctx context.Context, sent *models.BookingReminder) (bool, error) {
+	query := q(r.driver, `
+		INSERT INTO booking_reminders (id, booking_id, reminder_key, start_time, sent_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (booking_id, reminder_key, start_time) DO NOTHING
+	`)
+	result, err := r.db.ExecContext(ctx, query,
+		sent.ID, sent.BookingID, sent.ReminderKey, sent.StartTime, sent.SentAt)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
+
+// Release removes a claimed reminder that couldn't be sent, so it is tried
+// again.
+func (r *BookingReminderRepository) Release(ctx context.Context, id string) error {
+	query := q(r.driver, `DELETE FROM booking_reminders WHERE id = $1`)
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// ListByBooking returns the reminders sent for a booking, oldest first.
+func (r *BookingReminderRepository) ListByBooking(ctx context.Context, bookingID string) ([]*models.BookingReminder, error) {
+	query := q(r.driver, `
+		SELECT id, booking_id, reminder_key, start_time, sent_at
+		FROM booking_reminders WHERE booking_id = $1
+		ORDER BY sent_at, reminder_key
+	`)
+	rows, err := r.db.QueryContext(ctx, query, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var sent []*models.BookingReminder
+	for rows.Next() {
+		s := &models.BookingReminder{}
+		if err := rows.Scan(&s.ID, &s.BookingID, &s.ReminderKey, &s.StartTime, &s.SentAt); err != nil {
+			return nil, err
+		}
+		sent = append(sent, s)
+	}
+	return sent, rows.Err()
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"testing"
 	"time"
 
@@ -33,7 +35,7 @@ func TestBookingRepository_Update_PersistsTimeFields(t *testing.T) {
 			db, cleanup := setupTestDB(t, tt.driver)
 			defer cleanup()
 
-			repos := NewRepositories(db, tt.driver)
+			repos := NewRepositories(db, tt.driver, nil)
 
 			ctx := context.Background()
 			suffix := uuid.New().String()[:8]
@@ -150,3 +152,119 @@ func TestBookingRepository_Update_PersistsTimeFields(t *testing.T) {
 		})
 	}
 }
+
+// TestBookingRepository_CreateIfSlotFree checks the overlap guard, buffers
+// included, that concurrent inserts for one slot let exactly one through,
+// and the booking caps.
+func TestBookingRepository_CreateIfSlotFree(t *testing.T) {
+	for _, driver := range []string{"postgres", "sqlite"} {
+		t.Run(driver, func(t *testing.T) {
+			if driver == "postgres" && !isPostgresAvailable() {
+				t.Skip("PostgreSQL not available")
+			}
+			db, cleanup := setupTestDB(t, driver)
+			defer cleanup()
+			repos := NewRepositories(db, driver, nil)
+			ctx := context.Background()
+
+			host, _ := seedConnection(t, repos, uuid.New().String()[:8])
+			other, _ := seedConnection(t, repos, uuid.New().String()[:8])
+			templateID := uuid.New().String()
+			if _, err := db.Exec(q(driver, `
+				INSERT INTO meeting_templates (id, host_id, slug, name, durations, location_type, is_active, is_private, created_at, updated_at)
+				VALUES ($1, $2, $3, 'Test', '[30]', 'custom', true, false, $4, $5)
+			`), templateID, host.ID, "t-"+templateID[:8], models.Now(), models.Now()); err != nil {
+				t.Fatalf("create template: %v", err)
+			}
+
+			start := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+			booking := func(hostID string, at time.Time) *models.Booking {
+				return &models.Booking{
+					ID: uuid.New().String(), TemplateID: templateID, HostID: hostID,
+					Token: uuid.New().String(), Status: models.BookingStatusConfirmed,
+					StartTime: models.NewSQLiteTime(at), EndTime: models.NewSQLiteTime(at.Add(30 * time.Minute)),
+					Duration: 30, InviteeName: "Invitee", InviteeEmail: "invitee@example.com",
+					CreatedAt: models.Now(), UpdatedAt: models.Now(),
+				}
+			}
+
+			const n = 5
+			errs := make([]error, n)
+			var wg sync.WaitGroup
+			for i := range n {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs[i] = repos.Booking.CreateIfSlotFree(ctx, booking(host.ID, start), SlotCheck{HostIDs: []string{host.ID}})
+				}()
+			}
+			wg.Wait()
+			created := 0
+			for _, err := range errs {
+				switch {
+				case err == nil:
+					created++
+				case !errors.Is(err, ErrBookingConflict):
+					t.Errorf("unexpected error: %v", err)
+				}
+			}
+			if created != 1 {
+				t.Fatalf("%d concurrent inserts succeeded, want 1", created)
+			}
+
+			bookings, err := repos.Booking.GetByHostIDAndTimeRange(ctx, host.ID, start.Add(-time.Hour), start.Add(time.Hour))
+			if err != nil || len(bookings) != 1 {
+				t.Fatalf("GetByHostIDAndTimeRange = %d bookings, %v; want 1", len(bookings), err)
+			}
+
+			// Back-to-back is fine without buffers; a 15-minute buffer on
+			// either side rules it out.
+			next := start.Add(30 * time.Minute)
+			if err := repos.Booking.CreateIfSlotFree(ctx, booking(host.ID, next), SlotCheck{HostIDs: []string{host.ID}, After: 15 * time.Minute}); !errors.Is(err, ErrBookingConflict) {
+				t.Errorf("inside the existing booking's post-buffer: err = %v", err)
+			}
+			prev := start.Add(-30 * time.Minute)
+			if err := repos.Booking.CreateIfSlotFree(ctx, booking(host.ID, prev), SlotCheck{HostIDs: []string{host.ID}, Before: 15 * time.Minute}); !errors.Is(err, ErrBookingConflict) {
+				t.Errorf("inside the existing booking's pre-buffer: err = %v", err)
+			}
+			if err := repos.Booking.CreateIfSlotFree(ctx, booking(host.ID, next), SlotCheck{HostIDs: []string{host.ID}}); err != nil {
+				t.Errorf("back-to-back booking: %v", err)
+			}
+
+			// Every listed host is checked; an unrelated host is not.
+			if err := repos.Booking.CreateIfSlotFree(ctx, booking(other.ID, start), SlotCheck{HostIDs: []string{other.ID, host.ID}}); !errors.Is(err, ErrBookingConflict) {
+				t.Errorf("pooled booking over a busy host: err = %v", err)
+			}
+			if err := repos.Booking.CreateIfSlotFree(ctx, booking(other.ID, start), SlotCheck{HostIDs: []string{other.ID}}); err != nil {
+				t.Errorf("booking for a free host: %v", err)
+			}
+
+			// The host now has two 30-minute bookings starting in the window.
+			day := BookingCap{HostID: host.ID, From: start.Add(-time.Hour), To: start.Add(23 * time.Hour)}
+			later := start.Add(2 * time.Hour)
+			for _, tc := range []struct {
+				name string
+				cap  BookingCap
+				want error
+			}{
+				{"count cap reached", BookingCap{HostID: day.HostID, From: day.From, To: day.To, MaxBookings: 2}, ErrBookingCapReached},
+				{"minutes cap reached", BookingCap{HostID: day.HostID, From: day.From, To: day.To, MaxMinutes: 75}, ErrBookingCapReached},
+				{"other template's cap", BookingCap{HostID: day.HostID, TemplateID: uuid.New().String(), From: day.From, To: day.To, MaxBookings: 1}, nil},
+				{"window without bookings", BookingCap{HostID: day.HostID, From: day.To, To: day.To.Add(24 * time.Hour), MaxBookings: 1}, nil},
+			} {
+				b := booking(host.ID, later)
+				err := repos.Booking.CreateIfSlotFree(ctx, b, SlotCheck{HostIDs: []string{host.ID}, Caps: []BookingCap{tc.cap}})
+				if !errors.Is(err, tc.want) {
+					t.Errorf("%s: err = %v, want %v", tc.name, err, tc.want)
+				}
+				if err == nil {
+					// Free the slot again for the next case
+					b.Status = models.BookingStatusCancelled
+					if err := repos.Booking.Update(ctx, b); err != nil {
+						t.Fatalf("cancel: %v", err)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// BusyIntervalRepository persists the busy-time cache: the events of each
+// polled provider calendar and the sync state that says how current they are.
+type BusyIntervalRepository struct {
+	db     *sql.DB
+	driver string
+}
+
+const busyIntervalSelectColumns = `
+	id, calendar_id, source, start_time, end_time, is_all_day, availability, created_at`
+
+func scanBusyInterval(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.BusyInterval, error) {
+	iv := &models.BusyInterval{}
+	err := scanner.Scan(
+		&iv.ID, &iv.CalendarID, &iv.Source, &iv.StartTime, &iv.EndTime,
+		&iv.IsAllDay, &iv.Availability, &iv.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return iv, nil
+}
+
+// ReplaceAll swaps every cached interval of a calendar for the supplied set
+// in one transaction. Used after a full resync.
+func (r *BusyIntervalRepository) ReplaceAll(ctx context.Context, calendarID string, intervals []*models.BusyInterval) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, q(r.driver, `DELETE FROM busy_intervals WHERE calendar_id = $1`), calendarID); err != nil {
+		return err
+	}
+	if err := r.insert(ctx, tx, calendarID, intervals); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ReplaceSource swaps the intervals that came from one provider event (or
+// CalDAV resource) for the supplied set. An empty set deletes the source.
+func (r *BusyIntervalRepository) ReplaceSource(ctx context.Context, calendarID, source string, intervals []*models.BusyInterval) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query := q(r.driver, `DELETE FROM busy_intervals WHERE calendar_id = $1 AND source = $2`)
+	if _, err := tx.ExecContext(ctx, query, calendarID, source); err != nil {
+		return err
+	}
+	if err := r.insert(ctx, tx, calendarID, intervals); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *BusyIntervalRepository) insert(ctx context.Context, tx *sql.Tx, calendarID string, intervals []*models.BusyInterval) error {
+	query := q(r.driver, `
+		INSERT INTO busy_intervals (id, calendar_id, source, start_time, end_time, is_all_day, availability, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`)
+	for _, iv := range intervals {
+		iv.CalendarID = calendarID
+		if _, err := tx.ExecContext(ctx, query,
+			iv.ID, iv.CalendarID, iv.Source, iv.StartTime, iv.EndTime,
+			iv.IsAllDay, iv.Availability, iv.CreatedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListByCalendar returns the cached intervals of a calendar that overlap
+// [start, end), ordered by start time.
+func (r *BusyIntervalRepository) ListByCalendar(ctx context.Context, calendarID string, start, end time.Time) ([]*models.BusyInterval, error) {
+	query := q(r.driver, `
+		SELECT `+busyIntervalSelectColumns+`
+		FROM busy_intervals
+		WHERE calendar_id = $1 AND end_time > $2 AND start_time < $3
+		ORDER BY start_time ASC
+	`)
+	rows, err := r.db.QueryContext(ctx, query, calendarID, models.NewSQLiteTime(start), models.NewSQLiteTime(end))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*models.BusyInterval
+	for rows.Next() {
+		iv, err := scanBusyInterval(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, iv)
+	}
+	return out, rows.Err()
+}
+
+// GetSyncState returns the cache state of a calendar, or nil if it has never
+// been synced.
+func (r *BusyIntervalRepository) GetSyncState(ctx context.Context, calendarID string) (*models.BusySyncState, error) {
+	query := q(r.driver, `
+		SELECT calendar_id, sync_token, ctag, window_start, window_end, synced_at
+		FROM busy_sync_states
+		WHERE calendar_id = $1
+	`)
+	st := &models.BusySyncState{}
+	err := r.db.QueryRowContext(ctx, query, calendarID).Scan(
+		&st.CalendarID, &st.SyncToken, &st.CTag, &st.WindowStart, &st.WindowEnd, &st.SyncedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// SaveSyncState inserts or replaces the cache state of a calendar.
+func (r *BusyIntervalRepository) SaveSyncState(ctx context.Context, st *models.BusySyncState) error {
+	query := q(r.driver, `
+		INSERT INTO busy_sync_states (calendar_id, sync_token, ctag, window_start, window_end, synced_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (calendar_id) DO UPDATE SET
+			sync_token = excluded.sync_token,
+			ctag = excluded.ctag,
+			window_start = excluded.window_start,
+			window_end = excluded.window_end,
+			synced_at = excluded.synced_at
+	`)
+	_, err := r.db.ExecContext(ctx, query,
+		st.CalendarID, st.SyncToken, st.CTag, st.WindowStart, st.WindowEnd, st.SyncedAt)
+	return err
+}
+
+// TouchSyncState marks a calendar's cache as confirmed current without
+// changing its contents or cursor.
+func (r *BusyIntervalRepository) TouchSyncState(ctx context.Context, calendarID string) error {
+	query := q(r.driver, `UPDATE busy_sync_states SET synced_at = $1 WHERE calendar_id = $2`)
+	_, err := r.db.ExecContext(ctx, query, models.Now(), calendarID)
+	return err
+}
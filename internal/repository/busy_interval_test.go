@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+func busyInterval(source string, start time.Time, hours int) *models.BusyInterval {
+	return &models.BusyInterval{
+		ID:           uuid.New().String(),
+		Source:       source,
+		StartTime:    models.NewSQLiteTime(start),
+		EndTime:      models.NewSQLiteTime(start.Add(time.Duration(hours) * time.Hour)),
+		Availability: "busy",
+		CreatedAt:    models.Now(),
+	}
+}
+
+// TestBusyInterval_ReplaceAndList covers the cache primitives: full replace,
+// per-source replace and delete, overlap queries, and sync-state upserts.
+func TestBusyInterval_ReplaceAndList(t *testing.T) {
+	db, cleanup := setupTestDB(t, "sqlite")
+	defer cleanup()
+	repos := NewRepositories(db, "sqlite", nil)
+	ctx := context.Background()
+
+	_, conn := seedConnection(t, repos, "busy")
+	pc, err := repos.ProviderCalendar.UpsertFromProvider(ctx, conn.ID, "primary", "Primary", "", true, true)
+	if err != nil {
+		t.Fatalf("upsert calendar: %v", err)
+	}
+
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	if err := repos.BusyInterval.ReplaceAll(ctx, pc.ID, []*models.BusyInterval{
+		busyInterval("a", day.Add(9*time.Hour), 1),
+		busyInterval("b", day.Add(13*time.Hour), 2),
+		busyInterval("c", day.AddDate(0, 0, 1).Add(9*time.Hour), 1),
+	}); err != nil {
+		t.Fatalf("ReplaceAll: %v", err)
+	}
+
+	// [10:00, 14:00) overlaps b only; a ends exactly at 10:00.
+	got, err := repos.BusyInterval.ListByCalendar(ctx, pc.ID, day.Add(10*time.Hour), day.Add(14*time.Hour))
+	if err != nil {
+		t.Fatalf("ListByCalendar: %v", err)
+	}
+	if len(got) != 1 || got[0].Source != "b" || !got[0].StartTime.Equal(day.Add(13*time.Hour)) {
+		t.Fatalf("overlap = %+v, want b", got)
+	}
+
+	if err := repos.BusyInterval.ReplaceSource(ctx, pc.ID, "a", []*models.BusyInterval{busyInterval("a", day.Add(11*time.Hour), 1)}); err != nil {
+		t.Fatalf("ReplaceSource: %v", err)
+	}
+	if err := repos.BusyInterval.ReplaceSource(ctx, pc.ID, "b", nil); err != nil {
+		t.Fatalf("ReplaceSource delete: %v", err)
+	}
+	got, _ = repos.BusyInterval.ListByCalendar(ctx, pc.ID, day, day.AddDate(0, 0, 2))
+	if len(got) != 2 || got[0].Source != "a" || got[0].StartTime.Hour() != 11 || got[1].Source != "c" {
+		t.Fatalf("after replace = %+v", got)
+	}
+
+	if st, err := repos.BusyInterval.GetSyncState(ctx, pc.ID); err != nil || st != nil {
+		t.Fatalf("unsynced calendar state = %+v, %v", st, err)
+	}
+	state := &models.BusySyncState{
+		CalendarID:  pc.ID,
+		SyncToken:   "t1",
+		WindowStart: models.NewSQLiteTime(day),
+		WindowEnd:   models.NewSQLiteTime(day.AddDate(0, 3, 0)),
+		SyncedAt:    models.NewSQLiteTime(day),
+	}
+	if err := repos.BusyInterval.SaveSyncState(ctx, state); err != nil {
+		t.Fatalf("SaveSyncState: %v", err)
+	}
+	state.SyncToken = "t2"
+	if err := repos.BusyInterval.SaveSyncState(ctx, state); err != nil {
+		t.Fatalf("SaveSyncState update: %v", err)
+	}
+	if err := repos.BusyInterval.TouchSyncState(ctx, pc.ID); err != nil {
+		t.Fatalf("TouchSyncState: %v", err)
+	}
+	st, err := repos.BusyInterval.GetSyncState(ctx, pc.ID)
+	if err != nil || st == nil {
+		t.Fatalf("GetSyncState: %+v, %v", st, err)
+	}
+	if st.SyncToken != "t2" || !st.WindowEnd.Equal(day.AddDate(0, 3, 0)) || time.Since(st.SyncedAt.Time) > time.Minute {
+		t.Errorf("state = %+v", st)
+	}
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// CalendarWatchChannelRepository stores the Google push-notification channels
+// registered for provider calendars.
+type CalendarWatchChannelRepository struct {
+	db     *sql.DB
+	driver string
+}
+
+const calendarWatchChannelSelectColumns = `id, calendar_id, resource_id, token, expires_at, created_at`
+
+func scanCalendarWatchChannel(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.CalendarWatchChannel, error) {
+	ch := &models.CalendarWatchChannel{}
+	if err := scanner.Scan(&ch.ID, &ch.CalendarID, &ch.ResourceID, &ch.Token, &ch.ExpiresAt, &ch.CreatedAt); err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+func (r *CalendarWatchChannelRepository) Create(ctx context.Context, ch *models.CalendarWatchChannel) error {
+	query := q(r.driver, `
+		INSERT INTO calendar_watch_channels (id, calendar_id, resource_id, token, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`)
+	_, err := r.db.ExecContext(ctx, query,
+		ch.ID, ch.CalendarID, ch.ResourceID, ch.Token, ch.ExpiresAt, ch.CreatedAt)
+	return err
+}
+
+// GetByID returns a channel, or nil if it is unknown.
+func (r *CalendarWatchChannelRepository) GetByID(ctx context.Context, id string) (*models.CalendarWatchChannel, error) {
+	query := q(r.driver, `SELECT `+calendarWatchChannelSelectColumns+` FROM calendar_watch_channels WHERE id = $1`)
+	ch, err := scanCalendarWatchChannel(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return ch, err
+}
+
+// GetByCalendarID returns the channels of a calendar, latest expiry first.
+// There is normally one; a renewal briefly overlaps two.
+func (r *CalendarWatchChannelRepository) GetByCalendarID(ctx context.Context, calendarID string) ([]*models.CalendarWatchChannel, error) {
+	query := q(r.driver, `
+		SELECT `+calendarWatchChannelSelectColumns+`
+		FROM calendar_watch_channels
+		WHERE calendar_id = $1
+		ORDER BY expires_at DESC
+	`)
+	return r.list(ctx, query, calendarID)
+}
+
+// GetByConnectionID returns the channels of every calendar under a
+// connection.
+func (r *CalendarWatchChannelRepository) GetByConnectionID(ctx context.Context, connectionID string) ([]*models.CalendarWatchChannel, error) {
+	query := q(r.driver, `
+		SELECT w.id, w.calendar_id, w.resource_id, w.token, w.expires_at, w.created_at
+		FROM calendar_watch_channels w
+		JOIN provider_calendars pc ON pc.id = w.calendar_id
+		WHERE pc.connection_id = $1
+	`)
+	return r.list(ctx, query, connectionID)
+}
+
+// DeleteExpired removes channels Google has already stopped delivering to.
+func (r *CalendarWatchChannelRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	query := q(r.driver, `DELETE FROM calendar_watch_channels WHERE expires_at <= $1`)
+	_, err := r.db.ExecContext(ctx, query, models.NewSQLiteTime(now))
+	return err
+}
+
+func (r *CalendarWatchChannelRepository) Delete(ctx context.Context, id string) error {
+	query := q(r.driver, `DELETE FROM calendar_watch_channels WHERE id = $1`)
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *CalendarWatchChannelRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.CalendarWatchChannel, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*models.CalendarWatchChannel
+	for rows.Next() {
+		ch, err := scanCalendarWatchChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, ch)
+	}
+	return out, rows.Err()
+}
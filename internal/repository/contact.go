@@ -141,8 +141,8 @@ func (r *ContactRepository) GetBookings(ctx context.Context, tenantID, email str
 		SELECT b.id, b.template_id, b.host_id, b.token, b.status, b.start_time, b.end_time, b.duration,
 		       b.invitee_name, b.invitee_email, COALESCE(b.invitee_timezone, ''), COALESCE(b.invitee_phone, ''),
 		       b.additional_guests, b.answers, COALESCE(b.conference_link, ''), COALESCE(b.calendar_event_id, ''),
-		       COALESCE(b.cancelled_by, ''), COALESCE(b.cancel_reason, ''), COALESCE(b.reminder_sent, false),
-		       COALESCE(b.is_archived, false), b.created_at, b.updated_at,
+		       COALESCE(b.cancelled_by, ''), COALESCE(b.cancel_reason, ''),
+		       COALESCE(b.is_archived, false), COALESCE(b.review_reason, ''), b.created_at, b.updated_at,
 		       COALESCE(t.name, 'Unknown')
 		FROM bookings b
 		JOIN hosts h ON b.host_id = h.id
@@ -171,8 +171,8 @@ func (r *ContactRepository) GetBookings(ctx context.Context, tenantID, email str
 			&booking.InviteeName, &booking.InviteeEmail, &booking.InviteeTimezone,
 			&booking.InviteePhone, &booking.AdditionalGuests, &booking.Answers,
 			&booking.ConferenceLink, &booking.CalendarEventID,
-			&booking.CancelledBy, &booking.CancelReason, &booking.ReminderSent,
-			&booking.IsArchived, &booking.CreatedAt, &booking.UpdatedAt,
+			&booking.CancelledBy, &booking.CancelReason,
+			&booking.IsArchived, &booking.ReviewReason, &booking.CreatedAt, &booking.UpdatedAt,
 			&templateName)
 		if err != nil {
 			return nil, err
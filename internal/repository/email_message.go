@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// EmailMessageRepository stores outgoing emails and the delivery status the
+// provider reports for them.
+type EmailMessageRepository struct {
+	db     *sql.DB
+	driver string
+}
+
+const emailMessageSelectColumns = `id, provider, provider_message_id, recipient, subject, status, status_detail, created_at, updated_at`
+
+func scanEmailMessage(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.EmailMessage, error) {
+	m := &models.EmailMessage{}
+	if err := scanner.Scan(&m.ID, &m.Provider, &m.ProviderMessageID, &m.Recipient, &m.Subject,
+		&m.Status, &m.StatusDetail, &m.CreatedAt, &m.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (r *EmailMessageRepository) Create(ctx context.Context, m *models.EmailMessage) error {
+	query := q(r.driver, `
+		INSERT INTO email_messages (id, provider, provider_message_id, recipient, subject, status, status_detail, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`)
+	_, err := r.db.ExecContext(ctx, query,
+		m.ID, m.Provider, m.ProviderMessageID, m.Recipient, m.Subject, m.Status, m.StatusDetail, m.CreatedAt, m.UpdatedAt)
+	return err
+}
+
+// GetByProviderMessageID returns the email the provider knows by
+// messageID, or nil if there is none.
+func (r *EmailMessageRepository) GetByProviderMessageID(ctx context.Context, provider, messageID string) (*models.EmailMessage, error) {
+	query := q(r.driver, `SELECT `+emailMessageSelectColumns+` FROM email_messages WHERE provider = $1 AND provider_message_id = $2`)
+	m, err := scanEmailMessage(r.db.QueryRowContext(ctx, query, provider, messageID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return m, err
+}
+
+// UpdateStatus records the provider's latest status for an email.
+func (r *EmailMessageRepository) UpdateStatus(ctx context.Context, id string, status models.EmailMessageStatus, detail string) error {
+	query := q(r.driver, `UPDATE email_messages SET status = $1, status_detail = $2, updated_at = $3 WHERE id = $4`)
+	_, err := r.db.ExecContext(ctx, query, status, detail, models.Now(), id)
+	return err
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// EmailOutboxRepository stores emails waiting to be sent and the ones that
+// failed for good.
+type EmailOutboxRepository struct {
+	db     *sql.DB
+	driver string
+}
+
+const emailOutboxSelectColumns = `id, tenant_id, host_id, recipient, subject, text_body, html_body, ics,
+	status, attempts, next_attempt_at, last_error, created_at, updated_at`
+
+func scanOutboxEmail(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.OutboxEmail, error) {
+	e := &models.OutboxEmail{}
+	if err := scanner.Scan(&e.ID, &e.TenantID, &e.HostID, &e.Recipient, &e.Subject, &e.TextBody, &e.HTMLBody, &e.ICS,
+		&e.Status, &e.Attempts, &e.NextAttemptAt, &e.LastError, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (r *EmailOutboxRepository) Create(ctx context.Context, e *models.OutboxEmail) error {
+	query := q(r.driver, `
+		INSERT INTO email_outbox (id, tenant_id, host_id, recipient, subject, text_body, html_body, ics,
+			status, attempts, next_attempt_at, last_error, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`)
+	_, err := r.db.ExecContext(ctx, query,
+		e.ID, e.TenantID, e.HostID, e.Recipient, e.Subject, e.TextBody, e.HTMLBody, e.ICS,
+		e.Status, e.Attempts, e.NextAttemptAt, e.LastError, e.CreatedAt, e.UpdatedAt)
+	return err
+}
+
+// GetByID returns an outbox email, or nil if there is none.
+func (r *EmailOutboxRepository) GetByID(ctx context.Context, id string) (*models.OutboxEmail, error) {
+	query := q(r.driver, `SELECT `+emailOutboxSelectColumns+` FROM email_outbox WHERE id = $1`)
+	e, err := scanOutboxEmail(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return e, err
+}
+
+// GetDue returns up to limit pending emails whose next attempt is at or
+// before now, the longest waiting first.
+func (r *EmailOutboxRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]*models.OutboxEmail, error) {
+	query := q(r.driver, `
+		SELECT `+emailOutboxSelectColumns+` FROM email_outbox
+		WHERE status = $1 AND next_attempt_at <= $2
+		ORDER BY next_attempt_at
+		LIMIT $3
+	`)
+	return r.list(ctx, query, models.OutboxEmailStatusPending, models.NewSQLiteTime(now), limit)
+}
+
+// GetDead returns the emails of a tenant that failed every attempt, the most
+// recent first. With hostID set, only that host's.
+func (r *EmailOutboxRepository) GetDead(ctx context.Context, tenantID, hostID string) ([]*models.OutboxEmail, error) {
+	query := `SELECT ` + emailOutboxSelectColumns + ` FROM email_outbox WHERE status = $1 AND tenant_id = $2`
+	args := []interface{}{models.OutboxEmailStatusDead, tenantID}
+	if hostID != "" {
+		query += ` AND host_id = $3`
+		args = append(args, hostID)
+	}
+	query += ` ORDER BY updated_at DESC`
+	return r.list(ctx, q(r.driver, query), args...)
+}
+
+// CountDead returns how many of a tenant's emails failed every attempt. With
+// hostID set, only that host's.
+func (r *EmailOutboxRepository) CountDead(ctx context.Context, tenantID, hostID string) (int, error) {
+	query := `SELECT COUNT(*) FROM email_outbox WHERE status = $1 AND tenant_id = $2`
+	args := []interface{}{models.OutboxEmailStatusDead, tenantID}
+	if hostID != "" {
+		query += ` AND host_id = $3`
+		args = append(args, hostID)
+	}
+	var count int
+	err := r.db.QueryRowContext(ctx, q(r.driver, query), args...).Scan(&count)
+	return count, err
+}
+
+// Claim takes a due email for one send attempt by pushing its next attempt
+// to leaseUntil, so other workers skip it and it is retried if this one dies
+// mid-send. It reports false if the email is no longer due, e.g. because
+// another worker claimed it first.
+func (r *EmailOutboxRepository) Claim(ctx context.Context, id string, now, leaseUntil time.Time) (bool, error) {
+	query := q(r.driver, `
+		UPDATE email_outbox SET next_attempt_at = $1, updated_at = $2
+		WHERE id = $3 AND status = $4 AND next_attempt_at <= $5
+	`)
+	result, err := r.db.ExecContext(ctx, query,
+		models.NewSQLiteTime(leaseUntil), models.NewSQLiteTime(now), id, models.OutboxEmailStatusPending, models.NewSQLiteTime(now))
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
+
+// Retry records a failed attempt and when to make the next one.
+func (r *EmailOutboxRepository) Retry(ctx context.Context, id string, attempts int, next time.Time, lastError string) error {
+	query := q(r.driver, `
+		UPDATE email_outbox SET attempts = $1, next_attempt_at = $2, last_error = $3, updated_at = $4
+		WHERE id = $5
+	`)
+	_, err := r.db.ExecContext(ctx, query, attempts, models.NewSQLiteTime(next), lastError, models.Now(), id)
+	return err
+}
+
+// MarkDead records the last failed attempt and stops retrying.
+func (r *EmailOutboxRepository) MarkDead(ctx context.Context, id string, attempts int, lastError string) error {
+	query := q(r.driver, `
+		UPDATE email_outbox SET status = $1, attempts = $2, last_error = $3, updated_at = $4
+		WHERE id = $5
+	`)
+	_, err := r.db.ExecContext(ctx, query, models.OutboxEmailStatusDead, attempts, lastError, models.Now(), id)
+	return err
+}
+
+// Requeue puts a dead email back in line to be sent now, with a fresh set of
+// attempts. The last error is kept until the next attempt replaces it.
+func (r *EmailOutboxRepository) Requeue(ctx context.Context, id string) error {
+	now := models.Now()
+	query := q(r.driver, `
+		UPDATE email_outbox SET status = $1, attempts = 0, next_attempt_at = $2, updated_at = $3
+		WHERE id = $4 AND status = $5
+	`)
+	_, err := r.db.ExecContext(ctx, query, models.OutboxEmailStatusPending, now, now, id, models.OutboxEmailStatusDead)
+	return err
+}
+
+// Delete removes an email from the outbox, once it's been sent.
+func (r *EmailOutboxRepository) Delete(ctx context.Context, id string) error {
+	query := q(r.driver, `DELETE FROM email_outbox WHERE id = $1`)
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *EmailOutboxRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.OutboxEmail, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var emails []*models.OutboxEmail
+	for rows.Next() {
+		e, err := scanOutboxEmail(rows)
+		if err != nil {
+			return nil, err
+		}
+		emails = append(emails, e)
+	}
+	return emails, rows.Err()
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// HolidayRepository stores which public holiday set each host follows and
+// the holidays they have un-blocked. The holidays themselves are bundled
+// data, not database rows.
+type HolidayRepository struct {
+	db     *sql.DB
+	driver string
+}
+
+// GetSetCode returns the host's holiday set code, or "" if they follow none.
+func (r *HolidayRepository) GetSetCode(ctx context.Context, hostID string) (string, error) {
+	var code string
+	query := q(r.driver, `SELECT set_code FROM host_holiday_sets WHERE host_id = $1`)
+	err := r.db.QueryRowContext(ctx, query, hostID).Scan(&code)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return code, err
+}
+
+// SetSetCode switches the host to another holiday set, or to none when code
+// is empty. Un-blocked holidays belong to the old set and are dropped.
+func (r *HolidayRepository) SetSetCode(ctx context.Context, hostID, code string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("Error rolling back transaction: %v", err)
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx, q(r.driver, `DELETE FROM host_holiday_exceptions WHERE host_id = $1`), hostID); err != nil {
+		return err
+	}
+	if code == "" {
+		if _, err := tx.ExecContext(ctx, q(r.driver, `DELETE FROM host_holiday_sets WHERE host_id = $1`), hostID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	now := models.Now()
+	query := q(r.driver, `
+		INSERT INTO host_holiday_sets (host_id, set_code, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (host_id) DO UPDATE SET set_code = excluded.set_code, updated_at = excluded.updated_at
+	`)
+	if _, err := tx.ExecContext(ctx, query, hostID, code, now, now); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetUnblockedDates returns the holiday dates (YYYY-MM-DD) from from to to
+// inclusive that the host takes bookings on.
+func (r *HolidayRepository) GetUnblockedDates(ctx context.Context, hostID, from, to string) ([]string, error) {
+	query := q(r.driver, `
+		SELECT holiday_date FROM host_holiday_exceptions
+		WHERE host_id = $1 AND holiday_date >= $2 AND holiday_date <= $3
+		ORDER BY holiday_date ASC
+	`)
+	rows, err := r.db.QueryContext(ctx, query, hostID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var dates []string
+	for rows.Next() {
+		var date string
+		if err := rows.Scan(&date); err != nil {
+			return nil, err
+		}
+		dates = append(dates, date)
+	}
+	return dates, rows.Err()
+}
+
+// Unblock lets the host take bookings on a holiday.
+func (r *HolidayRepository) Unblock(ctx context.Context, hostID, date string) error {
+	query := q(r.driver, `
+		INSERT INTO host_holiday_exceptions (host_id, holiday_date, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (host_id, holiday_date) DO NOTHING
+	`)
+	_, err := r.db.ExecContext(ctx, query, hostID, date, models.Now())
+	return err
+}
+
+// Reblock makes a previously un-blocked holiday block availability again.
+func (r *HolidayRepository) Reblock(ctx context.Context, hostID, date string) error {
+	query := q(r.driver, `DELETE FROM host_holiday_exceptions WHERE host_id = $1 AND holiday_date = $2`)
+	_, err := r.db.ExecContext(ctx, query, hostID, date)
+	return err
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/meet-when/meet-when/internal/models"
 )
@@ -60,3 +61,30 @@ func (r *HostedEventCalendarEventRepository) Update(ctx context.Context, e *mode
 	_, err := r.db.ExecContext(ctx, query, e.EventID, e.CalendarID, e.ID)
 	return err
 }
+
+// ListUpcomingByCalendarID returns the tracking rows on a provider calendar
+// whose hosted event is still scheduled and starts after the given time.
+func (r *HostedEventCalendarEventRepository) ListUpcomingByCalendarID(ctx context.Context, calendarID string, after time.Time) ([]*models.HostedEventCalendarEvent, error) {
+	query := q(r.driver, `
+		SELECT c.id, c.hosted_event_id, c.host_id, c.calendar_id, c.event_id, c.created_at
+		FROM hosted_event_calendar_events c
+		JOIN hosted_events e ON e.id = c.hosted_event_id
+		WHERE c.calendar_id = $1 AND e.status = 'scheduled' AND e.start_time > $2
+		ORDER BY e.start_time ASC
+	`)
+	rows, err := r.db.QueryContext(ctx, query, calendarID, models.NewSQLiteTime(after))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*models.HostedEventCalendarEvent
+	for rows.Next() {
+		e := &models.HostedEventCalendarEvent{}
+		if err := rows.Scan(&e.ID, &e.HostedEventID, &e.HostID, &e.CalendarID, &e.EventID, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
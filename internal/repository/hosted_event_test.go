@@ -26,7 +26,7 @@ func TestHostedEventRepository_RoundTrip(t *testing.T) {
 
 			db, cleanup := setupTestDB(t, tt.driver)
 			defer cleanup()
-			repos := NewRepositories(db, tt.driver)
+			repos := NewRepositories(db, tt.driver, nil)
 			ctx := context.Background()
 
 			tenantID, hostID := seedHostedEventParents(t, repos)
@@ -117,7 +117,7 @@ func TestHostedEventAttendeeRepository_ReplaceForEvent(t *testing.T) {
 			}
 			db, cleanup := setupTestDB(t, tt.driver)
 			defer cleanup()
-			repos := NewRepositories(db, tt.driver)
+			repos := NewRepositories(db, tt.driver, nil)
 			ctx := context.Background()
 
 			tenantID, hostID := seedHostedEventParents(t, repos)
@@ -20,7 +20,9 @@ type ProviderCalendarRepository struct {
 
 const providerCalendarSelectColumns = `
 	id, connection_id, provider_calendar_id, name, color, is_primary, is_writable,
-	poll_busy, last_synced_at, COALESCE(sync_status, 'unknown'), COALESCE(sync_error, ''),
+	poll_busy, busy_on_tentative, busy_on_free, busy_on_declined,
+	buffer_before_minutes, buffer_after_minutes,
+	last_synced_at, COALESCE(sync_status, 'unknown'), COALESCE(sync_error, ''),
 	created_at, updated_at`
 
 func scanProviderCalendar(scanner interface {
@@ -30,6 +32,8 @@ func scanProviderCalendar(scanner interface {
 	err := scanner.Scan(
 		&pc.ID, &pc.ConnectionID, &pc.ProviderCalendarID, &pc.Name, &pc.Color,
 		&pc.IsPrimary, &pc.IsWritable, &pc.PollBusy,
+		&pc.BusyOnTentative, &pc.BusyOnFree, &pc.BusyOnDeclined,
+		&pc.BufferBefore, &pc.BufferAfter,
 		&pc.LastSyncedAt, &pc.SyncStatus, &pc.SyncError,
 		&pc.CreatedAt, &pc.UpdatedAt,
 	)
@@ -44,12 +48,16 @@ func (r *ProviderCalendarRepository) Create(ctx context.Context, pc *models.Prov
 	query := q(r.driver, `
 		INSERT INTO provider_calendars (
 			id, connection_id, provider_calendar_id, name, color, is_primary, is_writable,
-			poll_busy, last_synced_at, sync_status, sync_error, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			poll_busy, busy_on_tentative, busy_on_free, busy_on_declined,
+			buffer_before_minutes, buffer_after_minutes,
+			last_synced_at, sync_status, sync_error, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 	`)
 	_, err := r.db.ExecContext(ctx, query,
 		pc.ID, pc.ConnectionID, pc.ProviderCalendarID, pc.Name, pc.Color,
 		pc.IsPrimary, pc.IsWritable, pc.PollBusy,
+		pc.BusyOnTentative, pc.BusyOnFree, pc.BusyOnDeclined,
+		pc.BufferBefore, pc.BufferAfter,
 		pc.LastSyncedAt, pc.SyncStatus, pc.SyncError,
 		pc.CreatedAt, pc.UpdatedAt,
 	)
@@ -103,6 +111,8 @@ func (r *ProviderCalendarRepository) GetByHostID(ctx context.Context, hostID str
 	query := q(r.driver, `
 		SELECT pc.id, pc.connection_id, pc.provider_calendar_id, pc.name, pc.color,
 		       pc.is_primary, pc.is_writable, pc.poll_busy,
+		       pc.busy_on_tentative, pc.busy_on_free, pc.busy_on_declined,
+		       pc.buffer_before_minutes, pc.buffer_after_minutes,
 		       pc.last_synced_at, COALESCE(pc.sync_status, 'unknown'), COALESCE(pc.sync_error, ''),
 		       pc.created_at, pc.updated_at
 		FROM provider_calendars pc
@@ -137,6 +147,8 @@ func (r *ProviderCalendarRepository) GetPolledByHostID(ctx context.Context, host
 	query := q(r.driver, `
 		SELECT pc.id, pc.connection_id, pc.provider_calendar_id, pc.name, pc.color,
 		       pc.is_primary, pc.is_writable, pc.poll_busy,
+		       pc.busy_on_tentative, pc.busy_on_free, pc.busy_on_declined,
+		       pc.buffer_before_minutes, pc.buffer_after_minutes,
 		       pc.last_synced_at, COALESCE(pc.sync_status, 'unknown'), COALESCE(pc.sync_error, ''),
 		       pc.created_at, pc.updated_at
 		FROM provider_calendars pc
@@ -168,8 +180,8 @@ func (r *ProviderCalendarRepository) GetPolledByHostID(ctx context.Context, host
 }
 
 // UpsertFromProvider inserts or updates a provider_calendars row keyed by
-// (connection_id, provider_calendar_id). Existing user choices for poll_busy
-// and a non-empty user-set color are preserved; the supplied color is only
+// (connection_id, provider_calendar_id). Existing user choices for poll_busy,
+// the busy policy and a non-empty user-set color are preserved; the supplied color is only
 // applied when the row is new or its color was unset.
 //
 // Returns the resulting row.
@@ -226,6 +238,7 @@ func (r *ProviderCalendarRepository) UpsertFromProvider(
 		IsPrimary:          isPrimary,
 		IsWritable:         isWritable,
 		PollBusy:           true,
+		BusyOnTentative:    true,
 		SyncStatus:         models.CalendarSyncStatusUnknown,
 		CreatedAt:          now,
 		UpdatedAt:          now,
@@ -288,6 +301,52 @@ func (r *ProviderCalendarRepository) UpdatePollBusy(ctx context.Context, hostID,
 	return nil
 }
 
+// UpdateBusyPolicy stores which kinds of events on the calendar count as
+// busy. Ownership check is identical to UpdatePollBusy.
+func (r *ProviderCalendarRepository) UpdateBusyPolicy(ctx context.Context, hostID, providerCalendarID string, onTentative, onFree, onDeclined bool) error {
+	query := q(r.driver, `
+		UPDATE provider_calendars
+		SET busy_on_tentative = $1, busy_on_free = $2, busy_on_declined = $3, updated_at = $4
+		WHERE id = $5
+		  AND connection_id IN (SELECT id FROM calendar_connections WHERE host_id = $6)
+	`)
+	res, err := r.db.ExecContext(ctx, query, onTentative, onFree, onDeclined, models.Now(), providerCalendarID, hostID)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("provider calendar %s not found or not owned by host %s", providerCalendarID, hostID)
+	}
+	return nil
+}
+
+// UpdateBuffers stores the minutes kept free before and after each of the
+// calendar's events. Ownership check is identical to UpdatePollBusy.
+func (r *ProviderCalendarRepository) UpdateBuffers(ctx context.Context, hostID, providerCalendarID string, before, after int) error {
+	query := q(r.driver, `
+		UPDATE provider_calendars
+		SET buffer_before_minutes = $1, buffer_after_minutes = $2, updated_at = $3
+		WHERE id = $4
+		  AND connection_id IN (SELECT id FROM calendar_connections WHERE host_id = $5)
+	`)
+	res, err := r.db.ExecContext(ctx, query, before, after, models.Now(), providerCalendarID, hostID)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("provider calendar %s not found or not owned by host %s", providerCalendarID, hostID)
+	}
+	return nil
+}
+
 // UpdateColor sets the user-chosen color for a provider calendar. Ownership
 // check is identical to UpdatePollBusy.
 func (r *ProviderCalendarRepository) UpdateColor(ctx context.Context, hostID, providerCalendarID, color string) error {
@@ -66,7 +66,7 @@ func TestProviderCalendar_BackfillRowExists(t *testing.T) {
 		t.Run(driver, func(t *testing.T) {
 			db, cleanup := setupTestDB(t, driver)
 			defer cleanup()
-			repos := NewRepositories(db, driver)
+			repos := NewRepositories(db, driver, nil)
 
 			_, conn := seedConnection(t, repos, "backfill")
 
@@ -116,7 +116,7 @@ func TestProviderCalendar_UpsertFromProvider(t *testing.T) {
 		t.Run(driver, func(t *testing.T) {
 			db, cleanup := setupTestDB(t, driver)
 			defer cleanup()
-			repos := NewRepositories(db, driver)
+			repos := NewRepositories(db, driver, nil)
 
 			ctx := context.Background()
 			_, conn := seedConnection(t, repos, "upsert")
@@ -178,7 +178,7 @@ func TestProviderCalendar_DeleteMissing(t *testing.T) {
 		t.Run(driver, func(t *testing.T) {
 			db, cleanup := setupTestDB(t, driver)
 			defer cleanup()
-			repos := NewRepositories(db, driver)
+			repos := NewRepositories(db, driver, nil)
 			ctx := context.Background()
 
 			_, conn := seedConnection(t, repos, "delmissing")
@@ -220,7 +220,7 @@ func TestProviderCalendar_GetPolledByHostID(t *testing.T) {
 		t.Run(driver, func(t *testing.T) {
 			db, cleanup := setupTestDB(t, driver)
 			defer cleanup()
-			repos := NewRepositories(db, driver)
+			repos := NewRepositories(db, driver, nil)
 			ctx := context.Background()
 
 			host, conn := seedConnection(t, repos, "polled")
@@ -260,7 +260,7 @@ func TestProviderCalendar_OwnershipScoping(t *testing.T) {
 		t.Run(driver, func(t *testing.T) {
 			db, cleanup := setupTestDB(t, driver)
 			defer cleanup()
-			repos := NewRepositories(db, driver)
+			repos := NewRepositories(db, driver, nil)
 			ctx := context.Background()
 
 			_, connA := seedConnection(t, repos, "ownerA")
@@ -291,7 +291,7 @@ func TestProviderCalendar_GetByHostID_OrdersByConnectionThenPrimary(t *testing.T
 		t.Run(driver, func(t *testing.T) {
 			db, cleanup := setupTestDB(t, driver)
 			defer cleanup()
-			repos := NewRepositories(db, driver)
+			repos := NewRepositories(db, driver, nil)
 			ctx := context.Background()
 
 			host, conn := seedConnection(t, repos, "ordering")
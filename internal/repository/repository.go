@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"regexp"
+	"slices"
 	"time"
 
 	"github.com/meet-when/meet-when/internal/models"
@@ -17,11 +19,17 @@ type Repositories struct {
 	Host                     *HostRepository
 	Calendar                 *CalendarRepository
 	ProviderCalendar         *ProviderCalendarRepository
+	BusyInterval             *BusyIntervalRepository
+	CalendarWatchChannel     *CalendarWatchChannelRepository
 	Conferencing             *ConferencingRepository
 	Template                 *TemplateRepository
 	Booking                  *BookingRepository
+	BookingReminder          *BookingReminderRepository
 	Session                  *SessionRepository
 	WorkingHours             *WorkingHoursRepository
+	AvailabilityOverride     *AvailabilityOverrideRepository
+	AvailabilitySchedule     *AvailabilityScheduleRepository
+	Holiday                  *HolidayRepository
 	AuditLog                 *AuditLogRepository
 	SignupConversion         *SignupConversionRepository
 	TemplateHost             *TemplateHostRepository
@@ -30,20 +38,38 @@ type Repositories struct {
 	HostedEvent              *HostedEventRepository
 	HostedEventAttendee      *HostedEventAttendeeRepository
 	HostedEventCalendarEvent *HostedEventCalendarEventRepository
+	EmailMessage             *EmailMessageRepository
+	EmailOutbox              *EmailOutboxRepository
+	WebhookSubscription      *WebhookSubscriptionRepository
+	WebhookDelivery          *WebhookDeliveryRepository
+
+	db      *sql.DB
+	driver  string
+	secrets *SecretBox
 }
 
-// NewRepositories creates all repositories
-func NewRepositories(db *sql.DB, driver string) *Repositories {
+// NewRepositories creates all repositories. Connection secrets are sealed
+// with secrets; nil stores them as given.
+func NewRepositories(db *sql.DB, driver string, secrets *SecretBox) *Repositories {
 	return &Repositories{
+		db:                       db,
+		driver:                   driver,
+		secrets:                  secrets,
 		Tenant:                   &TenantRepository{db: db, driver: driver},
 		Host:                     &HostRepository{db: db, driver: driver},
-		Calendar:                 &CalendarRepository{db: db, driver: driver},
+		Calendar:                 &CalendarRepository{db: db, driver: driver, secrets: secrets},
 		ProviderCalendar:         &ProviderCalendarRepository{db: db, driver: driver},
-		Conferencing:             &ConferencingRepository{db: db, driver: driver},
+		BusyInterval:             &BusyIntervalRepository{db: db, driver: driver},
+		CalendarWatchChannel:     &CalendarWatchChannelRepository{db: db, driver: driver},
+		Conferencing:             &ConferencingRepository{db: db, driver: driver, secrets: secrets},
 		Template:                 &TemplateRepository{db: db, driver: driver},
 		Booking:                  &BookingRepository{db: db, driver: driver},
+		BookingReminder:          &BookingReminderRepository{db: db, driver: driver},
 		Session:                  &SessionRepository{db: db, driver: driver},
 		WorkingHours:             &WorkingHoursRepository{db: db, driver: driver},
+		AvailabilityOverride:     &AvailabilityOverrideRepository{db: db, driver: driver},
+		AvailabilitySchedule:     &AvailabilityScheduleRepository{db: db, driver: driver},
+		Holiday:                  &HolidayRepository{db: db, driver: driver},
 		AuditLog:                 &AuditLogRepository{db: db, driver: driver},
 		SignupConversion:         &SignupConversionRepository{db: db, driver: driver},
 		TemplateHost:             &TemplateHostRepository{db: db, driver: driver},
@@ -52,6 +78,10 @@ func NewRepositories(db *sql.DB, driver string) *Repositories {
 		HostedEvent:              &HostedEventRepository{db: db, driver: driver},
 		HostedEventAttendee:      &HostedEventAttendeeRepository{db: db, driver: driver},
 		HostedEventCalendarEvent: &HostedEventCalendarEventRepository{db: db, driver: driver},
+		EmailMessage:             &EmailMessageRepository{db: db, driver: driver},
+		EmailOutbox:              &EmailOutboxRepository{db: db, driver: driver},
+		WebhookSubscription:      &WebhookSubscriptionRepository{db: db, driver: driver, secrets: secrets},
+		WebhookDelivery:          &WebhookDeliveryRepository{db: db, driver: driver},
 	}
 }
 
@@ -87,19 +117,19 @@ type TenantRepository struct {
 
 func (r *TenantRepository) Create(ctx context.Context, tenant *models.Tenant) error {
 	query := q(r.driver, `
-		INSERT INTO tenants (id, slug, name, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO tenants (id, slug, name, logo_url, accent_color, email_footer, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`)
 	_, err := r.db.ExecContext(ctx, query,
-		tenant.ID, tenant.Slug, tenant.Name, tenant.CreatedAt, tenant.UpdatedAt)
+		tenant.ID, tenant.Slug, tenant.Name, tenant.LogoURL, tenant.AccentColor, tenant.EmailFooter, tenant.CreatedAt, tenant.UpdatedAt)
 	return err
 }
 
 func (r *TenantRepository) GetByID(ctx context.Context, id string) (*models.Tenant, error) {
 	tenant := &models.Tenant{}
-	query := q(r.driver, `SELECT id, slug, name, created_at, updated_at FROM tenants WHERE id = $1`)
+	query := q(r.driver, `SELECT id, slug, name, logo_url, accent_color, email_footer, created_at, updated_at FROM tenants WHERE id = $1`)
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&tenant.ID, &tenant.Slug, &tenant.Name, &tenant.CreatedAt, &tenant.UpdatedAt)
+		&tenant.ID, &tenant.Slug, &tenant.Name, &tenant.LogoURL, &tenant.AccentColor, &tenant.EmailFooter, &tenant.CreatedAt, &tenant.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -108,15 +138,22 @@ func (r *TenantRepository) GetByID(ctx context.Context, id string) (*models.Tena
 
 func (r *TenantRepository) GetBySlug(ctx context.Context, slug string) (*models.Tenant, error) {
 	tenant := &models.Tenant{}
-	query := q(r.driver, `SELECT id, slug, name, created_at, updated_at FROM tenants WHERE slug = $1`)
+	query := q(r.driver, `SELECT id, slug, name, logo_url, accent_color, email_footer, created_at, updated_at FROM tenants WHERE slug = $1`)
 	err := r.db.QueryRowContext(ctx, query, slug).Scan(
-		&tenant.ID, &tenant.Slug, &tenant.Name, &tenant.CreatedAt, &tenant.UpdatedAt)
+		&tenant.ID, &tenant.Slug, &tenant.Name, &tenant.LogoURL, &tenant.AccentColor, &tenant.EmailFooter, &tenant.CreatedAt, &tenant.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	return tenant, err
 }
 
+// UpdateBranding saves the tenant's email branding
+func (r *TenantRepository) UpdateBranding(ctx context.Context, tenant *models.Tenant) error {
+	query := q(r.driver, `UPDATE tenants SET logo_url = $1, accent_color = $2, email_footer = $3, updated_at = $4 WHERE id = $5`)
+	_, err := r.db.ExecContext(ctx, query, tenant.LogoURL, tenant.AccentColor, tenant.EmailFooter, models.Now(), tenant.ID)
+	return err
+}
+
 // HostRepository handles host database operations
 type HostRepository struct {
 	db     *sql.DB
@@ -140,14 +177,19 @@ func (r *HostRepository) GetByID(ctx context.Context, id string) (*models.Host,
 	query := q(r.driver, `
 		SELECT id, tenant_id, email, password_hash, name, slug, timezone,
 		       default_calendar_id, is_admin, COALESCE(onboarding_completed, false),
-		       google_id, google_email, COALESCE(smart_durations, false), created_at, updated_at
+		       google_id, google_email, COALESCE(smart_durations, false),
+		       max_bookings_per_day, max_bookings_per_week, max_minutes_per_day,
+		       calendar_buffer_before_minutes, calendar_buffer_after_minutes, min_gap_minutes,
+		       max_back_to_back_minutes, created_at, updated_at
 		FROM hosts WHERE id = $1
 	`)
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&host.ID, &host.TenantID, &host.Email, &host.PasswordHash, &host.Name,
 		&host.Slug, &host.Timezone, &host.DefaultCalendarID, &host.IsAdmin,
 		&host.OnboardingCompleted, &host.GoogleID, &host.GoogleEmail,
-		&host.SmartDurations, &host.CreatedAt, &host.UpdatedAt)
+		&host.SmartDurations, &host.MaxBookingsPerDay, &host.MaxBookingsPerWeek,
+		&host.MaxMinutesPerDay, &host.EventBufferBefore, &host.EventBufferAfter,
+		&host.MinGapMinutes, &host.MaxBackToBack, &host.CreatedAt, &host.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -159,14 +201,19 @@ func (r *HostRepository) GetByEmail(ctx context.Context, tenantID, email string)
 	query := q(r.driver, `
 		SELECT id, tenant_id, email, password_hash, name, slug, timezone,
 		       default_calendar_id, is_admin, COALESCE(onboarding_completed, false),
-		       google_id, google_email, COALESCE(smart_durations, false), created_at, updated_at
+		       google_id, google_email, COALESCE(smart_durations, false),
+		       max_bookings_per_day, max_bookings_per_week, max_minutes_per_day,
+		       calendar_buffer_before_minutes, calendar_buffer_after_minutes, min_gap_minutes,
+		       max_back_to_back_minutes, created_at, updated_at
 		FROM hosts WHERE tenant_id = $1 AND email = $2
 	`)
 	err := r.db.QueryRowContext(ctx, query, tenantID, email).Scan(
 		&host.ID, &host.TenantID, &host.Email, &host.PasswordHash, &host.Name,
 		&host.Slug, &host.Timezone, &host.DefaultCalendarID, &host.IsAdmin,
 		&host.OnboardingCompleted, &host.GoogleID, &host.GoogleEmail,
-		&host.SmartDurations, &host.CreatedAt, &host.UpdatedAt)
+		&host.SmartDurations, &host.MaxBookingsPerDay, &host.MaxBookingsPerWeek,
+		&host.MaxMinutesPerDay, &host.EventBufferBefore, &host.EventBufferAfter,
+		&host.MinGapMinutes, &host.MaxBackToBack, &host.CreatedAt, &host.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -180,7 +227,10 @@ func (r *HostRepository) GetAllByEmail(ctx context.Context, email string) ([]*mo
 	query := q(r.driver, `
 		SELECT id, tenant_id, email, password_hash, name, slug, timezone,
 		       default_calendar_id, is_admin, COALESCE(onboarding_completed, false),
-		       google_id, google_email, COALESCE(smart_durations, false), created_at, updated_at
+		       google_id, google_email, COALESCE(smart_durations, false),
+		       max_bookings_per_day, max_bookings_per_week, max_minutes_per_day,
+		       calendar_buffer_before_minutes, calendar_buffer_after_minutes, min_gap_minutes,
+		       max_back_to_back_minutes, created_at, updated_at
 		FROM hosts WHERE email = $1
 	`)
 	rows, err := r.db.QueryContext(ctx, query, email)
@@ -196,7 +246,9 @@ func (r *HostRepository) GetAllByEmail(ctx context.Context, email string) ([]*mo
 			&host.ID, &host.TenantID, &host.Email, &host.PasswordHash, &host.Name,
 			&host.Slug, &host.Timezone, &host.DefaultCalendarID, &host.IsAdmin,
 			&host.OnboardingCompleted, &host.GoogleID, &host.GoogleEmail,
-			&host.SmartDurations, &host.CreatedAt, &host.UpdatedAt)
+			&host.SmartDurations, &host.MaxBookingsPerDay, &host.MaxBookingsPerWeek,
+			&host.MaxMinutesPerDay, &host.EventBufferBefore, &host.EventBufferAfter,
+			&host.MinGapMinutes, &host.MaxBackToBack, &host.CreatedAt, &host.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -220,14 +272,19 @@ func (r *HostRepository) GetBySlug(ctx context.Context, tenantID, slug string) (
 	query := q(r.driver, `
 		SELECT id, tenant_id, email, password_hash, name, slug, timezone,
 		       default_calendar_id, is_admin, COALESCE(onboarding_completed, false),
-		       google_id, google_email, COALESCE(smart_durations, false), created_at, updated_at
+		       google_id, google_email, COALESCE(smart_durations, false),
+		       max_bookings_per_day, max_bookings_per_week, max_minutes_per_day,
+		       calendar_buffer_before_minutes, calendar_buffer_after_minutes, min_gap_minutes,
+		       max_back_to_back_minutes, created_at, updated_at
 		FROM hosts WHERE tenant_id = $1 AND slug = $2
 	`)
 	err := r.db.QueryRowContext(ctx, query, tenantID, slug).Scan(
 		&host.ID, &host.TenantID, &host.Email, &host.PasswordHash, &host.Name,
 		&host.Slug, &host.Timezone, &host.DefaultCalendarID, &host.IsAdmin,
 		&host.OnboardingCompleted, &host.GoogleID, &host.GoogleEmail,
-		&host.SmartDurations, &host.CreatedAt, &host.UpdatedAt)
+		&host.SmartDurations, &host.MaxBookingsPerDay, &host.MaxBookingsPerWeek,
+		&host.MaxMinutesPerDay, &host.EventBufferBefore, &host.EventBufferAfter,
+		&host.MinGapMinutes, &host.MaxBackToBack, &host.CreatedAt, &host.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -258,6 +315,29 @@ func (r *HostRepository) SetDefaultCalendar(ctx context.Context, hostID, provide
 	return err
 }
 
+// UpdateBookingCaps sets the host's caps across all of their meeting types.
+// Zero means no limit.
+func (r *HostRepository) UpdateBookingCaps(ctx context.Context, id string, perDay, perWeek, minutesPerDay int) error {
+	query := q(r.driver, `
+		UPDATE hosts SET max_bookings_per_day = $1, max_bookings_per_week = $2, max_minutes_per_day = $3
+		WHERE id = $4
+	`)
+	_, err := r.db.ExecContext(ctx, query, perDay, perWeek, minutesPerDay, id)
+	return err
+}
+
+// UpdateBusyPadding sets the time the host keeps free around calendar events
+// and between meetings. Zero turns each off.
+func (r *HostRepository) UpdateBusyPadding(ctx context.Context, id string, calendarBefore, calendarAfter, minGap, maxBackToBack int) error {
+	query := q(r.driver, `
+		UPDATE hosts SET calendar_buffer_before_minutes = $1, calendar_buffer_after_minutes = $2,
+		                 min_gap_minutes = $3, max_back_to_back_minutes = $4
+		WHERE id = $5
+	`)
+	_, err := r.db.ExecContext(ctx, query, calendarBefore, calendarAfter, minGap, maxBackToBack, id)
+	return err
+}
+
 func (r *HostRepository) UpdateOnboardingCompleted(ctx context.Context, id string, completed bool) error {
 	query := q(r.driver, `UPDATE hosts SET onboarding_completed = $1 WHERE id = $2`)
 	_, err := r.db.ExecContext(ctx, query, completed, id)
@@ -269,7 +349,10 @@ func (r *HostRepository) GetByTenantID(ctx context.Context, tenantID string) ([]
 	query := q(r.driver, `
 		SELECT id, tenant_id, email, password_hash, name, slug, timezone,
 		       default_calendar_id, is_admin, COALESCE(onboarding_completed, false),
-		       google_id, google_email, COALESCE(smart_durations, false), created_at, updated_at
+		       google_id, google_email, COALESCE(smart_durations, false),
+		       max_bookings_per_day, max_bookings_per_week, max_minutes_per_day,
+		       calendar_buffer_before_minutes, calendar_buffer_after_minutes, min_gap_minutes,
+		       max_back_to_back_minutes, created_at, updated_at
 		FROM hosts WHERE tenant_id = $1
 		ORDER BY name ASC
 	`)
@@ -286,7 +369,9 @@ func (r *HostRepository) GetByTenantID(ctx context.Context, tenantID string) ([]
 			&host.ID, &host.TenantID, &host.Email, &host.PasswordHash, &host.Name,
 			&host.Slug, &host.Timezone, &host.DefaultCalendarID, &host.IsAdmin,
 			&host.OnboardingCompleted, &host.GoogleID, &host.GoogleEmail,
-			&host.SmartDurations, &host.CreatedAt, &host.UpdatedAt)
+			&host.SmartDurations, &host.MaxBookingsPerDay, &host.MaxBookingsPerWeek,
+			&host.MaxMinutesPerDay, &host.EventBufferBefore, &host.EventBufferAfter,
+			&host.MinGapMinutes, &host.MaxBackToBack, &host.CreatedAt, &host.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -306,7 +391,10 @@ func (r *HostRepository) GetByGoogleID(ctx context.Context, googleID string) ([]
 	query := q(r.driver, `
 		SELECT id, tenant_id, email, password_hash, name, slug, timezone,
 		       default_calendar_id, is_admin, COALESCE(onboarding_completed, false),
-		       google_id, google_email, COALESCE(smart_durations, false), created_at, updated_at
+		       google_id, google_email, COALESCE(smart_durations, false),
+		       max_bookings_per_day, max_bookings_per_week, max_minutes_per_day,
+		       calendar_buffer_before_minutes, calendar_buffer_after_minutes, min_gap_minutes,
+		       max_back_to_back_minutes, created_at, updated_at
 		FROM hosts WHERE google_id = $1
 	`)
 	rows, err := r.db.QueryContext(ctx, query, googleID)
@@ -322,7 +410,9 @@ func (r *HostRepository) GetByGoogleID(ctx context.Context, googleID string) ([]
 			&host.ID, &host.TenantID, &host.Email, &host.PasswordHash, &host.Name,
 			&host.Slug, &host.Timezone, &host.DefaultCalendarID, &host.IsAdmin,
 			&host.OnboardingCompleted, &host.GoogleID, &host.GoogleEmail,
-			&host.SmartDurations, &host.CreatedAt, &host.UpdatedAt)
+			&host.SmartDurations, &host.MaxBookingsPerDay, &host.MaxBookingsPerWeek,
+			&host.MaxMinutesPerDay, &host.EventBufferBefore, &host.EventBufferAfter,
+			&host.MinGapMinutes, &host.MaxBackToBack, &host.CreatedAt, &host.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -351,10 +441,13 @@ func (r *HostRepository) LinkGoogleIdentity(ctx context.Context, hostID, googleI
 	return err
 }
 
-// CalendarRepository handles calendar connection database operations
+// CalendarRepository handles calendar connection database operations.
+// Tokens, CalDAV passwords and calendar URLs and ids, which for an ICS feed
+// are its secret URL, are sealed on write and opened on read.
 type CalendarRepository struct {
-	db     *sql.DB
-	driver string
+	db      *sql.DB
+	driver  string
+	secrets *SecretBox
 }
 
 func (r *CalendarRepository) Create(ctx context.Context, cal *models.CalendarConnection) error {
@@ -365,10 +458,14 @@ func (r *CalendarRepository) Create(ctx context.Context, cal *models.CalendarCon
 			created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 	`)
-	_, err := r.db.ExecContext(ctx, query,
-		cal.ID, cal.HostID, cal.Provider, cal.Name, cal.CalendarID,
-		cal.AccessToken, cal.RefreshToken, cal.TokenExpiry,
-		cal.CalDAVURL, cal.CalDAVUsername, cal.CalDAVPassword,
+	sealed, err := r.secrets.sealAll(cal.AccessToken, cal.RefreshToken, cal.CalDAVPassword, cal.CalendarID, cal.CalDAVURL)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, query,
+		cal.ID, cal.HostID, cal.Provider, cal.Name, sealed[3],
+		sealed[0], sealed[1], cal.TokenExpiry,
+		sealed[4], cal.CalDAVUsername, sealed[2],
 		cal.IsDefault, cal.Color, cal.LastSyncedAt, cal.SyncStatus, cal.SyncError,
 		cal.CreatedAt, cal.UpdatedAt)
 	return err
@@ -392,7 +489,13 @@ func (r *CalendarRepository) GetByID(ctx context.Context, id string) (*models.Ca
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
-	return cal, err
+	if err != nil {
+		return nil, err
+	}
+	if err := r.secrets.openAll(&cal.AccessToken, &cal.RefreshToken, &cal.CalDAVPassword, &cal.CalendarID, &cal.CalDAVURL); err != nil {
+		return nil, fmt.Errorf("calendar connection %s: %w", cal.ID, err)
+	}
+	return cal, nil
 }
 
 func (r *CalendarRepository) GetByHostID(ctx context.Context, hostID string) ([]*models.CalendarConnection, error) {
@@ -426,6 +529,9 @@ func (r *CalendarRepository) GetByHostID(ctx context.Context, hostID string) ([]
 		if err != nil {
 			return nil, err
 		}
+		if err := r.secrets.openAll(&cal.AccessToken, &cal.RefreshToken, &cal.CalDAVPassword, &cal.CalendarID, &cal.CalDAVURL); err != nil {
+			return nil, fmt.Errorf("calendar connection %s: %w", cal.ID, err)
+		}
 		calendars = append(calendars, cal)
 	}
 	return calendars, nil
@@ -439,9 +545,13 @@ func (r *CalendarRepository) Update(ctx context.Context, cal *models.CalendarCon
 		    last_synced_at = $9, sync_status = $10, sync_error = $11
 		WHERE id = $12
 	`)
-	_, err := r.db.ExecContext(ctx, query,
-		cal.Name, cal.AccessToken, cal.RefreshToken, cal.TokenExpiry,
-		cal.CalDAVURL, cal.CalDAVUsername, cal.CalDAVPassword,
+	sealed, err := r.secrets.sealAll(cal.AccessToken, cal.RefreshToken, cal.CalDAVPassword, cal.CalendarID, cal.CalDAVURL)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, query,
+		cal.Name, sealed[0], sealed[1], cal.TokenExpiry,
+		sealed[4], cal.CalDAVUsername, sealed[2],
 		cal.IsDefault, cal.LastSyncedAt, cal.SyncStatus, cal.SyncError, cal.ID)
 	return err
 }
@@ -510,6 +620,9 @@ func (r *CalendarRepository) GetAll(ctx context.Context) ([]*models.CalendarConn
 		if err != nil {
 			return nil, err
 		}
+		if err := r.secrets.openAll(&cal.AccessToken, &cal.RefreshToken, &cal.CalDAVPassword, &cal.CalendarID, &cal.CalDAVURL); err != nil {
+			return nil, fmt.Errorf("calendar connection %s: %w", cal.ID, err)
+		}
 		calendars = append(calendars, cal)
 	}
 	return calendars, nil
@@ -545,10 +658,12 @@ func (r *CalendarRepository) SetDefault(ctx context.Context, hostID, calendarID
 	return tx.Commit()
 }
 
-// ConferencingRepository handles conferencing connection database operations
+// ConferencingRepository handles conferencing connection database operations.
+// Tokens are sealed on write and opened on read.
 type ConferencingRepository struct {
-	db     *sql.DB
-	driver string
+	db      *sql.DB
+	driver  string
+	secrets *SecretBox
 }
 
 func (r *ConferencingRepository) Create(ctx context.Context, conn *models.ConferencingConnection) error {
@@ -557,9 +672,13 @@ func (r *ConferencingRepository) Create(ctx context.Context, conn *models.Confer
 			refresh_token, token_expiry, last_refresh_error, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`)
-	_, err := r.db.ExecContext(ctx, query,
-		conn.ID, conn.HostID, conn.Provider, conn.AccessToken,
-		conn.RefreshToken, conn.TokenExpiry, conn.LastRefreshError, conn.CreatedAt, conn.UpdatedAt)
+	sealed, err := r.secrets.sealAll(conn.AccessToken, conn.RefreshToken)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, query,
+		conn.ID, conn.HostID, conn.Provider, sealed[0],
+		sealed[1], conn.TokenExpiry, conn.LastRefreshError, conn.CreatedAt, conn.UpdatedAt)
 	return err
 }
 
@@ -588,6 +707,9 @@ func (r *ConferencingRepository) GetByHostID(ctx context.Context, hostID string)
 		if err != nil {
 			return nil, err
 		}
+		if err := r.secrets.openAll(&conn.AccessToken, &conn.RefreshToken); err != nil {
+			return nil, fmt.Errorf("conferencing connection %s: %w", conn.ID, err)
+		}
 		connections = append(connections, conn)
 	}
 	return connections, nil
@@ -606,7 +728,13 @@ func (r *ConferencingRepository) GetByHostAndProvider(ctx context.Context, hostI
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
-	return conn, err
+	if err != nil {
+		return nil, err
+	}
+	if err := r.secrets.openAll(&conn.AccessToken, &conn.RefreshToken); err != nil {
+		return nil, fmt.Errorf("conferencing connection %s: %w", conn.ID, err)
+	}
+	return conn, nil
 }
 
 func (r *ConferencingRepository) Update(ctx context.Context, conn *models.ConferencingConnection) error {
@@ -615,8 +743,12 @@ func (r *ConferencingRepository) Update(ctx context.Context, conn *models.Confer
 		SET access_token = $1, refresh_token = $2, token_expiry = $3, last_refresh_error = $4
 		WHERE id = $5
 	`)
-	_, err := r.db.ExecContext(ctx, query,
-		conn.AccessToken, conn.RefreshToken, conn.TokenExpiry, conn.LastRefreshError, conn.ID)
+	sealed, err := r.secrets.sealAll(conn.AccessToken, conn.RefreshToken)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, query,
+		sealed[0], sealed[1], conn.TokenExpiry, conn.LastRefreshError, conn.ID)
 	return err
 }
 
@@ -638,30 +770,37 @@ func (r *TemplateRepository) Create(ctx context.Context, tmpl *models.MeetingTem
 			location_type, custom_location, calendar_id, requires_approval,
 			min_notice_minutes, max_schedule_days, pre_buffer_minutes, post_buffer_minutes,
 			availability_rules, invitee_questions, confirmation_email, reminder_email,
-			is_active, is_private, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
+			is_active, is_private, max_bookings_per_day, max_bookings_per_week,
+			scheduling_type, round_robin_strategy, slot_interval, align_slots, start_minutes, schedule_id, seats, reminders,
+			created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32)
 	`)
 	// Empty CalendarID must be stored as NULL to satisfy the FK constraint.
 	calendarID := sql.NullString{String: tmpl.CalendarID, Valid: tmpl.CalendarID != ""}
+	scheduleID := sql.NullString{String: tmpl.ScheduleID, Valid: tmpl.ScheduleID != ""}
 	_, err := r.db.ExecContext(ctx, query,
 		tmpl.ID, tmpl.HostID, tmpl.Slug, tmpl.Name, tmpl.Description,
 		tmpl.Durations, tmpl.LocationType, tmpl.CustomLocation, calendarID,
 		tmpl.RequiresApproval, tmpl.MinNoticeMinutes, tmpl.MaxScheduleDays,
 		tmpl.PreBufferMinutes, tmpl.PostBufferMinutes, tmpl.AvailabilityRules,
 		tmpl.InviteeQuestions, tmpl.ConfirmationEmail, tmpl.ReminderEmail,
-		tmpl.IsActive, tmpl.IsPrivate, tmpl.CreatedAt, tmpl.UpdatedAt)
+		tmpl.IsActive, tmpl.IsPrivate, tmpl.MaxBookingsPerDay, tmpl.MaxBookingsPerWeek,
+		tmpl.SchedulingType, tmpl.RoundRobinStrategy, tmpl.SlotInterval, tmpl.AlignSlots, tmpl.StartMinutes, scheduleID, tmpl.Seats, tmpl.Reminders,
+		tmpl.CreatedAt, tmpl.UpdatedAt)
 	return err
 }
 
 func (r *TemplateRepository) GetByID(ctx context.Context, id string) (*models.MeetingTemplate, error) {
 	tmpl := &models.MeetingTemplate{}
-	var calendarID sql.NullString
+	var calendarID, scheduleID sql.NullString
 	query := q(r.driver, `
 		SELECT id, host_id, slug, name, description, durations, location_type,
 		       custom_location, calendar_id, requires_approval, min_notice_minutes,
 		       max_schedule_days, pre_buffer_minutes, post_buffer_minutes,
 		       availability_rules, invitee_questions, confirmation_email, reminder_email,
-		       is_active, COALESCE(is_private, false), created_at, updated_at
+		       is_active, COALESCE(is_private, false), max_bookings_per_day, max_bookings_per_week,
+		       scheduling_type, round_robin_strategy, slot_interval, align_slots, start_minutes, schedule_id, seats, reminders,
+		       created_at, updated_at
 		FROM meeting_templates WHERE id = $1
 	`)
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
@@ -670,7 +809,9 @@ func (r *TemplateRepository) GetByID(ctx context.Context, id string) (*models.Me
 		&tmpl.RequiresApproval, &tmpl.MinNoticeMinutes, &tmpl.MaxScheduleDays,
 		&tmpl.PreBufferMinutes, &tmpl.PostBufferMinutes, &tmpl.AvailabilityRules,
 		&tmpl.InviteeQuestions, &tmpl.ConfirmationEmail, &tmpl.ReminderEmail,
-		&tmpl.IsActive, &tmpl.IsPrivate, &tmpl.CreatedAt, &tmpl.UpdatedAt)
+		&tmpl.IsActive, &tmpl.IsPrivate, &tmpl.MaxBookingsPerDay, &tmpl.MaxBookingsPerWeek,
+		&tmpl.SchedulingType, &tmpl.RoundRobinStrategy, &tmpl.SlotInterval, &tmpl.AlignSlots, &tmpl.StartMinutes, &scheduleID, &tmpl.Seats, &tmpl.Reminders,
+		&tmpl.CreatedAt, &tmpl.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -679,18 +820,21 @@ func (r *TemplateRepository) GetByID(ctx context.Context, id string) (*models.Me
 		return nil, err
 	}
 	tmpl.CalendarID = nullStr(calendarID)
+	tmpl.ScheduleID = nullStr(scheduleID)
 	return tmpl, nil
 }
 
 func (r *TemplateRepository) GetByHostAndSlug(ctx context.Context, hostID, slug string) (*models.MeetingTemplate, error) {
 	tmpl := &models.MeetingTemplate{}
-	var calendarID sql.NullString
+	var calendarID, scheduleID sql.NullString
 	query := q(r.driver, `
 		SELECT id, host_id, slug, name, description, durations, location_type,
 		       custom_location, calendar_id, requires_approval, min_notice_minutes,
 		       max_schedule_days, pre_buffer_minutes, post_buffer_minutes,
 		       availability_rules, invitee_questions, confirmation_email, reminder_email,
-		       is_active, COALESCE(is_private, false), created_at, updated_at
+		       is_active, COALESCE(is_private, false), max_bookings_per_day, max_bookings_per_week,
+		       scheduling_type, round_robin_strategy, slot_interval, align_slots, start_minutes, schedule_id, seats, reminders,
+		       created_at, updated_at
 		FROM meeting_templates WHERE host_id = $1 AND slug = $2
 	`)
 	err := r.db.QueryRowContext(ctx, query, hostID, slug).Scan(
@@ -699,7 +843,9 @@ func (r *TemplateRepository) GetByHostAndSlug(ctx context.Context, hostID, slug
 		&tmpl.RequiresApproval, &tmpl.MinNoticeMinutes, &tmpl.MaxScheduleDays,
 		&tmpl.PreBufferMinutes, &tmpl.PostBufferMinutes, &tmpl.AvailabilityRules,
 		&tmpl.InviteeQuestions, &tmpl.ConfirmationEmail, &tmpl.ReminderEmail,
-		&tmpl.IsActive, &tmpl.IsPrivate, &tmpl.CreatedAt, &tmpl.UpdatedAt)
+		&tmpl.IsActive, &tmpl.IsPrivate, &tmpl.MaxBookingsPerDay, &tmpl.MaxBookingsPerWeek,
+		&tmpl.SchedulingType, &tmpl.RoundRobinStrategy, &tmpl.SlotInterval, &tmpl.AlignSlots, &tmpl.StartMinutes, &scheduleID, &tmpl.Seats, &tmpl.Reminders,
+		&tmpl.CreatedAt, &tmpl.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -708,6 +854,7 @@ func (r *TemplateRepository) GetByHostAndSlug(ctx context.Context, hostID, slug
 		return nil, err
 	}
 	tmpl.CalendarID = nullStr(calendarID)
+	tmpl.ScheduleID = nullStr(scheduleID)
 	return tmpl, nil
 }
 
@@ -717,7 +864,9 @@ func (r *TemplateRepository) GetByHostID(ctx context.Context, hostID string) ([]
 		       custom_location, calendar_id, requires_approval, min_notice_minutes,
 		       max_schedule_days, pre_buffer_minutes, post_buffer_minutes,
 		       availability_rules, invitee_questions, confirmation_email, reminder_email,
-		       is_active, COALESCE(is_private, false), created_at, updated_at
+		       is_active, COALESCE(is_private, false), max_bookings_per_day, max_bookings_per_week,
+		       scheduling_type, round_robin_strategy, slot_interval, align_slots, start_minutes, schedule_id, seats, reminders,
+		       created_at, updated_at
 		FROM meeting_templates WHERE host_id = $1
 		ORDER BY created_at DESC
 	`)
@@ -735,19 +884,22 @@ func (r *TemplateRepository) GetByHostID(ctx context.Context, hostID string) ([]
 	var templates []*models.MeetingTemplate
 	for rows.Next() {
 		tmpl := &models.MeetingTemplate{}
-		var calendarID sql.NullString
+		var calendarID, scheduleID sql.NullString
 		err := rows.Scan(
 			&tmpl.ID, &tmpl.HostID, &tmpl.Slug, &tmpl.Name, &tmpl.Description,
 			&tmpl.Durations, &tmpl.LocationType, &tmpl.CustomLocation, &calendarID,
 			&tmpl.RequiresApproval, &tmpl.MinNoticeMinutes, &tmpl.MaxScheduleDays,
 			&tmpl.PreBufferMinutes, &tmpl.PostBufferMinutes, &tmpl.AvailabilityRules,
 			&tmpl.InviteeQuestions, &tmpl.ConfirmationEmail, &tmpl.ReminderEmail,
-			&tmpl.IsActive, &tmpl.IsPrivate, &tmpl.CreatedAt, &tmpl.UpdatedAt)
+			&tmpl.IsActive, &tmpl.IsPrivate, &tmpl.MaxBookingsPerDay, &tmpl.MaxBookingsPerWeek,
+			&tmpl.SchedulingType, &tmpl.RoundRobinStrategy, &tmpl.SlotInterval, &tmpl.AlignSlots, &tmpl.StartMinutes, &scheduleID, &tmpl.Seats, &tmpl.Reminders,
+			&tmpl.CreatedAt, &tmpl.UpdatedAt)
 		if err != nil {
 			logQueryError("GetByHostID", "meeting_template (scan)", err, hostID)
 			return nil, err
 		}
 		tmpl.CalendarID = nullStr(calendarID)
+		tmpl.ScheduleID = nullStr(scheduleID)
 		templates = append(templates, tmpl)
 	}
 	return templates, nil
@@ -760,16 +912,24 @@ func (r *TemplateRepository) Update(ctx context.Context, tmpl *models.MeetingTem
 		    custom_location = $6, calendar_id = $7, requires_approval = $8,
 		    min_notice_minutes = $9, max_schedule_days = $10, pre_buffer_minutes = $11,
 		    post_buffer_minutes = $12, availability_rules = $13, invitee_questions = $14,
-		    confirmation_email = $15, reminder_email = $16, is_active = $17, is_private = $18
-		WHERE id = $19
+		    confirmation_email = $15, reminder_email = $16, is_active = $17, is_private = $18,
+		    max_bookings_per_day = $19, max_bookings_per_week = $20,
+		    scheduling_type = $21, round_robin_strategy = $22,
+		    slot_interval = $23, align_slots = $24, start_minutes = $25, schedule_id = $26, seats = $27,
+		    reminders = $28
+		WHERE id = $29
 	`)
 	calendarID := sql.NullString{String: tmpl.CalendarID, Valid: tmpl.CalendarID != ""}
+	scheduleID := sql.NullString{String: tmpl.ScheduleID, Valid: tmpl.ScheduleID != ""}
 	_, err := r.db.ExecContext(ctx, query,
 		tmpl.Slug, tmpl.Name, tmpl.Description, tmpl.Durations, tmpl.LocationType,
 		tmpl.CustomLocation, calendarID, tmpl.RequiresApproval,
 		tmpl.MinNoticeMinutes, tmpl.MaxScheduleDays, tmpl.PreBufferMinutes,
 		tmpl.PostBufferMinutes, tmpl.AvailabilityRules, tmpl.InviteeQuestions,
-		tmpl.ConfirmationEmail, tmpl.ReminderEmail, tmpl.IsActive, tmpl.IsPrivate, tmpl.ID)
+		tmpl.ConfirmationEmail, tmpl.ReminderEmail, tmpl.IsActive, tmpl.IsPrivate,
+		tmpl.MaxBookingsPerDay, tmpl.MaxBookingsPerWeek,
+		tmpl.SchedulingType, tmpl.RoundRobinStrategy,
+		tmpl.SlotInterval, tmpl.AlignSlots, tmpl.StartMinutes, scheduleID, tmpl.Seats, tmpl.Reminders, tmpl.ID)
 	return err
 }
 
@@ -779,6 +939,15 @@ func (r *TemplateRepository) Delete(ctx context.Context, id string) error {
 	return err
 }
 
+var (
+	// ErrBookingConflict is returned by CreateIfSlotFree when a host already
+	// has a booking in the requested slot.
+	ErrBookingConflict = errors.New("host already has a booking in this slot")
+	// ErrBookingCapReached is returned by CreateIfSlotFree when the booking
+	// would go over a daily or weekly cap.
+	ErrBookingCapReached = errors.New("booking cap reached")
+)
+
 // BookingRepository handles booking database operations
 type BookingRepository struct {
 	db     *sql.DB
@@ -786,19 +955,149 @@ type BookingRepository struct {
 }
 
 func (r *BookingRepository) Create(ctx context.Context, booking *models.Booking) error {
+	return r.insert(ctx, r.db, booking)
+}
+
+// SlotCheck is what CreateIfSlotFree verifies before inserting a booking.
+type SlotCheck struct {
+	HostIDs []string      // hosts whose time the booking takes
+	Before  time.Duration // the template's buffer ahead of a booking
+	After   time.Duration // the template's buffer after a booking
+	Caps    []BookingCap
+	Seats   int // the template's seats per slot, see CreateIfSlotFree
+}
+
+// BookingCap limits a host's pending and confirmed bookings that start in
+// [From, To), counting only TemplateID's when it is set. A zero limit is off.
+type BookingCap struct {
+	HostID      string
+	TemplateID  string
+	From        time.Time
+	To          time.Time
+	MaxBookings int
+	MaxMinutes  int
+}
+
+// CreateIfSlotFree inserts a booking unless one of check.HostIDs already has
+// a pending or confirmed booking that overlaps it once the template's
+// buffers are applied, returning ErrBookingConflict, or the booking would go
+// over one of check.Caps, returning ErrBookingCapReached. When check.Seats
+// is above 1, bookings of the same template, start time and duration are
+// seats of one slot rather than conflicts: the booking takes a seat if one is
+// left, and as the slot's meeting already counts towards the caps they aren't
+// checked again. The checks and the insert share a transaction. On Postgres,
+// concurrent calls for the same host queue on a per-host advisory lock held
+// until commit; SQLite runs on a single connection, so the transaction
+// already excludes every other writer.
+func (r *BookingRepository) CreateIfSlotFree(ctx context.Context, booking *models.Booking, check SlotCheck) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("Error rolling back transaction: %v", err)
+		}
+	}()
+
+	// Lock in a stable order so two multi-host bookings can't deadlock.
+	hosts := slices.Sorted(slices.Values(check.HostIDs))
+	hosts = slices.Compact(hosts)
+	if r.driver == "postgres" {
+		for _, id := range hosts {
+			if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('booking-slot:' || $1))`, id); err != nil {
+				return err
+			}
+		}
+	}
+
+	seatsTaken := 0
+	if check.Seats > 1 {
+		err := tx.QueryRowContext(ctx, q(r.driver, `
+			SELECT COUNT(*) FROM bookings
+			WHERE template_id = $1 AND start_time = $2 AND duration = $3
+			  AND status IN ('pending', 'confirmed')
+		`), booking.TemplateID, booking.StartTime, booking.Duration).Scan(&seatsTaken)
+		if err != nil {
+			return err
+		}
+		if seatsTaken >= check.Seats {
+			return ErrBookingConflict
+		}
+	}
+
+	// An existing booking's buffers extend it; widen the window to match.
+	windowStart := booking.StartTime.Add(-check.After)
+	windowEnd := booking.EndTime.Add(check.Before)
+	for _, id := range hosts {
+		query := `
+			SELECT COUNT(*) FROM bookings
+			WHERE host_id = $1
+			  AND status IN ('pending', 'confirmed')
+			  AND start_time < $2 AND end_time > $3`
+		args := []interface{}{id, models.NewSQLiteTime(windowEnd), models.NewSQLiteTime(windowStart)}
+		if check.Seats > 1 {
+			query += ` AND NOT (template_id = $4 AND start_time = $5 AND duration = $6)`
+			args = append(args, booking.TemplateID, booking.StartTime, booking.Duration)
+		}
+		var n int
+		if err := tx.QueryRowContext(ctx, q(r.driver, query), args...).Scan(&n); err != nil {
+			return err
+		}
+		if n > 0 {
+			return ErrBookingConflict
+		}
+	}
+
+	for _, c := range check.Caps {
+		if (c.MaxBookings <= 0 && c.MaxMinutes <= 0) || seatsTaken > 0 {
+			continue
+		}
+		// The seats of a slot are one meeting
+		query := `
+			SELECT COUNT(*), COALESCE(SUM(duration), 0) FROM (
+				SELECT DISTINCT template_id, start_time, duration FROM bookings
+				WHERE host_id = $1
+				  AND status IN ('pending', 'confirmed')
+				  AND start_time >= $2 AND start_time < $3`
+		args := []interface{}{c.HostID, models.NewSQLiteTime(c.From), models.NewSQLiteTime(c.To)}
+		if c.TemplateID != "" {
+			query += ` AND template_id = $4`
+			args = append(args, c.TemplateID)
+		}
+		query += `
+			) meetings`
+		var count, minutes int
+		if err := tx.QueryRowContext(ctx, q(r.driver, query), args...).Scan(&count, &minutes); err != nil {
+			return err
+		}
+		if (c.MaxBookings > 0 && count+1 > c.MaxBookings) || (c.MaxMinutes > 0 && minutes+booking.Duration > c.MaxMinutes) {
+			return ErrBookingCapReached
+		}
+	}
+
+	if err := r.insert(ctx, tx, booking); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *BookingRepository) insert(ctx context.Context, exec interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}, booking *models.Booking) error {
 	query := q(r.driver, `
 		INSERT INTO bookings (id, template_id, host_id, token, status, start_time,
 			end_time, duration, invitee_name, invitee_email, invitee_timezone,
 			invitee_phone, additional_guests, answers, conference_link,
-			calendar_event_id, reminder_sent, is_archived, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+			calendar_event_id, is_archived, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
 	`)
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := exec.ExecContext(ctx, query,
 		booking.ID, booking.TemplateID, booking.HostID, booking.Token,
 		booking.Status, booking.StartTime, booking.EndTime, booking.Duration,
 		booking.InviteeName, booking.InviteeEmail, booking.InviteeTimezone,
 		booking.InviteePhone, booking.AdditionalGuests, booking.Answers,
-		booking.ConferenceLink, booking.CalendarEventID, booking.ReminderSent,
+		booking.ConferenceLink, booking.CalendarEventID,
 		booking.IsArchived, booking.CreatedAt, booking.UpdatedAt)
 	return err
 }
@@ -809,8 +1108,8 @@ func (r *BookingRepository) GetByID(ctx context.Context, id string) (*models.Boo
 		SELECT id, template_id, host_id, token, status, start_time, end_time, duration,
 		       invitee_name, invitee_email, COALESCE(invitee_timezone, ''), COALESCE(invitee_phone, ''),
 		       additional_guests, answers, COALESCE(conference_link, ''), COALESCE(calendar_event_id, ''),
-		       COALESCE(cancelled_by, ''), COALESCE(cancel_reason, ''), COALESCE(reminder_sent, false),
-		       COALESCE(is_archived, false), created_at, updated_at
+		       COALESCE(cancelled_by, ''), COALESCE(cancel_reason, ''),
+		       COALESCE(is_archived, false), COALESCE(review_reason, ''), created_at, updated_at
 		FROM bookings WHERE id = $1
 	`)
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
@@ -819,8 +1118,8 @@ func (r *BookingRepository) GetByID(ctx context.Context, id string) (*models.Boo
 		&booking.InviteeName, &booking.InviteeEmail, &booking.InviteeTimezone,
 		&booking.InviteePhone, &booking.AdditionalGuests, &booking.Answers,
 		&booking.ConferenceLink, &booking.CalendarEventID,
-		&booking.CancelledBy, &booking.CancelReason, &booking.ReminderSent,
-		&booking.IsArchived, &booking.CreatedAt, &booking.UpdatedAt)
+		&booking.CancelledBy, &booking.CancelReason,
+		&booking.IsArchived, &booking.ReviewReason, &booking.CreatedAt, &booking.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -833,8 +1132,8 @@ func (r *BookingRepository) GetByToken(ctx context.Context, token string) (*mode
 		SELECT id, template_id, host_id, token, status, start_time, end_time, duration,
 		       invitee_name, invitee_email, COALESCE(invitee_timezone, ''), COALESCE(invitee_phone, ''),
 		       additional_guests, answers, COALESCE(conference_link, ''), COALESCE(calendar_event_id, ''),
-		       COALESCE(cancelled_by, ''), COALESCE(cancel_reason, ''), COALESCE(reminder_sent, false),
-		       COALESCE(is_archived, false), created_at, updated_at
+		       COALESCE(cancelled_by, ''), COALESCE(cancel_reason, ''),
+		       COALESCE(is_archived, false), COALESCE(review_reason, ''), created_at, updated_at
 		FROM bookings WHERE token = $1
 	`)
 	err := r.db.QueryRowContext(ctx, query, token).Scan(
@@ -843,8 +1142,8 @@ func (r *BookingRepository) GetByToken(ctx context.Context, token string) (*mode
 		&booking.InviteeName, &booking.InviteeEmail, &booking.InviteeTimezone,
 		&booking.InviteePhone, &booking.AdditionalGuests, &booking.Answers,
 		&booking.ConferenceLink, &booking.CalendarEventID,
-		&booking.CancelledBy, &booking.CancelReason, &booking.ReminderSent,
-		&booking.IsArchived, &booking.CreatedAt, &booking.UpdatedAt)
+		&booking.CancelledBy, &booking.CancelReason,
+		&booking.IsArchived, &booking.ReviewReason, &booking.CreatedAt, &booking.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -860,8 +1159,8 @@ func (r *BookingRepository) GetByHostID(ctx context.Context, hostID string, stat
 		SELECT id, template_id, host_id, token, status, start_time, end_time, duration,
 		       invitee_name, invitee_email, COALESCE(invitee_timezone, ''), COALESCE(invitee_phone, ''),
 		       additional_guests, answers, COALESCE(conference_link, ''), COALESCE(calendar_event_id, ''),
-		       COALESCE(cancelled_by, ''), COALESCE(cancel_reason, ''), COALESCE(reminder_sent, false),
-		       COALESCE(is_archived, false), created_at, updated_at
+		       COALESCE(cancelled_by, ''), COALESCE(cancel_reason, ''),
+		       COALESCE(is_archived, false), COALESCE(review_reason, ''), created_at, updated_at
 		FROM bookings WHERE host_id = $1`
 
 	// Build the WHERE conditions
@@ -897,8 +1196,8 @@ func (r *BookingRepository) GetByHostID(ctx context.Context, hostID string, stat
 			&booking.InviteeName, &booking.InviteeEmail, &booking.InviteeTimezone,
 			&booking.InviteePhone, &booking.AdditionalGuests, &booking.Answers,
 			&booking.ConferenceLink, &booking.CalendarEventID,
-			&booking.CancelledBy, &booking.CancelReason, &booking.ReminderSent,
-			&booking.IsArchived, &booking.CreatedAt, &booking.UpdatedAt)
+			&booking.CancelledBy, &booking.CancelReason,
+			&booking.IsArchived, &booking.ReviewReason, &booking.CreatedAt, &booking.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -912,15 +1211,15 @@ func (r *BookingRepository) GetByHostIDAndTimeRange(ctx context.Context, hostID
 		SELECT id, template_id, host_id, token, status, start_time, end_time, duration,
 		       invitee_name, invitee_email, COALESCE(invitee_timezone, ''), COALESCE(invitee_phone, ''),
 		       additional_guests, answers, COALESCE(conference_link, ''), COALESCE(calendar_event_id, ''),
-		       COALESCE(cancelled_by, ''), COALESCE(cancel_reason, ''), COALESCE(reminder_sent, false),
-		       COALESCE(is_archived, false), created_at, updated_at
+		       COALESCE(cancelled_by, ''), COALESCE(cancel_reason, ''),
+		       COALESCE(is_archived, false), COALESCE(review_reason, ''), created_at, updated_at
 		FROM bookings
 		WHERE host_id = $1
 		  AND status IN ('pending', 'confirmed')
-		  AND start_time < $3 AND end_time > $2
+		  AND end_time > $2 AND start_time < $3
 		ORDER BY start_time ASC
 	`)
-	rows, err := r.db.QueryContext(ctx, query, hostID, start, end)
+	rows, err := r.db.QueryContext(ctx, query, hostID, models.NewSQLiteTime(start), models.NewSQLiteTime(end))
 	if err != nil {
 		return nil, err
 	}
@@ -939,8 +1238,8 @@ func (r *BookingRepository) GetByHostIDAndTimeRange(ctx context.Context, hostID
 			&booking.InviteeName, &booking.InviteeEmail, &booking.InviteeTimezone,
 			&booking.InviteePhone, &booking.AdditionalGuests, &booking.Answers,
 			&booking.ConferenceLink, &booking.CalendarEventID,
-			&booking.CancelledBy, &booking.CancelReason, &booking.ReminderSent,
-			&booking.IsArchived, &booking.CreatedAt, &booking.UpdatedAt)
+			&booking.CancelledBy, &booking.CancelReason,
+			&booking.IsArchived, &booking.ReviewReason, &booking.CreatedAt, &booking.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -949,41 +1248,84 @@ func (r *BookingRepository) GetByHostIDAndTimeRange(ctx context.Context, hostID
 	return bookings, nil
 }
 
+// GetByTemplateIDAndTimeRange returns the template's pending and confirmed
+// bookings that start in [start, end), in start time and then booking order.
+func (r *BookingRepository) GetByTemplateIDAndTimeRange(ctx context.Context, templateID string, start, end time.Time) ([]*models.Booking, error) {
+	query := q(r.driver, `
+		SELECT id, template_id, host_id, token, status, start_time, end_time, duration,
+		       invitee_name, invitee_email, COALESCE(invitee_timezone, ''), COALESCE(invitee_phone, ''),
+		       additional_guests, answers, COALESCE(conference_link, ''), COALESCE(calendar_event_id, ''),
+		       COALESCE(cancelled_by, ''), COALESCE(cancel_reason, ''),
+		       COALESCE(is_archived, false), COALESCE(review_reason, ''), created_at, updated_at
+		FROM bookings
+		WHERE template_id = $1
+		  AND status IN ('pending', 'confirmed')
+		  AND start_time >= $2 AND start_time < $3
+		ORDER BY start_time ASC, created_at ASC
+	`)
+	rows, err := r.db.QueryContext(ctx, query, templateID, models.NewSQLiteTime(start), models.NewSQLiteTime(end))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	var bookings []*models.Booking
+	for rows.Next() {
+		booking := &models.Booking{}
+		err := rows.Scan(
+			&booking.ID, &booking.TemplateID, &booking.HostID, &booking.Token,
+			&booking.Status, &booking.StartTime, &booking.EndTime, &booking.Duration,
+			&booking.InviteeName, &booking.InviteeEmail, &booking.InviteeTimezone,
+			&booking.InviteePhone, &booking.AdditionalGuests, &booking.Answers,
+			&booking.ConferenceLink, &booking.CalendarEventID,
+			&booking.CancelledBy, &booking.CancelReason,
+			&booking.IsArchived, &booking.ReviewReason, &booking.CreatedAt, &booking.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		bookings = append(bookings, booking)
+	}
+	return bookings, rows.Err()
+}
+
 func (r *BookingRepository) Update(ctx context.Context, booking *models.Booking) error {
 	query := q(r.driver, `
 		UPDATE bookings
 		SET status = $1, conference_link = $2, calendar_event_id = $3,
-		    cancelled_by = $4, cancel_reason = $5, reminder_sent = $6, is_archived = $7,
-		    start_time = $8, end_time = $9, duration = $10,
-		    additional_guests = $11, answers = $12, updated_at = $13
+		    cancelled_by = $4, cancel_reason = $5, is_archived = $6,
+		    start_time = $7, end_time = $8, duration = $9,
+		    additional_guests = $10, answers = $11, review_reason = $12, updated_at = $13
 		WHERE id = $14
 	`)
 	_, err := r.db.ExecContext(ctx, query,
 		booking.Status, booking.ConferenceLink, booking.CalendarEventID,
-		booking.CancelledBy, booking.CancelReason, booking.ReminderSent,
+		booking.CancelledBy, booking.CancelReason,
 		booking.IsArchived, booking.StartTime, booking.EndTime, booking.Duration,
-		booking.AdditionalGuests, booking.Answers,
+		booking.AdditionalGuests, booking.Answers, booking.ReviewReason,
 		booking.UpdatedAt, booking.ID)
 	return err
 }
 
-// GetBookingsNeedingReminder returns confirmed bookings that start within the given time range
-// and haven't had a reminder sent yet
-func (r *BookingRepository) GetBookingsNeedingReminder(ctx context.Context, start, end time.Time) ([]*models.Booking, error) {
+// GetBookingsStartingBetween returns confirmed bookings that start within the
+// given time range, for the reminder service to check against its ledger
+func (r *BookingRepository) GetBookingsStartingBetween(ctx context.Context, start, end time.Time) ([]*models.Booking, error) {
 	query := q(r.driver, `
 		SELECT id, template_id, host_id, token, status, start_time, end_time, duration,
 		       invitee_name, invitee_email, COALESCE(invitee_timezone, ''), COALESCE(invitee_phone, ''),
 		       additional_guests, answers, COALESCE(conference_link, ''), COALESCE(calendar_event_id, ''),
-		       COALESCE(cancelled_by, ''), COALESCE(cancel_reason, ''), COALESCE(reminder_sent, false),
-		       COALESCE(is_archived, false), created_at, updated_at
+		       COALESCE(cancelled_by, ''), COALESCE(cancel_reason, ''),
+		       COALESCE(is_archived, false), COALESCE(review_reason, ''), created_at, updated_at
 		FROM bookings
 		WHERE status = 'confirmed'
-		  AND (reminder_sent = false OR reminder_sent IS NULL)
 		  AND start_time >= $1
 		  AND start_time <= $2
 		ORDER BY start_time ASC
 	`)
-	rows, err := r.db.QueryContext(ctx, query, start, end)
+	rows, err := r.db.QueryContext(ctx, query, models.NewSQLiteTime(start), models.NewSQLiteTime(end))
 	if err != nil {
 		return nil, err
 	}
@@ -1002,8 +1344,8 @@ func (r *BookingRepository) GetBookingsNeedingReminder(ctx context.Context, star
 			&booking.InviteeName, &booking.InviteeEmail, &booking.InviteeTimezone,
 			&booking.InviteePhone, &booking.AdditionalGuests, &booking.Answers,
 			&booking.ConferenceLink, &booking.CalendarEventID,
-			&booking.CancelledBy, &booking.CancelReason, &booking.ReminderSent,
-			&booking.IsArchived, &booking.CreatedAt, &booking.UpdatedAt)
+			&booking.CancelledBy, &booking.CancelReason,
+			&booking.IsArchived, &booking.ReviewReason, &booking.CreatedAt, &booking.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -1012,13 +1354,6 @@ func (r *BookingRepository) GetBookingsNeedingReminder(ctx context.Context, star
 	return bookings, nil
 }
 
-// MarkReminderSent marks a booking's reminder as sent
-func (r *BookingRepository) MarkReminderSent(ctx context.Context, bookingID string) error {
-	query := q(r.driver, `UPDATE bookings SET reminder_sent = $1 WHERE id = $2`)
-	_, err := r.db.ExecContext(ctx, query, true, bookingID)
-	return err
-}
-
 // BookingCount holds the count of bookings by status for a template
 type BookingCount struct {
 	TemplateID string
@@ -1070,6 +1405,47 @@ func (r *BookingRepository) GetBookingCountsByHostID(ctx context.Context, hostID
 	return counts, nil
 }
 
+// AssignmentStat summarizes the pending and confirmed bookings of one
+// template assigned to one host
+type AssignmentStat struct {
+	HostID       string
+	Count        int // made since the time given to GetAssignmentStats
+	LastBookedAt time.Time
+}
+
+// GetAssignmentStats returns, by host, how many of the template's pending and
+// confirmed bookings were made since since and when the latest was made.
+// Hosts with no such bookings are left out.
+func (r *BookingRepository) GetAssignmentStats(ctx context.Context, templateID string, since time.Time) (map[string]*AssignmentStat, error) {
+	query := q(r.driver, `
+		SELECT host_id, COUNT(CASE WHEN created_at >= $1 THEN 1 END), MAX(created_at)
+		FROM bookings
+		WHERE template_id = $2 AND status IN ('pending', 'confirmed')
+		GROUP BY host_id
+	`)
+	rows, err := r.db.QueryContext(ctx, query, models.NewSQLiteTime(since), templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Error closing rows: %v", err)
+		}
+	}()
+
+	stats := make(map[string]*AssignmentStat)
+	for rows.Next() {
+		stat := &AssignmentStat{}
+		var last models.SQLiteTime
+		if err := rows.Scan(&stat.HostID, &stat.Count, &last); err != nil {
+			return nil, err
+		}
+		stat.LastBookedAt = last.Time
+		stats[stat.HostID] = stat
+	}
+	return stats, rows.Err()
+}
+
 // ArchiveOldBookings archives all unarchived bookings where end_time is before cutoffTime.
 // Returns the number of bookings archived.
 func (r *BookingRepository) ArchiveOldBookings(ctx context.Context, cutoffTime time.Time) (int, error) {
@@ -1117,8 +1493,8 @@ func (r *BookingRepository) ListConfirmedByTenant(ctx context.Context, tenantID
 		SELECT b.id, b.template_id, b.host_id, b.token, b.status, b.start_time, b.end_time, b.duration,
 		       b.invitee_name, b.invitee_email, COALESCE(b.invitee_timezone, ''), COALESCE(b.invitee_phone, ''),
 		       b.additional_guests, b.answers, COALESCE(b.conference_link, ''), COALESCE(b.calendar_event_id, ''),
-		       COALESCE(b.cancelled_by, ''), COALESCE(b.cancel_reason, ''), COALESCE(b.reminder_sent, false),
-		       COALESCE(b.is_archived, false), b.created_at, b.updated_at
+		       COALESCE(b.cancelled_by, ''), COALESCE(b.cancel_reason, ''),
+		       COALESCE(b.is_archived, false), COALESCE(b.review_reason, ''), b.created_at, b.updated_at
 		FROM bookings b
 		JOIN hosts h ON b.host_id = h.id
 		WHERE h.tenant_id = $1 AND b.status = 'confirmed'
@@ -1144,8 +1520,8 @@ func (r *BookingRepository) ListConfirmedByTenant(ctx context.Context, tenantID
 			&booking.InviteeName, &booking.InviteeEmail, &booking.InviteeTimezone,
 			&booking.InviteePhone, &booking.AdditionalGuests, &booking.Answers,
 			&booking.ConferenceLink, &booking.CalendarEventID,
-			&booking.CancelledBy, &booking.CancelReason, &booking.ReminderSent,
-			&booking.IsArchived, &booking.CreatedAt, &booking.UpdatedAt)
+			&booking.CancelledBy, &booking.CancelReason,
+			&booking.IsArchived, &booking.ReviewReason, &booking.CreatedAt, &booking.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -1444,18 +1820,18 @@ type TemplateHostRepository struct {
 // Create adds a new host to a template
 func (r *TemplateHostRepository) Create(ctx context.Context, th *models.TemplateHost) error {
 	query := q(r.driver, `
-		INSERT INTO template_hosts (id, template_id, host_id, role, is_optional, display_order, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO template_hosts (id, template_id, host_id, role, is_optional, display_order, weight, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`)
 	_, err := r.db.ExecContext(ctx, query,
-		th.ID, th.TemplateID, th.HostID, th.Role, th.IsOptional, th.DisplayOrder, th.CreatedAt, th.UpdatedAt)
+		th.ID, th.TemplateID, th.HostID, th.Role, th.IsOptional, th.DisplayOrder, th.Weight, th.CreatedAt, th.UpdatedAt)
 	return err
 }
 
 // GetByTemplateID returns all hosts for a template
 func (r *TemplateHostRepository) GetByTemplateID(ctx context.Context, templateID string) ([]*models.TemplateHost, error) {
 	query := q(r.driver, `
-		SELECT id, template_id, host_id, role, is_optional, display_order, created_at, updated_at
+		SELECT id, template_id, host_id, role, is_optional, display_order, weight, created_at, updated_at
 		FROM template_hosts
 		WHERE template_id = $1
 		ORDER BY display_order ASC, created_at ASC
@@ -1471,7 +1847,7 @@ func (r *TemplateHostRepository) GetByTemplateID(ctx context.Context, templateID
 		th := &models.TemplateHost{}
 		err := rows.Scan(
 			&th.ID, &th.TemplateID, &th.HostID, &th.Role, &th.IsOptional,
-			&th.DisplayOrder, &th.CreatedAt, &th.UpdatedAt)
+			&th.DisplayOrder, &th.Weight, &th.CreatedAt, &th.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -1484,7 +1860,7 @@ func (r *TemplateHostRepository) GetByTemplateID(ctx context.Context, templateID
 func (r *TemplateHostRepository) GetByTemplateIDWithHost(ctx context.Context, templateID string) ([]*models.TemplateHost, error) {
 	query := q(r.driver, `
 		SELECT th.id, th.template_id, th.host_id, th.role, th.is_optional, th.display_order,
-		       th.created_at, th.updated_at,
+		       th.weight, th.created_at, th.updated_at,
 		       h.id, h.tenant_id, h.email, h.name, h.slug, h.timezone,
 		       h.default_calendar_id, h.is_admin, COALESCE(h.onboarding_completed, false),
 		       COALESCE(h.smart_durations, false), h.created_at, h.updated_at
@@ -1505,7 +1881,7 @@ func (r *TemplateHostRepository) GetByTemplateIDWithHost(ctx context.Context, te
 		h := &models.Host{}
 		err := rows.Scan(
 			&th.ID, &th.TemplateID, &th.HostID, &th.Role, &th.IsOptional,
-			&th.DisplayOrder, &th.CreatedAt, &th.UpdatedAt,
+			&th.DisplayOrder, &th.Weight, &th.CreatedAt, &th.UpdatedAt,
 			&h.ID, &h.TenantID, &h.Email, &h.Name, &h.Slug, &h.Timezone,
 			&h.DefaultCalendarID, &h.IsAdmin, &h.OnboardingCompleted,
 			&h.SmartDurations, &h.CreatedAt, &h.UpdatedAt)
@@ -1522,13 +1898,13 @@ func (r *TemplateHostRepository) GetByTemplateIDWithHost(ctx context.Context, te
 func (r *TemplateHostRepository) GetOwner(ctx context.Context, templateID string) (*models.TemplateHost, error) {
 	th := &models.TemplateHost{}
 	query := q(r.driver, `
-		SELECT id, template_id, host_id, role, is_optional, display_order, created_at, updated_at
+		SELECT id, template_id, host_id, role, is_optional, display_order, weight, created_at, updated_at
 		FROM template_hosts
 		WHERE template_id = $1 AND role = 'owner'
 	`)
 	err := r.db.QueryRowContext(ctx, query, templateID).Scan(
 		&th.ID, &th.TemplateID, &th.HostID, &th.Role, &th.IsOptional,
-		&th.DisplayOrder, &th.CreatedAt, &th.UpdatedAt)
+		&th.DisplayOrder, &th.Weight, &th.CreatedAt, &th.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -1539,13 +1915,13 @@ func (r *TemplateHostRepository) GetOwner(ctx context.Context, templateID string
 func (r *TemplateHostRepository) GetByTemplateAndHost(ctx context.Context, templateID, hostID string) (*models.TemplateHost, error) {
 	th := &models.TemplateHost{}
 	query := q(r.driver, `
-		SELECT id, template_id, host_id, role, is_optional, display_order, created_at, updated_at
+		SELECT id, template_id, host_id, role, is_optional, display_order, weight, created_at, updated_at
 		FROM template_hosts
 		WHERE template_id = $1 AND host_id = $2
 	`)
 	err := r.db.QueryRowContext(ctx, query, templateID, hostID).Scan(
 		&th.ID, &th.TemplateID, &th.HostID, &th.Role, &th.IsOptional,
-		&th.DisplayOrder, &th.CreatedAt, &th.UpdatedAt)
+		&th.DisplayOrder, &th.Weight, &th.CreatedAt, &th.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -1556,10 +1932,10 @@ func (r *TemplateHostRepository) GetByTemplateAndHost(ctx context.Context, templ
 func (r *TemplateHostRepository) Update(ctx context.Context, th *models.TemplateHost) error {
 	query := q(r.driver, `
 		UPDATE template_hosts
-		SET is_optional = $1, display_order = $2, updated_at = $3
-		WHERE id = $4
+		SET is_optional = $1, display_order = $2, weight = $3, updated_at = $4
+		WHERE id = $5
 	`)
-	_, err := r.db.ExecContext(ctx, query, th.IsOptional, th.DisplayOrder, th.UpdatedAt, th.ID)
+	_, err := r.db.ExecContext(ctx, query, th.IsOptional, th.DisplayOrder, th.Weight, th.UpdatedAt, th.ID)
 	return err
 }
 
@@ -1627,6 +2003,41 @@ func (r *BookingCalendarEventRepository) DeleteByBookingID(ctx context.Context,
 	return err
 }
 
+// MoveToBooking hands a booking's calendar events over to another booking,
+// as when the seat holding a group slot's shared event is given up.
+func (r *BookingCalendarEventRepository) MoveToBooking(ctx context.Context, fromBookingID, toBookingID string) error {
+	query := q(r.driver, `UPDATE booking_calendar_events SET booking_id = $1 WHERE booking_id = $2`)
+	_, err := r.db.ExecContext(ctx, query, toBookingID, fromBookingID)
+	return err
+}
+
+// ListUpcomingByCalendarID returns the tracking rows on a provider calendar
+// whose booking is confirmed and starts after the given time.
+func (r *BookingCalendarEventRepository) ListUpcomingByCalendarID(ctx context.Context, calendarID string, after time.Time) ([]*models.BookingCalendarEvent, error) {
+	query := q(r.driver, `
+		SELECT e.id, e.booking_id, e.host_id, e.calendar_id, e.event_id, e.created_at
+		FROM booking_calendar_events e
+		JOIN bookings b ON b.id = e.booking_id
+		WHERE e.calendar_id = $1 AND b.status = 'confirmed' AND b.start_time > $2
+		ORDER BY b.start_time ASC
+	`)
+	rows, err := r.db.QueryContext(ctx, query, calendarID, models.NewSQLiteTime(after))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*models.BookingCalendarEvent
+	for rows.Next() {
+		e := &models.BookingCalendarEvent{}
+		if err := rows.Scan(&e.ID, &e.BookingID, &e.HostID, &e.CalendarID, &e.EventID, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
 // Update changes the calendar event ID for a booking_calendar_events row.
 // Used after a CalDAV "update" (which is implemented as delete + create) so
 // the new event ID is persisted.
@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// Connection secrets (OAuth tokens, CalDAV passwords, ICS feed URLs) are
+// sealed with AES-256-GCM before they reach the database and opened again
+// on the way out, so services only ever see plaintext. A sealed value looks
+// like
+//
+//	enc:v<version>:<base64(nonce || ciphertext)>
+//
+// where version names the key it was sealed with. New values always use the
+// current key; older versions stay readable for as long as their key is
+// configured, and ResealSecrets moves them onto the current one.
+
+const secretPrefix = "enc:v"
+
+var ErrSecretKeyMissing = errors.New("secret sealed with an unknown key version")
+
+// SecretBox seals and opens connection secrets. A nil *SecretBox stores
+// values as given, which is what the tests use.
+type SecretBox struct {
+	current int
+	aeads   map[int]cipher.AEAD
+}
+
+// NewSecretBox builds a box that seals with keys[current] and can open
+// anything sealed with any of keys. Keys are arbitrary strings; the AES key
+// is derived from each with HKDF-SHA256, so the same ENCRYPTION_KEY can keep
+// signing cookies without the two uses sharing key material.
+func NewSecretBox(current int, keys map[int]string) (*SecretBox, error) {
+	if _, ok := keys[current]; !ok {
+		return nil, fmt.Errorf("no key configured for current version %d", current)
+	}
+	box := &SecretBox{current: current, aeads: make(map[int]cipher.AEAD, len(keys))}
+	for version, key := range keys {
+		if version < 1 {
+			return nil, fmt.Errorf("invalid key version %d", version)
+		}
+		if key == "" {
+			return nil, fmt.Errorf("empty key for version %d", version)
+		}
+		derived, err := hkdf.Key(sha256.New, []byte(key), nil, "meet-when connection secrets", 32)
+		if err != nil {
+			return nil, err
+		}
+		block, err := aes.NewCipher(derived)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		box.aeads[version] = aead
+	}
+	return box, nil
+}
+
+// Seal encrypts a secret with the current key. Empty values stay empty so
+// "not set" remains distinguishable without a key.
+func (b *SecretBox) Seal(plaintext string) (string, error) {
+	if b == nil || plaintext == "" {
+		return plaintext, nil
+	}
+	aead := b.aeads[b.current]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return secretPrefix + strconv.Itoa(b.current) + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a stored secret. Values without the prefix predate
+// encryption and are returned as-is until ResealSecrets has run.
+func (b *SecretBox) Open(stored string) (string, error) {
+	version, payload, ok := parseSealed(stored)
+	if !ok {
+		return stored, nil
+	}
+	if b == nil {
+		return "", ErrSecretKeyMissing
+	}
+	aead, ok := b.aeads[version]
+	if !ok {
+		return "", fmt.Errorf("%w: v%d", ErrSecretKeyMissing, version)
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(payload)
+	if err != nil || len(raw) < aead.NonceSize() {
+		return "", fmt.Errorf("malformed sealed secret (v%d)", version)
+	}
+	plain, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("open sealed secret (v%d): %w", version, err)
+	}
+	return string(plain), nil
+}
+
+// needsReseal reports whether a stored value is plaintext or sealed with
+// something other than the current key.
+func (b *SecretBox) needsReseal(stored string) bool {
+	if b == nil || stored == "" {
+		return false
+	}
+	version, _, ok := parseSealed(stored)
+	return !ok || version != b.current
+}
+
+func parseSealed(stored string) (version int, payload string, ok bool) {
+	rest, found := strings.CutPrefix(stored, secretPrefix)
+	if !found {
+		return 0, "", false
+	}
+	v, payload, found := strings.Cut(rest, ":")
+	if !found {
+		return 0, "", false
+	}
+	version, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, "", false
+	}
+	return version, payload, true
+}
+
+// openAll decrypts each field in place, stopping at the first failure.
+func (b *SecretBox) openAll(fields ...*string) error {
+	for _, f := range fields {
+		plain, err := b.Open(*f)
+		if err != nil {
+			return err
+		}
+		*f = plain
+	}
+	return nil
+}
+
+// sealAll returns the sealed form of each value, in order.
+func (b *SecretBox) sealAll(values ...string) ([]interface{}, error) {
+	out := make([]interface{}, len(values))
+	for i, v := range values {
+		sealed, err := b.Seal(v)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = sealed
+	}
+	return out, nil
+}
+
+// secretColumns lists the tables and columns holding connection secrets,
+// and the webhook signing secrets sealed alongside them.
+var secretColumns = []struct {
+	table   string
+	columns []string
+}{
+	{"calendar_connections", []string{"access_token", "refresh_token", "caldav_password", "calendar_id", "caldav_url"}},
+	{"conferencing_connections", []string{"access_token", "refresh_token"}},
+	{"webhook_subscriptions", []string{"secret"}},
+}
+
+// ResealSecrets brings every stored secret onto the current key: plaintext
+// left over from before encryption is sealed, and values sealed with a
+// retired key are re-sealed. It runs at startup, before anything else
+// touches the connections, and is a no-op once everything is current, so
+// a retired key can be dropped from the config after one clean start.
+func (r *Repositories) ResealSecrets(ctx context.Context) (int, error) {
+	if r.secrets == nil {
+		return 0, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Printf("Error rolling back transaction: %v", err)
+		}
+	}()
+
+	total := 0
+	for _, sc := range secretColumns {
+		n, err := r.resealTable(ctx, tx, sc.table, sc.columns)
+		if err != nil {
+			return 0, fmt.Errorf("reseal %s: %w", sc.table, err)
+		}
+		total += n
+	}
+	return total, tx.Commit()
+}
+
+func (r *Repositories) resealTable(ctx context.Context, tx *sql.Tx, table string, columns []string) (int, error) {
+	selectCols := make([]string, len(columns))
+	for i, c := range columns {
+		selectCols[i] = "COALESCE(" + c + ", '')"
+	}
+	rows, err := tx.QueryContext(ctx, "SELECT id, "+strings.Join(selectCols, ", ")+" FROM "+table)
+	if err != nil {
+		return 0, err
+	}
+
+	type pending struct {
+		id     string
+		values []string
+	}
+	var stale []pending
+	for rows.Next() {
+		p := pending{values: make([]string, len(columns))}
+		dest := []interface{}{&p.id}
+		for i := range p.values {
+			dest = append(dest, &p.values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		for _, v := range p.values {
+			if r.secrets.needsReseal(v) {
+				stale = append(stale, p)
+				break
+			}
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return 0, err
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	sets := make([]string, len(columns))
+	for i, c := range columns {
+		sets[i] = fmt.Sprintf("%s = $%d", c, i+1)
+	}
+	update := q(r.driver, fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d", table, strings.Join(sets, ", "), len(columns)+1))
+
+	for _, p := range stale {
+		if err := r.secrets.openAll(stringPtrs(p.values)...); err != nil {
+			return 0, fmt.Errorf("row %s: %w", p.id, err)
+		}
+		args, err := r.secrets.sealAll(p.values...)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.ExecContext(ctx, update, append(args, p.id)...); err != nil {
+			return 0, err
+		}
+	}
+	return len(stale), nil
+}
+
+func stringPtrs(values []string) []*string {
+	out := make([]*string, len(values))
+	for i := range values {
+		out[i] = &values[i]
+	}
+	return out
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+func TestSecretBox_SealOpen(t *testing.T) {
+	box, err := NewSecretBox(1, map[int]string{1: "first-key"})
+	if err != nil {
+		t.Fatalf("NewSecretBox: %v", err)
+	}
+
+	sealed, err := box.Seal("ya29.token")
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if !strings.HasPrefix(sealed, "enc:v1:") || strings.Contains(sealed, "ya29") {
+		t.Fatalf("sealed = %q", sealed)
+	}
+	again, _ := box.Seal("ya29.token")
+	if again == sealed {
+		t.Error("sealing twice gave the same ciphertext; nonce not random")
+	}
+	if plain, err := box.Open(sealed); err != nil || plain != "ya29.token" {
+		t.Fatalf("Open = %q, %v", plain, err)
+	}
+
+	// Empty and pre-encryption plaintext pass through untouched.
+	if got, _ := box.Seal(""); got != "" {
+		t.Errorf("Seal(\"\") = %q", got)
+	}
+	if got, err := box.Open("legacy-plaintext"); err != nil || got != "legacy-plaintext" {
+		t.Errorf("Open(plaintext) = %q, %v", got, err)
+	}
+
+	tampered := sealed[:len(sealed)-2] + "AA"
+	if _, err := box.Open(tampered); err == nil {
+		t.Error("Open accepted a tampered value")
+	}
+	other, _ := NewSecretBox(2, map[int]string{2: "second-key"})
+	if _, err := other.Open(sealed); !errors.Is(err, ErrSecretKeyMissing) {
+		t.Errorf("Open with the wrong key version: err = %v", err)
+	}
+
+	if _, err := NewSecretBox(2, map[int]string{1: "first-key"}); err == nil {
+		t.Error("NewSecretBox accepted a current version with no key")
+	}
+}
+
+// TestResealSecrets covers the startup pass: plaintext rows left from before
+// encryption get sealed, and a key rotation re-seals them under the new key.
+func TestResealSecrets(t *testing.T) {
+	db, cleanup := setupTestDB(t, "sqlite")
+	defer cleanup()
+	ctx := context.Background()
+
+	// Rows written before encryption existed.
+	_, conn := seedConnection(t, NewRepositories(db, "sqlite", nil), uuid.New().String()[:8])
+	conn.AccessToken, conn.RefreshToken, conn.CalDAVPassword = "access", "refresh", "app-password"
+	conn.CalDAVURL = "https://calendar.example.com/private-token.ics"
+	if err := NewRepositories(db, "sqlite", nil).Calendar.Update(ctx, conn); err != nil {
+		t.Fatalf("update connection: %v", err)
+	}
+	zoom := &models.ConferencingConnection{
+		ID: uuid.New().String(), HostID: conn.HostID, Provider: models.ConferencingProviderZoom,
+		AccessToken: "zoom-access", RefreshToken: "zoom-refresh",
+		CreatedAt: models.Now(), UpdatedAt: models.Now(),
+	}
+	if err := NewRepositories(db, "sqlite", nil).Conferencing.Create(ctx, zoom); err != nil {
+		t.Fatalf("create conferencing connection: %v", err)
+	}
+
+	stored := func() []string {
+		t.Helper()
+		var a, r, p, id, u, za, zr string
+		if err := db.QueryRow(`SELECT access_token, refresh_token, caldav_password, calendar_id, caldav_url FROM calendar_connections WHERE id = ?`, conn.ID).Scan(&a, &r, &p, &id, &u); err != nil {
+			t.Fatalf("raw select: %v", err)
+		}
+		if err := db.QueryRow(`SELECT access_token, refresh_token FROM conferencing_connections WHERE id = ?`, zoom.ID).Scan(&za, &zr); err != nil {
+			t.Fatalf("raw select: %v", err)
+		}
+		return []string{a, r, p, id, u, za, zr}
+	}
+	expectSealed := func(version string) {
+		t.Helper()
+		for _, v := range stored() {
+			if !strings.HasPrefix(v, "enc:"+version+":") {
+				t.Fatalf("stored value %q, want sealed with %s", v, version)
+			}
+		}
+	}
+	expectReadable := func(repos *Repositories) {
+		t.Helper()
+		got, err := repos.Calendar.GetByID(ctx, conn.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.AccessToken != "access" || got.RefreshToken != "refresh" || got.CalDAVPassword != "app-password" {
+			t.Errorf("calendar secrets = %q %q %q", got.AccessToken, got.RefreshToken, got.CalDAVPassword)
+		}
+		if got.CalendarID != "primary" || got.CalDAVURL != "https://calendar.example.com/private-token.ics" {
+			t.Errorf("calendar URLs = %q %q", got.CalendarID, got.CalDAVURL)
+		}
+		z, err := repos.Conferencing.GetByHostAndProvider(ctx, conn.HostID, models.ConferencingProviderZoom)
+		if err != nil {
+			t.Fatalf("GetByHostAndProvider: %v", err)
+		}
+		if z.AccessToken != "zoom-access" || z.RefreshToken != "zoom-refresh" {
+			t.Errorf("zoom secrets = %q %q", z.AccessToken, z.RefreshToken)
+		}
+	}
+
+	v1, _ := NewSecretBox(1, map[int]string{1: "first-key"})
+	repos := NewRepositories(db, "sqlite", v1)
+	// Plaintext is still readable before the pass has run.
+	expectReadable(repos)
+
+	if n, err := repos.ResealSecrets(ctx); err != nil || n != 2 {
+		t.Fatalf("ResealSecrets = %d, %v; want 2 rows", n, err)
+	}
+	expectSealed("v1")
+	expectReadable(repos)
+	if n, err := repos.ResealSecrets(ctx); err != nil || n != 0 {
+		t.Fatalf("second ResealSecrets = %d, %v; want nothing to do", n, err)
+	}
+
+	// Writes through the repository are sealed too.
+	conn.AccessToken = "access"
+	if err := repos.Calendar.Update(ctx, conn); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	expectSealed("v1")
+
+	// Rotate: v2 is current, v1 kept for reading until the pass re-seals.
+	v2, _ := NewSecretBox(2, map[int]string{1: "first-key", 2: "second-key"})
+	rotated := NewRepositories(db, "sqlite", v2)
+	expectReadable(rotated)
+	if n, err := rotated.ResealSecrets(ctx); err != nil || n != 2 {
+		t.Fatalf("ResealSecrets after rotation = %d, %v; want 2 rows", n, err)
+	}
+	expectSealed("v2")
+
+	// The retired key is no longer needed.
+	v2only, _ := NewSecretBox(2, map[int]string{2: "second-key"})
+	expectReadable(NewRepositories(db, "sqlite", v2only))
+	if _, err := NewRepositories(db, "sqlite", v1).Calendar.GetByID(ctx, conn.ID); !errors.Is(err, ErrSecretKeyMissing) {
+		t.Errorf("reading v2 secrets with only v1: err = %v", err)
+	}
+}
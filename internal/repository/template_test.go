@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// TestTemplate_GetByIDReadsColumnDefaults reads back a template created
+// before start_minutes existed. SQLite hands the column's default back as
+// TEXT, unlike the values the repository writes.
+func TestTemplate_GetByIDReadsColumnDefaults(t *testing.T) {
+	db, cleanup := setupTestDB(t, "sqlite")
+	defer cleanup()
+	ctx := context.Background()
+	repos := NewRepositories(db, "sqlite", nil)
+
+	host, _ := seedConnection(t, repos, uuid.New().String()[:8])
+	tmpl := &models.MeetingTemplate{
+		ID: uuid.New().String(), HostID: host.ID, Slug: "intro", Name: "Intro",
+		Durations: models.IntSlice{30}, LocationType: models.ConferencingProviderGoogleMeet,
+		StartMinutes: models.IntSlice{0, 30}, IsActive: true,
+		CreatedAt: models.Now(), UpdatedAt: models.Now(),
+	}
+	if err := repos.Template.Create(ctx, tmpl); err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+	// What the migration's DEFAULT '[]' leaves on existing rows
+	if _, err := db.Exec(`UPDATE meeting_templates SET start_minutes = '[]' WHERE id = ?`, tmpl.ID); err != nil {
+		t.Fatalf("reset start_minutes: %v", err)
+	}
+
+	got, err := repos.Template.GetByID(ctx, tmpl.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if len(got.StartMinutes) != 0 {
+		t.Errorf("start minutes = %v, want none", got.StartMinutes)
+	}
+}
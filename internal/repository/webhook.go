@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// WebhookSubscriptionRepository stores the URLs booking and hosted event
+// events are posted to. Signing secrets are sealed like connection secrets.
+type WebhookSubscriptionRepository struct {
+	db      *sql.DB
+	driver  string
+	secrets *SecretBox
+}
+
+const webhookSubscriptionSelectColumns = `id, tenant_id, host_id, url, secret, event_types, is_active, created_at, updated_at`
+
+func (r *WebhookSubscriptionRepository) scan(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.WebhookSubscription, error) {
+	s := &models.WebhookSubscription{}
+	if err := scanner.Scan(&s.ID, &s.TenantID, &s.HostID, &s.URL, &s.Secret, &s.EventTypes, &s.IsActive,
+		&s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := r.secrets.openAll(&s.Secret); err != nil {
+		return nil, fmt.Errorf("webhook subscription %s: %w", s.ID, err)
+	}
+	return s, nil
+}
+
+func (r *WebhookSubscriptionRepository) Create(ctx context.Context, s *models.WebhookSubscription) error {
+	query := q(r.driver, `
+		INSERT INTO webhook_subscriptions (id, tenant_id, host_id, url, secret, event_types, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`)
+	sealed, err := r.secrets.sealAll(s.Secret)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, query,
+		s.ID, s.TenantID, s.HostID, s.URL, sealed[0], s.EventTypes, s.IsActive, s.CreatedAt, s.UpdatedAt)
+	return err
+}
+
+// GetByID returns a subscription, or nil if there is none.
+func (r *WebhookSubscriptionRepository) GetByID(ctx context.Context, id string) (*models.WebhookSubscription, error) {
+	query := q(r.driver, `SELECT `+webhookSubscriptionSelectColumns+` FROM webhook_subscriptions WHERE id = $1`)
+	s, err := r.scan(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return s, err
+}
+
+// GetByTenantID returns a tenant's subscriptions, the oldest first. With
+// hostID set, only that host's own.
+func (r *WebhookSubscriptionRepository) GetByTenantID(ctx context.Context, tenantID, hostID string) ([]*models.WebhookSubscription, error) {
+	query := `SELECT ` + webhookSubscriptionSelectColumns + ` FROM webhook_subscriptions WHERE tenant_id = $1`
+	args := []interface{}{tenantID}
+	if hostID != "" {
+		query += ` AND host_id = $2`
+		args = append(args, hostID)
+	}
+	query += ` ORDER BY created_at`
+	return r.list(ctx, q(r.driver, query), args...)
+}
+
+// GetActiveForHost returns the active subscriptions that hear about a
+// host's bookings and events: the tenant's and the host's own.
+func (r *WebhookSubscriptionRepository) GetActiveForHost(ctx context.Context, tenantID, hostID string) ([]*models.WebhookSubscription, error) {
+	query := q(r.driver, `
+		SELECT `+webhookSubscriptionSelectColumns+` FROM webhook_subscriptions
+		WHERE tenant_id = $1 AND is_active = $2 AND (host_id IS NULL OR host_id = $3)
+		ORDER BY created_at
+	`)
+	return r.list(ctx, query, tenantID, true, hostID)
+}
+
+// Update saves a subscription's URL, event types and whether it's active.
+// The secret never changes.
+func (r *WebhookSubscriptionRepository) Update(ctx context.Context, s *models.WebhookSubscription) error {
+	query := q(r.driver, `
+		UPDATE webhook_subscriptions SET url = $1, event_types = $2, is_active = $3, updated_at = $4
+		WHERE id = $5
+	`)
+	_, err := r.db.ExecContext(ctx, query, s.URL, s.EventTypes, s.IsActive, s.UpdatedAt, s.ID)
+	return err
+}
+
+// Delete removes a subscription and its delivery log.
+func (r *WebhookSubscriptionRepository) Delete(ctx context.Context, id string) error {
+	query := q(r.driver, `DELETE FROM webhook_subscriptions WHERE id = $1`)
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *WebhookSubscriptionRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.WebhookSubscription, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []*models.WebhookSubscription
+	for rows.Next() {
+		s, err := r.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, s)
+	}
+	return subs, rows.Err()
+}
+
+// WebhookDeliveryRepository stores each event posted, or waiting to be
+// posted, to each subscription.
+type WebhookDeliveryRepository struct {
+	db     *sql.DB
+	driver string
+}
+
+const webhookDeliverySelectColumns = `id, subscription_id, event_id, event_type, payload, status, attempts,
+	next_attempt_at, response_status, last_error, created_at, updated_at`
+
+func scanWebhookDelivery(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*models.WebhookDelivery, error) {
+	d := &models.WebhookDelivery{}
+	if err := scanner.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.ResponseStatus, &d.LastError, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, d *models.WebhookDelivery) error {
+	query := q(r.driver, `
+		INSERT INTO webhook_deliveries (id, subscription_id, event_id, event_type, payload, status, attempts,
+			next_attempt_at, response_status, last_error, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`)
+	_, err := r.db.ExecContext(ctx, query,
+		d.ID, d.SubscriptionID, d.EventID, d.EventType, d.Payload, d.Status, d.Attempts,
+		d.NextAttemptAt, d.ResponseStatus, d.LastError, d.CreatedAt, d.UpdatedAt)
+	return err
+}
+
+// GetByID returns a delivery, or nil if there is none.
+func (r *WebhookDeliveryRepository) GetByID(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	query := q(r.driver, `SELECT `+webhookDeliverySelectColumns+` FROM webhook_deliveries WHERE id = $1`)
+	d, err := scanWebhookDelivery(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return d, err
+}
+
+// GetBySubscriptionID returns up to limit of a subscription's deliveries,
+// the most recent first.
+func (r *WebhookDeliveryRepository) GetBySubscriptionID(ctx context.Context, subscriptionID string, limit int) ([]*models.WebhookDelivery, error) {
+	query := q(r.driver, `
+		SELECT `+webhookDeliverySelectColumns+` FROM webhook_deliveries
+		WHERE subscription_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`)
+	return r.list(ctx, query, subscriptionID, limit)
+}
+
+// GetDue returns up to limit pending deliveries whose next attempt is at or
+// before now, the longest waiting first.
+func (r *WebhookDeliveryRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	query := q(r.driver, `
+		SELECT `+webhookDeliverySelectColumns+` FROM webhook_deliveries
+		WHERE status = $1 AND next_attempt_at <= $2
+		ORDER BY next_attempt_at
+		LIMIT $3
+	`)
+	return r.list(ctx, query, models.WebhookDeliveryStatusPending, models.NewSQLiteTime(now), limit)
+}
+
+// Claim takes a due delivery for one attempt by pushing its next attempt to
+// leaseUntil, so other workers skip it and it is retried if this one dies
+// mid-post. It reports false if the delivery is no longer due, e.g. because
+// another worker claimed it first.
+func (r *WebhookDeliveryRepository) Claim(ctx context.Context, id string, now, leaseUntil time.Time) (bool, error) {
+	query := q(r.driver, `
+		UPDATE webhook_deliveries SET next_attempt_at = $1, updated_at = $2
+		WHERE id = $3 AND status = $4 AND next_attempt_at <= $5
+	`)
+	result, err := r.db.ExecContext(ctx, query,
+		models.NewSQLiteTime(leaseUntil), models.NewSQLiteTime(now), id, models.WebhookDeliveryStatusPending, models.NewSQLiteTime(now))
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
+
+// Finish records the last attempt of a delivery, successful or not, and
+// stops trying it.
+func (r *WebhookDeliveryRepository) Finish(ctx context.Context, id string, status models.WebhookDeliveryStatus, attempts, responseStatus int, lastError string) error {
+	query := q(r.driver, `
+		UPDATE webhook_deliveries SET status = $1, attempts = $2, response_status = $3, last_error = $4, updated_at = $5
+		WHERE id = $6
+	`)
+	_, err := r.db.ExecContext(ctx, query, status, attempts, responseStatus, lastError, models.Now(), id)
+	return err
+}
+
+// Retry records a failed attempt and when to make the next one.
+func (r *WebhookDeliveryRepository) Retry(ctx context.Context, id string, attempts int, next time.Time, responseStatus int, lastError string) error {
+	query := q(r.driver, `
+		UPDATE webhook_deliveries SET attempts = $1, next_attempt_at = $2, response_status = $3, last_error = $4, updated_at = $5
+		WHERE id = $6
+	`)
+	_, err := r.db.ExecContext(ctx, query, attempts, models.NewSQLiteTime(next), responseStatus, lastError, models.Now(), id)
+	return err
+}
+
+// DeleteFinishedBefore removes the delivered and failed deliveries created
+// before cutoff, and returns how many it removed.
+func (r *WebhookDeliveryRepository) DeleteFinishedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := q(r.driver, `DELETE FROM webhook_deliveries WHERE status <> $1 AND created_at < $2`)
+	result, err := r.db.ExecContext(ctx, query, models.WebhookDeliveryStatusPending, models.NewSQLiteTime(cutoff))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *WebhookDeliveryRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*models.WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
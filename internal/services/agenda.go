@@ -17,6 +17,7 @@ type DayEvents struct {
 	DayStart time.Time
 	DayEnd   time.Time
 	Events   []AgendaEvent
+	Holiday  *HostHoliday // public holiday from the host's set, if any
 }
 
 // WeekView is the view model for a full 7-day week.
@@ -31,11 +32,12 @@ type WeekView struct {
 type AgendaService struct {
 	repos    *repository.Repositories
 	calendar *CalendarService
+	holidays *HolidayService
 }
 
-// NewAgendaService creates a new AgendaService.
-func NewAgendaService(repos *repository.Repositories, calendar *CalendarService) *AgendaService {
-	return &AgendaService{repos: repos, calendar: calendar}
+// NewAgendaService creates a new AgendaService. holidays may be nil.
+func NewAgendaService(repos *repository.Repositories, calendar *CalendarService, holidays *HolidayService) *AgendaService {
+	return &AgendaService{repos: repos, calendar: calendar, holidays: holidays}
 }
 
 // AgendaView is the view model for a single agenda day.
@@ -45,6 +47,7 @@ type AgendaView struct {
 	DayStart  time.Time
 	DayEnd    time.Time
 	HostTZ    *time.Location
+	Holiday   *HostHoliday
 }
 
 // GetDay returns the AgendaView for the given host and date.
@@ -81,12 +84,19 @@ func (s *AgendaService) GetDay(ctx context.Context, hostID string, date time.Tim
 		return a.Start.Compare(b.Start)
 	})
 
+	dateKey := dayStart.Format("2006-01-02")
+	holidays, err := s.hostHolidays(ctx, hostID, dateKey, dateKey)
+	if err != nil {
+		return nil, fmt.Errorf("load holidays: %w", err)
+	}
+
 	return &AgendaView{
 		Calendars: calendars,
 		Events:    events,
 		DayStart:  dayStart,
 		DayEnd:    dayEnd,
 		HostTZ:    loc,
+		Holiday:   holidays[dateKey],
 	}, nil
 }
 
@@ -151,6 +161,14 @@ func (s *AgendaService) GetWeek(ctx context.Context, hostID string, weekStart ti
 
 	assignEventsToBuckets(events, &days, loc)
 
+	holidays, err := s.hostHolidays(ctx, hostID, monday.Format("2006-01-02"), monday.AddDate(0, 0, 6).Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("load holidays: %w", err)
+	}
+	for i := range days {
+		days[i].Holiday = holidays[days[i].DayStart.Format("2006-01-02")]
+	}
+
 	// Sort each day's events by start time.
 	for i := range days {
 		slices.SortFunc(days[i].Events, func(a, b AgendaEvent) int {
@@ -166,6 +184,23 @@ func (s *AgendaService) GetWeek(ctx context.Context, hostID string, weekStart ti
 	}, nil
 }
 
+// hostHolidays returns the host's holidays from from to to (YYYY-MM-DD,
+// inclusive) keyed by date.
+func (s *AgendaService) hostHolidays(ctx context.Context, hostID, from, to string) (map[string]*HostHoliday, error) {
+	byDate := make(map[string]*HostHoliday)
+	if s.holidays == nil {
+		return byDate, nil
+	}
+	holidays, err := s.holidays.GetHostHolidays(ctx, hostID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for i := range holidays {
+		byDate[holidays[i].Date] = &holidays[i]
+	}
+	return byDate, nil
+}
+
 // assignEventsToBuckets assigns each event to every day bucket it overlaps.
 // All-day events (stored as UTC midnight by calendar providers) are
 // re-interpreted in loc so that "2026-04-21 UTC" maps to April 21 locally
@@ -372,6 +372,7 @@ type WeekDayView struct {
 	Blocks            []StripBlock
 	Events            []AgendaEvent // original unclipped events for the detail list
 	EventCount        int
+	Holiday           *HostHoliday
 	IsToday           bool
 	IsActive          bool
 }
@@ -416,6 +417,7 @@ func BuildWeekDayViews(week *WeekView, sharedWindowStart, sharedWindowEnd time.T
 			Blocks:            FlatLane(day.Events, dayWindowStart, dayWindowEnd),
 			Events:            day.Events,
 			EventCount:        len(day.Events),
+			Holiday:           day.Holiday,
 			IsToday:           isToday,
 		}
 
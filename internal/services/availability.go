@@ -2,25 +2,36 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/meet-when/meet-when/internal/models"
 	"github.com/meet-when/meet-when/internal/repository"
 )
 
+// ErrInvalidOverride is returned when an availability override has bad
+// dates or intervals, or names a template or schedule the host doesn't own.
+var ErrInvalidOverride = errors.New("invalid availability override")
+
 // AvailabilityService handles availability calculations
 type AvailabilityService struct {
 	repos    *repository.Repositories
 	calendar *CalendarService
+	holidays *HolidayService
 }
 
-// NewAvailabilityService creates a new availability service
-func NewAvailabilityService(repos *repository.Repositories, calendar *CalendarService) *AvailabilityService {
+// NewAvailabilityService creates a new availability service. holidays may be
+// nil, in which case public holidays never block availability.
+func NewAvailabilityService(repos *repository.Repositories, calendar *CalendarService, holidays *HolidayService) *AvailabilityService {
 	return &AvailabilityService{
 		repos:    repos,
 		calendar: calendar,
+		holidays: holidays,
 	}
 }
 
@@ -49,20 +60,7 @@ func (s *AvailabilityService) GetAvailableSlots(ctx context.Context, input GetAv
 	}
 
 	// Calculate date range
-	now := time.Now()
-	minNotice := time.Duration(template.MinNoticeMinutes) * time.Minute
-	earliestStart := now.Add(minNotice)
-
-	// Ensure start date is not before earliest allowed
-	if input.StartDate.Before(earliestStart) {
-		input.StartDate = earliestStart
-	}
-
-	// Ensure end date is within max schedule days
-	maxEnd := now.AddDate(0, 0, template.MaxScheduleDays)
-	if input.EndDate.After(maxEnd) {
-		input.EndDate = maxEnd
-	}
+	earliestStart := clampToBookingWindow(&input, template)
 
 	// Load pooled hosts for this template
 	pooledHosts, err := s.repos.TemplateHost.GetByTemplateIDWithHost(ctx, input.TemplateID)
@@ -70,6 +68,15 @@ func (s *AvailabilityService) GetAvailableSlots(ctx context.Context, input GetAv
 		return nil, err
 	}
 
+	// Round-robin templates offer any one pooled host's free time
+	if isRoundRobin(template, pooledHosts) {
+		slots, err := s.getRoundRobinSlots(ctx, input, template, pooledHosts, earliestStart)
+		if err != nil {
+			return nil, err
+		}
+		return convertToInviteeTimezone(slots, inviteeLoc), nil
+	}
+
 	// Get required hosts (non-optional) for availability calculation
 	var requiredHosts []*models.TemplateHost
 	for _, th := range pooledHosts {
@@ -78,17 +85,26 @@ func (s *AvailabilityService) GetAvailableSlots(ctx context.Context, input GetAv
 		}
 	}
 
-	// If no pooled hosts or only one required host, use single-host logic
+	// If no pooled hosts or only one required host, use single-host logic.
+	// For pooled templates, compute intersection of all required hosts' availability
+	var slots []models.TimeSlot
 	if len(requiredHosts) <= 1 {
-		slots, err := s.getSingleHostSlots(ctx, input, template, earliestStart)
-		if err != nil {
+		slots, err = s.getSingleHostSlots(ctx, input, template, earliestStart)
+	} else {
+		slots, err = s.getPooledHostSlots(ctx, input, template, requiredHosts, earliestStart)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if template.HasSeats() {
+		if err := s.countSeatsLeft(ctx, template, slots, input.Duration); err != nil {
 			return nil, err
 		}
-		return convertToInviteeTimezone(slots, inviteeLoc), nil
 	}
 
-	// For pooled templates, compute intersection of all required hosts' availability
-	slots, err := s.getPooledHostSlots(ctx, input, template, requiredHosts, earliestStart)
+	// Hide days and weeks that are already fully booked
+	slots, err = s.applyBookingCaps(ctx, input.HostID, template, slots, input.Duration)
 	if err != nil {
 		return nil, err
 	}
@@ -96,6 +112,84 @@ func (s *AvailabilityService) GetAvailableSlots(ctx context.Context, input GetAv
 	return convertToInviteeTimezone(slots, inviteeLoc), nil
 }
 
+// clampToBookingWindow limits input's dates to the template's minimum notice
+// and scheduling window, and returns the earliest time a slot may start.
+func clampToBookingWindow(input *GetAvailableSlotsInput, template *models.MeetingTemplate) time.Time {
+	now := time.Now()
+	minNotice := time.Duration(template.MinNoticeMinutes) * time.Minute
+	earliestStart := now.Add(minNotice)
+
+	// Ensure start date is not before earliest allowed
+	if input.StartDate.Before(earliestStart) {
+		input.StartDate = earliestStart
+	}
+
+	// Ensure end date is within max schedule days
+	maxEnd := now.AddDate(0, 0, template.MaxScheduleDays)
+	if input.EndDate.After(maxEnd) {
+		input.EndDate = maxEnd
+	}
+	return earliestStart
+}
+
+// IsSlotAvailable re-runs the availability calculation around start and
+// reports whether a slot of the given duration still starts exactly there.
+// The window spans a day either side so the slot grid lines up with the one
+// the booking page was built from.
+func (s *AvailabilityService) IsSlotAvailable(ctx context.Context, hostID, templateID string, start time.Time, duration int) (bool, error) {
+	slots, err := s.GetAvailableSlots(ctx, GetAvailableSlotsInput{
+		HostID:     hostID,
+		TemplateID: templateID,
+		StartDate:  start.Add(-24 * time.Hour),
+		EndDate:    start.Add(time.Duration(duration)*time.Minute + 24*time.Hour),
+		Duration:   duration,
+		Timezone:   "UTC",
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, slot := range slots {
+		if slot.Start.Equal(start) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// NearbySlots returns up to limit open slots closest to around, in
+// chronological order and the invitee's timezone. It is what the booking
+// page offers when the slot the invitee picked has just been taken.
+func (s *AvailabilityService) NearbySlots(ctx context.Context, hostID, templateID string, around time.Time, duration int, timezone string, limit int) ([]models.TimeSlot, error) {
+	slots, err := s.GetAvailableSlots(ctx, GetAvailableSlotsInput{
+		HostID:     hostID,
+		TemplateID: templateID,
+		StartDate:  around.AddDate(0, 0, -1),
+		EndDate:    around.AddDate(0, 0, 14),
+		Duration:   duration,
+		Timezone:   timezone,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	distance := func(t time.Time) time.Duration {
+		if d := t.Sub(around); d >= 0 {
+			return d
+		}
+		return around.Sub(t)
+	}
+	sort.SliceStable(slots, func(i, j int) bool {
+		return distance(slots[i].Start) < distance(slots[j].Start)
+	})
+	if len(slots) > limit {
+		slots = slots[:limit]
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i].Start.Before(slots[j].Start)
+	})
+	return slots, nil
+}
+
 // getSingleHostSlots returns available slots for a single host
 func (s *AvailabilityService) getSingleHostSlots(ctx context.Context, input GetAvailableSlotsInput, template *models.MeetingTemplate, earliestStart time.Time) ([]models.TimeSlot, error) {
 	// Load host
@@ -116,56 +210,82 @@ func (s *AvailabilityService) getSingleHostSlots(ctx context.Context, input GetA
 		return nil, err
 	}
 
+	// A template on one of the host's named schedules takes its hours and
+	// timezone from the schedule. Pooled hosts keep their own working hours.
+	var schedule *models.AvailabilitySchedule
+	if template.ScheduleID != "" {
+		schedule, err = s.repos.AvailabilitySchedule.GetByID(ctx, input.HostID, template.ScheduleID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	scheduleID := ""
+	if schedule != nil {
+		scheduleID = schedule.ID
+		workingHours = scheduleWorkingHours(schedule)
+		if loc, err := time.LoadLocation(schedule.Timezone); err == nil {
+			hostLoc = loc
+		}
+	}
+
+	// Get date overrides for this host, template and schedule. Dates are
+	// the host's, so pad the range by a day either side of the UTC window.
+	overrides, err := s.overridesForTemplate(ctx, input.HostID, template.ID, scheduleID, hostLoc, input.StartDate, input.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get existing bookings (as busy times)
 	bookings, err := s.repos.Booking.GetByHostIDAndTimeRange(ctx, input.HostID, input.StartDate, input.EndDate)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get calendar busy times
-	calendarBusy, err := s.calendar.GetBusyTimes(ctx, input.HostID, input.StartDate, input.EndDate)
+	// Get calendar busy times, per calendar for its buffers
+	calendarBusy, err := s.calendar.GetBusyTimesByCalendar(ctx, input.HostID, input.StartDate, input.EndDate)
 	if err != nil {
 		// Log but don't fail - calendar might be disconnected
 		calendarBusy = nil
 	}
 
-	// Combine all busy times
-	var busySlots []models.TimeSlot
-
-	// Add booking busy times (with buffers)
-	for _, b := range bookings {
-		busyStart := b.StartTime.Add(-time.Duration(template.PreBufferMinutes) * time.Minute)
-		busyEnd := b.EndTime.Add(time.Duration(template.PostBufferMinutes) * time.Minute)
-		busySlots = append(busySlots, models.TimeSlot{Start: busyStart, End: busyEnd})
+	// A slot of a template with seats stays open until it's full
+	var seated *seatedSlots
+	if template.HasSeats() {
+		seated, err = s.loadSeatedSlots(ctx, template, input.StartDate, input.EndDate, input.Duration)
+		if err != nil {
+			return nil, err
+		}
+		bookings, calendarBusy = seated.free(bookings, calendarBusy)
 	}
 
-	// Add calendar busy times
-	busySlots = append(busySlots, calendarBusy...)
-
-	// Sort and merge overlapping busy slots
-	busySlots = mergeTimeSlots(busySlots)
+	// Combine all busy times, padded by buffers and the host's minimum gap
+	busySlots, meetings := busyIntervals(host, template, bookings, calendarBusy)
 
 	// Generate available slots
 	duration := time.Duration(input.Duration) * time.Minute
-	slotIncrement := 15 * time.Minute // 15-minute slot intervals
+	grid := templateSlotGrid(template)
 
 	var availableSlots []models.TimeSlot
 
-	// Parse template availability rules
+	// Parse template availability rules, left from before schedules
 	var templateRules *TemplateAvailabilityRules
-	if template.AvailabilityRules != nil {
+	if template.AvailabilityRules != nil && schedule == nil {
 		templateRules = parseAvailabilityRules(template.AvailabilityRules)
 	}
 
 	// Iterate through each day
 	current := input.StartDate.Truncate(24 * time.Hour)
 	for current.Before(input.EndDate) {
-		daySlots := s.getSlotsForDay(current, workingHours, hostLoc, duration, slotIncrement, busySlots, earliestStart, input.EndDate, templateRules)
+		daySlots := s.getSlotsForDay(current, workingHours, hostLoc, duration, grid, busySlots, earliestStart, input.EndDate, templateRules, overrides)
 		availableSlots = append(availableSlots, daySlots...)
 		current = current.AddDate(0, 0, 1)
 	}
+	if seated != nil {
+		availableSlots = seated.keep(availableSlots, host, template)
+		meetings = mergeTimeSlots(append(meetings, seated.open...))
+	}
 
-	return availableSlots, nil
+	return limitBackToBack(availableSlots, meetings, time.Duration(host.MaxBackToBack)*time.Minute), nil
 }
 
 // getPooledHostSlots computes the intersection of availability for multiple required hosts
@@ -349,15 +469,56 @@ func (s *AvailabilityService) getSlotsForDay(
 	workingHours []*models.WorkingHours,
 	hostLoc *time.Location,
 	duration time.Duration,
-	increment time.Duration,
+	grid slotGrid,
 	busySlots []models.TimeSlot,
 	earliestStart time.Time,
 	latestEnd time.Time,
 	templateRules *TemplateAvailabilityRules,
+	overrides []*models.AvailabilityOverride,
 ) []models.TimeSlot {
 	// Get day of week (0=Sunday)
 	dayOfWeek := int(day.In(hostLoc).Weekday())
 
+	// A date override replaces both template rules and working hours
+	if intervals, ok := overrideIntervals(overrides, day.In(hostLoc).Format("2006-01-02")); ok {
+		var slots []models.TimeSlot
+		dayInHostTz := day.In(hostLoc)
+
+		for _, interval := range intervals {
+			startTime, err := time.ParseInLocation("15:04", interval.Start, hostLoc)
+			if err != nil {
+				continue
+			}
+			endTime, err := time.ParseInLocation("15:04", interval.End, hostLoc)
+			if err != nil {
+				continue
+			}
+
+			workStart := time.Date(
+				dayInHostTz.Year(), dayInHostTz.Month(), dayInHostTz.Day(),
+				startTime.Hour(), startTime.Minute(), 0, 0, hostLoc,
+			)
+			workEnd := time.Date(
+				dayInHostTz.Year(), dayInHostTz.Month(), dayInHostTz.Day(),
+				endTime.Hour(), endTime.Minute(), 0, 0, hostLoc,
+			)
+
+			slots = append(slots, s.generateSlotsInRange(workStart, workEnd, duration, grid, busySlots, earliestStart, latestEnd)...)
+		}
+
+		// Intervals from several overrides may overlap
+		sort.Slice(slots, func(i, j int) bool {
+			return slots[i].Start.Before(slots[j].Start)
+		})
+		deduped := slots[:0]
+		for i, slot := range slots {
+			if i == 0 || !slot.Start.Equal(slots[i-1].Start) {
+				deduped = append(deduped, slot)
+			}
+		}
+		return deduped
+	}
+
 	// If template has custom availability rules, use those instead of working hours
 	if templateRules != nil && templateRules.Enabled {
 		// Check if this day is enabled in template rules
@@ -390,7 +551,7 @@ func (s *AvailabilityService) getSlotsForDay(
 				endTime.Hour(), endTime.Minute(), 0, 0, hostLoc,
 			)
 
-			slots = append(slots, s.generateSlotsInRange(workStart, workEnd, duration, increment, busySlots, earliestStart, latestEnd)...)
+			slots = append(slots, s.generateSlotsInRange(workStart, workEnd, duration, grid, busySlots, earliestStart, latestEnd)...)
 		}
 
 		// Sort slots by start time (in case intervals were not in order)
@@ -437,28 +598,121 @@ func (s *AvailabilityService) getSlotsForDay(
 			endTime.Hour(), endTime.Minute(), 0, 0, hostLoc,
 		)
 
-		slots = append(slots, s.generateSlotsInRange(workStart, workEnd, duration, increment, busySlots, earliestStart, latestEnd)...)
+		slots = append(slots, s.generateSlotsInRange(workStart, workEnd, duration, grid, busySlots, earliestStart, latestEnd)...)
 	}
 
 	return slots
 }
 
-// generateSlotsInRange generates available slots within a time range
+// overrideIntervals resolves the overrides covering date (YYYY-MM-DD).
+// Template-scoped overrides take precedence over the template's schedule's,
+// and those over host-wide ones; within a scope, time off wins over custom hours and custom hours are combined.
+// ok is false when no override covers the date.
+func overrideIntervals(overrides []*models.AvailabilityOverride, date string) (intervals []models.TimeRange, ok bool) {
+	var scoped, scheduled, hostWide []*models.AvailabilityOverride
+	for _, o := range overrides {
+		if !o.Covers(date) {
+			continue
+		}
+		switch {
+		case o.TemplateID != nil:
+			scoped = append(scoped, o)
+		case o.ScheduleID != nil:
+			scheduled = append(scheduled, o)
+		default:
+			hostWide = append(hostWide, o)
+		}
+	}
+
+	applicable := scoped
+	if len(applicable) == 0 {
+		applicable = scheduled
+	}
+	if len(applicable) == 0 {
+		applicable = hostWide
+	}
+	if len(applicable) == 0 {
+		return nil, false
+	}
+	for _, o := range applicable {
+		if o.IsTimeOff() {
+			return nil, true
+		}
+		intervals = append(intervals, o.Intervals...)
+	}
+	return intervals, true
+}
+
+// overridesForTemplate loads the host's overrides that can affect slots
+// between start and end: host-wide ones and those scoped to templateID or
+// to scheduleID, the template's schedule if it has one. Blocked public
+// holidays are added as host-wide time off on dates the host has no
+// host-wide override of their own, so custom hours set for a holiday still
+// apply.
+func (s *AvailabilityService) overridesForTemplate(ctx context.Context, hostID, templateID, scheduleID string, hostLoc *time.Location, start, end time.Time) ([]*models.AvailabilityOverride, error) {
+	from := start.In(hostLoc).AddDate(0, 0, -1).Format("2006-01-02")
+	to := end.In(hostLoc).AddDate(0, 0, 1).Format("2006-01-02")
+	all, err := s.repos.AvailabilityOverride.GetByHostIDAndDateRange(ctx, hostID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	var overrides []*models.AvailabilityOverride
+	for _, o := range all {
+		switch {
+		case o.TemplateID != nil:
+			if *o.TemplateID == templateID {
+				overrides = append(overrides, o)
+			}
+		case o.ScheduleID != nil:
+			if *o.ScheduleID == scheduleID {
+				overrides = append(overrides, o)
+			}
+		default:
+			overrides = append(overrides, o)
+		}
+	}
+
+	if s.holidays == nil {
+		return overrides, nil
+	}
+	holidays, err := s.holidays.GetHostHolidays(ctx, hostID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range holidays {
+		if !h.Blocked || slices.ContainsFunc(all, func(o *models.AvailabilityOverride) bool {
+			return o.TemplateID == nil && o.ScheduleID == nil && o.Covers(h.Date)
+		}) {
+			continue
+		}
+		overrides = append(overrides, &models.AvailabilityOverride{
+			HostID:    hostID,
+			StartDate: h.Date,
+			EndDate:   h.Date,
+			Note:      h.Name,
+		})
+	}
+	return overrides, nil
+}
+
+// generateSlotsInRange generates available slots within a time range, starting
+// where the grid allows
 func (s *AvailabilityService) generateSlotsInRange(
 	workStart, workEnd time.Time,
-	duration, increment time.Duration,
+	duration time.Duration,
+	grid slotGrid,
 	busySlots []models.TimeSlot,
 	earliestStart, latestEnd time.Time,
 ) []models.TimeSlot {
 	var slots []models.TimeSlot
 
-	slotStart := workStart
+	slotStart := grid.first(workStart)
 	for slotStart.Add(duration).Before(workEnd) || slotStart.Add(duration).Equal(workEnd) {
 		slotEnd := slotStart.Add(duration)
 
 		// Check constraints
 		if slotStart.Before(earliestStart) || slotEnd.After(latestEnd) {
-			slotStart = slotStart.Add(increment)
+			slotStart = grid.next(slotStart)
 			continue
 		}
 
@@ -470,7 +724,7 @@ func (s *AvailabilityService) generateSlotsInRange(
 			})
 		}
 
-		slotStart = slotStart.Add(increment)
+		slotStart = grid.next(slotStart)
 	}
 
 	return slots
@@ -487,6 +741,64 @@ func slotOverlapsBusy(start, end time.Time, busySlots []models.TimeSlot) bool {
 	return false
 }
 
+// busyIntervals returns the host's busy time for slot generation and the
+// meetings behind it. Bookings are padded by the template's buffers and
+// calendar events by the larger of the host's and their calendar's event
+// buffers, with the host's minimum gap as the least padding on either side.
+// Meetings are unpadded and merged, for limitBackToBack.
+func busyIntervals(host *models.Host, template *models.MeetingTemplate, bookings []*models.Booking, calendars []CalendarBusy) (busy, meetings []models.TimeSlot) {
+	gap := host.MinGapMinutes
+	pad := func(slot models.TimeSlot, before, after int) {
+		meetings = append(meetings, slot)
+		busy = append(busy, models.TimeSlot{
+			Start: slot.Start.Add(-time.Duration(max(before, gap)) * time.Minute),
+			End:   slot.End.Add(time.Duration(max(after, gap)) * time.Minute),
+		})
+	}
+
+	for _, b := range bookings {
+		pad(models.TimeSlot{Start: b.StartTime.Time, End: b.EndTime.Time}, template.PreBufferMinutes, template.PostBufferMinutes)
+	}
+	for _, cb := range calendars {
+		before, after := host.EventBufferBefore, host.EventBufferAfter
+		if cb.Calendar != nil {
+			before, after = max(before, cb.Calendar.BufferBefore), max(after, cb.Calendar.BufferAfter)
+		}
+		for _, slot := range cb.Busy {
+			pad(slot, before, after)
+		}
+	}
+
+	return mergeTimeSlots(busy), mergeTimeSlots(meetings)
+}
+
+// limitBackToBack drops the slots that would join meetings on either side
+// into a run longer than maxRun with no break. meetings must be merged, as
+// mergeTimeSlots returns them. A maxRun of 0 keeps every slot.
+func limitBackToBack(slots, meetings []models.TimeSlot, maxRun time.Duration) []models.TimeSlot {
+	if maxRun <= 0 || len(meetings) == 0 {
+		return slots
+	}
+	var kept []models.TimeSlot
+	for _, slot := range slots {
+		runStart, runEnd := slot.Start, slot.End
+		for _, m := range meetings {
+			if !m.Start.After(slot.Start) && !m.End.Before(slot.Start) {
+				runStart = m.Start
+			}
+			if !m.Start.After(slot.End) && !m.End.Before(slot.End) {
+				runEnd = m.End
+			}
+		}
+		joined := runStart.Before(slot.Start) || runEnd.After(slot.End)
+		if joined && runEnd.Sub(runStart) > maxRun {
+			continue
+		}
+		kept = append(kept, slot)
+	}
+	return kept
+}
+
 // mergeTimeSlots sorts and merges overlapping time slots
 func mergeTimeSlots(slots []models.TimeSlot) []models.TimeSlot {
 	if len(slots) == 0 {
@@ -523,7 +835,194 @@ func (s *AvailabilityService) GetWorkingHours(ctx context.Context, hostID string
 	return s.repos.WorkingHours.GetByHostID(ctx, hostID)
 }
 
+// WorkingWindows returns the stretches of the host's own working hours that
+// fall between start and end, in UTC, after host-wide date overrides and
+// blocked public holidays. Named schedules and template rules don't apply.
+func (s *AvailabilityService) WorkingWindows(ctx context.Context, hostID string, start, end time.Time) ([]models.TimeSlot, error) {
+	host, err := s.repos.Host.GetByID(ctx, hostID)
+	if err != nil || host == nil {
+		return nil, err
+	}
+	hostLoc, err := time.LoadLocation(host.Timezone)
+	if err != nil {
+		hostLoc = time.UTC
+	}
+	workingHours, err := s.repos.WorkingHours.GetByHostID(ctx, hostID)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := s.overridesForTemplate(ctx, hostID, "", "", hostLoc, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var windows []models.TimeSlot
+	local := start.In(hostLoc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, hostLoc)
+	for day.Before(end) {
+		intervals, ok := overrideIntervals(overrides, day.Format("2006-01-02"))
+		if !ok {
+			for _, wh := range workingHours {
+				if wh.IsEnabled && wh.DayOfWeek == int(day.Weekday()) {
+					intervals = append(intervals, models.TimeRange{Start: normalizeTimeHHMM(wh.StartTime), End: normalizeTimeHHMM(wh.EndTime)})
+				}
+			}
+		}
+		for _, interval := range intervals {
+			from, err := time.ParseInLocation("15:04", interval.Start, hostLoc)
+			if err != nil {
+				continue
+			}
+			to, err := time.ParseInLocation("15:04", interval.End, hostLoc)
+			if err != nil {
+				continue
+			}
+			window := models.TimeSlot{
+				Start: time.Date(day.Year(), day.Month(), day.Day(), from.Hour(), from.Minute(), 0, 0, hostLoc).UTC(),
+				End:   time.Date(day.Year(), day.Month(), day.Day(), to.Hour(), to.Minute(), 0, 0, hostLoc).UTC(),
+			}
+			if window.Start.Before(start) {
+				window.Start = start
+			}
+			if window.End.After(end) {
+				window.End = end
+			}
+			if window.Start.Before(window.End) {
+				windows = append(windows, window)
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return mergeTimeSlots(windows), nil
+}
+
 // SetWorkingHours sets working hours for a host
 func (s *AvailabilityService) SetWorkingHours(ctx context.Context, hostID string, hours []*models.WorkingHours) error {
 	return s.repos.WorkingHours.SetForHost(ctx, hostID, hours)
 }
+
+// SetBookingCaps sets the host's daily and weekly booking caps and daily
+// meeting minutes across all of their meeting types. Zero or less turns a
+// cap off.
+func (s *AvailabilityService) SetBookingCaps(ctx context.Context, hostID string, perDay, perWeek, minutesPerDay int) error {
+	return s.repos.Host.UpdateBookingCaps(ctx, hostID, max(perDay, 0), max(perWeek, 0), max(minutesPerDay, 0))
+}
+
+// SetBusyPadding sets the minutes the host keeps free around every calendar
+// event and between meetings, and the longest run of meetings they take
+// without a break. Negative values are treated as zero, which turns each off.
+func (s *AvailabilityService) SetBusyPadding(ctx context.Context, hostID string, eventBefore, eventAfter, minGap, maxBackToBack int) error {
+	return s.repos.Host.UpdateBusyPadding(ctx, hostID, max(eventBefore, 0), max(eventAfter, 0), max(minGap, 0), max(maxBackToBack, 0))
+}
+
+// CreateOverrideInput represents input for adding an availability override
+type CreateOverrideInput struct {
+	TemplateID string // empty applies to every meeting type
+	ScheduleID string // set instead of TemplateID for a schedule's own override
+	StartDate  string // YYYY-MM-DD
+	EndDate    string // YYYY-MM-DD; empty means StartDate
+	Intervals  []models.TimeRange
+	Note       string
+}
+
+// CreateOverride adds a date override or time-off block for a host.
+func (s *AvailabilityService) CreateOverride(ctx context.Context, hostID string, input CreateOverrideInput) (*models.AvailabilityOverride, error) {
+	if input.EndDate == "" {
+		input.EndDate = input.StartDate
+	}
+	start, err := time.Parse("2006-01-02", input.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad start date", ErrInvalidOverride)
+	}
+	end, err := time.Parse("2006-01-02", input.EndDate)
+	if err != nil || end.Before(start) {
+		return nil, fmt.Errorf("%w: bad end date", ErrInvalidOverride)
+	}
+
+	intervals := make(models.TimeRanges, 0, len(input.Intervals))
+	for _, iv := range input.Intervals {
+		from, err1 := time.Parse("15:04", iv.Start)
+		to, err2 := time.Parse("15:04", iv.End)
+		if err1 != nil || err2 != nil || !from.Before(to) {
+			return nil, fmt.Errorf("%w: bad interval %s-%s", ErrInvalidOverride, iv.Start, iv.End)
+		}
+		intervals = append(intervals, models.TimeRange{Start: from.Format("15:04"), End: to.Format("15:04")})
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].Start < intervals[j].Start })
+
+	var templateID *string
+	if input.TemplateID != "" {
+		tmpl, err := s.repos.Template.GetByID(ctx, input.TemplateID)
+		if err != nil {
+			return nil, err
+		}
+		if tmpl == nil {
+			return nil, fmt.Errorf("%w: unknown meeting type", ErrInvalidOverride)
+		}
+		if tmpl.HostID != hostID {
+			// Pooled hosts can scope overrides to templates they're on
+			th, err := s.repos.TemplateHost.GetByTemplateAndHost(ctx, tmpl.ID, hostID)
+			if err != nil {
+				return nil, err
+			}
+			if th == nil {
+				return nil, fmt.Errorf("%w: unknown meeting type", ErrInvalidOverride)
+			}
+		}
+		templateID = &tmpl.ID
+	}
+
+	var scheduleID *string
+	if input.ScheduleID != "" {
+		if templateID != nil {
+			return nil, fmt.Errorf("%w: both a meeting type and a schedule", ErrInvalidOverride)
+		}
+		schedule, err := s.repos.AvailabilitySchedule.GetByID(ctx, hostID, input.ScheduleID)
+		if err != nil {
+			return nil, err
+		}
+		if schedule == nil {
+			return nil, fmt.Errorf("%w: unknown schedule", ErrInvalidOverride)
+		}
+		scheduleID = &schedule.ID
+	}
+
+	now := models.Now()
+	override := &models.AvailabilityOverride{
+		ID:         uuid.New().String(),
+		HostID:     hostID,
+		TemplateID: templateID,
+		ScheduleID: scheduleID,
+		StartDate:  input.StartDate,
+		EndDate:    input.EndDate,
+		Intervals:  intervals,
+		Note:       input.Note,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.repos.AvailabilityOverride.Create(ctx, override); err != nil {
+		return nil, err
+	}
+	return override, nil
+}
+
+// GetUpcomingOverrides returns a host's overrides that haven't ended yet
+// in their timezone.
+func (s *AvailabilityService) GetUpcomingOverrides(ctx context.Context, host *models.Host) ([]*models.AvailabilityOverride, error) {
+	loc, err := time.LoadLocation(host.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return s.repos.AvailabilityOverride.GetUpcomingByHostID(ctx, host.ID, time.Now().In(loc).Format("2006-01-02"))
+}
+
+// GetOverridesInRange returns a host's overrides covering any date from
+// from to to (YYYY-MM-DD, inclusive).
+func (s *AvailabilityService) GetOverridesInRange(ctx context.Context, hostID, from, to string) ([]*models.AvailabilityOverride, error) {
+	return s.repos.AvailabilityOverride.GetByHostIDAndDateRange(ctx, hostID, from, to)
+}
+
+// DeleteOverride removes one of a host's overrides.
+func (s *AvailabilityService) DeleteOverride(ctx context.Context, hostID, id string) error {
+	return s.repos.AvailabilityOverride.Delete(ctx, hostID, id)
+}
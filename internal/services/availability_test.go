@@ -1,9 +1,13 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/meet-when/meet-when/internal/models"
 )
 
@@ -148,7 +152,7 @@ func TestGenerateSlotsInRange_SingleInterval(t *testing.T) {
 	workStart := time.Date(2024, 1, 15, 9, 0, 0, 0, loc)
 	workEnd := time.Date(2024, 1, 15, 12, 0, 0, 0, loc)
 	duration := 30 * time.Minute
-	increment := 15 * time.Minute
+	increment := slotGrid{interval: 15 * time.Minute}
 	earliestStart := time.Date(2024, 1, 15, 0, 0, 0, 0, loc)
 	latestEnd := time.Date(2024, 1, 16, 0, 0, 0, 0, loc)
 
@@ -191,7 +195,7 @@ func TestGenerateSlotsInRange_WithBusyTimes(t *testing.T) {
 	workStart := time.Date(2024, 1, 15, 9, 0, 0, 0, loc)
 	workEnd := time.Date(2024, 1, 15, 11, 0, 0, 0, loc)
 	duration := 30 * time.Minute
-	increment := 30 * time.Minute
+	increment := slotGrid{interval: 30 * time.Minute}
 	earliestStart := time.Date(2024, 1, 15, 0, 0, 0, 0, loc)
 	latestEnd := time.Date(2024, 1, 16, 0, 0, 0, 0, loc)
 
@@ -228,7 +232,7 @@ func TestGenerateSlotsInRange_EarliestStartConstraint(t *testing.T) {
 	workStart := time.Date(2024, 1, 15, 9, 0, 0, 0, loc)
 	workEnd := time.Date(2024, 1, 15, 11, 0, 0, 0, loc)
 	duration := 30 * time.Minute
-	increment := 30 * time.Minute
+	increment := slotGrid{interval: 30 * time.Minute}
 	earliestStart := time.Date(2024, 1, 15, 10, 0, 0, 0, loc) // Can only start at 10:00 or later
 	latestEnd := time.Date(2024, 1, 16, 0, 0, 0, 0, loc)
 
@@ -249,13 +253,68 @@ func TestGenerateSlotsInRange_EarliestStartConstraint(t *testing.T) {
 	}
 }
 
+func TestGenerateSlotsInRange_SlotGrid(t *testing.T) {
+	svc := &AvailabilityService{}
+	loc, _ := time.LoadLocation("America/New_York")
+
+	// Hours of 9:10 to 12:00 and a 45-minute meeting
+	workStart := time.Date(2024, 1, 15, 9, 10, 0, 0, loc)
+	workEnd := time.Date(2024, 1, 15, 12, 0, 0, 0, loc)
+	duration := 45 * time.Minute
+	earliestStart := time.Date(2024, 1, 15, 0, 0, 0, 0, loc)
+	latestEnd := time.Date(2024, 1, 16, 0, 0, 0, 0, loc)
+
+	tests := []struct {
+		name string
+		grid slotGrid
+		want []string
+	}{
+		{"default interval", slotGrid{interval: 15 * time.Minute},
+			[]string{"09:10", "09:25", "09:40", "09:55", "10:10", "10:25", "10:40", "10:55", "11:10"}},
+		{"interval from start of hours", slotGrid{interval: 30 * time.Minute},
+			[]string{"09:10", "09:40", "10:10", "10:40", "11:10"}},
+		{"aligned to the clock", slotGrid{interval: 30 * time.Minute, align: true},
+			[]string{"09:30", "10:00", "10:30", "11:00"}},
+		{"aligned hourly", slotGrid{interval: time.Hour, align: true},
+			[]string{"10:00", "11:00"}},
+		{"specific minutes", slotGrid{interval: 15 * time.Minute, minutes: []int{0, 50}},
+			[]string{"09:50", "10:00", "10:50", "11:00"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slots := svc.generateSlotsInRange(workStart, workEnd, duration, tt.grid, nil, earliestStart, latestEnd)
+			var got []string
+			for _, slot := range slots {
+				got = append(got, slot.Start.In(loc).Format("15:04"))
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("starts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeSlotSettings(t *testing.T) {
+	interval, minutes := normalizeSlotSettings(25, []int{30, 0, 30, 60, -5})
+	if interval != 0 {
+		t.Errorf("interval = %d, want 0 for one not offered", interval)
+	}
+	if len(minutes) != 2 || minutes[0] != 0 || minutes[1] != 30 {
+		t.Errorf("minutes = %v, want [0 30]", minutes)
+	}
+	if interval, _ := normalizeSlotSettings(45, nil); interval != 45 {
+		t.Errorf("interval = %d, want 45", interval)
+	}
+}
+
 func TestGetSlotsForDay_MultipleIntervals(t *testing.T) {
 	svc := &AvailabilityService{}
 	loc, _ := time.LoadLocation("UTC")
 
 	day := time.Date(2024, 1, 15, 0, 0, 0, 0, loc) // Monday
 	duration := 30 * time.Minute
-	increment := 30 * time.Minute
+	increment := slotGrid{interval: 30 * time.Minute}
 	earliestStart := time.Date(2024, 1, 15, 0, 0, 0, 0, loc)
 	latestEnd := time.Date(2024, 1, 16, 0, 0, 0, 0, loc)
 
@@ -273,7 +332,7 @@ func TestGetSlotsForDay_MultipleIntervals(t *testing.T) {
 		},
 	}
 
-	slots := svc.getSlotsForDay(day, nil, loc, duration, increment, nil, earliestStart, latestEnd, templateRules)
+	slots := svc.getSlotsForDay(day, nil, loc, duration, increment, nil, earliestStart, latestEnd, templateRules, nil)
 
 	// Two 1-hour intervals with 30min slots = 2 + 2 = 4 slots
 	expectedCount := 4
@@ -312,7 +371,7 @@ func TestGetSlotsForDay_EmptyIntervals(t *testing.T) {
 
 	day := time.Date(2024, 1, 15, 0, 0, 0, 0, loc) // Monday
 	duration := 30 * time.Minute
-	increment := 30 * time.Minute
+	increment := slotGrid{interval: 30 * time.Minute}
 	earliestStart := time.Date(2024, 1, 15, 0, 0, 0, 0, loc)
 	latestEnd := time.Date(2024, 1, 16, 0, 0, 0, 0, loc)
 
@@ -327,7 +386,7 @@ func TestGetSlotsForDay_EmptyIntervals(t *testing.T) {
 		},
 	}
 
-	slots := svc.getSlotsForDay(day, nil, loc, duration, increment, nil, earliestStart, latestEnd, templateRules)
+	slots := svc.getSlotsForDay(day, nil, loc, duration, increment, nil, earliestStart, latestEnd, templateRules, nil)
 
 	if len(slots) != 0 {
 		t.Errorf("Expected 0 slots for empty intervals, got %d", len(slots))
@@ -340,7 +399,7 @@ func TestGetSlotsForDay_DayDisabled(t *testing.T) {
 
 	day := time.Date(2024, 1, 15, 0, 0, 0, 0, loc) // Monday
 	duration := 30 * time.Minute
-	increment := 30 * time.Minute
+	increment := slotGrid{interval: 30 * time.Minute}
 	earliestStart := time.Date(2024, 1, 15, 0, 0, 0, 0, loc)
 	latestEnd := time.Date(2024, 1, 16, 0, 0, 0, 0, loc)
 
@@ -357,7 +416,7 @@ func TestGetSlotsForDay_DayDisabled(t *testing.T) {
 		},
 	}
 
-	slots := svc.getSlotsForDay(day, nil, loc, duration, increment, nil, earliestStart, latestEnd, templateRules)
+	slots := svc.getSlotsForDay(day, nil, loc, duration, increment, nil, earliestStart, latestEnd, templateRules, nil)
 
 	if len(slots) != 0 {
 		t.Errorf("Expected 0 slots for disabled day, got %d", len(slots))
@@ -370,7 +429,7 @@ func TestGetSlotsForDay_FallsBackToWorkingHours(t *testing.T) {
 
 	day := time.Date(2024, 1, 15, 0, 0, 0, 0, loc) // Monday
 	duration := 30 * time.Minute
-	increment := 30 * time.Minute
+	increment := slotGrid{interval: 30 * time.Minute}
 	earliestStart := time.Date(2024, 1, 15, 0, 0, 0, 0, loc)
 	latestEnd := time.Date(2024, 1, 16, 0, 0, 0, 0, loc)
 
@@ -384,7 +443,7 @@ func TestGetSlotsForDay_FallsBackToWorkingHours(t *testing.T) {
 	}
 
 	// No template rules - should fall back to working hours
-	slots := svc.getSlotsForDay(day, workingHours, loc, duration, increment, nil, earliestStart, latestEnd, nil)
+	slots := svc.getSlotsForDay(day, workingHours, loc, duration, increment, nil, earliestStart, latestEnd, nil, nil)
 
 	// 1 hour with 30min slots = 2 slots
 	expectedCount := 2
@@ -399,7 +458,7 @@ func TestGetSlotsForDay_TemplateRulesDisabled(t *testing.T) {
 
 	day := time.Date(2024, 1, 15, 0, 0, 0, 0, loc) // Monday
 	duration := 30 * time.Minute
-	increment := 30 * time.Minute
+	increment := slotGrid{interval: 30 * time.Minute}
 	earliestStart := time.Date(2024, 1, 15, 0, 0, 0, 0, loc)
 	latestEnd := time.Date(2024, 1, 16, 0, 0, 0, 0, loc)
 
@@ -425,7 +484,7 @@ func TestGetSlotsForDay_TemplateRulesDisabled(t *testing.T) {
 		},
 	}
 
-	slots := svc.getSlotsForDay(day, workingHours, loc, duration, increment, nil, earliestStart, latestEnd, templateRules)
+	slots := svc.getSlotsForDay(day, workingHours, loc, duration, increment, nil, earliestStart, latestEnd, templateRules, nil)
 
 	// Should use working hours (9-10), not template rules (14-17)
 	expectedCount := 2
@@ -441,6 +500,90 @@ func TestGetSlotsForDay_TemplateRulesDisabled(t *testing.T) {
 	}
 }
 
+func TestGetSlotsForDay_DateOverrides(t *testing.T) {
+	svc := &AvailabilityService{}
+	loc, _ := time.LoadLocation("America/New_York")
+
+	// Noon UTC keeps the host's date on the 15th
+	day := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC) // Monday
+	duration := 30 * time.Minute
+	increment := slotGrid{interval: 30 * time.Minute}
+	earliestStart := time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)
+	latestEnd := time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC)
+
+	workingHours := []*models.WorkingHours{
+		{DayOfWeek: 1, StartTime: "09:00", EndTime: "10:00", IsEnabled: true},
+	}
+	templateRules := &TemplateAvailabilityRules{
+		Enabled: true,
+		Days: map[int]DayAvailability{
+			1: {Enabled: true, Intervals: []TimeInterval{{Start: "09:00", End: "17:00"}}},
+		},
+	}
+	templateID := "tmpl-1"
+
+	tests := []struct {
+		name      string
+		overrides []*models.AvailabilityOverride
+		rules     *TemplateAvailabilityRules
+		want      []string // slot starts, host time
+	}{
+		{
+			name:      "time off range covering the day",
+			overrides: []*models.AvailabilityOverride{{StartDate: "2024-01-14", EndDate: "2024-01-16"}},
+			want:      nil,
+		},
+		{
+			name: "custom hours replace working hours",
+			overrides: []*models.AvailabilityOverride{{StartDate: "2024-01-15", EndDate: "2024-01-15",
+				Intervals: models.TimeRanges{{Start: "14:00", End: "15:00"}}}},
+			want: []string{"14:00", "14:30"},
+		},
+		{
+			name: "custom hours replace template rules too",
+			overrides: []*models.AvailabilityOverride{{StartDate: "2024-01-15", EndDate: "2024-01-15",
+				Intervals: models.TimeRanges{{Start: "18:00", End: "19:00"}}}},
+			rules: templateRules,
+			want:  []string{"18:00", "18:30"},
+		},
+		{
+			name:      "override on another date is ignored",
+			overrides: []*models.AvailabilityOverride{{StartDate: "2024-01-16", EndDate: "2024-01-16"}},
+			want:      []string{"09:00", "09:30"},
+		},
+		{
+			name: "template scope beats host-wide time off",
+			overrides: []*models.AvailabilityOverride{
+				{StartDate: "2024-01-15", EndDate: "2024-01-15"},
+				{StartDate: "2024-01-15", EndDate: "2024-01-15", TemplateID: &templateID,
+					Intervals: models.TimeRanges{{Start: "10:00", End: "10:30"}}},
+			},
+			want: []string{"10:00"},
+		},
+		{
+			name: "overlapping custom hours are combined without duplicates",
+			overrides: []*models.AvailabilityOverride{
+				{StartDate: "2024-01-15", EndDate: "2024-01-15", Intervals: models.TimeRanges{{Start: "09:00", End: "10:00"}}},
+				{StartDate: "2024-01-15", EndDate: "2024-01-15", Intervals: models.TimeRanges{{Start: "09:30", End: "10:30"}}},
+			},
+			want: []string{"09:00", "09:30", "10:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slots := svc.getSlotsForDay(day, workingHours, loc, duration, increment, nil, earliestStart, latestEnd, tt.rules, tt.overrides)
+			var got []string
+			for _, slot := range slots {
+				got = append(got, slot.Start.In(loc).Format("15:04"))
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("slots = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestMergeTimeSlots(t *testing.T) {
 	loc, _ := time.LoadLocation("UTC")
 
@@ -490,6 +633,91 @@ func TestMergeTimeSlots(t *testing.T) {
 	}
 }
 
+func TestBusyIntervals_Padding(t *testing.T) {
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	slot := func(h1, m1, h2, m2 int) models.TimeSlot { return models.TimeSlot{Start: at(h1, m1), End: at(h2, m2)} }
+	format := func(slots []models.TimeSlot) string {
+		var out []string
+		for _, s := range slots {
+			out = append(out, s.Start.Format("15:04")+"-"+s.End.Format("15:04"))
+		}
+		return strings.Join(out, " ")
+	}
+
+	template := &models.MeetingTemplate{PreBufferMinutes: 5, PostBufferMinutes: 10}
+	bookings := []*models.Booking{{
+		StartTime: models.NewSQLiteTime(at(9, 0)),
+		EndTime:   models.NewSQLiteTime(at(10, 0)),
+	}}
+	work := &models.ProviderCalendar{ID: "work", BufferAfter: 10}
+	personal := &models.ProviderCalendar{ID: "personal"}
+	calendars := []CalendarBusy{
+		{Calendar: work, Busy: []models.TimeSlot{slot(12, 0, 13, 0)}},
+		{Calendar: personal, Busy: []models.TimeSlot{slot(15, 0, 15, 30)}},
+	}
+
+	tests := []struct {
+		name string
+		host *models.Host
+		want string
+	}{
+		{"calendar buffers only", &models.Host{},
+			"08:55-10:10 12:00-13:10 15:00-15:30"},
+		{"host event buffers, larger of host and calendar", &models.Host{EventBufferBefore: 15, EventBufferAfter: 5},
+			"08:55-10:10 11:45-13:10 14:45-15:35"},
+		{"minimum gap", &models.Host{MinGapMinutes: 20},
+			"08:40-10:20 11:40-13:20 14:40-15:50"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			busy, meetings := busyIntervals(tt.host, template, bookings, calendars)
+			if got := format(busy); got != tt.want {
+				t.Errorf("busy = %s, want %s", got, tt.want)
+			}
+			if got := format(meetings); got != "09:00-10:00 12:00-13:00 15:00-15:30" {
+				t.Errorf("meetings = %s, want them unpadded", got)
+			}
+		})
+	}
+}
+
+func TestLimitBackToBack(t *testing.T) {
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	slot := func(h1, m1, h2, m2 int) models.TimeSlot {
+		return models.TimeSlot{
+			Start: day.Add(time.Duration(h1)*time.Hour + time.Duration(m1)*time.Minute),
+			End:   day.Add(time.Duration(h2)*time.Hour + time.Duration(m2)*time.Minute),
+		}
+	}
+	// Meetings 9:00-10:30 and 11:00-12:00
+	meetings := []models.TimeSlot{slot(9, 0, 10, 30), slot(11, 0, 12, 0)}
+	slots := []models.TimeSlot{
+		slot(8, 0, 9, 0),    // makes a 2.5h run
+		slot(8, 30, 9, 0),   // makes a 2h run
+		slot(10, 30, 11, 0), // joins both meetings into 3h
+		slot(12, 0, 12, 30), // makes a 1.5h run
+		slot(13, 0, 16, 0),  // a long meeting on its own
+	}
+
+	tests := []struct {
+		maxRun time.Duration
+		want   int
+	}{
+		{0, 5},
+		{2 * time.Hour, 3},
+		{3 * time.Hour, 5},
+		{time.Hour, 1},
+	}
+	for _, tt := range tests {
+		if got := limitBackToBack(slots, meetings, tt.maxRun); len(got) != tt.want {
+			t.Errorf("maxRun %v: kept %d slots, want %d: %v", tt.maxRun, len(got), tt.want, got)
+		}
+	}
+}
+
 func TestSlotOverlapsBusy(t *testing.T) {
 	loc, _ := time.LoadLocation("UTC")
 
@@ -565,3 +793,77 @@ func TestSlotOverlapsBusy(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateOverride_AppliesToAvailableSlots(t *testing.T) {
+	bookings, fix, cleanup := makeCreateBookingService(t)
+	defer cleanup()
+	ctx := context.Background()
+	svc := bookings.availability
+	owner := fix.hostIDs[0]
+
+	day := time.Now().UTC().AddDate(0, 0, 3).Truncate(24 * time.Hour)
+	date := day.Format("2006-01-02")
+	slotsOn := func() []models.TimeSlot {
+		t.Helper()
+		slots, err := svc.GetAvailableSlots(ctx, GetAvailableSlotsInput{
+			HostID: owner, TemplateID: fix.templateID,
+			StartDate: day, EndDate: day.Add(24 * time.Hour), Duration: 30, Timezone: "UTC",
+		})
+		if err != nil {
+			t.Fatalf("GetAvailableSlots: %v", err)
+		}
+		return slots
+	}
+
+	if _, err := svc.CreateOverride(ctx, owner, CreateOverrideInput{
+		StartDate: date, Intervals: []models.TimeRange{{Start: "13:00", End: "14:00"}},
+	}); err != nil {
+		t.Fatalf("CreateOverride: %v", err)
+	}
+	slots := slotsOn()
+	if len(slots) != 3 || slots[0].Start.Hour() != 13 || slots[2].End.Hour() != 14 {
+		t.Fatalf("slots = %v, want 13:00 to 14:00", slots)
+	}
+
+	// Time off scoped to another meeting type leaves this one alone
+	other := &models.MeetingTemplate{
+		ID: uuid.New().String(), HostID: owner, Slug: "other-" + uuid.New().String()[:6], Name: "Other",
+		Durations: models.IntSlice{30}, LocationType: models.ConferencingProviderGoogleMeet,
+		MaxScheduleDays: 30, IsActive: true, CreatedAt: models.Now(), UpdatedAt: models.Now(),
+	}
+	if err := bookings.repos.Template.Create(ctx, other); err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+	if _, err := svc.CreateOverride(ctx, owner, CreateOverrideInput{TemplateID: other.ID, StartDate: date}); err != nil {
+		t.Fatalf("CreateOverride scoped: %v", err)
+	}
+	if got := len(slotsOn()); got != 3 {
+		t.Errorf("another template's time off changed this one: %d slots", got)
+	}
+
+	// Time off scoped to this template wins over the host-wide hours
+	off, err := svc.CreateOverride(ctx, owner, CreateOverrideInput{TemplateID: fix.templateID, StartDate: date})
+	if err != nil {
+		t.Fatalf("CreateOverride scoped: %v", err)
+	}
+	if got := len(slotsOn()); got != 0 {
+		t.Errorf("time off left %d slots", got)
+	}
+	if err := svc.DeleteOverride(ctx, owner, off.ID); err != nil {
+		t.Fatalf("DeleteOverride: %v", err)
+	}
+	if got := len(slotsOn()); got != 3 {
+		t.Errorf("after delete: %d slots, want 3", got)
+	}
+
+	for _, bad := range []CreateOverrideInput{
+		{StartDate: "2024-13-01"},
+		{StartDate: date, EndDate: day.AddDate(0, 0, -1).Format("2006-01-02")},
+		{StartDate: date, Intervals: []models.TimeRange{{Start: "15:00", End: "14:00"}}},
+		{StartDate: date, TemplateID: uuid.New().String()},
+	} {
+		if _, err := svc.CreateOverride(ctx, owner, bad); !errors.Is(err, ErrInvalidOverride) {
+			t.Errorf("CreateOverride(%+v): err = %v, want ErrInvalidOverride", bad, err)
+		}
+	}
+}
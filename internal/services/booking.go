@@ -19,6 +19,7 @@ var (
 	ErrSlotNotAvailable   = errors.New("selected time slot is no longer available")
 	ErrInvalidBookingTime = errors.New("invalid booking time")
 	ErrBookingCancelled   = errors.New("booking has already been cancelled")
+	ErrInvalidInvitee     = errors.New("invitee name and a valid email are required")
 )
 
 // calculateSmartDuration adjusts a meeting duration to end early, giving buffer time.
@@ -41,11 +42,13 @@ type BookingService struct {
 	cfg          *config.Config
 	repos        *repository.Repositories
 	calendar     *CalendarService
+	availability *AvailabilityService
 	syncer       *CalendarEventSyncer
 	conferencing *ConferencingService
 	email        *EmailService
 	auditLog     *AuditLogService
 	contact      *ContactService
+	webhooks     *WebhookService
 }
 
 // NewBookingService creates a new booking service
@@ -53,21 +56,25 @@ func NewBookingService(
 	cfg *config.Config,
 	repos *repository.Repositories,
 	calendar *CalendarService,
+	availability *AvailabilityService,
 	syncer *CalendarEventSyncer,
 	conferencing *ConferencingService,
 	email *EmailService,
 	auditLog *AuditLogService,
 	contact *ContactService,
+	webhooks *WebhookService,
 ) *BookingService {
 	return &BookingService{
 		cfg:          cfg,
 		repos:        repos,
 		calendar:     calendar,
+		availability: availability,
 		syncer:       syncer,
 		conferencing: conferencing,
 		email:        email,
 		auditLog:     auditLog,
 		contact:      contact,
+		webhooks:     webhooks,
 	}
 }
 
@@ -92,6 +99,7 @@ type BookingWithDetails struct {
 	Template *models.MeetingTemplate
 	Host     *models.Host
 	Tenant   *models.Tenant
+	Seats    []*models.Booking // confirmed seats sharing the booking's calendar event, for templates with seats
 }
 
 // CreateBooking creates a new booking request
@@ -102,6 +110,12 @@ func (s *BookingService) CreateBooking(ctx context.Context, input CreateBookingI
 		return nil, ErrTemplateNotFound
 	}
 
+	input.InviteeName = strings.TrimSpace(input.InviteeName)
+	input.InviteeEmail = strings.TrimSpace(input.InviteeEmail)
+	if input.InviteeName == "" || !isValidEmail(input.InviteeEmail) {
+		return nil, ErrInvalidInvitee
+	}
+
 	// Validate duration is allowed
 	validDuration := false
 	for _, d := range template.Durations {
@@ -131,8 +145,15 @@ func (s *BookingService) CreateBooking(ctx context.Context, input CreateBookingI
 		return nil, ErrInvalidBookingTime
 	}
 
-	// TODO: Validate slot is still available (race condition check)
-	// For MVP, we'll rely on the calendar event creation to catch conflicts
+	// Re-check the slot against working hours, calendars and bookings made
+	// since the page was loaded.
+	available, err := s.availability.IsSlotAvailable(ctx, input.HostID, input.TemplateID, input.StartTime, input.Duration)
+	if err != nil {
+		return nil, err
+	}
+	if !available {
+		return nil, ErrSlotNotAvailable
+	}
 
 	// Generate booking token
 	token, err := generateToken(32)
@@ -166,9 +187,39 @@ func (s *BookingService) CreateBooking(ctx context.Context, input CreateBookingI
 		UpdatedAt:        now,
 	}
 
-	if err := s.repos.Booking.Create(ctx, booking); err != nil {
+	// The check above can race another invitee picking the same slot; the
+	// insert re-checks the booked hosts for overlaps and the booking caps
+	// under a lock.
+	pooled, err := s.repos.TemplateHost.GetByTemplateID(ctx, input.TemplateID)
+	if err != nil {
 		return nil, err
 	}
+	if isRoundRobin(template, pooled) {
+		// The booking belongs to whichever pooled host it is assigned to
+		assigned, err := s.createRoundRobinBooking(ctx, input.TenantID, booking, template, pooled)
+		if err != nil {
+			return nil, err
+		}
+		host = assigned
+	} else {
+		minGap := 0
+		if host != nil {
+			minGap = host.MinGapMinutes
+		}
+		check := repository.SlotCheck{
+			HostIDs: slotHostIDs(pooled, input.HostID),
+			Before:  time.Duration(max(template.PreBufferMinutes, minGap)) * time.Minute,
+			After:   time.Duration(max(template.PostBufferMinutes, minGap)) * time.Minute,
+			Caps:    bookingCaps(host, template, input.StartTime),
+			Seats:   template.Seats,
+		}
+		if err := s.repos.Booking.CreateIfSlotFree(ctx, booking, check); err != nil {
+			if errors.Is(err, repository.ErrBookingConflict) || errors.Is(err, repository.ErrBookingCapReached) {
+				return nil, ErrSlotNotAvailable
+			}
+			return nil, err
+		}
+	}
 
 	details := &BookingWithDetails{
 		Booking:  booking,
@@ -192,10 +243,27 @@ func (s *BookingService) CreateBooking(ctx context.Context, input CreateBookingI
 		"invitee_email": input.InviteeEmail,
 		"status":        string(status),
 	}, "")
+	s.webhooks.EmitBooking(ctx, models.WebhookEventBookingCreated, details, time.Time{}, nil)
 
 	return details, nil
 }
 
+// slotHostIDs returns the hosts whose time a booking of a collective template
+// takes: the required pooled hosts when there are several, otherwise just the
+// owner. It mirrors which hosts GetAvailableSlots checks.
+func slotHostIDs(pooled []*models.TemplateHost, ownerID string) []string {
+	var required []string
+	for _, th := range pooled {
+		if !th.IsOptional {
+			required = append(required, th.HostID)
+		}
+	}
+	if len(required) <= 1 {
+		return []string{ownerID}
+	}
+	return required
+}
+
 // ApproveBooking approves a pending booking
 func (s *BookingService) ApproveBooking(ctx context.Context, hostID, tenantID, bookingID string) (*BookingWithDetails, error) {
 	booking, err := s.repos.Booking.GetByID(ctx, bookingID)
@@ -232,6 +300,7 @@ func (s *BookingService) ApproveBooking(ctx context.Context, hostID, tenantID, b
 
 	// Audit log
 	s.auditLog.Log(ctx, tenantID, &hostID, "booking.approved", "booking", bookingID, nil, "")
+	s.webhooks.EmitBooking(ctx, models.WebhookEventBookingApproved, details, time.Time{}, nil)
 
 	return details, nil
 }
@@ -273,6 +342,7 @@ func (s *BookingService) RejectBooking(ctx context.Context, hostID, tenantID, bo
 	s.auditLog.Log(ctx, tenantID, &hostID, "booking.rejected", "booking", bookingID, models.JSONMap{
 		"reason": reason,
 	}, "")
+	s.webhooks.EmitBooking(ctx, models.WebhookEventBookingRejected, details, time.Time{}, nil)
 
 	return nil
 }
@@ -288,6 +358,7 @@ func (s *BookingService) CancelBooking(ctx context.Context, bookingID, cancelled
 		return ErrBookingCancelled
 	}
 
+	wasConfirmed := booking.Status == models.BookingStatusConfirmed
 	booking.Status = models.BookingStatusCancelled
 	booking.CancelledBy = cancelledBy
 	booking.CancelReason = reason
@@ -296,9 +367,6 @@ func (s *BookingService) CancelBooking(ctx context.Context, bookingID, cancelled
 		return err
 	}
 
-	// Delete calendar events for all pooled hosts
-	s.deleteAllCalendarEvents(ctx, booking)
-
 	// Get related entities and send cancellation email
 	template, _ := s.repos.Template.GetByID(ctx, booking.TemplateID)
 	host, _ := s.repos.Host.GetByID(ctx, booking.HostID)
@@ -311,6 +379,18 @@ func (s *BookingService) CancelBooking(ctx context.Context, bookingID, cancelled
 		Tenant:   tenant,
 	}
 
+	// Delete calendar events for all pooled hosts. A seat only leaves the
+	// event it shares with the rest of its slot.
+	if template != nil && template.HasSeats() {
+		if wasConfirmed {
+			if err := s.syncSeatEvent(ctx, details, booking.StartTime.Time, booking.Duration); err != nil {
+				log.Printf("[BOOKING] Error updating shared calendar event for booking %s: %v", booking.ID, err)
+			}
+		}
+	} else {
+		s.deleteAllCalendarEvents(ctx, booking)
+	}
+
 	s.email.SendBookingCancelled(ctx, details)
 
 	// Audit log
@@ -318,6 +398,7 @@ func (s *BookingService) CancelBooking(ctx context.Context, bookingID, cancelled
 		"cancelled_by": cancelledBy,
 		"reason":       reason,
 	}, "")
+	s.webhooks.EmitBooking(ctx, models.WebhookEventBookingCancelled, details, time.Time{}, nil)
 
 	return nil
 }
@@ -466,6 +547,7 @@ func (s *BookingService) UpdateBooking(ctx context.Context, input UpdateBookingI
 	s.auditLog.Log(ctx, tenantID, &hostID, "booking.updated", "booking", booking.ID, models.JSONMap{
 		"changed_fields": changed,
 	}, "")
+	s.webhooks.EmitBooking(ctx, models.WebhookEventBookingUpdated, details, time.Time{}, changed)
 
 	return details, changed, nil
 }
@@ -474,8 +556,16 @@ func (s *BookingService) UpdateBooking(ctx context.Context, input UpdateBookingI
 // host edit. Tracked rows flow through the syncer (which preserves the
 // per-host ID and writes back any replacement ID returned by the provider).
 // For pre-refactor bookings that only have booking.CalendarEventID +
-// template.CalendarID, falls back to a direct UpdateEvent call.
+// template.CalendarID, falls back to a direct UpdateEvent call. A seat's
+// edits go to the event it shares with the rest of its slot.
 func (s *BookingService) updateAllCalendarEvents(ctx context.Context, details *BookingWithDetails, template *models.MeetingTemplate) {
+	if template.HasSeats() {
+		if err := s.syncSeatEvent(ctx, details, details.Booking.StartTime.Time, details.Booking.Duration); err != nil {
+			log.Printf("[BOOKING] Error updating shared calendar event for booking %s: %v", details.Booking.ID, err)
+		}
+		return
+	}
+
 	rows, err := s.repos.BookingCalendarEvent.GetByBookingID(ctx, details.Booking.ID)
 	if err != nil {
 		log.Printf("[BOOKING] Error loading calendar events for booking %s: %v", details.Booking.ID, err)
@@ -649,8 +739,26 @@ func (s *BookingService) RescheduleBooking(ctx context.Context, input Reschedule
 		return nil, time.Time{}, ErrInvalidBookingTime
 	}
 
-	// Delete all old calendar events (pooled and legacy)
-	s.deleteAllCalendarEvents(ctx, oldBooking)
+	// A seat can only move to a slot with a seat left
+	oldDuration := oldBooking.Duration
+	if template.HasSeats() {
+		seats, err := s.slotSeats(ctx, template.ID, input.NewStartTime.UTC(), input.NewDuration)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		taken := 0
+		for _, b := range seats {
+			if b.ID != oldBooking.ID {
+				taken++
+			}
+		}
+		if taken >= template.Seats {
+			return nil, time.Time{}, ErrSlotNotAvailable
+		}
+	} else {
+		// Delete all old calendar events (pooled and legacy)
+		s.deleteAllCalendarEvents(ctx, oldBooking)
+	}
 
 	// Update booking with new times
 	oldBooking.StartTime = models.NewSQLiteTime(input.NewStartTime.UTC())
@@ -659,6 +767,7 @@ func (s *BookingService) RescheduleBooking(ctx context.Context, input Reschedule
 	oldBooking.UpdatedAt = models.Now()
 	oldBooking.CalendarEventID = "" // Will be set when new event is created
 	oldBooking.ConferenceLink = ""  // Will be regenerated if needed
+	oldBooking.ReviewReason = ""    // Every host's event is rewritten below
 
 	if err := s.repos.Booking.Update(ctx, oldBooking); err != nil {
 		return nil, time.Time{}, err
@@ -673,9 +782,19 @@ func (s *BookingService) RescheduleBooking(ctx context.Context, input Reschedule
 
 	// If booking was confirmed, recreate calendar event and conference link
 	if oldBooking.Status == models.BookingStatusConfirmed {
+		// A seat leaves the event it shared with its old slot, and takes the
+		// new slot's link if it has one
+		if template.HasSeats() {
+			if err := s.syncSeatEvent(ctx, details, oldStartTime, oldDuration); err != nil {
+				log.Printf("[RESCHEDULE] Error updating shared calendar event of the old slot: %v", err)
+			}
+			details.Booking.ConferenceLink = s.sharedSeatLink(ctx, details.Booking)
+		}
+
 		// Create new conference link if needed
-		if template.LocationType == models.ConferencingProviderGoogleMeet ||
-			template.LocationType == models.ConferencingProviderZoom {
+		if details.Booking.ConferenceLink == "" &&
+			(template.LocationType == models.ConferencingProviderGoogleMeet ||
+				template.LocationType == models.ConferencingProviderZoom) {
 			link, err := s.conferencing.CreateMeeting(ctx, details)
 			if err != nil {
 				log.Printf("[RESCHEDULE] Error creating conference link: %v", err)
@@ -684,22 +803,28 @@ func (s *BookingService) RescheduleBooking(ctx context.Context, input Reschedule
 			}
 		}
 
-		hosts := s.bookingHostTargets(ctx, details)
-		input := s.calendar.BuildCalendarEventInputForBooking(details)
-		firstID, conferenceLink, err := s.syncer.Create(ctx, CalendarSyncRequest{
-			Kind:   ItemKindBooking,
-			ItemID: details.Booking.ID,
-			Input:  *input,
-			Hosts:  hosts,
-		})
-		if err != nil {
-			log.Printf("[RESCHEDULE] Error during syncer create: %v", err)
-		}
-		if firstID != "" {
-			details.Booking.CalendarEventID = firstID
-		}
-		if conferenceLink != "" && details.Booking.ConferenceLink == "" {
-			details.Booking.ConferenceLink = conferenceLink
+		if template.HasSeats() {
+			if err := s.syncSeatEvent(ctx, details, details.Booking.StartTime.Time, details.Booking.Duration); err != nil {
+				log.Printf("[RESCHEDULE] Error updating shared calendar event: %v", err)
+			}
+		} else {
+			hosts := s.bookingHostTargets(ctx, details)
+			input := s.calendar.BuildCalendarEventInputForBooking(details)
+			firstID, conferenceLink, err := s.syncer.Create(ctx, CalendarSyncRequest{
+				Kind:   ItemKindBooking,
+				ItemID: details.Booking.ID,
+				Input:  *input,
+				Hosts:  hosts,
+			})
+			if err != nil {
+				log.Printf("[RESCHEDULE] Error during syncer create: %v", err)
+			}
+			if firstID != "" {
+				details.Booking.CalendarEventID = firstID
+			}
+			if conferenceLink != "" && details.Booking.ConferenceLink == "" {
+				details.Booking.ConferenceLink = conferenceLink
+			}
 		}
 
 		// Update booking with new conference link and event ID
@@ -713,6 +838,7 @@ func (s *BookingService) RescheduleBooking(ctx context.Context, input Reschedule
 		"old_start_time": oldStartTime.Format(time.RFC3339),
 		"new_start_time": input.NewStartTime.Format(time.RFC3339),
 	}, "")
+	s.webhooks.EmitBooking(ctx, models.WebhookEventBookingRescheduled, details, oldStartTime, nil)
 
 	return details, oldStartTime, nil
 }
@@ -850,6 +976,15 @@ func (s *BookingService) RetryCalendarEvent(ctx context.Context, hostID, tenantI
 		Tenant:   tenant,
 	}
 
+	if template.HasSeats() {
+		// Creates the event for all of the slot's seats
+		if err := s.syncSeatEvent(ctx, details, booking.StartTime.Time, booking.Duration); err != nil {
+			return fmt.Errorf("failed to create calendar event: %w", err)
+		}
+		s.auditLog.Log(ctx, tenantID, &hostID, "booking.calendar_retry", "booking", bookingID, nil, "")
+		return nil
+	}
+
 	hosts := s.bookingHostTargets(ctx, details)
 	input := s.calendar.BuildCalendarEventInputForBooking(details)
 	firstID, conferenceLink, err := s.syncer.Create(ctx, CalendarSyncRequest{
@@ -901,6 +1036,12 @@ func (s *BookingService) BulkRetryCalendarEvents(ctx context.Context, hostID, te
 		if err != nil || template == nil || template.CalendarID == "" {
 			continue // No calendar configured for this template
 		}
+		if template.HasSeats() {
+			// Retrying an earlier seat may have covered its whole slot
+			if b, err := s.repos.Booking.GetByID(ctx, booking.ID); err != nil || b == nil || b.CalendarEventID != "" {
+				continue
+			}
+		}
 
 		if err := s.RetryCalendarEvent(ctx, hostID, tenantID, booking.ID); err != nil {
 			log.Printf("[BOOKING] Failed to retry calendar event for booking %s: %v", booking.ID, err)
@@ -920,14 +1061,197 @@ func (s *BookingService) BulkRetryCalendarEvents(ctx context.Context, hostID, te
 	return successCount, failCount, nil
 }
 
+// ReconcileCalendarEvent brings a booking in line with an edit made directly
+// in a host's calendar, as reported for one of its tracking rows. The booking
+// host's own copy is authoritative: deleting it cancels the booking and
+// moving it reschedules the booking, with the invitee getting the usual
+// cancellation or reschedule email. A pooled sibling's copy drifting, or a
+// copy turned into an all-day event, only flags the booking for review, as
+// does any edit of the event shared by a slot's seats.
+func (s *BookingService) ReconcileCalendarEvent(ctx context.Context, row *models.BookingCalendarEvent, ev *ExternalEvent) error {
+	booking, err := s.repos.Booking.GetByID(ctx, row.BookingID)
+	if err != nil {
+		return err
+	}
+	if booking == nil || booking.Status != models.BookingStatusConfirmed {
+		return nil
+	}
+
+	moved := !ev.Deleted && (!ev.Start.Equal(booking.StartTime.Time) || !ev.End.Equal(booking.EndTime.Time))
+	if !ev.Deleted && !moved {
+		return nil
+	}
+
+	if template, _ := s.repos.Template.GetByID(ctx, booking.TemplateID); template != nil && template.HasSeats() {
+		if ev.Deleted {
+			return s.flagForReview(ctx, booking, "This group meeting was deleted from a host's calendar")
+		}
+		return s.flagForReview(ctx, booking, "This group meeting was moved in a host's calendar")
+	}
+
+	if row.HostID != booking.HostID {
+		name := "A pooled host"
+		if h, _ := s.repos.Host.GetByID(ctx, row.HostID); h != nil {
+			name = h.Name
+		}
+		if ev.Deleted {
+			return s.flagForReview(ctx, booking, name+" deleted this meeting from their calendar")
+		}
+		return s.flagForReview(ctx, booking, name+" moved this meeting in their calendar")
+	}
+
+	if ev.Deleted {
+		log.Printf("[BOOKING] Calendar event for booking %s was deleted by the host, cancelling", booking.ID)
+		return s.CancelBooking(ctx, booking.ID, "host", "Removed from the host's calendar")
+	}
+	if ev.IsAllDay {
+		return s.flagForReview(ctx, booking, "The calendar event was changed to an all-day event")
+	}
+	return s.applyCalendarMove(ctx, booking, ev.Start, ev.End)
+}
+
+// applyCalendarMove reschedules a booking to where the host dragged its
+// calendar event, carries the move over to any pooled hosts' copies and
+// sends the reschedule emails.
+func (s *BookingService) applyCalendarMove(ctx context.Context, booking *models.Booking, start, end time.Time) error {
+	template, err := s.repos.Template.GetByID(ctx, booking.TemplateID)
+	if err != nil || template == nil {
+		return ErrTemplateNotFound
+	}
+	host, _ := s.repos.Host.GetByID(ctx, booking.HostID)
+	if host == nil {
+		return fmt.Errorf("host not found: %s", booking.HostID)
+	}
+	tenant, _ := s.repos.Tenant.GetByID(ctx, host.TenantID)
+
+	oldStart := booking.StartTime.Time
+	oldLength := booking.EndTime.Sub(oldStart)
+	if length := end.Sub(start); length != oldLength {
+		booking.Duration = int(length / time.Minute)
+	}
+	booking.StartTime = models.NewSQLiteTime(start)
+	booking.EndTime = models.NewSQLiteTime(end)
+	booking.ReviewReason = ""
+	booking.UpdatedAt = models.Now()
+	if err := s.repos.Booking.Update(ctx, booking); err != nil {
+		return err
+	}
+
+	details := &BookingWithDetails{
+		Booking:  booking,
+		Template: template,
+		Host:     host,
+		Tenant:   tenant,
+	}
+	s.updateAllCalendarEvents(ctx, details, template)
+	s.email.SendBookingRescheduled(ctx, details, oldStart)
+
+	s.auditLog.Log(ctx, host.TenantID, &host.ID, "booking.rescheduled", "booking", booking.ID, models.JSONMap{
+		"old_start_time": oldStart.Format(time.RFC3339),
+		"new_start_time": start.Format(time.RFC3339),
+		"source":         "calendar",
+	}, "")
+	s.webhooks.EmitBooking(ctx, models.WebhookEventBookingRescheduled, details, oldStart, nil)
+	return nil
+}
+
+// flagForReview records why a booking no longer matches the hosts'
+// calendars. Re-flagging with the same reason is a no-op, so a drift that
+// persists across syncs is only logged once.
+func (s *BookingService) flagForReview(ctx context.Context, booking *models.Booking, reason string) error {
+	if booking.ReviewReason == reason {
+		return nil
+	}
+	booking.ReviewReason = reason
+	booking.UpdatedAt = models.Now()
+	if err := s.repos.Booking.Update(ctx, booking); err != nil {
+		return err
+	}
+
+	log.Printf("[BOOKING] Booking %s flagged for review: %s", booking.ID, reason)
+	if host, _ := s.repos.Host.GetByID(ctx, booking.HostID); host != nil {
+		s.auditLog.Log(ctx, host.TenantID, &host.ID, "booking.flagged_for_review", "booking", booking.ID, models.JSONMap{
+			"reason": reason,
+		}, "")
+	}
+	return nil
+}
+
+// RestoreCalendarEvents resolves a booking flagged for review by rewriting
+// every host's calendar event at the booked time, which puts back deleted
+// copies and moves drifted ones home, and clears the flag.
+func (s *BookingService) RestoreCalendarEvents(ctx context.Context, hostID, tenantID, bookingID string) error {
+	booking, err := s.repos.Booking.GetByID(ctx, bookingID)
+	if err != nil || booking == nil || booking.HostID != hostID {
+		return ErrBookingNotFound
+	}
+	if booking.Status != models.BookingStatusConfirmed {
+		return fmt.Errorf("booking is not confirmed")
+	}
+
+	template, err := s.repos.Template.GetByID(ctx, booking.TemplateID)
+	if err != nil || template == nil {
+		return ErrTemplateNotFound
+	}
+	host, _ := s.repos.Host.GetByID(ctx, hostID)
+	tenant, _ := s.repos.Tenant.GetByID(ctx, tenantID)
+
+	s.deleteAllCalendarEvents(ctx, booking)
+	booking.CalendarEventID = ""
+
+	details := &BookingWithDetails{
+		Booking:  booking,
+		Template: template,
+		Host:     host,
+		Tenant:   tenant,
+	}
+	if template.HasSeats() {
+		// Recreates the event the slot's seats share
+		if err := s.syncSeatEvent(ctx, details, booking.StartTime.Time, booking.Duration); err != nil {
+			return fmt.Errorf("failed to recreate calendar events: %w", err)
+		}
+		booking.ReviewReason = ""
+		booking.UpdatedAt = models.Now()
+		if err := s.repos.Booking.Update(ctx, booking); err != nil {
+			return fmt.Errorf("failed to update booking: %w", err)
+		}
+		s.auditLog.Log(ctx, tenantID, &hostID, "booking.calendar_restored", "booking", bookingID, nil, "")
+		return nil
+	}
+	input := s.calendar.BuildCalendarEventInputForBooking(details)
+	firstID, _, err := s.syncer.Create(ctx, CalendarSyncRequest{
+		Kind:   ItemKindBooking,
+		ItemID: booking.ID,
+		Input:  *input,
+		Hosts:  s.bookingHostTargets(ctx, details),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to recreate calendar events: %w", err)
+	}
+	booking.CalendarEventID = firstID
+	booking.ReviewReason = ""
+	booking.UpdatedAt = models.Now()
+	if err := s.repos.Booking.Update(ctx, booking); err != nil {
+		return fmt.Errorf("failed to update booking: %w", err)
+	}
+
+	s.auditLog.Log(ctx, tenantID, &hostID, "booking.calendar_restored", "booking", bookingID, nil, "")
+	return nil
+}
+
 // processConfirmedBooking handles post-confirmation actions
 func (s *BookingService) processConfirmedBooking(ctx context.Context, details *BookingWithDetails) error {
 	log.Printf("[BOOKING] processConfirmedBooking: booking=%s template=%s calendar=%s",
 		details.Booking.ID, details.Template.ID, details.Template.CalendarID)
 
-	// Create conference link if needed (use owner's credentials)
-	if details.Template.LocationType == models.ConferencingProviderGoogleMeet ||
-		details.Template.LocationType == models.ConferencingProviderZoom {
+	// Create conference link if needed (use owner's credentials). The seats
+	// of a slot share the link of the first to get one.
+	if details.Template.HasSeats() {
+		details.Booking.ConferenceLink = s.sharedSeatLink(ctx, details.Booking)
+	}
+	if details.Booking.ConferenceLink == "" &&
+		(details.Template.LocationType == models.ConferencingProviderGoogleMeet ||
+			details.Template.LocationType == models.ConferencingProviderZoom) {
 		log.Printf("[BOOKING] Creating conference link for location type: %s", details.Template.LocationType)
 		link, err := s.conferencing.CreateMeeting(ctx, details)
 		if err != nil {
@@ -939,22 +1263,29 @@ func (s *BookingService) processConfirmedBooking(ctx context.Context, details *B
 		}
 	}
 
-	hosts := s.bookingHostTargets(ctx, details)
-	input := s.calendar.BuildCalendarEventInputForBooking(details)
-	firstID, conferenceLink, err := s.syncer.Create(ctx, CalendarSyncRequest{
-		Kind:   ItemKindBooking,
-		ItemID: details.Booking.ID,
-		Input:  *input,
-		Hosts:  hosts,
-	})
-	if err != nil {
-		log.Printf("[BOOKING] Error during syncer create for booking %s: %v", details.Booking.ID, err)
-	}
-	if firstID != "" && details.Booking.CalendarEventID == "" {
-		details.Booking.CalendarEventID = firstID
-	}
-	if conferenceLink != "" && details.Booking.ConferenceLink == "" {
-		details.Booking.ConferenceLink = conferenceLink
+	if details.Template.HasSeats() {
+		// Join the calendar event shared by the slot's seats
+		if err := s.syncSeatEvent(ctx, details, details.Booking.StartTime.Time, details.Booking.Duration); err != nil {
+			log.Printf("[BOOKING] Error updating shared calendar event for booking %s: %v", details.Booking.ID, err)
+		}
+	} else {
+		hosts := s.bookingHostTargets(ctx, details)
+		input := s.calendar.BuildCalendarEventInputForBooking(details)
+		firstID, conferenceLink, err := s.syncer.Create(ctx, CalendarSyncRequest{
+			Kind:   ItemKindBooking,
+			ItemID: details.Booking.ID,
+			Input:  *input,
+			Hosts:  hosts,
+		})
+		if err != nil {
+			log.Printf("[BOOKING] Error during syncer create for booking %s: %v", details.Booking.ID, err)
+		}
+		if firstID != "" && details.Booking.CalendarEventID == "" {
+			details.Booking.CalendarEventID = firstID
+		}
+		if conferenceLink != "" && details.Booking.ConferenceLink == "" {
+			details.Booking.ConferenceLink = conferenceLink
+		}
 	}
 
 	// Update booking with conference link and event ID
@@ -974,7 +1305,7 @@ func (s *BookingService) processConfirmedBooking(ctx context.Context, details *B
 
 // bookingHostTargets resolves the calendar fan-out targets for a booking:
 // pooled hosts when the template has any (owner first, siblings after), or
-// just the booking host when the template is single-host. Each target's
+// just the booking host when the template is single-host or round-robin. Each target's
 // CalendarID is left empty when the syncer should resolve it from the host's
 // default + first writable fallback.
 func (s *BookingService) bookingHostTargets(ctx context.Context, details *BookingWithDetails) []HostTarget {
@@ -991,6 +1322,15 @@ func (s *BookingService) bookingHostTargets(ctx context.Context, details *Bookin
 			IsOwner:    true,
 		}}
 	}
+	if isRoundRobin(details.Template, pooled) {
+		// Only the assigned host attends. The template's calendar is the
+		// owner's, so anyone else gets their default.
+		target := HostTarget{HostID: details.Booking.HostID, IsOwner: true}
+		if details.Booking.HostID == details.Template.HostID {
+			target.CalendarID = details.Template.CalendarID
+		}
+		return []HostTarget{target}
+	}
 	targets := make([]HostTarget, 0, len(pooled))
 	for _, th := range pooled {
 		targets = append(targets, HostTarget{
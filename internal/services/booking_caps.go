@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/models"
+	"github.com/meet-when/meet-when/internal/repository"
+)
+
+// bookingCaps returns the caps a booking starting at start must fit under:
+// the host's daily and weekly caps across all their templates, and the
+// template's own. Days run midnight to midnight and weeks Monday to Monday in
+// the host's timezone. Caps that are turned off are left out.
+func bookingCaps(host *models.Host, template *models.MeetingTemplate, start time.Time) []repository.BookingCap {
+	if host == nil {
+		return nil
+	}
+	loc, err := time.LoadLocation(host.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	dayStart, dayEnd := localDay(start, loc)
+	daysFromMonday := (int(dayStart.Weekday()) + 6) % 7
+	weekStart := dayStart.AddDate(0, 0, -daysFromMonday)
+	weekEnd := weekStart.AddDate(0, 0, 7)
+
+	var caps []repository.BookingCap
+	add := func(c repository.BookingCap) {
+		if c.MaxBookings > 0 || c.MaxMinutes > 0 {
+			c.HostID = host.ID
+			caps = append(caps, c)
+		}
+	}
+	add(repository.BookingCap{From: dayStart, To: dayEnd, MaxBookings: host.MaxBookingsPerDay, MaxMinutes: host.MaxMinutesPerDay})
+	add(repository.BookingCap{From: weekStart, To: weekEnd, MaxBookings: host.MaxBookingsPerWeek})
+	if template != nil {
+		add(repository.BookingCap{TemplateID: template.ID, From: dayStart, To: dayEnd, MaxBookings: template.MaxBookingsPerDay})
+		add(repository.BookingCap{TemplateID: template.ID, From: weekStart, To: weekEnd, MaxBookings: template.MaxBookingsPerWeek})
+	}
+	return caps
+}
+
+// localDay returns the midnights that start and end t's day in loc.
+func localDay(t time.Time, loc *time.Location) (start, end time.Time) {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc), time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+}
+
+// applyBookingCaps drops the slots that start on a day or in a week where a
+// booking cap has been reached, or where a booking of duration minutes would
+// go over the host's daily meeting minutes. Caps are hostID's: the owner of
+// a collective template, or each host in turn of a round-robin one. The
+// seats of a slot count as one meeting, and taking a seat in a slot that's
+// already booked adds none, so those slots are kept.
+func (s *AvailabilityService) applyBookingCaps(ctx context.Context, hostID string, template *models.MeetingTemplate, slots []models.TimeSlot, duration int) ([]models.TimeSlot, error) {
+	if len(slots) == 0 {
+		return slots, nil
+	}
+	host, err := s.repos.Host.GetByID(ctx, hostID)
+	if err != nil || host == nil {
+		return slots, err
+	}
+	if host.MaxBookingsPerDay <= 0 && host.MaxBookingsPerWeek <= 0 && host.MaxMinutesPerDay <= 0 &&
+		template.MaxBookingsPerDay <= 0 && template.MaxBookingsPerWeek <= 0 {
+		return slots, nil
+	}
+
+	// Every cap window falls within the weeks around the first and last slot.
+	var from, to time.Time
+	for _, c := range bookingCaps(host, template, slots[0].Start) {
+		if from.IsZero() || c.From.Before(from) {
+			from = c.From
+		}
+	}
+	for _, c := range bookingCaps(host, template, slots[len(slots)-1].Start) {
+		if c.To.After(to) {
+			to = c.To
+		}
+	}
+	bookings, err := s.repos.Booking.GetByHostIDAndTimeRange(ctx, hostID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	type usage struct{ count, minutes int }
+	type meeting struct {
+		templateID string
+		start      int64
+		duration   int
+	}
+	used := make(map[repository.BookingCap]usage)
+	full := func(c repository.BookingCap) bool {
+		u, ok := used[c]
+		if !ok {
+			counted := make(map[meeting]bool)
+			for _, b := range bookings {
+				if b.StartTime.Before(c.From) || !b.StartTime.Before(c.To) {
+					continue
+				}
+				if c.TemplateID != "" && b.TemplateID != c.TemplateID {
+					continue
+				}
+				m := meeting{b.TemplateID, b.StartTime.Unix(), b.Duration}
+				if counted[m] {
+					continue
+				}
+				counted[m] = true
+				u.count++
+				u.minutes += b.Duration
+			}
+			used[c] = u
+		}
+		return (c.MaxBookings > 0 && u.count+1 > c.MaxBookings) ||
+			(c.MaxMinutes > 0 && u.minutes+duration > c.MaxMinutes)
+	}
+
+	var open []models.TimeSlot
+	for _, slot := range slots {
+		if template.HasSeats() && slot.SeatsLeft < template.Seats {
+			open = append(open, slot)
+			continue
+		}
+		ok := true
+		for _, c := range bookingCaps(host, template, slot.Start) {
+			if full(c) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			open = append(open, slot)
+		}
+	}
+	return open, nil
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBookingCaps(t *testing.T) {
+	bookings, fix, cleanup := makeCreateBookingService(t)
+	defer cleanup()
+	ctx := context.Background()
+	owner := fix.hostIDs[0]
+	repos := bookings.repos
+	svc := bookings.availability
+
+	host, _ := repos.Host.GetByID(ctx, owner)
+	loc, err := time.LoadLocation(host.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	// A Monday two to eight days out, so the Sunday before it falls in
+	// another week that is still wholly in the future.
+	today := time.Now().In(loc)
+	monday := time.Date(today.Year(), today.Month(), today.Day()+2, 0, 0, 0, 0, loc)
+	for monday.Weekday() != time.Monday {
+		monday = monday.AddDate(0, 0, 1)
+	}
+	at := func(day time.Time, hour int) time.Time { return day.Add(time.Duration(hour) * time.Hour) }
+	slotsOn := func(day time.Time) int {
+		t.Helper()
+		slots, err := svc.GetAvailableSlots(ctx, GetAvailableSlotsInput{
+			HostID: owner, TemplateID: fix.templateID,
+			StartDate: day, EndDate: day.AddDate(0, 0, 1), Duration: 30, Timezone: "UTC",
+		})
+		if err != nil {
+			t.Fatalf("GetAvailableSlots: %v", err)
+		}
+		return len(slots)
+	}
+	setCaps := func(templatePerDay, hostPerWeek, hostMinutes int) {
+		t.Helper()
+		tmpl, _ := repos.Template.GetByID(ctx, fix.templateID)
+		tmpl.MaxScheduleDays = 30
+		tmpl.MaxBookingsPerDay = templatePerDay
+		if err := repos.Template.Update(ctx, tmpl); err != nil {
+			t.Fatalf("update template: %v", err)
+		}
+		if err := svc.SetBookingCaps(ctx, owner, 0, hostPerWeek, hostMinutes); err != nil {
+			t.Fatalf("SetBookingCaps: %v", err)
+		}
+	}
+	setCaps(0, 0, 0)
+	open := slotsOn(monday)
+	if open == 0 {
+		t.Fatal("no slots before setting caps")
+	}
+
+	// One booking a day on this meeting type
+	setCaps(1, 0, 0)
+	if _, err := bookings.CreateBooking(ctx, createInput(fix, at(monday, 9), "first@example.com")); err != nil {
+		t.Fatalf("first booking: %v", err)
+	}
+	if got := slotsOn(monday); got != 0 {
+		t.Errorf("daily cap reached but %d slots still open", got)
+	}
+	if _, err := bookings.CreateBooking(ctx, createInput(fix, at(monday, 12), "second@example.com")); !errors.Is(err, ErrSlotNotAvailable) {
+		t.Errorf("booking over the daily cap: err = %v, want ErrSlotNotAvailable", err)
+	}
+	if got := slotsOn(monday.AddDate(0, 0, 1)); got == 0 {
+		t.Error("daily cap closed the next day too")
+	}
+
+	// An hour of meetings a day: a 30-minute booking still fits, then the
+	// day is full.
+	setCaps(0, 0, 60)
+	if got := slotsOn(monday); got == 0 {
+		t.Error("30 of 60 minutes booked but no slots open")
+	}
+	if _, err := bookings.CreateBooking(ctx, createInput(fix, at(monday, 12), "second@example.com")); err != nil {
+		t.Fatalf("booking within the minutes cap: %v", err)
+	}
+	if got := slotsOn(monday); got != 0 {
+		t.Errorf("minutes cap reached but %d slots still open", got)
+	}
+
+	// Two bookings a week across every meeting type
+	setCaps(0, 2, 0)
+	if got := slotsOn(monday.AddDate(0, 0, 3)); got != 0 {
+		t.Errorf("weekly cap reached but %d slots open later in the week", got)
+	}
+	if _, err := bookings.CreateBooking(ctx, createInput(fix, at(monday.AddDate(0, 0, 3), 9), "third@example.com")); !errors.Is(err, ErrSlotNotAvailable) {
+		t.Errorf("booking over the weekly cap: err = %v, want ErrSlotNotAvailable", err)
+	}
+	if got := slotsOn(monday.AddDate(0, 0, -1)); got == 0 {
+		t.Error("weekly cap closed the week before too")
+	}
+
+	setCaps(0, 0, 0)
+	if got := slotsOn(monday.AddDate(0, 0, 3)); got != open {
+		t.Errorf("caps off: %d slots, want %d", got, open)
+	}
+}
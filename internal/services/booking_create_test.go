@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-when/meet-when/internal/config"
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// makeCreateBookingService wires a BookingService over the syncer fixture
+// with the owner open around the clock and no calendars polled, so slots
+// depend only on bookings.
+func makeCreateBookingService(t *testing.T) (*BookingService, *fixture, func()) {
+	t.Helper()
+	sh := makeSyncerHarness(t, 1)
+	ctx := context.Background()
+	repos := sh.syncer.repos
+	owner := sh.fixture.hostIDs[0]
+
+	if err := repos.ProviderCalendar.UpdatePollBusy(ctx, owner, sh.fixture.calendars[owner], false); err != nil {
+		t.Fatalf("disable polling: %v", err)
+	}
+	var hours []*models.WorkingHours
+	for day := 0; day < 7; day++ {
+		hours = append(hours, &models.WorkingHours{ID: uuid.New().String(), HostID: owner, DayOfWeek: day, StartTime: "00:00", EndTime: "23:45", IsEnabled: true})
+	}
+	if err := repos.WorkingHours.SetForHost(ctx, owner, hours); err != nil {
+		t.Fatalf("set working hours: %v", err)
+	}
+	// Pending bookings hold the slot without touching calendars or
+	// conferencing.
+	tmpl, _ := repos.Template.GetByID(ctx, sh.fixture.templateID)
+	tmpl.RequiresApproval = true
+	tmpl.PostBufferMinutes = 15
+	if err := repos.Template.Update(ctx, tmpl); err != nil {
+		t.Fatalf("update template: %v", err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Server.BaseURL = "http://test.local"
+	cfg.Email.SMTPHost = "127.0.0.1"
+	cfg.Email.SMTPPort = 1
+	calendar := NewCalendarService(cfg, repos)
+	bookings := NewBookingService(cfg, repos, calendar, NewAvailabilityService(repos, calendar, nil), sh.syncer,
+		NewConferencingService(cfg, repos), NewEmailService(cfg, repos), NewAuditLogService(repos), NewContactService(repos), NewWebhookService(repos))
+	return bookings, sh.fixture, sh.cleanup
+}
+
+func createInput(fix *fixture, start time.Time, email string) CreateBookingInput {
+	return CreateBookingInput{
+		TemplateID:      fix.templateID,
+		HostID:          fix.hostIDs[0],
+		TenantID:        fix.tenantID,
+		StartTime:       start,
+		Duration:        30,
+		InviteeName:     "Invitee",
+		InviteeEmail:    email,
+		InviteeTimezone: "UTC",
+	}
+}
+
+func TestCreateBooking_ConcurrentRequestsForOneSlot(t *testing.T) {
+	bookings, fix, cleanup := makeCreateBookingService(t)
+	defer cleanup()
+
+	start := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Hour)
+
+	const n = 4
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = bookings.CreateBooking(context.Background(), createInput(fix, start, "racer@example.com"))
+		}()
+	}
+	wg.Wait()
+
+	created := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, ErrSlotNotAvailable):
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if created != 1 {
+		t.Fatalf("%d bookings created for one slot, want exactly 1", created)
+	}
+
+	// The post-meeting buffer keeps the next quarter-hour closed too, while
+	// the slot after it is still open.
+	if _, err := bookings.CreateBooking(context.Background(), createInput(fix, start.Add(30*time.Minute), "next@example.com")); !errors.Is(err, ErrSlotNotAvailable) {
+		t.Errorf("booking inside the buffer: err = %v, want ErrSlotNotAvailable", err)
+	}
+	if _, err := bookings.CreateBooking(context.Background(), createInput(fix, start.Add(45*time.Minute), "later@example.com")); err != nil {
+		t.Errorf("booking after the buffer: %v", err)
+	}
+}
+
+func TestNearbySlots(t *testing.T) {
+	bookings, fix, cleanup := makeCreateBookingService(t)
+	defer cleanup()
+
+	start := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Hour)
+	if _, err := bookings.CreateBooking(context.Background(), createInput(fix, start, "first@example.com")); err != nil {
+		t.Fatalf("CreateBooking: %v", err)
+	}
+
+	slots, err := bookings.availability.NearbySlots(context.Background(), fix.hostIDs[0], fix.templateID, start, 30, "Europe/Paris", 4)
+	if err != nil {
+		t.Fatalf("NearbySlots: %v", err)
+	}
+	if len(slots) != 4 {
+		t.Fatalf("got %d slots, want 4", len(slots))
+	}
+	for i, s := range slots {
+		if s.Start.Location().String() != "Europe/Paris" {
+			t.Errorf("slot %d in %s, want the invitee's timezone", i, s.Start.Location())
+		}
+		if s.Start.Before(start.Add(45*time.Minute)) && s.End.After(start) {
+			t.Errorf("slot %v overlaps the booked time", s.Start.UTC())
+		}
+		if d := s.Start.Sub(start); d > 2*time.Hour || d < -2*time.Hour {
+			t.Errorf("slot %v is not near %v", s.Start.UTC(), start)
+		}
+		if i > 0 && !slots[i-1].Start.Before(s.Start) {
+			t.Errorf("slots out of order at %d", i)
+		}
+	}
+}
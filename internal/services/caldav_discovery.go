@@ -18,10 +18,12 @@ import (
 type caldavMultistatus struct {
 	XMLName   xml.Name         `xml:"multistatus"`
 	Responses []caldavResponse `xml:"response"`
+	SyncToken string           `xml:"sync-token"` // sync-collection REPORT (RFC 6578)
 }
 
 type caldavResponse struct {
 	Href     string           `xml:"href"`
+	Status   string           `xml:"status"` // set instead of propstat for removed members in sync-collection
 	Propstat []caldavPropstat `xml:"propstat"`
 }
 
@@ -38,6 +40,9 @@ type caldavProp struct {
 	ResourceType           *caldavResourceType     `xml:"resourcetype"`
 	CalendarUserPrivileges *caldavPrivilegeSet     `xml:"current-user-privilege-set"`
 	SupportedCalendarComps *caldavSupportedCompSet `xml:"supported-calendar-component-set"`
+	CTag                   string                  `xml:"getctag"`
+	SyncToken              string                  `xml:"sync-token"`
+	CalendarData           string                  `xml:"calendar-data"`
 }
 
 type caldavHrefHolder struct {
@@ -262,6 +267,39 @@ func (s *CalendarService) caldavPropfind(ctx context.Context, conn *models.Calen
 	return parseCalDAVMultistatus(raw)
 }
 
+// caldavReport issues a REPORT request and returns the parsed multistatus.
+func (s *CalendarService) caldavReport(ctx context.Context, conn *models.CalendarConnection, url, depth, body string) (*caldavMultistatus, error) {
+	req, err := http.NewRequestWithContext(ctx, "REPORT", url, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(conn.CalDAVUsername, conn.CalDAVPassword)
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", depth)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("Error closing response body: %v", cerr)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrCalendarAuth
+	}
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusMultiStatus && resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("REPORT %s returned %d: %s", url, resp.StatusCode, string(raw))
+	}
+	return parseCalDAVMultistatus(raw)
+}
+
 func parseCalDAVMultistatus(raw []byte) (*caldavMultistatus, error) {
 	var ms caldavMultistatus
 	dec := xml.NewDecoder(strings.NewReader(string(raw)))
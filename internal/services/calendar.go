@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -28,13 +29,30 @@ var (
 type CalendarService struct {
 	cfg   *config.Config
 	repos *repository.Repositories
+
+	// Microsoft endpoints; overridden in tests to point at a Graph stand-in.
+	graphBaseURL      string
+	microsoftLoginURL string
+
+	icsFeeds *icsFeedCache
+	// feedClient fetches ICS feeds, from public addresses only; overridden
+	// in tests to reach a local stand-in.
+	feedClient *http.Client
+
+	// busySyncLocks serialises busy-cache syncs per provider calendar, so a
+	// push notification and the ticker can't interleave writes.
+	busySyncLocks sync.Map // provider calendar ID -> *sync.Mutex
 }
 
 // NewCalendarService creates a new calendar service
 func NewCalendarService(cfg *config.Config, repos *repository.Repositories) *CalendarService {
 	return &CalendarService{
-		cfg:   cfg,
-		repos: repos,
+		cfg:               cfg,
+		repos:             repos,
+		graphBaseURL:      "https://graph.microsoft.com/v1.0",
+		microsoftLoginURL: "https://login.microsoftonline.com",
+		icsFeeds:          newICSFeedCache(),
+		feedClient:        newPublicHTTPClient(icsFeedTimeout),
 	}
 }
 
@@ -91,6 +109,10 @@ func (s *CalendarService) ConnectGoogleCalendar(ctx context.Context, input Googl
 		_ = s.repos.Calendar.Update(ctx, connection)
 	}
 
+	if err := s.EnsureGoogleWatchChannels(ctx, connection); err != nil {
+		log.Printf("[CALENDAR] initial watch registration incomplete for connection %s: %v", connection.ID, err)
+	}
+
 	return connection, nil
 }
 
@@ -142,7 +164,7 @@ func (s *CalendarService) ConnectCalDAV(ctx context.Context, input CalDAVConnect
 		Name:           displayName,
 		CalDAVURL:      caldavURL,
 		CalDAVUsername: input.Username,
-		CalDAVPassword: input.Password, // Should be encrypted in production
+		CalDAVPassword: input.Password,
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}
@@ -170,6 +192,11 @@ func (s *CalendarService) DisconnectCalendar(ctx context.Context, hostID, connec
 		return ErrCalendarNotFound
 	}
 
+	// Stop push channels while the connection's tokens are still at hand.
+	if cal.Provider == models.CalendarProviderGoogle {
+		s.StopGoogleWatchChannels(ctx, cal)
+	}
+
 	return s.repos.Calendar.Delete(ctx, connectionID)
 }
 
@@ -274,6 +301,18 @@ func (s *CalendarService) SetProviderCalendarPollBusy(ctx context.Context, hostI
 	return s.repos.ProviderCalendar.UpdatePollBusy(ctx, hostID, providerCalendarID, pollBusy)
 }
 
+// SetProviderCalendarBusyPolicy stores which tentative, free (transparent)
+// and declined events on a calendar block availability.
+func (s *CalendarService) SetProviderCalendarBusyPolicy(ctx context.Context, hostID, providerCalendarID string, onTentative, onFree, onDeclined bool) error {
+	return s.repos.ProviderCalendar.UpdateBusyPolicy(ctx, hostID, providerCalendarID, onTentative, onFree, onDeclined)
+}
+
+// SetProviderCalendarBuffers stores the minutes kept free before and after
+// each event on a calendar. Negative values are treated as zero.
+func (s *CalendarService) SetProviderCalendarBuffers(ctx context.Context, hostID, providerCalendarID string, before, after int) error {
+	return s.repos.ProviderCalendar.UpdateBuffers(ctx, hostID, providerCalendarID, max(before, 0), max(after, 0))
+}
+
 // RefreshConnectionCalendarList re-enumerates the calendars for a connection.
 // Useful both at first connect time and when the user clicks "Refresh" so newly
 // added calendars at the provider show up in our UI.
@@ -285,6 +324,10 @@ func (s *CalendarService) RefreshConnectionCalendarList(ctx context.Context, hos
 	switch conn.Provider {
 	case models.CalendarProviderGoogle:
 		return s.refreshGoogleCalendarList(ctx, conn)
+	case models.CalendarProviderOutlook:
+		return s.refreshOutlookCalendarList(ctx, conn)
+	case models.CalendarProviderICSFeed:
+		return s.refreshICSFeedCalendarList(ctx, conn)
 	case models.CalendarProviderCalDAV, models.CalendarProviderICloud:
 		return s.refreshCalDAVCalendarList(ctx, conn)
 	}
@@ -307,12 +350,18 @@ func (s *CalendarService) RefreshCalendarSync(ctx context.Context, hostID, conne
 		return err
 	}
 
+	host, err := s.repos.Host.GetByID(ctx, hostID)
+	if err != nil {
+		log.Printf("[CALENDAR] sync: failed to load host %s: %v", hostID, err)
+	}
+	owner := ownerForConnection(host, conn)
+
 	start := time.Now()
 	end := start.Add(24 * time.Hour)
 
 	var firstErr error
 	for _, pc := range calendars {
-		if syncErr := s.syncProviderCalendar(ctx, conn, pc, start, end); syncErr != nil && firstErr == nil {
+		if syncErr := s.syncProviderCalendar(ctx, conn, pc, owner, start, end); syncErr != nil && firstErr == nil {
 			firstErr = syncErr
 		}
 	}
@@ -332,10 +381,16 @@ func (s *CalendarService) RefreshCalendarSync(ctx context.Context, hostID, conne
 	return nil
 }
 
-// syncProviderCalendar tests connectivity to a single calendar and records the
+// syncProviderCalendar brings a polled calendar's busy-time cache up to date,
+// or just tests connectivity to a calendar that isn't polled, and records the
 // outcome on its provider_calendars row.
-func (s *CalendarService) syncProviderCalendar(ctx context.Context, conn *models.CalendarConnection, pc *models.ProviderCalendar, start, end time.Time) error {
-	_, err := s.busyTimesForProviderCalendar(ctx, conn, pc, start, end)
+func (s *CalendarService) syncProviderCalendar(ctx context.Context, conn *models.CalendarConnection, pc *models.ProviderCalendar, owner calendarOwner, start, end time.Time) error {
+	var err error
+	if pc.PollBusy {
+		err = s.syncBusyIntervals(ctx, conn, pc, owner)
+	} else {
+		_, err = s.busyTimesForProviderCalendar(ctx, conn, pc, owner, start, end)
+	}
 	now := models.Now()
 	if err != nil {
 		errMsg := err.Error()
@@ -369,8 +424,12 @@ func (s *CalendarService) SyncCalendar(ctx context.Context, cal *models.Calendar
 	switch cal.Provider {
 	case models.CalendarProviderGoogle:
 		_, syncErr = s.getGoogleBusyTimes(ctx, cal, start, end)
+	case models.CalendarProviderOutlook:
+		_, syncErr = s.getOutlookEventBusyTimes(ctx, cal, cal.CalendarID, defaultBusyPolicy(), start, end)
+	case models.CalendarProviderICSFeed:
+		_, syncErr = s.getICSFeedBusyTimes(ctx, cal.CalDAVURL, defaultBusyPolicy(), start, end)
 	case models.CalendarProviderCalDAV, models.CalendarProviderICloud:
-		_, syncErr = s.getCalDAVBusyTimes(ctx, cal, start, end)
+		_, syncErr = s.getCalDAVBusyTimes(ctx, cal, defaultBusyPolicy(), start, end)
 	default:
 		// Unknown provider - skip
 		return nil
@@ -396,10 +455,35 @@ func (s *CalendarService) GetAllCalendars(ctx context.Context) ([]*models.Calend
 }
 
 // GetBusyTimes returns busy times across every provider_calendar belonging to
-// the host that has poll_busy=true. Calendars under the same connection share
-// credentials, so we group by connection_id; a connection-level auth failure
-// short-circuits all of its calendars.
+// the host that has poll_busy=true. Calendars whose busy-time cache is fresh
+// and covers the range are answered from it; the rest are fetched live.
+// Calendars under the same connection share credentials, so live fetches are
+// grouped by connection_id; a connection-level auth failure short-circuits
+// all of its calendars. When a live fetch fails, a stale cache is still
+// preferred over treating the calendar as free. Which events count as busy
+// is decided per calendar by its busy policy.
 func (s *CalendarService) GetBusyTimes(ctx context.Context, hostID string, start, end time.Time) ([]models.TimeSlot, error) {
+	byCalendar, err := s.GetBusyTimesByCalendar(ctx, hostID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	var allBusyTimes []models.TimeSlot
+	for _, cb := range byCalendar {
+		allBusyTimes = append(allBusyTimes, cb.Busy...)
+	}
+	return allBusyTimes, nil
+}
+
+// CalendarBusy is the busy time read from one provider calendar
+type CalendarBusy struct {
+	Calendar *models.ProviderCalendar
+	Busy     []models.TimeSlot
+}
+
+// GetBusyTimesByCalendar is GetBusyTimes keeping each calendar's busy times
+// apart, so per-calendar settings such as buffers can be applied to them.
+// Calendars come back in the order GetPolledByHostID returns them.
+func (s *CalendarService) GetBusyTimesByCalendar(ctx context.Context, hostID string, start, end time.Time) ([]CalendarBusy, error) {
 	polled, err := s.repos.ProviderCalendar.GetPolledByHostID(ctx, hostID)
 	if err != nil {
 		return nil, err
@@ -408,6 +492,14 @@ func (s *CalendarService) GetBusyTimes(ctx context.Context, hostID string, start
 		return nil, nil
 	}
 
+	// The host anchors all-day events to their timezone and identifies their
+	// own attendee entry (for declined invitations). A lookup failure only
+	// degrades to UTC and no attendee matching.
+	host, err := s.repos.Host.GetByID(ctx, hostID)
+	if err != nil {
+		log.Printf("[CALENDAR] busy times: failed to load host %s: %v", hostID, err)
+	}
+
 	// Group polled calendars by connection so we issue one auth-bearing request
 	// per provider account rather than per calendar.
 	byConn := make(map[string][]*models.ProviderCalendar)
@@ -419,7 +511,7 @@ func (s *CalendarService) GetBusyTimes(ctx context.Context, hostID string, start
 		byConn[pc.ConnectionID] = append(byConn[pc.ConnectionID], pc)
 	}
 
-	var allBusyTimes []models.TimeSlot
+	busyByCalendar := make(map[string][]models.TimeSlot, len(polled))
 
 	for _, connID := range connOrder {
 		conn, err := s.repos.Calendar.GetByID(ctx, connID)
@@ -427,9 +519,23 @@ func (s *CalendarService) GetBusyTimes(ctx context.Context, hostID string, start
 			continue
 		}
 
-		busy, errs := s.busyTimesForConnection(ctx, conn, byConn[connID], start, end)
-		now := models.Now()
+		owner := ownerForConnection(host, conn)
+		maxAge := s.busyCacheMaxAge()
+		var live []*models.ProviderCalendar
 		for _, pc := range byConn[connID] {
+			if cached, ok := s.cachedBusyTimes(ctx, pc, owner, start, end, maxAge); ok {
+				busyByCalendar[pc.ID] = cached
+				continue
+			}
+			live = append(live, pc)
+		}
+		if len(live) == 0 {
+			continue
+		}
+
+		busy, errs := s.busyTimesForConnection(ctx, conn, live, owner, start, end)
+		now := models.Now()
+		for _, pc := range live {
 			if pcErr, ok := errs[pc.ID]; ok && pcErr != nil {
 				errMsg := pcErr.Error()
 				if errors.Is(pcErr, ErrCalendarAuth) {
@@ -437,60 +543,87 @@ func (s *CalendarService) GetBusyTimes(ctx context.Context, hostID string, start
 				}
 				_ = s.repos.ProviderCalendar.UpdateSyncStatus(ctx, pc.ID, models.CalendarSyncStatusFailed, errMsg, nil)
 				log.Printf("Calendar sync failed for %s: %v", pc.ID, pcErr)
+				if stale, ok := s.cachedBusyTimes(ctx, pc, owner, start, end, 0); ok {
+					log.Printf("[CALENDAR] serving stale busy cache for calendar %s", pc.ID)
+					busyByCalendar[pc.ID] = stale
+				}
 			} else {
 				_ = s.repos.ProviderCalendar.UpdateSyncStatus(ctx, pc.ID, models.CalendarSyncStatusSynced, "", &now)
+				busyByCalendar[pc.ID] = busy[pc.ID]
 			}
 		}
-		allBusyTimes = append(allBusyTimes, busy...)
 	}
 
-	return allBusyTimes, nil
+	var out []CalendarBusy
+	for _, pc := range polled {
+		if busy, ok := busyByCalendar[pc.ID]; ok {
+			out = append(out, CalendarBusy{Calendar: pc, Busy: busy})
+		}
+	}
+	return out, nil
 }
 
 // busyTimesForConnection fetches busy times for the supplied calendars under a
-// single connection. For Google we issue ONE freeBusy call covering all
-// calendars; for CalDAV we still need one HTTP call per calendar collection.
+// single connection. For Google we issue ONE freeBusy call covering every
+// calendar whose busy policy matches Google's own classification, and list
+// events for the rest; for Outlook and CalDAV we still need one HTTP call per
+// calendar.
 //
-// Returns the union of busy times and a per-calendar error map.
-func (s *CalendarService) busyTimesForConnection(ctx context.Context, conn *models.CalendarConnection, calendars []*models.ProviderCalendar, start, end time.Time) ([]models.TimeSlot, map[string]error) {
+// Returns the busy times and errors, both keyed by provider calendar row ID.
+func (s *CalendarService) busyTimesForConnection(ctx context.Context, conn *models.CalendarConnection, calendars []*models.ProviderCalendar, owner calendarOwner, start, end time.Time) (map[string][]models.TimeSlot, map[string]error) {
+	busy := make(map[string][]models.TimeSlot, len(calendars))
 	errs := make(map[string]error, len(calendars))
 	if len(calendars) == 0 {
-		return nil, errs
+		return busy, errs
 	}
 
 	switch conn.Provider {
 	case models.CalendarProviderGoogle:
-		ids := make([]string, 0, len(calendars))
+		var freeBusy []*models.ProviderCalendar
 		for _, pc := range calendars {
-			if pc.ProviderCalendarID != "" {
-				ids = append(ids, pc.ProviderCalendarID)
+			if pc.ProviderCalendarID == "" {
+				continue
+			}
+			policy := policyForCalendar(pc, owner)
+			if policy.matchesFreeBusy() {
+				freeBusy = append(freeBusy, pc)
+				continue
 			}
+			slots, err := s.getGoogleEventBusyTimes(ctx, conn, pc.ProviderCalendarID, policy, start, end)
+			if err != nil {
+				errs[pc.ID] = err
+				continue
+			}
+			busy[pc.ID] = slots
+		}
+		if len(freeBusy) == 0 {
+			return busy, errs
+		}
+		ids := make([]string, 0, len(freeBusy))
+		for _, pc := range freeBusy {
+			ids = append(ids, pc.ProviderCalendarID)
 		}
 		busyByID, err := s.getGoogleBusyTimesMulti(ctx, conn, ids, start, end)
-		if err != nil {
-			for _, pc := range calendars {
+		for _, pc := range freeBusy {
+			if err != nil {
 				errs[pc.ID] = err
+				continue
 			}
-			return nil, errs
-		}
-		var all []models.TimeSlot
-		for _, pc := range calendars {
-			all = append(all, busyByID[pc.ProviderCalendarID]...)
+			busy[pc.ID] = busyByID[pc.ProviderCalendarID]
 		}
-		return all, errs
-	case models.CalendarProviderCalDAV, models.CalendarProviderICloud:
-		var all []models.TimeSlot
+		return busy, errs
+	case models.CalendarProviderOutlook, models.CalendarProviderCalDAV, models.CalendarProviderICloud, models.CalendarProviderICSFeed:
 		for _, pc := range calendars {
-			busy, err := s.busyTimesForProviderCalendar(ctx, conn, pc, start, end)
+			slots, err := s.busyTimesForProviderCalendar(ctx, conn, pc, owner, start, end)
 			if err != nil {
 				errs[pc.ID] = err
 				continue
 			}
-			all = append(all, busy...)
+			busy[pc.ID] = slots
 		}
-		return all, errs
+		return busy, errs
 	}
-	return nil, errs
+	return busy, errs
 }
 
 // getGoogleBusyTimesMulti issues a single freeBusy request covering multiple
@@ -601,8 +734,12 @@ type calendarEventWriter interface {
 // BookingWithDetails. Description includes the template description, any
 // invitee-supplied agenda, host notes (when surfacing on update), and the
 // reschedule link — i.e. what the previous booking-coupled providers used to
-// build inline.
+// build inline. With details.Seats set it is the seats' shared event instead.
 func (s *CalendarService) BuildCalendarEventInputForBooking(details *BookingWithDetails) *CalendarEventInput {
+	if len(details.Seats) > 0 {
+		return s.buildSeatsEventInput(details)
+	}
+
 	attendees := make([]string, 0, 1+len(details.Booking.AdditionalGuests))
 	if details.Booking.InviteeEmail != "" {
 		attendees = append(attendees, details.Booking.InviteeEmail)
@@ -651,6 +788,38 @@ func (s *CalendarService) BuildCalendarEventInputForBooking(details *BookingWith
 	}
 }
 
+// buildSeatsEventInput composes the calendar event shared by the seats of a
+// slot: every seat's invitee and guests attend, and as each invitee has their
+// own answers and reschedule link, only the template description is shown.
+func (s *CalendarService) buildSeatsEventInput(details *BookingWithDetails) *CalendarEventInput {
+	var attendees []string
+	for _, seat := range details.Seats {
+		if seat.InviteeEmail != "" {
+			attendees = append(attendees, seat.InviteeEmail)
+		}
+		for _, guest := range seat.AdditionalGuests {
+			if isValidEmail(guest) {
+				attendees = append(attendees, guest)
+			}
+		}
+	}
+
+	return &CalendarEventInput{
+		Summary:            details.Template.Name,
+		Description:        details.Template.Description,
+		Start:              details.Booking.StartTime.Time,
+		End:                details.Booking.EndTime.Time,
+		LocationType:       details.Template.LocationType,
+		CustomLocation:     details.Template.CustomLocation,
+		ConferenceLink:     details.Booking.ConferenceLink,
+		Attendees:          attendees,
+		HostName:           details.Host.Name,
+		HostEmail:          details.Host.Email,
+		EventID:            details.Booking.CalendarEventID,
+		MeetIdempotencyKey: details.Booking.ID,
+	}
+}
+
 // CreateEvent is the booking-side adapter for legacy single-host call sites
 // that still hand over a *BookingWithDetails. Builds an input and delegates.
 // The returned conferenceLink is also written onto details.Booking.ConferenceLink
@@ -695,6 +864,10 @@ func (s *CalendarService) CreateEventForHost(ctx context.Context, providerCalend
 	case models.CalendarProviderGoogle:
 		view.CalendarID = pc.ProviderCalendarID
 		return s.createGoogleEvent(ctx, &view, input)
+	case models.CalendarProviderOutlook:
+		view.CalendarID = pc.ProviderCalendarID
+		eventID, err := s.createOutlookEvent(ctx, &view, input)
+		return eventID, "", err
 	case models.CalendarProviderCalDAV, models.CalendarProviderICloud:
 		if pc.ProviderCalendarID != "" {
 			view.CalDAVURL = pc.ProviderCalendarID
@@ -710,9 +883,9 @@ func (s *CalendarService) CreateEventForHost(ctx context.Context, providerCalend
 // reconnect-then-edit produces a fresh event — and the new event ID is
 // returned to the caller, which is responsible for persisting it.
 //
-// For Google: PATCH so existing attendees keep their RSVP state. For CalDAV:
-// replaces the event via delete + create; the new ID is returned and may
-// differ from input.EventID.
+// For Google and Outlook: PATCH so existing attendees keep their RSVP state.
+// For CalDAV: replaces the event via delete + create; the new ID is returned
+// and may differ from input.EventID.
 func (s *CalendarService) UpdateEvent(ctx context.Context, providerCalendarID string, input *CalendarEventInput) (string, error) {
 	if input.EventID == "" {
 		eventID, _, err := s.CreateEventForHost(ctx, providerCalendarID, input)
@@ -739,6 +912,9 @@ func (s *CalendarService) UpdateEvent(ctx context.Context, providerCalendarID st
 	case models.CalendarProviderGoogle:
 		view.CalendarID = pc.ProviderCalendarID
 		return s.updateGoogleEvent(ctx, &view, input)
+	case models.CalendarProviderOutlook:
+		view.CalendarID = pc.ProviderCalendarID
+		return s.updateOutlookEvent(ctx, &view, input)
 	case models.CalendarProviderCalDAV, models.CalendarProviderICloud:
 		if pc.ProviderCalendarID != "" {
 			view.CalDAVURL = pc.ProviderCalendarID
@@ -774,6 +950,8 @@ func (s *CalendarService) DeleteEvent(ctx context.Context, hostID, providerCalen
 	case models.CalendarProviderGoogle:
 		view.CalendarID = pc.ProviderCalendarID
 		return s.deleteGoogleEvent(ctx, &view, eventID)
+	case models.CalendarProviderOutlook:
+		return s.deleteOutlookEvent(ctx, &view, eventID)
 	case models.CalendarProviderCalDAV, models.CalendarProviderICloud:
 		if pc.ProviderCalendarID != "" {
 			view.CalDAVURL = pc.ProviderCalendarID
@@ -783,6 +961,47 @@ func (s *CalendarService) DeleteEvent(ctx context.Context, hostID, providerCalen
 	return nil
 }
 
+// ExternalEvent is the provider's current copy of an event MeetWhen wrote.
+type ExternalEvent struct {
+	Deleted  bool // removed, or cancelled, at the provider
+	Start    time.Time
+	End      time.Time
+	IsAllDay bool
+}
+
+// GetEvent reads back an event MeetWhen created in a provider calendar so
+// the reconciler can spot edits made directly in the host's calendar. A
+// missing or cancelled event is reported as Deleted rather than an error;
+// errors mean the state is unknown and must not be acted on.
+func (s *CalendarService) GetEvent(ctx context.Context, providerCalendarID, eventID string) (*ExternalEvent, error) {
+	pc, err := s.repos.ProviderCalendar.GetByID(ctx, providerCalendarID)
+	if err != nil {
+		return nil, err
+	}
+	if pc == nil {
+		return nil, ErrCalendarNotFound
+	}
+	conn, err := s.repos.Calendar.GetByID(ctx, pc.ConnectionID)
+	if err != nil || conn == nil {
+		return nil, ErrCalendarNotFound
+	}
+
+	view := *conn
+	switch conn.Provider {
+	case models.CalendarProviderGoogle:
+		view.CalendarID = pc.ProviderCalendarID
+		return s.getGoogleEvent(ctx, &view, eventID)
+	case models.CalendarProviderOutlook:
+		return s.getOutlookEvent(ctx, &view, eventID)
+	case models.CalendarProviderCalDAV, models.CalendarProviderICloud:
+		if pc.ProviderCalendarID != "" {
+			view.CalDAVURL = pc.ProviderCalendarID
+		}
+		return s.getCalDAVEvent(ctx, &view, eventID)
+	}
+	return nil, fmt.Errorf("provider %s does not hold MeetWhen events", conn.Provider)
+}
+
 // GetGoogleAuthURL returns the Google OAuth URL
 func (s *CalendarService) GetGoogleAuthURL(state string) string {
 	return fmt.Sprintf(
@@ -1018,20 +1237,28 @@ func normalizeColor(c string) string {
 // busyTimesForProviderCalendar fetches busy times from the upstream provider
 // for a single calendar. It dispatches by provider and synthesizes the
 // per-call inputs the legacy single-calendar implementations need.
-func (s *CalendarService) busyTimesForProviderCalendar(ctx context.Context, conn *models.CalendarConnection, pc *models.ProviderCalendar, start, end time.Time) ([]models.TimeSlot, error) {
+func (s *CalendarService) busyTimesForProviderCalendar(ctx context.Context, conn *models.CalendarConnection, pc *models.ProviderCalendar, owner calendarOwner, start, end time.Time) ([]models.TimeSlot, error) {
+	policy := policyForCalendar(pc, owner)
 	switch conn.Provider {
 	case models.CalendarProviderGoogle:
+		if !policy.matchesFreeBusy() {
+			return s.getGoogleEventBusyTimes(ctx, conn, pc.ProviderCalendarID, policy, start, end)
+		}
 		// Use a temporary connection struct overriding CalendarID with this
 		// sub-calendar's id so the existing freebusy implementation can be reused.
 		view := *conn
 		view.CalendarID = pc.ProviderCalendarID
 		return s.getGoogleBusyTimes(ctx, &view, start, end)
+	case models.CalendarProviderOutlook:
+		return s.outlookBusyTimes(ctx, conn, pc, policy, start, end)
+	case models.CalendarProviderICSFeed:
+		return s.getICSFeedBusyTimes(ctx, conn.CalDAVURL, policy, start, end)
 	case models.CalendarProviderCalDAV, models.CalendarProviderICloud:
 		view := *conn
 		if pc.ProviderCalendarID != "" {
 			view.CalDAVURL = pc.ProviderCalendarID
 		}
-		return s.getCalDAVBusyTimes(ctx, &view, start, end)
+		return s.getCalDAVBusyTimes(ctx, &view, policy, start, end)
 	}
 	return nil, nil
 }
@@ -1270,6 +1497,50 @@ func (s *CalendarService) deleteGoogleEvent(ctx context.Context, cal *models.Cal
 	return nil
 }
 
+func (s *CalendarService) getGoogleEvent(ctx context.Context, cal *models.CalendarConnection, eventID string) (*ExternalEvent, error) {
+	if err := s.refreshGoogleToken(cal); err != nil {
+		return nil, err
+	}
+
+	eventURL := fmt.Sprintf("https://www.googleapis.com/calendar/v3/calendars/%s/events/%s", url.PathEscape(cal.CalendarID), url.PathEscape(eventID))
+	req, _ := http.NewRequestWithContext(ctx, "GET", eventURL, nil)
+	req.Header.Set("Authorization", "Bearer "+cal.AccessToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("Error closing response body: %v", err)
+		}
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusGone:
+		return &ExternalEvent{Deleted: true}, nil
+	case http.StatusUnauthorized:
+		return nil, ErrCalendarAuth
+	default:
+		raw, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("event request failed (%d): %s", resp.StatusCode, string(raw))
+	}
+
+	var ev googleBusyEvent
+	if err := json.NewDecoder(resp.Body).Decode(&ev); err != nil {
+		return nil, err
+	}
+	if ev.Status == "cancelled" {
+		return &ExternalEvent{Deleted: true}, nil
+	}
+	start, end, isAllDay, ok := ev.span()
+	if !ok {
+		return nil, fmt.Errorf("event %s has no usable start/end", eventID)
+	}
+	return &ExternalEvent{Start: start.UTC(), End: end.UTC(), IsAllDay: isAllDay}, nil
+}
+
 // CalDAV implementations (simplified)
 
 func (s *CalendarService) validateCalDAVConnection(url, username, password string) error {
@@ -1293,7 +1564,7 @@ func (s *CalendarService) validateCalDAVConnection(url, username, password strin
 	return nil
 }
 
-func (s *CalendarService) getCalDAVBusyTimes(ctx context.Context, cal *models.CalendarConnection, start, end time.Time) ([]models.TimeSlot, error) {
+func (s *CalendarService) getCalDAVBusyTimes(ctx context.Context, cal *models.CalendarConnection, policy busyPolicy, start, end time.Time) ([]models.TimeSlot, error) {
 	// Use calendar-query REPORT to fetch VEVENTs in the time range
 	// This is more widely supported than free-busy-query, especially on iCloud
 	query := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8" ?>
@@ -1341,14 +1612,17 @@ func (s *CalendarService) getCalDAVBusyTimes(ctx context.Context, cal *models.Ca
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
+	if resp.StatusCode != http.StatusMultiStatus && resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("CalDAV REPORT returned %d", resp.StatusCode)
+	}
 
 	// Extract busy times from VCALENDAR data in the response
-	busyTimes := parseCalDAVResponse(string(body), start, end)
+	busyTimes := parseCalDAVResponse(string(body), policy, start, end)
 	return busyTimes, nil
 }
 
 // parseCalDAVResponse extracts busy times from CalDAV XML response containing VCALENDAR data
-func parseCalDAVResponse(body string, rangeStart, rangeEnd time.Time) []models.TimeSlot {
+func parseCalDAVResponse(body string, policy busyPolicy, rangeStart, rangeEnd time.Time) []models.TimeSlot {
 	var busyTimes []models.TimeSlot
 
 	// Find all calendar-data content (contains ICS data)
@@ -1356,7 +1630,7 @@ func parseCalDAVResponse(body string, rangeStart, rangeEnd time.Time) []models.T
 	calDataParts := extractCalendarData(body)
 
 	for _, icsData := range calDataParts {
-		events := parseVEvents(icsData, rangeStart, rangeEnd)
+		events := parseVEvents(icsData, policy, rangeStart, rangeEnd)
 		busyTimes = append(busyTimes, events...)
 	}
 
@@ -1409,73 +1683,11 @@ func extractCalendarData(xmlBody string) []string {
 	return results
 }
 
-// parseVEvents extracts VEVENT start/end times from ICS data
-func parseVEvents(icsData string, rangeStart, rangeEnd time.Time) []models.TimeSlot {
-	var events []models.TimeSlot
-
-	// Split into lines and unfold (ICS allows long lines to be folded with leading space)
-	lines := unfoldICSLines(icsData)
-
-	var inEvent bool
-	var eventStart, eventEnd time.Time
-	var hasStart, hasEnd bool
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		if line == "BEGIN:VEVENT" {
-			inEvent = true
-			hasStart = false
-			hasEnd = false
-			eventStart = time.Time{}
-			eventEnd = time.Time{}
-			continue
-		}
-
-		if line == "END:VEVENT" {
-			if inEvent && hasStart && hasEnd {
-				// Check if event overlaps with our query range
-				if eventEnd.After(rangeStart) && eventStart.Before(rangeEnd) {
-					events = append(events, models.TimeSlot{
-						Start: eventStart,
-						End:   eventEnd,
-					})
-				}
-			}
-			inEvent = false
-			continue
-		}
-
-		if !inEvent {
-			continue
-		}
-
-		// Parse DTSTART
-		if strings.HasPrefix(line, "DTSTART") {
-			if t, ok := parseICSDateTime(line); ok {
-				eventStart = t
-				hasStart = true
-			}
-		}
-
-		// Parse DTEND
-		if strings.HasPrefix(line, "DTEND") {
-			if t, ok := parseICSDateTime(line); ok {
-				eventEnd = t
-				hasEnd = true
-			}
-		}
-
-		// Handle DURATION if DTEND is not present
-		if strings.HasPrefix(line, "DURATION") && hasStart && !hasEnd {
-			if d, ok := parseICSDuration(line); ok {
-				eventEnd = eventStart.Add(d)
-				hasEnd = true
-			}
-		}
-	}
-
-	return events
+// parseVEvents extracts busy time slots from ICS data. Recurring events are
+// expanded so every instance that overlaps the range is reported, and each
+// instance is kept or dropped according to the calendar's busy policy.
+func parseVEvents(icsData string, policy busyPolicy, rangeStart, rangeEnd time.Time) []models.TimeSlot {
+	return policy.icsBusySlots(icsData, rangeStart, rangeEnd)
 }
 
 // unfoldICSLines handles ICS line folding (continuation lines start with space or tab)
@@ -1503,72 +1715,6 @@ func unfoldICSLines(icsData string) []string {
 	return result
 }
 
-// parseICSDateTime parses various ICS date-time formats
-// Handles: DTSTART:20240115T100000Z, DTSTART;TZID=America/New_York:20240115T100000
-// Also handles VALUE=DATE for all-day events: DTSTART;VALUE=DATE:20240115
-func parseICSDateTime(line string) (time.Time, bool) {
-	// Split on colon to get the value part
-	colonIdx := strings.LastIndex(line, ":")
-	if colonIdx == -1 {
-		return time.Time{}, false
-	}
-
-	value := strings.TrimSpace(line[colonIdx+1:])
-	params := line[:colonIdx]
-
-	// Check if it's a date-only value (all-day event)
-	isDateOnly := strings.Contains(params, "VALUE=DATE")
-
-	if isDateOnly {
-		// Format: YYYYMMDD
-		if len(value) >= 8 {
-			t, err := time.Parse("20060102", value[:8])
-			if err == nil {
-				return t, true
-			}
-		}
-		return time.Time{}, false
-	}
-
-	// Try UTC format first: YYYYMMDDTHHMMSSZ
-	if strings.HasSuffix(value, "Z") {
-		t, err := time.Parse("20060102T150405Z", value)
-		if err == nil {
-			return t, true
-		}
-	}
-
-	// Try local format: YYYYMMDDTHHMMSS
-	// If TZID is specified, we should use it, but for simplicity we'll treat as UTC
-	// as busy times are typically converted anyway
-	if len(value) >= 15 {
-		t, err := time.Parse("20060102T150405", value[:15])
-		if err == nil {
-			// Check for TZID in params
-			if strings.Contains(params, "TZID=") {
-				// Extract timezone and try to load it
-				tzStart := strings.Index(params, "TZID=")
-				if tzStart != -1 {
-					tzPart := params[tzStart+5:]
-					tzEnd := strings.IndexAny(tzPart, ";:")
-					if tzEnd == -1 {
-						tzEnd = len(tzPart)
-					}
-					tzName := tzPart[:tzEnd]
-					if loc, err := time.LoadLocation(tzName); err == nil {
-						t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
-						return t.UTC(), true
-					}
-				}
-			}
-			// No timezone info, assume UTC
-			return t.UTC(), true
-		}
-	}
-
-	return time.Time{}, false
-}
-
 // parseICSDuration parses ICS DURATION format (RFC 5545)
 // Format: DURATION:P1DT2H30M (1 day, 2 hours, 30 minutes)
 func parseICSDuration(line string) (time.Duration, bool) {
@@ -1705,6 +1851,48 @@ END:VCALENDAR`,
 	return eventUID, nil
 }
 
+func (s *CalendarService) getCalDAVEvent(ctx context.Context, cal *models.CalendarConnection, eventID string) (*ExternalEvent, error) {
+	eventURL := fmt.Sprintf("%s/%s.ics", strings.TrimSuffix(cal.CalDAVURL, "/"), eventID)
+	req, _ := http.NewRequestWithContext(ctx, "GET", eventURL, nil)
+	req.SetBasicAuth(cal.CalDAVUsername, cal.CalDAVPassword)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("Error closing response body: %v", err)
+		}
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusGone:
+		return &ExternalEvent{Deleted: true}, nil
+	case http.StatusUnauthorized:
+		return nil, ErrCalendarAuth
+	default:
+		return nil, fmt.Errorf("CalDAV GET failed (%d)", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	// Events we write carry UTC times, so the floating zone never applies.
+	for _, ev := range parseICSEvents(string(body), time.UTC) {
+		if ev.UID != eventID || !ev.RecurrenceID.IsZero() {
+			continue
+		}
+		if ev.Status == "CANCELLED" {
+			return &ExternalEvent{Deleted: true}, nil
+		}
+		return &ExternalEvent{Start: ev.Start.UTC(), End: ev.End.UTC(), IsAllDay: ev.IsAllDay}, nil
+	}
+	return &ExternalEvent{Deleted: true}, nil
+}
+
 func (s *CalendarService) deleteCalDAVEvent(ctx context.Context, cal *models.CalendarConnection, eventID string) error {
 	eventURL := fmt.Sprintf("%s/%s.ics", strings.TrimSuffix(cal.CalDAVURL, "/"), eventID)
 	req, _ := http.NewRequest("DELETE", eventURL, nil)
@@ -1746,19 +1934,20 @@ func (s *CalendarService) GetAgendaEvents(ctx context.Context, hostID string, st
 }
 
 // GetAgendaEventsWithCalendars fetches events for the provided connections (each
-// pre-populated with .Calendars) without reloading from DB. Host is accepted
-// for future use (e.g. timezone) and may be nil.
+// pre-populated with .Calendars) without reloading from DB. Host may be nil;
+// when set, floating ICS times are read in the host's timezone.
 //
 // Only provider calendars with poll_busy=true contribute events to the agenda.
 func (s *CalendarService) GetAgendaEventsWithCalendars(ctx context.Context, connections []*models.CalendarConnection, host *models.Host, start, end time.Time) ([]AgendaEvent, error) {
 	var allEvents []AgendaEvent
 
 	for _, conn := range connections {
+		owner := ownerForConnection(host, conn)
 		for _, pc := range conn.Calendars {
 			if !pc.PollBusy {
 				continue
 			}
-			events, fetchErr := s.agendaEventsForProviderCalendar(ctx, conn, pc, start, end)
+			events, fetchErr := s.agendaEventsForProviderCalendar(ctx, conn, pc, owner.Location, start, end)
 			if fetchErr != nil {
 				log.Printf("Failed to fetch agenda events from calendar %s (%s): %v", pc.Name, pc.ID, fetchErr)
 				continue
@@ -1771,7 +1960,7 @@ func (s *CalendarService) GetAgendaEventsWithCalendars(ctx context.Context, conn
 	return allEvents, nil
 }
 
-func (s *CalendarService) agendaEventsForProviderCalendar(ctx context.Context, conn *models.CalendarConnection, pc *models.ProviderCalendar, start, end time.Time) ([]AgendaEvent, error) {
+func (s *CalendarService) agendaEventsForProviderCalendar(ctx context.Context, conn *models.CalendarConnection, pc *models.ProviderCalendar, floating *time.Location, start, end time.Time) ([]AgendaEvent, error) {
 	view := *conn
 	color := pc.Color
 	if color == "" {
@@ -1795,11 +1984,23 @@ func (s *CalendarService) agendaEventsForProviderCalendar(ctx context.Context, c
 			events[i].CalendarName = pc.Name
 		}
 		return events, nil
+	case models.CalendarProviderOutlook:
+		view.CalendarID = pc.ProviderCalendarID
+		events, err := s.getOutlookAgendaEvents(ctx, &view, start, end)
+		if err != nil {
+			return nil, err
+		}
+		for i := range events {
+			events[i].CalendarID = pc.ID
+			events[i].CalendarColor = color
+			events[i].CalendarName = pc.Name
+		}
+		return events, nil
 	case models.CalendarProviderCalDAV, models.CalendarProviderICloud:
 		if pc.ProviderCalendarID != "" {
 			view.CalDAVURL = pc.ProviderCalendarID
 		}
-		events, err := s.getCalDAVAgendaEvents(ctx, &view, start, end)
+		events, err := s.getCalDAVAgendaEvents(ctx, &view, floating, start, end)
 		if err != nil {
 			return nil, err
 		}
@@ -1809,6 +2010,9 @@ func (s *CalendarService) agendaEventsForProviderCalendar(ctx context.Context, c
 			events[i].CalendarName = pc.Name
 		}
 		return events, nil
+	case models.CalendarProviderICSFeed:
+		view.ID = pc.ID
+		return s.getICSFeedAgendaEvents(ctx, &view, floating, start, end)
 	}
 	return nil, nil
 }
@@ -1908,7 +2112,7 @@ func (s *CalendarService) getGoogleAgendaEvents(ctx context.Context, cal *models
 }
 
 // getCalDAVAgendaEvents fetches events from CalDAV/iCloud for the agenda view
-func (s *CalendarService) getCalDAVAgendaEvents(ctx context.Context, cal *models.CalendarConnection, start, end time.Time) ([]AgendaEvent, error) {
+func (s *CalendarService) getCalDAVAgendaEvents(ctx context.Context, cal *models.CalendarConnection, floating *time.Location, start, end time.Time) ([]AgendaEvent, error) {
 	// Use calendar-query REPORT to fetch VEVENTs in the time range
 	query := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8" ?>
 <C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
@@ -1960,116 +2164,42 @@ func (s *CalendarService) getCalDAVAgendaEvents(ctx context.Context, cal *models
 		calColor = "#5F5E5A"
 	}
 	// Extract agenda events from VCALENDAR data in the response
-	events := parseCalDAVAgendaResponse(string(body), cal.Name, cal.ID, calColor, start, end)
+	events := parseCalDAVAgendaResponse(string(body), cal.Name, cal.ID, calColor, floating, start, end)
 	return events, nil
 }
 
 // parseCalDAVAgendaResponse extracts agenda events from CalDAV XML response containing VCALENDAR data
-func parseCalDAVAgendaResponse(body string, calendarName string, calendarID string, calendarColor string, rangeStart, rangeEnd time.Time) []AgendaEvent {
+func parseCalDAVAgendaResponse(body string, calendarName string, calendarID string, calendarColor string, floating *time.Location, rangeStart, rangeEnd time.Time) []AgendaEvent {
 	var events []AgendaEvent
 
 	calDataParts := extractCalendarData(body)
 
 	for _, icsData := range calDataParts {
-		parsedEvents := parseVEventsForAgenda(icsData, calendarName, calendarID, calendarColor, rangeStart, rangeEnd)
+		parsedEvents := parseVEventsForAgenda(icsData, calendarName, calendarID, calendarColor, floating, rangeStart, rangeEnd)
 		events = append(events, parsedEvents...)
 	}
 
 	return events
 }
 
-// parseVEventsForAgenda extracts VEVENT details for the agenda view from ICS data
-func parseVEventsForAgenda(icsData string, calendarName string, calendarID string, calendarColor string, rangeStart, rangeEnd time.Time) []AgendaEvent {
+// parseVEventsForAgenda extracts VEVENT details for the agenda view from ICS
+// data. Each instance of a recurring event gets its own ID (UID plus instance
+// start) so the agenda can tell them apart. Floating times are read in the
+// floating location (nil means UTC).
+func parseVEventsForAgenda(icsData string, calendarName string, calendarID string, calendarColor string, floating *time.Location, rangeStart, rangeEnd time.Time) []AgendaEvent {
 	var events []AgendaEvent
 
-	// Split into lines and unfold
-	lines := unfoldICSLines(icsData)
-
-	var inEvent bool
-	var eventStart, eventEnd time.Time
-	var eventTitle, eventUID string
-	var hasStart, hasEnd bool
-	var isAllDay bool
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		if line == "BEGIN:VEVENT" {
-			inEvent = true
-			hasStart = false
-			hasEnd = false
-			isAllDay = false
-			eventStart = time.Time{}
-			eventEnd = time.Time{}
-			eventTitle = ""
-			eventUID = ""
-			continue
-		}
-
-		if line == "END:VEVENT" {
-			if inEvent && hasStart && hasEnd {
-				// Check if event overlaps with our query range
-				if eventEnd.After(rangeStart) && eventStart.Before(rangeEnd) {
-					events = append(events, AgendaEvent{
-						ID:            eventUID,
-						CalendarID:    calendarID,
-						CalendarColor: calendarColor,
-						Title:         eventTitle,
-						Start:         eventStart,
-						End:           eventEnd,
-						CalendarName:  calendarName,
-						IsAllDay:      isAllDay,
-					})
-				}
-			}
-			inEvent = false
-			continue
-		}
-
-		if !inEvent {
-			continue
-		}
-
-		// Parse UID
-		if strings.HasPrefix(line, "UID:") {
-			eventUID = strings.TrimSpace(line[4:])
-		}
-
-		// Parse SUMMARY (title)
-		if strings.HasPrefix(line, "SUMMARY") {
-			colonIdx := strings.Index(line, ":")
-			if colonIdx != -1 {
-				eventTitle = strings.TrimSpace(line[colonIdx+1:])
-			}
-		}
-
-		// Parse DTSTART
-		if strings.HasPrefix(line, "DTSTART") {
-			// Check if it's an all-day event
-			if strings.Contains(line, "VALUE=DATE") && !strings.Contains(line, "VALUE=DATE-TIME") {
-				isAllDay = true
-			}
-			if t, ok := parseICSDateTime(line); ok {
-				eventStart = t
-				hasStart = true
-			}
-		}
-
-		// Parse DTEND
-		if strings.HasPrefix(line, "DTEND") {
-			if t, ok := parseICSDateTime(line); ok {
-				eventEnd = t
-				hasEnd = true
-			}
-		}
-
-		// Handle DURATION if DTEND is not present
-		if strings.HasPrefix(line, "DURATION") && hasStart && !hasEnd {
-			if d, ok := parseICSDuration(line); ok {
-				eventEnd = eventStart.Add(d)
-				hasEnd = true
-			}
-		}
+	for _, occ := range expandICSEvents(parseICSEvents(icsData, floating), rangeStart, rangeEnd) {
+		events = append(events, AgendaEvent{
+			ID:            occ.instanceID(),
+			CalendarID:    calendarID,
+			CalendarColor: calendarColor,
+			Title:         occ.Event.Summary,
+			Start:         occ.Start,
+			End:           occ.End,
+			CalendarName:  calendarName,
+			IsAllDay:      occ.Event.IsAllDay,
+		})
 	}
 
 	return events
@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// eventAvailability is how a single calendar event affects its owner's time.
+// Providers express this differently (ICS TRANSP/STATUS/PARTSTAT, Google
+// transparency/status/responseStatus); both are reduced to these values so a
+// single busyPolicy can decide what blocks availability.
+type eventAvailability string
+
+const (
+	eventBusy      eventAvailability = "busy"
+	eventTentative eventAvailability = "tentative"
+	eventFree      eventAvailability = "free"
+	eventDeclined  eventAvailability = "declined"
+	eventCancelled eventAvailability = "cancelled"
+)
+
+// classifyICSEvent derives the availability of a VEVENT. selfEmails are the
+// calendar owner's addresses (lower-case), used to find the owner's own
+// ATTENDEE entry and its PARTSTAT.
+func classifyICSEvent(ev *icsEvent, selfEmails []string) eventAvailability {
+	if ev.Status == "CANCELLED" {
+		return eventCancelled
+	}
+
+	partStat := ""
+	for _, a := range ev.Attendees {
+		if slices.Contains(selfEmails, a.Email) {
+			partStat = a.PartStat
+			break
+		}
+	}
+
+	switch {
+	case partStat == "DECLINED":
+		return eventDeclined
+	case ev.Transp == "TRANSPARENT":
+		return eventFree
+	case ev.Status == "TENTATIVE" || partStat == "TENTATIVE":
+		return eventTentative
+	}
+	return eventBusy
+}
+
+// googleBusyEvent is the subset of a Google events.list item needed to
+// classify it.
+type googleBusyEvent struct {
+	ID           string `json:"id"`
+	Status       string `json:"status"`
+	Transparency string `json:"transparency"`
+	Start        struct {
+		DateTime string `json:"dateTime"`
+		Date     string `json:"date"`
+	} `json:"start"`
+	End struct {
+		DateTime string `json:"dateTime"`
+		Date     string `json:"date"`
+	} `json:"end"`
+	Attendees []struct {
+		Self           bool   `json:"self"`
+		ResponseStatus string `json:"responseStatus"`
+	} `json:"attendees"`
+}
+
+// classifyGoogleEvent derives the availability of a Google Calendar event.
+// Google marks the owner's own attendee entry with self=true.
+func classifyGoogleEvent(ev *googleBusyEvent) eventAvailability {
+	if ev.Status == "cancelled" {
+		return eventCancelled
+	}
+
+	response := ""
+	for _, a := range ev.Attendees {
+		if a.Self {
+			response = a.ResponseStatus
+			break
+		}
+	}
+
+	switch {
+	case response == "declined":
+		return eventDeclined
+	case ev.Transparency == "transparent":
+		return eventFree
+	case ev.Status == "tentative" || response == "tentative":
+		return eventTentative
+	}
+	return eventBusy
+}
+
+// calendarOwner identifies whose calendar is being read: the addresses the
+// owner appears under as an attendee, and the timezone all-day events are
+// anchored to.
+type calendarOwner struct {
+	Emails   []string
+	Location *time.Location
+}
+
+// ownerForConnection builds the calendarOwner for a host's connection. The
+// CalDAV username is included when it looks like an address, since iCloud and
+// most hosted CalDAV servers invite the account by that address.
+func ownerForConnection(host *models.Host, conn *models.CalendarConnection) calendarOwner {
+	owner := calendarOwner{Location: time.UTC}
+	addEmail := func(e string) {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e != "" && !slices.Contains(owner.Emails, e) {
+			owner.Emails = append(owner.Emails, e)
+		}
+	}
+
+	if host != nil {
+		addEmail(host.Email)
+		if host.GoogleEmail != nil {
+			addEmail(*host.GoogleEmail)
+		}
+		if loc, err := time.LoadLocation(host.Timezone); err == nil && host.Timezone != "" {
+			owner.Location = loc
+		}
+	}
+	if conn != nil && strings.Contains(conn.CalDAVUsername, "@") {
+		addEmail(conn.CalDAVUsername)
+	}
+	return owner
+}
+
+// busyPolicy decides which events on one calendar block availability. The
+// flags come from the ProviderCalendar; confirmed opaque events always block
+// and cancelled events never do.
+type busyPolicy struct {
+	OnTentative bool
+	OnFree      bool
+	OnDeclined  bool
+	Owner       calendarOwner
+}
+
+// defaultBusyPolicy mirrors the defaults of a freshly discovered calendar:
+// tentative events block, free and declined ones don't.
+func defaultBusyPolicy() busyPolicy {
+	return busyPolicy{OnTentative: true, Owner: calendarOwner{Location: time.UTC}}
+}
+
+// policyForCalendar returns the busy policy configured on pc for the given
+// owner. A nil pc yields the default policy.
+func policyForCalendar(pc *models.ProviderCalendar, owner calendarOwner) busyPolicy {
+	p := defaultBusyPolicy()
+	if pc != nil {
+		p.OnTentative = pc.BusyOnTentative
+		p.OnFree = pc.BusyOnFree
+		p.OnDeclined = pc.BusyOnDeclined
+	}
+	p.Owner = owner
+	return p
+}
+
+// blocks reports whether an event with the given availability counts as busy.
+func (p busyPolicy) blocks(a eventAvailability) bool {
+	switch a {
+	case eventBusy:
+		return true
+	case eventTentative:
+		return p.OnTentative
+	case eventFree:
+		return p.OnFree
+	case eventDeclined:
+		return p.OnDeclined
+	}
+	return false
+}
+
+// matchesFreeBusy reports whether the policy classifies events exactly as
+// the providers' own free/busy endpoints do (Google freeBusy, Graph
+// getSchedule), in which case those cheaper calls can be used instead of
+// listing events.
+func (p busyPolicy) matchesFreeBusy() bool {
+	return p.OnTentative && !p.OnFree && !p.OnDeclined
+}
+
+// slot converts an event span into a busy slot. All-day events carry their
+// dates as UTC midnights; they are re-anchored to midnight in the owner's
+// timezone so a day off in Sydney blocks the Sydney day, not the UTC one.
+func (p busyPolicy) slot(start, end time.Time, isAllDay bool) models.TimeSlot {
+	if !isAllDay {
+		return models.TimeSlot{Start: start.UTC(), End: end.UTC()}
+	}
+	loc := p.Owner.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	sy, sm, sd := start.UTC().Date()
+	ey, em, ed := end.UTC().Date()
+	return models.TimeSlot{
+		Start: time.Date(sy, sm, sd, 0, 0, 0, 0, loc).UTC(),
+		End:   time.Date(ey, em, ed, 0, 0, 0, 0, loc).UTC(),
+	}
+}
+
+// icsBusySlots expands the VEVENTs in icsData and returns the busy slots the
+// policy keeps within [rangeStart, rangeEnd). The expansion window is widened
+// by a day on each side so all-day events that only overlap the range once
+// shifted into the owner's timezone are not lost.
+func (p busyPolicy) icsBusySlots(icsData string, rangeStart, rangeEnd time.Time) []models.TimeSlot {
+	var slots []models.TimeSlot
+	events := parseICSEvents(icsData, p.Owner.Location)
+	for _, occ := range expandICSEvents(events, rangeStart.AddDate(0, 0, -1), rangeEnd.AddDate(0, 0, 1)) {
+		if !p.blocks(classifyICSEvent(occ.Event, p.Owner.Emails)) {
+			continue
+		}
+		s := p.slot(occ.Start, occ.End, occ.Event.IsAllDay)
+		if s.End.After(rangeStart) && s.Start.Before(rangeEnd) {
+			slots = append(slots, s)
+		}
+	}
+	return slots
+}
+
+// span returns the event's start and end. All-day events come back as
+// UTC-midnight dates like ICS all-day events.
+func (ev *googleBusyEvent) span() (start, end time.Time, isAllDay, ok bool) {
+	if ev.Start.Date != "" {
+		start, err1 := time.Parse("2006-01-02", ev.Start.Date)
+		end, err2 := time.Parse("2006-01-02", ev.End.Date)
+		return start, end, true, err1 == nil && err2 == nil
+	}
+	start, err1 := time.Parse(time.RFC3339, ev.Start.DateTime)
+	end, err2 := time.Parse(time.RFC3339, ev.End.DateTime)
+	return start, end, false, err1 == nil && err2 == nil
+}
+
+// googleBusySlots applies the policy to Google events.list items.
+func (p busyPolicy) googleBusySlots(items []googleBusyEvent) []models.TimeSlot {
+	var slots []models.TimeSlot
+	for i := range items {
+		ev := &items[i]
+		if !p.blocks(classifyGoogleEvent(ev)) {
+			continue
+		}
+		start, end, isAllDay, ok := ev.span()
+		if !ok {
+			continue
+		}
+		slots = append(slots, p.slot(start, end, isAllDay))
+	}
+	return slots
+}
+
+// getGoogleEventBusyTimes lists the events of one Google calendar and applies
+// the busy policy to each. Used instead of freeBusy when the calendar's
+// policy differs from Google's own classification.
+func (s *CalendarService) getGoogleEventBusyTimes(ctx context.Context, conn *models.CalendarConnection, calendarID string, policy busyPolicy, start, end time.Time) ([]models.TimeSlot, error) {
+	items, _, err := s.listGoogleEvents(ctx, conn, calendarID, url.Values{
+		"timeMin":     {start.UTC().Format(time.RFC3339)},
+		"timeMax":     {end.UTC().Format(time.RFC3339)},
+		"showDeleted": {"false"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return policy.googleBusySlots(items), nil
+}
+
+// errGoogleSyncTokenExpired is returned by listGoogleEvents when Google
+// answers 410 Gone to a syncToken request; the caller must resync in full.
+var errGoogleSyncTokenExpired = errors.New("google sync token expired")
+
+// listGoogleEvents pages through events.list for one calendar with recurring
+// events expanded, returning the items and the nextSyncToken Google hands out
+// on the last page.
+func (s *CalendarService) listGoogleEvents(ctx context.Context, conn *models.CalendarConnection, calendarID string, params url.Values) ([]googleBusyEvent, string, error) {
+	if err := s.refreshGoogleToken(conn); err != nil {
+		return nil, "", err
+	}
+
+	params.Set("singleEvents", "true")
+	params.Set("maxResults", "2500")
+
+	var items []googleBusyEvent
+	for {
+		eventsURL := fmt.Sprintf("https://www.googleapis.com/calendar/v3/calendars/%s/events?%s",
+			url.PathEscape(calendarID), params.Encode())
+
+		req, _ := http.NewRequestWithContext(ctx, "GET", eventsURL, nil)
+		req.Header.Set("Authorization", "Bearer "+conn.AccessToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized {
+			_ = resp.Body.Close()
+			return nil, "", ErrCalendarAuth
+		}
+		if resp.StatusCode == http.StatusGone && params.Get("syncToken") != "" {
+			_ = resp.Body.Close()
+			return nil, "", errGoogleSyncTokenExpired
+		}
+		if resp.StatusCode != http.StatusOK {
+			raw, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			return nil, "", fmt.Errorf("events request failed (%d): %s", resp.StatusCode, string(raw))
+		}
+
+		var page struct {
+			Items         []googleBusyEvent `json:"items"`
+			NextPageToken string            `json:"nextPageToken"`
+			NextSyncToken string            `json:"nextSyncToken"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("Error closing response body: %v", cerr)
+		}
+		if err != nil {
+			return nil, "", err
+		}
+
+		items = append(items, page.Items...)
+		if page.NextPageToken == "" {
+			return items, page.NextSyncToken, nil
+		}
+		params.Set("pageToken", page.NextPageToken)
+	}
+}
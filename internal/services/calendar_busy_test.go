@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+const busyClassificationICS = `
+UID:opaque
+DTSTART:20240110T090000Z
+DTEND:20240110T100000Z
+---
+UID:transparent
+TRANSP:TRANSPARENT
+DTSTART:20240110T100000Z
+DTEND:20240110T110000Z
+---
+UID:cancelled
+STATUS:CANCELLED
+DTSTART:20240110T110000Z
+DTEND:20240110T120000Z
+---
+UID:tentative
+STATUS:TENTATIVE
+DTSTART:20240110T120000Z
+DTEND:20240110T130000Z
+---
+UID:declined
+ATTENDEE;CN=Host;PARTSTAT=DECLINED:mailto:Host@Example.com
+ATTENDEE;PARTSTAT=ACCEPTED:mailto:other@example.com
+DTSTART:20240110T130000Z
+DTEND:20240110T140000Z`
+
+func busyClassificationCalendar() string {
+	return icsCalendar(strings.Split(busyClassificationICS, "---")...)
+}
+
+func slotHours(slots []models.TimeSlot) []int {
+	var out []int
+	for _, s := range slots {
+		out = append(out, s.Start.UTC().Hour())
+	}
+	return out
+}
+
+// TestICSBusySlots_DefaultPolicy checks the out-of-the-box classification:
+// opaque and tentative events block; transparent, cancelled and declined
+// events don't.
+func TestICSBusySlots_DefaultPolicy(t *testing.T) {
+	policy := policyForCalendar(nil, calendarOwner{Emails: []string{"host@example.com"}})
+	got := slotHours(policy.icsBusySlots(busyClassificationCalendar(),
+		mustUTC(t, "2024-01-10T00:00:00Z"), mustUTC(t, "2024-01-11T00:00:00Z")))
+
+	if want := []int{9, 12}; !slices.Equal(got, want) {
+		t.Errorf("busy hours = %v, want %v", got, want)
+	}
+}
+
+// TestICSBusySlots_PolicyFlags flips every flag on the calendar and checks
+// that cancelled events still never block.
+func TestICSBusySlots_PolicyFlags(t *testing.T) {
+	pc := &models.ProviderCalendar{BusyOnTentative: false, BusyOnFree: true, BusyOnDeclined: true}
+	policy := policyForCalendar(pc, calendarOwner{Emails: []string{"host@example.com"}})
+	got := slotHours(policy.icsBusySlots(busyClassificationCalendar(),
+		mustUTC(t, "2024-01-10T00:00:00Z"), mustUTC(t, "2024-01-11T00:00:00Z")))
+
+	if want := []int{9, 10, 13}; !slices.Equal(got, want) {
+		t.Errorf("busy hours = %v, want %v", got, want)
+	}
+}
+
+// TestICSBusySlots_AllDayUsesOwnerTimezone verifies that an all-day event
+// blocks midnight-to-midnight in the host's timezone, not in UTC, and is
+// still found when that local day starts before the query range in UTC.
+func TestICSBusySlots_AllDayUsesOwnerTimezone(t *testing.T) {
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	ics := icsCalendar(`
+UID:off
+DTSTART;VALUE=DATE:20240115
+DTEND;VALUE=DATE:20240116`)
+
+	policy := policyForCalendar(nil, calendarOwner{Location: sydney})
+	slots := policy.icsBusySlots(ics, mustUTC(t, "2024-01-15T00:00:00Z"), mustUTC(t, "2024-01-16T00:00:00Z"))
+	if len(slots) != 1 {
+		t.Fatalf("expected 1 busy slot, got %d", len(slots))
+	}
+	// Sydney is UTC+11 in January.
+	if want := mustUTC(t, "2024-01-14T13:00:00Z"); !slots[0].Start.Equal(want) {
+		t.Errorf("start = %s, want %s", slots[0].Start, want)
+	}
+	if want := mustUTC(t, "2024-01-15T13:00:00Z"); !slots[0].End.Equal(want) {
+		t.Errorf("end = %s, want %s", slots[0].End, want)
+	}
+}
+
+// TestGoogleBusySlots_SharesClassification feeds an events.list payload
+// through the same policy used for CalDAV.
+func TestGoogleBusySlots_SharesClassification(t *testing.T) {
+	payload := `[
+		{"status":"confirmed","start":{"dateTime":"2024-01-10T09:00:00Z"},"end":{"dateTime":"2024-01-10T10:00:00Z"}},
+		{"status":"confirmed","transparency":"transparent","start":{"dateTime":"2024-01-10T10:00:00Z"},"end":{"dateTime":"2024-01-10T11:00:00Z"}},
+		{"status":"tentative","start":{"dateTime":"2024-01-10T12:00:00Z"},"end":{"dateTime":"2024-01-10T13:00:00Z"}},
+		{"status":"confirmed","attendees":[{"self":true,"responseStatus":"declined"}],"start":{"dateTime":"2024-01-10T13:00:00Z"},"end":{"dateTime":"2024-01-10T14:00:00Z"}},
+		{"status":"confirmed","start":{"date":"2024-01-11"},"end":{"date":"2024-01-12"}}
+	]`
+	var items []googleBusyEvent
+	if err := json.Unmarshal([]byte(payload), &items); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	got := slotHours(defaultBusyPolicy().googleBusySlots(items))
+	if want := []int{9, 12, 0}; !slices.Equal(got, want) {
+		t.Errorf("busy hours = %v, want %v", got, want)
+	}
+
+	declinedBlocks := defaultBusyPolicy()
+	declinedBlocks.OnDeclined = true
+	if got := len(declinedBlocks.googleBusySlots(items)); got != 4 {
+		t.Errorf("with declined blocking: got %d slots, want 4", got)
+	}
+}
+
+// TestGetBusyTimes_AppliesCalendarBusyPolicy runs GetBusyTimes end-to-end
+// against a CalDAV mock and checks that the stored per-calendar policy is
+// honoured and the host's email identifies declined invitations.
+func TestGetBusyTimes_AppliesCalendarBusyPolicy(t *testing.T) {
+	// The fixture declines as Host@Example.com; the mock rewrites that to the
+	// seeded host's address once it is known.
+	var hostEmail string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ics := strings.ReplaceAll(busyClassificationCalendar(), "Host@Example.com", hostEmail)
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = w.Write([]byte(`<?xml version="1.0"?><multistatus xmlns="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav"><response><propstat><prop><C:calendar-data>` +
+			ics + `</C:calendar-data></prop></propstat></response></multistatus>`))
+	}))
+	defer srv.Close()
+
+	_, repos, cal := setupServiceTestDB(t)
+	host, conn := seedHostAndConnection(t, repos, models.CalendarProviderCalDAV, "", srv.URL+"/dav/")
+	hostEmail = host.Email
+	ctx := context.Background()
+
+	pc, err := repos.ProviderCalendar.UpsertFromProvider(ctx, conn.ID, srv.URL+"/dav/", "Work", "", true, true)
+	if err != nil {
+		t.Fatalf("upsert calendar: %v", err)
+	}
+
+	start := mustUTC(t, "2024-01-10T00:00:00Z")
+	end := start.Add(24 * time.Hour)
+
+	slots, err := cal.GetBusyTimes(ctx, host.ID, start, end)
+	if err != nil {
+		t.Fatalf("GetBusyTimes: %v", err)
+	}
+	if got, want := slotHours(slots), []int{9, 12}; !slices.Equal(got, want) {
+		t.Errorf("default policy busy hours = %v, want %v", got, want)
+	}
+
+	if err := repos.ProviderCalendar.UpdateBusyPolicy(ctx, host.ID, pc.ID, false, false, true); err != nil {
+		t.Fatalf("update policy: %v", err)
+	}
+	slots, err = cal.GetBusyTimes(ctx, host.ID, start, end)
+	if err != nil {
+		t.Fatalf("GetBusyTimes: %v", err)
+	}
+	if got, want := slotHours(slots), []int{9, 13}; !slices.Equal(got, want) {
+		t.Errorf("custom policy busy hours = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,490 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// The busy-time cache keeps a copy of every polled calendar's events in
+// busy_intervals so GetBusyTimes can answer from the database. The sync
+// loop refreshes it (incrementally where the provider allows); GetBusyTimes
+// only trusts a calendar's copy while it is younger than busyCacheMaxAge and
+// covers the requested range, and otherwise goes to the provider as before.
+
+const (
+	// defaultBusyCacheMaxAge applies when the config leaves the window unset.
+	// Twice the sync interval, so one missed tick doesn't fall back to live.
+	defaultBusyCacheMaxAge = 30 * time.Minute
+
+	// busyCacheLookback keeps the recent past cached so that a query for
+	// "today" starting at the host's local midnight is still covered.
+	busyCacheLookback = 48 * time.Hour
+
+	// busyCacheSlack is how far beyond the booking horizon a full sync
+	// reaches. Incremental syncs can't move the window, so once the horizon
+	// outruns it the next sync starts over with a fresh window.
+	busyCacheSlack = 14 * 24 * time.Hour
+)
+
+// busyCacheMaxAge is the staleness window for cached busy times.
+func (s *CalendarService) busyCacheMaxAge() time.Duration {
+	if s.cfg != nil && s.cfg.App.BusyCacheMaxAge > 0 {
+		return s.cfg.App.BusyCacheMaxAge
+	}
+	return defaultBusyCacheMaxAge
+}
+
+// busyCacheHorizon is how far ahead the cache must reach: as far as guests
+// can book.
+func (s *CalendarService) busyCacheHorizon() time.Duration {
+	days := 90
+	if s.cfg != nil && s.cfg.App.MaxSchedulingDays > 0 {
+		days = s.cfg.App.MaxSchedulingDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// busyCacheWindow is the range a full sync starting now reads.
+func (s *CalendarService) busyCacheWindow() (start, end time.Time) {
+	now := time.Now().UTC()
+	start = now.Add(-busyCacheLookback).Truncate(24 * time.Hour)
+	end = now.Add(s.busyCacheHorizon() + busyCacheSlack).Truncate(24 * time.Hour)
+	return start, end
+}
+
+// cachedBusyTimes returns pc's busy times within [start, end) from the cache,
+// applying the calendar's current busy policy. ok is false when the cache
+// doesn't cover the range or was last synced more than maxAge ago; a maxAge
+// of zero accepts any age.
+func (s *CalendarService) cachedBusyTimes(ctx context.Context, pc *models.ProviderCalendar, owner calendarOwner, start, end time.Time, maxAge time.Duration) ([]models.TimeSlot, bool) {
+	state, err := s.repos.BusyInterval.GetSyncState(ctx, pc.ID)
+	if err != nil || state == nil {
+		return nil, false
+	}
+	if maxAge > 0 && time.Since(state.SyncedAt.Time) > maxAge {
+		return nil, false
+	}
+	if start.Before(state.WindowStart.Time) || end.After(state.WindowEnd.Time) {
+		return nil, false
+	}
+
+	// All-day rows are stored as UTC dates and may land up to a day either
+	// side once anchored to the owner's timezone.
+	intervals, err := s.repos.BusyInterval.ListByCalendar(ctx, pc.ID, start.AddDate(0, 0, -1), end.AddDate(0, 0, 1))
+	if err != nil {
+		log.Printf("[CALENDAR] busy cache read failed for calendar %s: %v", pc.ID, err)
+		return nil, false
+	}
+
+	policy := policyForCalendar(pc, owner)
+	slots := make([]models.TimeSlot, 0, len(intervals))
+	for _, iv := range intervals {
+		if !policy.blocks(eventAvailability(iv.Availability)) {
+			continue
+		}
+		slot := policy.slot(iv.StartTime.Time, iv.EndTime.Time, iv.IsAllDay)
+		if slot.End.After(start) && slot.Start.Before(end) {
+			slots = append(slots, slot)
+		}
+	}
+	return slots, true
+}
+
+// syncBusyIntervals brings pc's cached busy intervals up to date. Google
+// calendars follow their syncToken and CalDAV collections their getctag and
+// sync-token; everything else, and any calendar whose window no longer
+// reaches the booking horizon, is re-read in full.
+func (s *CalendarService) syncBusyIntervals(ctx context.Context, conn *models.CalendarConnection, pc *models.ProviderCalendar, owner calendarOwner) error {
+	lock, _ := s.busySyncLocks.LoadOrStore(pc.ID, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	state, err := s.repos.BusyInterval.GetSyncState(ctx, pc.ID)
+	if err != nil {
+		return err
+	}
+
+	if state != nil && !state.WindowEnd.Before(time.Now().Add(s.busyCacheHorizon())) {
+		switch conn.Provider {
+		case models.CalendarProviderGoogle:
+			if state.SyncToken != "" {
+				return s.syncGoogleBusyIntervals(ctx, conn, pc, owner, state)
+			}
+		case models.CalendarProviderCalDAV, models.CalendarProviderICloud:
+			return s.syncCalDAVBusyIntervals(ctx, conn, pc, owner, state)
+		}
+	}
+
+	start, end := s.busyCacheWindow()
+	return s.resyncBusyIntervals(ctx, conn, pc, owner, start, end)
+}
+
+// resyncBusyIntervals replaces pc's cache with a full read of [start, end)
+// and records the provider's cursor for the next incremental sync.
+func (s *CalendarService) resyncBusyIntervals(ctx context.Context, conn *models.CalendarConnection, pc *models.ProviderCalendar, owner calendarOwner, start, end time.Time) error {
+	state := &models.BusySyncState{
+		CalendarID:  pc.ID,
+		WindowStart: models.NewSQLiteTime(start),
+		WindowEnd:   models.NewSQLiteTime(end),
+	}
+
+	var intervals []*models.BusyInterval
+	switch conn.Provider {
+	case models.CalendarProviderGoogle:
+		items, syncToken, err := s.listGoogleEvents(ctx, conn, pc.ProviderCalendarID, url.Values{
+			"timeMin": {start.Format(time.RFC3339)},
+			"timeMax": {end.Format(time.RFC3339)},
+		})
+		if err != nil {
+			return err
+		}
+		state.SyncToken = syncToken
+		for i := range items {
+			intervals = append(intervals, googleBusyIntervals(&items[i], start, end)...)
+		}
+	case models.CalendarProviderOutlook:
+		events, err := s.listOutlookCalendarView(ctx, conn, pc.ProviderCalendarID, start, end)
+		if err != nil {
+			return err
+		}
+		for i := range events {
+			ev := &events[i]
+			a := classifyOutlookEvent(ev)
+			evStart, evEnd, ok := ev.span()
+			if a == eventCancelled || !ok {
+				continue
+			}
+			intervals = append(intervals, newBusyInterval(ev.ID, evStart, evEnd, ev.IsAllDay, a))
+		}
+	case models.CalendarProviderICSFeed:
+		body, err := s.fetchICSFeed(ctx, conn.CalDAVURL)
+		if err != nil {
+			return err
+		}
+		intervals = icsBusyIntervals(body, "", owner, start, end)
+	case models.CalendarProviderCalDAV, models.CalendarProviderICloud:
+		// Read the collection's tags before its contents so that a change
+		// landing in between is picked up by the next sync.
+		ctag, syncToken, err := s.caldavCollectionTags(ctx, conn, pc.ProviderCalendarID)
+		if err != nil {
+			return err
+		}
+		state.CTag, state.SyncToken = ctag, syncToken
+		intervals, err = s.caldavBusyIntervals(ctx, conn, pc.ProviderCalendarID, owner, start, end)
+		if err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+
+	if err := s.repos.BusyInterval.ReplaceAll(ctx, pc.ID, intervals); err != nil {
+		return err
+	}
+	state.SyncedAt = models.Now()
+	return s.repos.BusyInterval.SaveSyncState(ctx, state)
+}
+
+// syncGoogleBusyIntervals applies the changes since the stored syncToken.
+// Google answers 410 Gone once a token has expired, in which case the cache
+// is rebuilt from scratch.
+func (s *CalendarService) syncGoogleBusyIntervals(ctx context.Context, conn *models.CalendarConnection, pc *models.ProviderCalendar, owner calendarOwner, state *models.BusySyncState) error {
+	items, syncToken, err := s.listGoogleEvents(ctx, conn, pc.ProviderCalendarID, url.Values{
+		"syncToken": {state.SyncToken},
+	})
+	if errors.Is(err, errGoogleSyncTokenExpired) {
+		log.Printf("[CALENDAR] sync token expired for calendar %s, resyncing", pc.ID)
+		start, end := s.busyCacheWindow()
+		return s.resyncBusyIntervals(ctx, conn, pc, owner, start, end)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Incremental results aren't limited to the window; events that moved
+	// out of it are dropped like deleted ones.
+	for i := range items {
+		ev := &items[i]
+		if ev.ID == "" {
+			continue
+		}
+		intervals := googleBusyIntervals(ev, state.WindowStart.Time, state.WindowEnd.Time)
+		if err := s.repos.BusyInterval.ReplaceSource(ctx, pc.ID, ev.ID, intervals); err != nil {
+			return err
+		}
+	}
+
+	if syncToken != "" {
+		state.SyncToken = syncToken
+	}
+	state.SyncedAt = models.Now()
+	return s.repos.BusyInterval.SaveSyncState(ctx, state)
+}
+
+// syncCalDAVBusyIntervals skips the collection entirely when its getctag is
+// unchanged, and otherwise asks for the resources changed since the stored
+// sync-token (RFC 6578). Servers without sync-collection, or that reject
+// the token, get a full re-read.
+func (s *CalendarService) syncCalDAVBusyIntervals(ctx context.Context, conn *models.CalendarConnection, pc *models.ProviderCalendar, owner calendarOwner, state *models.BusySyncState) error {
+	calendarURL := pc.ProviderCalendarID
+	ctag, syncToken, err := s.caldavCollectionTags(ctx, conn, calendarURL)
+	if err != nil {
+		return err
+	}
+	if ctag != "" && ctag == state.CTag {
+		return s.repos.BusyInterval.TouchSyncState(ctx, pc.ID)
+	}
+
+	if state.SyncToken != "" {
+		err = s.applyCalDAVSyncCollection(ctx, conn, pc, owner, state)
+		if err == nil || errors.Is(err, ErrCalendarAuth) {
+			return err
+		}
+		log.Printf("[CALENDAR] sync-collection failed for calendar %s, resyncing: %v", pc.ID, err)
+	}
+
+	intervals, err := s.caldavBusyIntervals(ctx, conn, calendarURL, owner, state.WindowStart.Time, state.WindowEnd.Time)
+	if err != nil {
+		return err
+	}
+	if err := s.repos.BusyInterval.ReplaceAll(ctx, pc.ID, intervals); err != nil {
+		return err
+	}
+	state.CTag, state.SyncToken = ctag, syncToken
+	state.SyncedAt = models.Now()
+	return s.repos.BusyInterval.SaveSyncState(ctx, state)
+}
+
+// applyCalDAVSyncCollection fetches the members changed since state's
+// sync-token, re-reads them with calendar-multiget and drops the removed ones.
+func (s *CalendarService) applyCalDAVSyncCollection(ctx context.Context, conn *models.CalendarConnection, pc *models.ProviderCalendar, owner calendarOwner, state *models.BusySyncState) error {
+	var body bytes.Buffer
+	body.WriteString(`<?xml version="1.0" encoding="utf-8" ?>
+<D:sync-collection xmlns:D="DAV:">
+  <D:sync-token>`)
+	_ = xml.EscapeText(&body, []byte(state.SyncToken))
+	body.WriteString(`</D:sync-token>
+  <D:sync-level>1</D:sync-level>
+  <D:prop>
+    <D:getetag/>
+  </D:prop>
+</D:sync-collection>`)
+
+	ms, err := s.caldavReport(ctx, conn, pc.ProviderCalendarID, "0", body.String())
+	if err != nil {
+		return err
+	}
+
+	var changed, removed []string
+	for _, resp := range ms.Responses {
+		href := strings.TrimSpace(resp.Href)
+		if href == "" || strings.HasSuffix(href, "/") {
+			continue
+		}
+		if strings.Contains(resp.Status, "404") {
+			removed = append(removed, href)
+			continue
+		}
+		changed = append(changed, href)
+	}
+
+	for _, href := range removed {
+		if err := s.repos.BusyInterval.ReplaceSource(ctx, pc.ID, href, nil); err != nil {
+			return err
+		}
+	}
+
+	if len(changed) > 0 {
+		data, err := s.caldavMultiget(ctx, conn, pc.ProviderCalendarID, changed)
+		if err != nil {
+			return err
+		}
+		for _, href := range changed {
+			// A member missing from the multiget response was deleted again
+			// in the meantime.
+			intervals := icsBusyIntervals(data[href], href, owner, state.WindowStart.Time, state.WindowEnd.Time)
+			if err := s.repos.BusyInterval.ReplaceSource(ctx, pc.ID, href, intervals); err != nil {
+				return err
+			}
+		}
+	}
+
+	if ms.SyncToken != "" {
+		state.SyncToken = ms.SyncToken
+	}
+	// Keep the stored ctag in step so the next sync can skip on it.
+	if ctag, _, err := s.caldavCollectionTags(ctx, conn, pc.ProviderCalendarID); err == nil {
+		state.CTag = ctag
+	}
+	state.SyncedAt = models.Now()
+	return s.repos.BusyInterval.SaveSyncState(ctx, state)
+}
+
+// caldavCollectionTags reads a collection's getctag (a CalendarServer
+// extension most servers implement) and its RFC 6578 sync-token. Servers
+// that support neither return empty strings, which simply disables the
+// shortcuts; only an auth failure is reported.
+func (s *CalendarService) caldavCollectionTags(ctx context.Context, conn *models.CalendarConnection, calendarURL string) (ctag, syncToken string, err error) {
+	body := `<?xml version="1.0" encoding="utf-8" ?>
+<D:propfind xmlns:D="DAV:" xmlns:CS="http://calendarserver.org/ns/">
+  <D:prop>
+    <CS:getctag/>
+    <D:sync-token/>
+  </D:prop>
+</D:propfind>`
+	ms, err := s.caldavPropfind(ctx, conn, calendarURL, "0", body)
+	if err != nil {
+		if errors.Is(err, ErrCalendarAuth) {
+			return "", "", err
+		}
+		return "", "", nil
+	}
+	for _, resp := range ms.Responses {
+		for _, ps := range resp.Propstat {
+			if !strings.Contains(ps.Status, "200") {
+				continue
+			}
+			if ps.Prop.CTag != "" {
+				ctag = strings.TrimSpace(ps.Prop.CTag)
+			}
+			if ps.Prop.SyncToken != "" {
+				syncToken = strings.TrimSpace(ps.Prop.SyncToken)
+			}
+		}
+	}
+	return ctag, syncToken, nil
+}
+
+// caldavBusyIntervals reads every event in [start, end) from a collection
+// with calendar-query, keyed by resource href.
+func (s *CalendarService) caldavBusyIntervals(ctx context.Context, conn *models.CalendarConnection, calendarURL string, owner calendarOwner, start, end time.Time) ([]*models.BusyInterval, error) {
+	query := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8" ?>
+<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop>
+    <D:getetag/>
+    <C:calendar-data/>
+  </D:prop>
+  <C:filter>
+    <C:comp-filter name="VCALENDAR">
+      <C:comp-filter name="VEVENT">
+        <C:time-range start="%s" end="%s"/>
+      </C:comp-filter>
+    </C:comp-filter>
+  </C:filter>
+</C:calendar-query>`,
+		start.UTC().Format("20060102T150405Z"),
+		end.UTC().Format("20060102T150405Z"),
+	)
+
+	ms, err := s.caldavReport(ctx, conn, calendarURL, "1", query)
+	if err != nil {
+		return nil, err
+	}
+
+	var intervals []*models.BusyInterval
+	for href, data := range caldavCalendarData(ms) {
+		intervals = append(intervals, icsBusyIntervals(data, href, owner, start, end)...)
+	}
+	return intervals, nil
+}
+
+// caldavMultiget fetches the calendar data of specific resources, keyed by
+// href.
+func (s *CalendarService) caldavMultiget(ctx context.Context, conn *models.CalendarConnection, calendarURL string, hrefs []string) (map[string]string, error) {
+	var body bytes.Buffer
+	body.WriteString(`<?xml version="1.0" encoding="utf-8" ?>
+<C:calendar-multiget xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop>
+    <D:getetag/>
+    <C:calendar-data/>
+  </D:prop>
+`)
+	for _, href := range hrefs {
+		body.WriteString("  <D:href>")
+		_ = xml.EscapeText(&body, []byte(href))
+		body.WriteString("</D:href>\n")
+	}
+	body.WriteString(`</C:calendar-multiget>`)
+
+	ms, err := s.caldavReport(ctx, conn, calendarURL, "1", body.String())
+	if err != nil {
+		return nil, err
+	}
+	return caldavCalendarData(ms), nil
+}
+
+// caldavCalendarData collects the calendar-data of each successful response
+// in a multistatus, keyed by href.
+func caldavCalendarData(ms *caldavMultistatus) map[string]string {
+	out := make(map[string]string, len(ms.Responses))
+	for _, resp := range ms.Responses {
+		for _, ps := range resp.Propstat {
+			if strings.Contains(ps.Status, "200") && strings.TrimSpace(ps.Prop.CalendarData) != "" {
+				out[strings.TrimSpace(resp.Href)] = ps.Prop.CalendarData
+			}
+		}
+	}
+	return out
+}
+
+// newBusyInterval builds a cache row for one event occurrence.
+func newBusyInterval(source string, start, end time.Time, isAllDay bool, a eventAvailability) *models.BusyInterval {
+	return &models.BusyInterval{
+		ID:           uuid.New().String(),
+		Source:       source,
+		StartTime:    models.NewSQLiteTime(start),
+		EndTime:      models.NewSQLiteTime(end),
+		IsAllDay:     isAllDay,
+		Availability: string(a),
+		CreatedAt:    models.Now(),
+	}
+}
+
+// googleBusyIntervals converts one events.list item into cache rows: none
+// for cancelled events or ones outside [start, end).
+func googleBusyIntervals(ev *googleBusyEvent, start, end time.Time) []*models.BusyInterval {
+	a := classifyGoogleEvent(ev)
+	if a == eventCancelled {
+		return nil
+	}
+	evStart, evEnd, isAllDay, ok := ev.span()
+	if !ok || !evEnd.After(start) || !evStart.Before(end) {
+		return nil
+	}
+	return []*models.BusyInterval{newBusyInterval(ev.ID, evStart, evEnd, isAllDay, a)}
+}
+
+// icsBusyIntervals expands the VEVENTs in icsData over [start, end) into
+// cache rows. Rows carry source when given (a CalDAV href), else the UID.
+// Attendee matching and floating times are resolved for owner now, since
+// the raw ICS isn't kept.
+func icsBusyIntervals(icsData, source string, owner calendarOwner, start, end time.Time) []*models.BusyInterval {
+	if icsData == "" {
+		return nil
+	}
+	var intervals []*models.BusyInterval
+	events := parseICSEvents(icsData, owner.Location)
+	for _, occ := range expandICSEvents(events, start, end) {
+		a := classifyICSEvent(occ.Event, owner.Emails)
+		if a == eventCancelled {
+			continue
+		}
+		src := source
+		if src == "" {
+			src = occ.Event.UID
+		}
+		intervals = append(intervals, newBusyInterval(src, occ.Start, occ.End, occ.Event.IsAllDay, a))
+	}
+	return intervals
+}
@@ -0,0 +1,382 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// davStandIn is a CalDAV collection that supports getctag, sync-token and
+// the three REPORTs the busy cache uses. Resources are keyed by href.
+type davStandIn struct {
+	mu        sync.Mutex
+	ctag      string
+	syncToken string
+	resources map[string]string
+	removed   []string // hrefs reported as 404 by the next sync-collection
+	requests  []string // "PROPFIND", "calendar-query", "sync-collection", "calendar-multiget"
+	fail      bool
+}
+
+func (d *davStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.fail {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	body := readReqBody(r)
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusMultiStatus)
+
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/">`)
+	writeData := func(href string) {
+		fmt.Fprintf(&b, `<d:response><d:href>%s</d:href><d:propstat><d:prop><d:getetag>"1"</d:getetag><C:calendar-data>%s</C:calendar-data></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`,
+			href, d.resources[href])
+	}
+
+	switch {
+	case r.Method == "PROPFIND":
+		d.requests = append(d.requests, "PROPFIND")
+		fmt.Fprintf(&b, `<d:response><d:href>/cal/</d:href><d:propstat><d:prop><cs:getctag>%s</cs:getctag><d:sync-token>%s</d:sync-token></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`,
+			d.ctag, d.syncToken)
+	case strings.Contains(body, "sync-collection"):
+		d.requests = append(d.requests, "sync-collection")
+		for href := range d.resources {
+			fmt.Fprintf(&b, `<d:response><d:href>%s</d:href><d:propstat><d:prop><d:getetag>"2"</d:getetag></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`, href)
+		}
+		for _, href := range d.removed {
+			fmt.Fprintf(&b, `<d:response><d:href>%s</d:href><d:status>HTTP/1.1 404 Not Found</d:status></d:response>`, href)
+		}
+		fmt.Fprintf(&b, `<d:sync-token>%s</d:sync-token>`, d.syncToken)
+	case strings.Contains(body, "calendar-multiget"):
+		d.requests = append(d.requests, "calendar-multiget")
+		for href := range d.resources {
+			if strings.Contains(body, href) {
+				writeData(href)
+			}
+		}
+	default:
+		d.requests = append(d.requests, "calendar-query")
+		for href := range d.resources {
+			writeData(href)
+		}
+	}
+	b.WriteString(`</d:multistatus>`)
+	_, _ = w.Write([]byte(b.String()))
+}
+
+func (d *davStandIn) takeRequests() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := d.requests
+	d.requests = nil
+	return out
+}
+
+// busyCacheDay is a day inside the cache window.
+func busyCacheDay() time.Time {
+	return time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 2)
+}
+
+func timedEvent(uid string, start time.Time, hours int, extra string) string {
+	return icsCalendar(fmt.Sprintf("UID:%s\nDTSTART:%s\nDTEND:%s\n%s",
+		uid, start.Format("20060102T150405Z"), start.Add(time.Duration(hours)*time.Hour).Format("20060102T150405Z"), extra))
+}
+
+func seedDAVCalendar(t *testing.T, dav *davStandIn) (*CalendarService, *models.Host, *models.CalendarConnection, *models.ProviderCalendar) {
+	t.Helper()
+	srv := httptest.NewServer(dav)
+	t.Cleanup(srv.Close)
+
+	_, repos, cal := setupServiceTestDB(t)
+	host, conn := seedHostAndConnection(t, repos, models.CalendarProviderCalDAV, "", srv.URL+"/cal/")
+	pc, err := repos.ProviderCalendar.UpsertFromProvider(context.Background(), conn.ID, srv.URL+"/cal/", "Work", "", true, true)
+	if err != nil {
+		t.Fatalf("upsert calendar: %v", err)
+	}
+	return cal, host, conn, pc
+}
+
+// TestGetBusyTimes_ServedFromFreshCache checks that once the sync has filled
+// the cache, availability is answered without calling the provider and the
+// busy policy is applied at read time.
+func TestGetBusyTimes_ServedFromFreshCache(t *testing.T) {
+	day := busyCacheDay()
+	dav := &davStandIn{ctag: "c1", resources: map[string]string{
+		"/cal/review.ics": timedEvent("review", day.Add(9*time.Hour), 1, ""),
+		"/cal/maybe.ics":  timedEvent("maybe", day.Add(14*time.Hour), 1, "STATUS:TENTATIVE"),
+	}}
+	cal, host, conn, pc := seedDAVCalendar(t, dav)
+	ctx := context.Background()
+
+	if err := cal.RefreshCalendarSync(ctx, host.ID, conn.ID); err != nil {
+		t.Fatalf("RefreshCalendarSync: %v", err)
+	}
+	dav.takeRequests()
+
+	slots, err := cal.GetBusyTimes(ctx, host.ID, day, day.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("GetBusyTimes: %v", err)
+	}
+	if got := dav.takeRequests(); len(got) != 0 {
+		t.Errorf("fresh cache should not hit the provider, got %v", got)
+	}
+	slices.SortFunc(slots, func(a, b models.TimeSlot) int { return a.Start.Compare(b.Start) })
+	if got, want := slotHours(slots), []int{9, 14}; !slices.Equal(got, want) {
+		t.Errorf("busy hours = %v, want %v", got, want)
+	}
+
+	if err := cal.repos.ProviderCalendar.UpdateBusyPolicy(ctx, host.ID, pc.ID, false, false, false); err != nil {
+		t.Fatalf("update policy: %v", err)
+	}
+	slots, _ = cal.GetBusyTimes(ctx, host.ID, day, day.Add(24*time.Hour))
+	if got, want := slotHours(slots), []int{9}; !slices.Equal(got, want) {
+		t.Errorf("busy hours after policy change = %v, want %v", got, want)
+	}
+
+	// A range past the cached window goes to the provider.
+	far := day.AddDate(1, 0, 0)
+	if _, err := cal.GetBusyTimes(ctx, host.ID, far, far.Add(24*time.Hour)); err != nil {
+		t.Fatalf("GetBusyTimes: %v", err)
+	}
+	if got := dav.takeRequests(); !slices.Contains(got, "calendar-query") {
+		t.Errorf("range outside the cache window should be fetched live, got %v", got)
+	}
+}
+
+// TestGetBusyTimes_StaleCache checks that an expired cache is bypassed for a
+// live fetch, but still used when the provider is down.
+func TestGetBusyTimes_StaleCache(t *testing.T) {
+	day := busyCacheDay()
+	dav := &davStandIn{ctag: "c1", resources: map[string]string{
+		"/cal/review.ics": timedEvent("review", day.Add(9*time.Hour), 1, ""),
+	}}
+	cal, host, conn, pc := seedDAVCalendar(t, dav)
+	ctx := context.Background()
+
+	if err := cal.RefreshCalendarSync(ctx, host.ID, conn.ID); err != nil {
+		t.Fatalf("RefreshCalendarSync: %v", err)
+	}
+	state, _ := cal.repos.BusyInterval.GetSyncState(ctx, pc.ID)
+	state.SyncedAt = models.NewSQLiteTime(time.Now().Add(-2 * cal.busyCacheMaxAge()))
+	if err := cal.repos.BusyInterval.SaveSyncState(ctx, state); err != nil {
+		t.Fatalf("save state: %v", err)
+	}
+
+	dav.mu.Lock()
+	dav.resources["/cal/later.ics"] = timedEvent("later", day.Add(15*time.Hour), 1, "")
+	dav.mu.Unlock()
+	dav.takeRequests()
+
+	slots, _ := cal.GetBusyTimes(ctx, host.ID, day, day.Add(24*time.Hour))
+	slices.SortFunc(slots, func(a, b models.TimeSlot) int { return a.Start.Compare(b.Start) })
+	if got, want := slotHours(slots), []int{9, 15}; !slices.Equal(got, want) {
+		t.Errorf("live busy hours = %v, want %v", got, want)
+	}
+	if got := dav.takeRequests(); len(got) == 0 {
+		t.Error("stale cache should fall back to a live fetch")
+	}
+
+	dav.mu.Lock()
+	dav.fail = true
+	dav.mu.Unlock()
+	slots, _ = cal.GetBusyTimes(ctx, host.ID, day, day.Add(24*time.Hour))
+	if got, want := slotHours(slots), []int{9}; !slices.Equal(got, want) {
+		t.Errorf("busy hours with provider down = %v, want the stale %v", got, want)
+	}
+}
+
+// TestSyncBusyIntervals_CalDAVIncremental checks that an unchanged getctag
+// skips the collection and that a changed one is applied via sync-collection
+// and calendar-multiget rather than a full calendar-query.
+func TestSyncBusyIntervals_CalDAVIncremental(t *testing.T) {
+	day := busyCacheDay()
+	dav := &davStandIn{ctag: "c1", syncToken: "s1", resources: map[string]string{
+		"/cal/review.ics": timedEvent("review", day.Add(9*time.Hour), 1, ""),
+		"/cal/lunch.ics":  timedEvent("lunch", day.Add(12*time.Hour), 1, ""),
+	}}
+	cal, host, conn, pc := seedDAVCalendar(t, dav)
+	ctx := context.Background()
+	sync := func() {
+		t.Helper()
+		if err := cal.RefreshCalendarSync(ctx, host.ID, conn.ID); err != nil {
+			t.Fatalf("RefreshCalendarSync: %v", err)
+		}
+	}
+	cachedHours := func() []int {
+		t.Helper()
+		slots, ok := cal.cachedBusyTimes(ctx, pc, calendarOwner{Location: time.UTC}, day, day.Add(24*time.Hour), 0)
+		if !ok {
+			t.Fatal("cache should cover the day")
+		}
+		slices.SortFunc(slots, func(a, b models.TimeSlot) int { return a.Start.Compare(b.Start) })
+		return slotHours(slots)
+	}
+
+	sync()
+	if got := dav.takeRequests(); !slices.Equal(got, []string{"PROPFIND", "calendar-query"}) {
+		t.Errorf("first sync requests = %v", got)
+	}
+
+	sync()
+	if got := dav.takeRequests(); !slices.Equal(got, []string{"PROPFIND"}) {
+		t.Errorf("unchanged ctag should stop after PROPFIND, got %v", got)
+	}
+
+	// The review moves to 10:00 and lunch is deleted.
+	dav.mu.Lock()
+	dav.ctag, dav.syncToken = "c2", "s2"
+	dav.resources = map[string]string{"/cal/review.ics": timedEvent("review", day.Add(10*time.Hour), 1, "")}
+	dav.removed = []string{"/cal/lunch.ics"}
+	dav.mu.Unlock()
+
+	sync()
+	if got := dav.takeRequests(); slices.Contains(got, "calendar-query") || !slices.Contains(got, "calendar-multiget") {
+		t.Errorf("changed ctag should sync incrementally, got %v", got)
+	}
+	if got, want := cachedHours(), []int{10}; !slices.Equal(got, want) {
+		t.Errorf("cached hours = %v, want %v", got, want)
+	}
+	state, _ := cal.repos.BusyInterval.GetSyncState(ctx, pc.ID)
+	if state.SyncToken != "s2" || state.CTag != "c2" {
+		t.Errorf("state = %q/%q, want s2/c2", state.SyncToken, state.CTag)
+	}
+}
+
+// googleEventsStandIn serves events.list: a full listing for requests
+// without a syncToken, and the queued changes for requests that carry the
+// current one.
+type googleEventsStandIn struct {
+	mu      sync.Mutex
+	items   []map[string]interface{}
+	changes []map[string]interface{}
+	token   string
+	gone    bool
+	queries []url.Values
+}
+
+func (g *googleEventsStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	query := r.URL.Query()
+	g.queries = append(g.queries, query)
+
+	items := g.items
+	if query.Get("syncToken") != "" {
+		if g.gone || query.Get("syncToken") != g.token {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		items = g.changes
+	}
+	g.token = fmt.Sprintf("t%d", len(g.queries))
+	writeJSON(w, map[string]interface{}{"items": items, "nextSyncToken": g.token})
+}
+
+func googleTimedItem(id string, start time.Time, hours int, status string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":     id,
+		"status": status,
+		"start":  map[string]string{"dateTime": start.Format(time.RFC3339)},
+		"end":    map[string]string{"dateTime": start.Add(time.Duration(hours) * time.Hour).Format(time.RFC3339)},
+	}
+}
+
+// hostRewriteTransport sends every request for host to target instead.
+type hostRewriteTransport struct {
+	base   http.RoundTripper
+	host   string
+	target *url.URL
+}
+
+func (h *hostRewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host == h.host {
+		req = req.Clone(req.Context())
+		req.URL.Scheme, req.URL.Host, req.Host = h.target.Scheme, h.target.Host, h.target.Host
+	}
+	return h.base.RoundTrip(req)
+}
+
+// TestSyncBusyIntervals_GoogleSyncToken checks that Google calendars are
+// kept current from their syncToken, and rebuilt when Google expires it.
+func TestSyncBusyIntervals_GoogleSyncToken(t *testing.T) {
+	day := busyCacheDay()
+	google := &googleEventsStandIn{items: []map[string]interface{}{
+		googleTimedItem("a", day.Add(9*time.Hour), 1, "confirmed"),
+		googleTimedItem("b", day.Add(11*time.Hour), 1, "confirmed"),
+	}}
+	srv := httptest.NewServer(google)
+	defer srv.Close()
+	target, _ := url.Parse(srv.URL)
+	http.DefaultClient = &http.Client{Transport: &hostRewriteTransport{base: http.DefaultTransport, host: "www.googleapis.com", target: target}}
+	defer func() { http.DefaultClient = &http.Client{} }()
+
+	_, repos, cal := setupServiceTestDB(t)
+	host, conn := seedHostAndConnection(t, repos, models.CalendarProviderGoogle, "tok", "")
+	ctx := context.Background()
+	pc, err := repos.ProviderCalendar.UpsertFromProvider(ctx, conn.ID, "primary", "Primary", "", true, true)
+	if err != nil {
+		t.Fatalf("upsert calendar: %v", err)
+	}
+	sync := func() {
+		t.Helper()
+		if err := cal.RefreshCalendarSync(ctx, host.ID, conn.ID); err != nil {
+			t.Fatalf("RefreshCalendarSync: %v", err)
+		}
+	}
+	busyHours := func() []int {
+		t.Helper()
+		slots, err := cal.GetBusyTimes(ctx, host.ID, day, day.Add(24*time.Hour))
+		if err != nil {
+			t.Fatalf("GetBusyTimes: %v", err)
+		}
+		slices.SortFunc(slots, func(a, b models.TimeSlot) int { return a.Start.Compare(b.Start) })
+		return slotHours(slots)
+	}
+
+	sync()
+	if got, want := busyHours(), []int{9, 11}; !slices.Equal(got, want) {
+		t.Errorf("initial busy hours = %v, want %v", got, want)
+	}
+
+	google.mu.Lock()
+	google.changes = []map[string]interface{}{
+		{"id": "a", "status": "cancelled"},
+		googleTimedItem("b", day.Add(15*time.Hour), 1, "confirmed"),
+	}
+	google.mu.Unlock()
+	sync()
+	if got, want := busyHours(), []int{15}; !slices.Equal(got, want) {
+		t.Errorf("busy hours after incremental sync = %v, want %v", got, want)
+	}
+
+	google.mu.Lock()
+	last := google.queries[len(google.queries)-1]
+	google.gone = true
+	google.items = []map[string]interface{}{googleTimedItem("c", day.Add(8*time.Hour), 1, "confirmed")}
+	google.mu.Unlock()
+	if last.Get("syncToken") == "" || last.Get("timeMin") != "" {
+		t.Errorf("incremental request should carry only the syncToken, got %v", last)
+	}
+
+	sync()
+	if got, want := busyHours(), []int{8}; !slices.Equal(got, want) {
+		t.Errorf("busy hours after expired token = %v, want %v", got, want)
+	}
+	state, _ := repos.BusyInterval.GetSyncState(ctx, pc.ID)
+	if state == nil || state.SyncToken == "" {
+		t.Errorf("resync should store a fresh sync token, got %+v", state)
+	}
+}
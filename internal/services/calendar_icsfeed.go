@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// icsFeedFreshFor is how long a fetched feed is served from memory before the
+// next conditional request. Busy times are computed on every booking page
+// view; publishers don't appreciate being polled that often.
+const icsFeedFreshFor = 5 * time.Minute
+
+// icsFeedEvictAfter is how long a feed nobody reads stays in memory. A feed
+// in use is rechecked every icsFeedFreshFor, so only disconnected or idle
+// feeds get this old.
+const icsFeedEvictAfter = time.Hour
+
+// icsFeedTimeout is how long a publisher has to serve a feed.
+const icsFeedTimeout = 30 * time.Second
+
+// maxICSFeedBytes caps how much of a feed we read.
+const maxICSFeedBytes = 10 << 20
+
+// icsFeedCalendarID is the provider calendar id of a feed's single
+// calendar. The feed URL itself is a secret, so it's only kept, sealed, on
+// the connection.
+const icsFeedCalendarID = "feed"
+
+// ErrInvalidICSFeed is returned when a feed URL is malformed or does not
+// serve an iCalendar document.
+var ErrInvalidICSFeed = errors.New("not a valid iCalendar feed")
+
+// icsFeedEntry is the cached copy of one feed along with the validators used
+// to make the next request conditional.
+type icsFeedEntry struct {
+	body         string
+	etag         string
+	lastModified string
+	checkedAt    time.Time
+}
+
+// icsFeedCache holds feeds by URL. Feeds not checked for icsFeedEvictAfter,
+// such as disconnected ones, are swept out as new feeds are stored.
+type icsFeedCache struct {
+	mu        sync.Mutex
+	entries   map[string]*icsFeedEntry
+	lastSweep time.Time
+}
+
+func newICSFeedCache() *icsFeedCache {
+	return &icsFeedCache{entries: make(map[string]*icsFeedEntry)}
+}
+
+func (c *icsFeedCache) get(feedURL string) *icsFeedEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[feedURL]; ok {
+		cp := *e
+		return &cp
+	}
+	return nil
+}
+
+func (c *icsFeedCache) put(feedURL string, e *icsFeedEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[feedURL] = e
+
+	if now := time.Now(); now.Sub(c.lastSweep) >= icsFeedFreshFor {
+		c.lastSweep = now
+		for u, entry := range c.entries {
+			if now.Sub(entry.checkedAt) >= icsFeedEvictAfter {
+				delete(c.entries, u)
+			}
+		}
+	}
+}
+
+// normalizeICSFeedURL accepts http(s) and webcal(s) URLs on public hosts,
+// returning the URL that should actually be fetched.
+func normalizeICSFeedURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "", ErrInvalidICSFeed
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "webcal", "webcals":
+		u.Scheme = "https"
+	case "http", "https":
+		u.Scheme = strings.ToLower(u.Scheme)
+	default:
+		return "", ErrInvalidICSFeed
+	}
+	if err := checkPublicURLHost(u); err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// ICSFeedConnectInput represents input for subscribing to an ICS feed
+type ICSFeedConnectInput struct {
+	HostID string
+	Name   string
+	URL    string
+}
+
+// ConnectICSFeed subscribes a host to a read-only ICS feed. The feed is
+// fetched once up front so a wrong or private URL is rejected immediately.
+// The URL is stored, sealed, in caldav_url; the single provider calendar
+// gets the fixed id icsFeedCalendarID.
+func (s *CalendarService) ConnectICSFeed(ctx context.Context, input ICSFeedConnectInput) (*models.CalendarConnection, error) {
+	feedURL, err := normalizeICSFeedURL(input.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := s.fetchICSFeed(ctx, feedURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ICS feed: %w", err)
+	}
+
+	displayName := strings.TrimSpace(input.Name)
+	if displayName == "" {
+		displayName = icsCalendarName(body)
+	}
+	if displayName == "" {
+		displayName = "Subscribed Calendar"
+	}
+
+	now := models.Now()
+	connection := &models.CalendarConnection{
+		ID:           uuid.New().String(),
+		HostID:       input.HostID,
+		Provider:     models.CalendarProviderICSFeed,
+		Name:         displayName,
+		CalendarID:   feedURL,
+		CalDAVURL:    feedURL,
+		SyncStatus:   models.CalendarSyncStatusSynced,
+		LastSyncedAt: &now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := s.repos.Calendar.Create(ctx, connection); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.refreshICSFeedCalendarList(ctx, connection); err != nil {
+		log.Printf("[CALENDAR] initial ICS feed calendar failed for connection %s: %v", connection.ID, err)
+	}
+
+	return connection, nil
+}
+
+// icsCalendarName returns the feed's X-WR-CALNAME, which most publishers set.
+func icsCalendarName(icsData string) string {
+	for _, line := range unfoldICSLines(icsData) {
+		if line == "BEGIN:VEVENT" {
+			break
+		}
+		name, _, value := splitICSProperty(line)
+		if name == "X-WR-CALNAME" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// refreshICSFeedCalendarList keeps the feed's single, read-only provider
+// calendar in place. There is nothing to enumerate; this exists so feeds go
+// through the same re-list path as every other connection.
+func (s *CalendarService) refreshICSFeedCalendarList(ctx context.Context, conn *models.CalendarConnection) ([]*models.ProviderCalendar, error) {
+	pc, err := s.repos.ProviderCalendar.UpsertFromProvider(ctx, conn.ID, icsFeedCalendarID, conn.Name, "", true, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repos.ProviderCalendar.DeleteMissing(ctx, conn.ID, []string{icsFeedCalendarID}); err != nil {
+		log.Printf("[CALENDAR] DeleteMissing failed for connection %s: %v", conn.ID, err)
+	}
+
+	saved := []*models.ProviderCalendar{pc}
+	AssignProviderCalendarColors(saved)
+	_ = s.repos.ProviderCalendar.UpdateColor(ctx, conn.HostID, pc.ID, pc.Color)
+	return saved, nil
+}
+
+// fetchICSFeed returns the feed body, serving it from memory while fresh and
+// otherwise revalidating with If-None-Match / If-Modified-Since. Only public
+// addresses are fetched, redirects included. Errors never include the URL:
+// feed URLs are secrets.
+func (s *CalendarService) fetchICSFeed(ctx context.Context, feedURL string) (string, error) {
+	cached := s.icsFeeds.get(feedURL)
+	if cached != nil && time.Since(cached.checkedAt) < icsFeedFreshFor {
+		return cached.body, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
+	if err != nil {
+		return "", ErrInvalidICSFeed
+	}
+	req.Header.Set("Accept", "text/calendar, */*;q=0.5")
+	if cached != nil {
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+
+	resp, err := s.feedClient.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return "", fmt.Errorf("ICS feed request failed: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("Error closing response body: %v", err)
+		}
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		cached.checkedAt = time.Now()
+		s.icsFeeds.put(feedURL, cached)
+		return cached.body, nil
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return "", ErrCalendarAuth
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("ICS feed returned %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxICSFeedBytes+1))
+	if err != nil {
+		return "", err
+	}
+	if len(raw) > maxICSFeedBytes {
+		return "", fmt.Errorf("ICS feed is larger than %d MB", maxICSFeedBytes>>20)
+	}
+	body := string(raw)
+	if !strings.Contains(body, "BEGIN:VCALENDAR") {
+		return "", ErrInvalidICSFeed
+	}
+
+	s.icsFeeds.put(feedURL, &icsFeedEntry{
+		body:         body,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+		checkedAt:    time.Now(),
+	})
+	return body, nil
+}
+
+// getICSFeedBusyTimes reads busy times from a feed through the calendar's
+// busy policy.
+func (s *CalendarService) getICSFeedBusyTimes(ctx context.Context, feedURL string, policy busyPolicy, start, end time.Time) ([]models.TimeSlot, error) {
+	body, err := s.fetchICSFeed(ctx, feedURL)
+	if err != nil {
+		return nil, err
+	}
+	return policy.icsBusySlots(body, start, end), nil
+}
+
+// getICSFeedAgendaEvents reads agenda events from a feed.
+func (s *CalendarService) getICSFeedAgendaEvents(ctx context.Context, cal *models.CalendarConnection, floating *time.Location, start, end time.Time) ([]AgendaEvent, error) {
+	body, err := s.fetchICSFeed(ctx, cal.CalDAVURL)
+	if err != nil {
+		return nil, err
+	}
+	return parseVEventsForAgenda(body, cal.Name, cal.ID, cal.Color, floating, start, end), nil
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+const feedICS = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nX-WR-CALNAME:Corporate\r\n" +
+	"BEGIN:VEVENT\r\nUID:review\r\nSUMMARY:Quarterly review\r\nDTSTART:20240110T090000Z\r\nDTEND:20240110T100000Z\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nUID:ooo\r\nSUMMARY:Lunch\r\nTRANSP:TRANSPARENT\r\nDTSTART:20240110T120000Z\r\nDTEND:20240110T130000Z\r\nEND:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+// standInClient returns a client that sends every request to srv whatever
+// its URL, so tests can use public feed URLs that the URL checks accept
+func standInClient(srv *httptest.Server) *http.Client {
+	return &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+		},
+	}}
+}
+
+func TestNormalizeICSFeedURL(t *testing.T) {
+	cases := map[string]string{
+		"webcal://example.com/feed.ics":    "https://example.com/feed.ics",
+		" WEBCALS://example.com/a?b=c ":    "https://example.com/a?b=c",
+		"https://example.com/feed.ics":     "https://example.com/feed.ics",
+		"http://example.com/private/x.ics": "http://example.com/private/x.ics",
+	}
+	for in, want := range cases {
+		got, err := normalizeICSFeedURL(in)
+		if err != nil || got != want {
+			t.Errorf("normalizeICSFeedURL(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "ftp://example.com/x.ics", "file:///etc/passwd", "not a url"} {
+		if _, err := normalizeICSFeedURL(bad); err == nil {
+			t.Errorf("normalizeICSFeedURL(%q) should fail", bad)
+		}
+	}
+	for _, internal := range []string{
+		"http://127.0.0.1:8080/x.ics", "webcal://localhost/x.ics", "http://[::1]/x.ics",
+		"http://169.254.169.254/latest/meta-data/", "https://10.1.2.3/x.ics",
+	} {
+		if _, err := normalizeICSFeedURL(internal); !errors.Is(err, ErrPrivateAddress) {
+			t.Errorf("normalizeICSFeedURL(%q) = %v, want ErrPrivateAddress", internal, err)
+		}
+	}
+}
+
+// TestFetchICSFeed_RefusesPrivateAddresses checks that the feed client
+// won't connect to an internal address, however the URL got past the
+// checks at subscribe time.
+func TestFetchICSFeed_RefusesPrivateAddresses(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(feedICS))
+	}))
+	defer srv.Close()
+
+	cal := NewCalendarService(nil, nil)
+	if _, err := cal.fetchICSFeed(context.Background(), srv.URL+"/feed.ics"); !errors.Is(err, ErrPrivateAddress) {
+		t.Errorf("fetch from %s: err = %v, want ErrPrivateAddress", srv.URL, err)
+	}
+	if requests.Load() != 0 {
+		t.Errorf("loopback feed was requested %d times", requests.Load())
+	}
+}
+
+func TestICSFeedCache_EvictsIdleFeeds(t *testing.T) {
+	c := newICSFeedCache()
+	c.put("https://example.com/old.ics", &icsFeedEntry{body: "old", checkedAt: time.Now().Add(-icsFeedEvictAfter)})
+	c.lastSweep = time.Time{}
+	c.put("https://example.com/new.ics", &icsFeedEntry{body: "new", checkedAt: time.Now()})
+
+	if c.get("https://example.com/old.ics") != nil {
+		t.Error("idle feed was not evicted")
+	}
+	if c.get("https://example.com/new.ics") == nil {
+		t.Error("fresh feed was evicted")
+	}
+}
+
+// TestFetchICSFeed_RevalidatesWithETag checks that a fresh feed is served
+// from memory, and that a stale one is revalidated with If-None-Match and
+// reused on 304.
+func TestFetchICSFeed_RevalidatesWithETag(t *testing.T) {
+	var requests, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "text/calendar")
+		_, _ = w.Write([]byte(feedICS))
+	}))
+	defer srv.Close()
+
+	cal := NewCalendarService(nil, nil)
+	cal.feedClient = standInClient(srv)
+	ctx := context.Background()
+	feedURL := "http://calendar.example.com/feed.ics"
+
+	for i := 0; i < 2; i++ {
+		body, err := cal.fetchICSFeed(ctx, feedURL)
+		if err != nil || body != feedICS {
+			t.Fatalf("fetch %d: %v", i, err)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("fresh feed should be served from memory; got %d requests", got)
+	}
+
+	entry := cal.icsFeeds.get(feedURL)
+	entry.checkedAt = time.Now().Add(-icsFeedFreshFor)
+	cal.icsFeeds.put(feedURL, entry)
+
+	body, err := cal.fetchICSFeed(ctx, feedURL)
+	if err != nil || body != feedICS {
+		t.Fatalf("revalidated fetch: %v", err)
+	}
+	if notModified.Load() != 1 {
+		t.Errorf("expected one conditional request answered with 304, got %d", notModified.Load())
+	}
+}
+
+// TestConnectICSFeed_ContributesBusyTimesAndAgenda subscribes to a feed and
+// reads it back through GetBusyTimes and the agenda.
+func TestConnectICSFeed_ContributesBusyTimesAndAgenda(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(feedICS))
+	}))
+	defer srv.Close()
+
+	_, repos, cal := setupServiceTestDB(t)
+	cal.feedClient = standInClient(srv)
+	host, _ := seedHostAndConnection(t, repos, models.CalendarProviderCalDAV, "", "http://unused/")
+	ctx := context.Background()
+
+	conn, err := cal.ConnectICSFeed(ctx, ICSFeedConnectInput{HostID: host.ID, URL: "http://calendar.example.com/secret.ics"})
+	if err != nil {
+		t.Fatalf("ConnectICSFeed: %v", err)
+	}
+	if conn.Provider != models.CalendarProviderICSFeed || conn.Name != "Corporate" {
+		t.Errorf("connection = %s/%q, want ics_feed/Corporate", conn.Provider, conn.Name)
+	}
+
+	pcs, _ := repos.ProviderCalendar.GetByConnectionID(ctx, conn.ID)
+	if len(pcs) != 1 || pcs[0].IsWritable || !pcs[0].PollBusy || pcs[0].ProviderCalendarID != icsFeedCalendarID {
+		t.Fatalf("expected one read-only polled calendar, got %+v", pcs)
+	}
+	// The seeded CalDAV connection has no calendars, so only the feed counts.
+	start := mustUTC(t, "2024-01-10T00:00:00Z")
+	slots, err := cal.GetBusyTimes(ctx, host.ID, start, start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("GetBusyTimes: %v", err)
+	}
+	if len(slots) != 1 || slots[0].Start.Hour() != 9 {
+		t.Errorf("busy slots = %v, want the 09:00 review only", slots)
+	}
+
+	events, err := cal.GetAgendaEvents(ctx, host.ID, start, start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("GetAgendaEvents: %v", err)
+	}
+	if len(events) != 2 || events[0].CalendarID != pcs[0].ID || events[0].Title != "Quarterly review" {
+		t.Errorf("agenda = %+v", events)
+	}
+
+	if _, _, err := cal.CreateEventForHost(ctx, pcs[0].ID, &CalendarEventInput{Summary: "x"}); err == nil {
+		t.Error("writing to a feed should fail")
+	}
+}
+
+// TestRefreshCalendarSync_ICSFeedFailure checks that a feed that disappears
+// marks the connection failed, without leaking the secret URL into the
+// stored error.
+func TestRefreshCalendarSync_ICSFeedFailure(t *testing.T) {
+	var gone atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if gone.Load() {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(feedICS))
+	}))
+	defer srv.Close()
+
+	_, repos, cal := setupServiceTestDB(t)
+	cal.feedClient = standInClient(srv)
+	host, _ := seedHostAndConnection(t, repos, models.CalendarProviderCalDAV, "", "http://unused/")
+	ctx := context.Background()
+
+	conn, err := cal.ConnectICSFeed(ctx, ICSFeedConnectInput{HostID: host.ID, URL: "http://calendar.example.com/token-abc123.ics"})
+	if err != nil {
+		t.Fatalf("ConnectICSFeed: %v", err)
+	}
+
+	gone.Store(true)
+	cal.icsFeeds = newICSFeedCache()
+	if err := cal.RefreshCalendarSync(ctx, host.ID, conn.ID); err == nil {
+		t.Fatal("expected sync error for a missing feed")
+	}
+
+	stored, _ := repos.Calendar.GetByID(ctx, conn.ID)
+	if stored.SyncStatus != models.CalendarSyncStatusFailed {
+		t.Errorf("sync status = %s, want failed", stored.SyncStatus)
+	}
+	if strings.Contains(stored.SyncError, "token-abc123") || !strings.Contains(stored.SyncError, "404") {
+		t.Errorf("sync error = %q", stored.SyncError)
+	}
+}
@@ -0,0 +1,653 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// outlookScopes are the delegated Microsoft Graph permissions requested at
+// connect time. offline_access is what makes the token endpoint hand back a
+// refresh token.
+const outlookScopes = "offline_access User.Read Calendars.ReadWrite"
+
+// outlookScheduleMaxRange is the longest window getSchedule accepts; wider
+// ranges fall back to calendarView.
+const outlookScheduleMaxRange = 62 * 24 * time.Hour
+
+// errGraphNotFound is wrapped into graphRequest errors for a 404.
+var errGraphNotFound = errors.New("graph resource not found")
+
+// OutlookCalendarConnectInput represents input for connecting an Outlook /
+// Microsoft 365 calendar
+type OutlookCalendarConnectInput struct {
+	HostID      string
+	AuthCode    string
+	RedirectURI string
+}
+
+// ConnectOutlookCalendar connects a Microsoft 365 / Outlook.com account using
+// OAuth and enumerates its calendars into provider_calendars.
+func (s *CalendarService) ConnectOutlookCalendar(ctx context.Context, input OutlookCalendarConnectInput) (*models.CalendarConnection, error) {
+	tokens, err := s.exchangeOutlookAuthCode(ctx, input.AuthCode, input.RedirectURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange auth code: %w", err)
+	}
+
+	now := models.Now()
+	expiry := models.NewSQLiteTime(time.Now().Add(time.Duration(tokens.ExpiresIn) * time.Second))
+
+	connection := &models.CalendarConnection{
+		ID:           uuid.New().String(),
+		HostID:       input.HostID,
+		Provider:     models.CalendarProviderOutlook,
+		Name:         "Outlook Calendar",
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		TokenExpiry:  &expiry,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := s.repos.Calendar.Create(ctx, connection); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.refreshOutlookCalendarList(ctx, connection); err != nil {
+		log.Printf("[CALENDAR] initial Outlook calendar list failed for connection %s: %v", connection.ID, err)
+	}
+
+	// Outlook names the default calendar "Calendar", so unlike Google the
+	// account address has to come from the signed-in user's profile. It is
+	// also the mailbox getSchedule is asked about.
+	if email, err := s.outlookAccountEmail(ctx, connection); err != nil {
+		log.Printf("[CALENDAR] Outlook profile lookup failed for connection %s: %v", connection.ID, err)
+	} else if email != "" {
+		connection.Name = email
+	}
+	if primary, _ := s.primaryProviderCalendar(ctx, connection.ID); primary != nil {
+		connection.CalendarID = primary.ProviderCalendarID
+	}
+	_ = s.repos.Calendar.Update(ctx, connection)
+
+	return connection, nil
+}
+
+// GetOutlookAuthURL returns the Microsoft identity platform OAuth URL and
+// the random state it carries. The caller keeps the state, in a cookie, to
+// check the callback against.
+func (s *CalendarService) GetOutlookAuthURL() (string, string, error) {
+	state, err := generateToken(16)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate state: %w", err)
+	}
+	params := url.Values{
+		"client_id":     {s.cfg.OAuth.Microsoft.ClientID},
+		"redirect_uri":  {s.cfg.OAuth.Microsoft.RedirectURL},
+		"response_type": {"code"},
+		"response_mode": {"query"},
+		"scope":         {outlookScopes},
+		"prompt":        {"select_account"},
+		"state":         {state},
+	}
+	return s.outlookAuthorityURL() + "/oauth2/v2.0/authorize?" + params.Encode(), state, nil
+}
+
+// outlookAuthorityURL returns the login endpoint for the configured tenant.
+func (s *CalendarService) outlookAuthorityURL() string {
+	tenant := s.cfg.OAuth.Microsoft.Tenant
+	if tenant == "" {
+		tenant = "common"
+	}
+	return strings.TrimRight(s.microsoftLoginURL, "/") + "/" + url.PathEscape(tenant)
+}
+
+func (s *CalendarService) exchangeOutlookAuthCode(ctx context.Context, code, redirectURI string) (*googleTokenResponse, error) {
+	return s.requestOutlookToken(ctx, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {redirectURI},
+	})
+}
+
+// requestOutlookToken posts a grant to the token endpoint. Microsoft's token
+// response has the same shape as Google's.
+func (s *CalendarService) requestOutlookToken(ctx context.Context, form url.Values) (*googleTokenResponse, error) {
+	form.Set("client_id", s.cfg.OAuth.Microsoft.ClientID)
+	form.Set("client_secret", s.cfg.OAuth.Microsoft.ClientSecret)
+	form.Set("scope", outlookScopes)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.outlookAuthorityURL()+"/oauth2/v2.0/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("Error closing response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("token request failed (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var tokens googleTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return nil, err
+	}
+	return &tokens, nil
+}
+
+func (s *CalendarService) refreshOutlookToken(ctx context.Context, cal *models.CalendarConnection) error {
+	// Nil expiry means unknown/expired — always refresh in that case
+	if cal.TokenExpiry != nil && time.Now().UTC().Before(cal.TokenExpiry.Add(-5*time.Minute)) {
+		return nil
+	}
+
+	tokens, err := s.requestOutlookToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {cal.RefreshToken},
+	})
+	if err != nil {
+		log.Printf("[CALENDAR] Outlook token refresh failed for calendar %s: %v", cal.ID, err)
+		return ErrCalendarAuth
+	}
+
+	cal.AccessToken = tokens.AccessToken
+	expiry := models.NewSQLiteTime(time.Now().Add(time.Duration(tokens.ExpiresIn) * time.Second))
+	cal.TokenExpiry = &expiry
+
+	// Microsoft rotates refresh tokens; the old one stops working once a new
+	// one has been issued.
+	if tokens.RefreshToken != "" {
+		cal.RefreshToken = tokens.RefreshToken
+	}
+
+	return s.repos.Calendar.Update(ctx, cal)
+}
+
+// graphRequest sends an authenticated request to Microsoft Graph and decodes
+// the JSON response into out (when non-nil). path is either relative to the
+// Graph base URL or an absolute @odata.nextLink. Times in responses are
+// requested in UTC.
+func (s *CalendarService) graphRequest(ctx context.Context, conn *models.CalendarConnection, method, path string, body, out interface{}) error {
+	if err := s.refreshOutlookToken(ctx, conn); err != nil {
+		return err
+	}
+
+	target := path
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		target = strings.TrimRight(s.graphBaseURL, "/") + path
+	}
+
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+conn.AccessToken)
+	req.Header.Set("Prefer", `outlook.timezone="UTC"`)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("Error closing response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return ErrCalendarAuth
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("graph %s %s: %w", method, path, errGraphNotFound)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		raw, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("graph %s %s failed (%d): %s", method, path, resp.StatusCode, string(raw))
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// outlookCalendarPath returns the Graph path of a calendar; an empty id means
+// the mailbox's default calendar.
+func outlookCalendarPath(calendarID string) string {
+	if calendarID == "" {
+		return "/me/calendar"
+	}
+	return "/me/calendars/" + url.PathEscape(calendarID)
+}
+
+// outlookAccountEmail returns the signed-in user's primary SMTP address.
+func (s *CalendarService) outlookAccountEmail(ctx context.Context, conn *models.CalendarConnection) (string, error) {
+	var me struct {
+		Mail              string `json:"mail"`
+		UserPrincipalName string `json:"userPrincipalName"`
+	}
+	if err := s.graphRequest(ctx, conn, "GET", "/me?$select=mail,userPrincipalName", nil, &me); err != nil {
+		return "", err
+	}
+	// Personal Microsoft accounts have no mail attribute.
+	if me.Mail != "" {
+		return me.Mail, nil
+	}
+	return me.UserPrincipalName, nil
+}
+
+// outlookCalendar mirrors the relevant fields of a Graph calendar resource
+// (https://learn.microsoft.com/graph/api/resources/calendar).
+type outlookCalendar struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	HexColor          string `json:"hexColor"`
+	IsDefaultCalendar bool   `json:"isDefaultCalendar"`
+	CanEdit           bool   `json:"canEdit"`
+}
+
+// listOutlookCalendars returns every calendar in the connected mailbox,
+// following @odata.nextLink pages.
+func (s *CalendarService) listOutlookCalendars(ctx context.Context, conn *models.CalendarConnection) ([]outlookCalendar, error) {
+	var out []outlookCalendar
+	next := "/me/calendars?$select=id,name,hexColor,isDefaultCalendar,canEdit&$top=100"
+	for next != "" {
+		var page struct {
+			Value    []outlookCalendar `json:"value"`
+			NextLink string            `json:"@odata.nextLink"`
+		}
+		if err := s.graphRequest(ctx, conn, "GET", next, nil, &page); err != nil {
+			return nil, err
+		}
+		out = append(out, page.Value...)
+		next = page.NextLink
+	}
+	return out, nil
+}
+
+// refreshOutlookCalendarList re-enumerates the connection's calendars from
+// Graph and upserts them into provider_calendars, removing calendars that no
+// longer exist in the mailbox.
+func (s *CalendarService) refreshOutlookCalendarList(ctx context.Context, conn *models.CalendarConnection) ([]*models.ProviderCalendar, error) {
+	items, err := s.listOutlookCalendars(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var saved []*models.ProviderCalendar
+	keep := make([]string, 0, len(items))
+	for _, it := range items {
+		pc, err := s.repos.ProviderCalendar.UpsertFromProvider(
+			ctx, conn.ID, it.ID, it.Name, normalizeColor(it.HexColor),
+			it.IsDefaultCalendar, it.CanEdit,
+		)
+		if err != nil {
+			return nil, err
+		}
+		saved = append(saved, pc)
+		keep = append(keep, it.ID)
+	}
+
+	if err := s.repos.ProviderCalendar.DeleteMissing(ctx, conn.ID, keep); err != nil {
+		log.Printf("[CALENDAR] DeleteMissing failed for connection %s: %v", conn.ID, err)
+	}
+
+	AssignProviderCalendarColors(saved)
+	for _, pc := range saved {
+		_ = s.repos.ProviderCalendar.UpdateColor(ctx, conn.HostID, pc.ID, pc.Color)
+	}
+
+	return saved, nil
+}
+
+// graphDateTime is Graph's dateTimeTimeZone type. dateTime has no offset;
+// timeZone names the zone it is in (IANA or Windows).
+type graphDateTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+func newGraphDateTime(t time.Time) graphDateTime {
+	return graphDateTime{DateTime: t.UTC().Format("2006-01-02T15:04:05"), TimeZone: "UTC"}
+}
+
+// parse reads the wall-clock value in its zone. We ask Graph for UTC, but
+// fall back to resolving the zone name in case a value comes back untouched.
+func (d graphDateTime) parse() (time.Time, bool) {
+	loc := time.UTC
+	if d.TimeZone != "" && d.TimeZone != "UTC" {
+		if l := locationByName(d.TimeZone); l != nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation("2006-01-02T15:04:05", d.DateTime, loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t.UTC(), true
+}
+
+// outlookEvent is the subset of a Graph event used for busy times and the
+// agenda.
+type outlookEvent struct {
+	ID             string        `json:"id"`
+	Subject        string        `json:"subject"`
+	ShowAs         string        `json:"showAs"`
+	IsAllDay       bool          `json:"isAllDay"`
+	IsCancelled    bool          `json:"isCancelled"`
+	Start          graphDateTime `json:"start"`
+	End            graphDateTime `json:"end"`
+	ResponseStatus struct {
+		Response string `json:"response"`
+	} `json:"responseStatus"`
+}
+
+// classifyOutlookEvent derives the availability of a Graph event. Graph
+// reports the owner's own response directly in responseStatus.
+func classifyOutlookEvent(ev *outlookEvent) eventAvailability {
+	if ev.IsCancelled {
+		return eventCancelled
+	}
+	switch {
+	case ev.ResponseStatus.Response == "declined":
+		return eventDeclined
+	case ev.ShowAs == "free" || ev.ShowAs == "workingElsewhere":
+		return eventFree
+	case ev.ShowAs == "tentative" || ev.ResponseStatus.Response == "tentativelyAccepted":
+		return eventTentative
+	}
+	return eventBusy
+}
+
+// span returns the event's start and end in UTC. All-day events come back as
+// midnights; they are returned as UTC-midnight dates like ICS all-day events.
+func (ev *outlookEvent) span() (time.Time, time.Time, bool) {
+	start, ok1 := ev.Start.parse()
+	end, ok2 := ev.End.parse()
+	if !ok1 || !ok2 {
+		return time.Time{}, time.Time{}, false
+	}
+	if ev.IsAllDay {
+		start, _ = time.Parse("2006-01-02", ev.Start.DateTime[:10])
+		end, _ = time.Parse("2006-01-02", ev.End.DateTime[:10])
+	}
+	return start, end, true
+}
+
+// listOutlookCalendarView returns the expanded event instances of a calendar
+// in [start, end), following @odata.nextLink pages.
+func (s *CalendarService) listOutlookCalendarView(ctx context.Context, conn *models.CalendarConnection, calendarID string, start, end time.Time) ([]outlookEvent, error) {
+	params := url.Values{
+		"startDateTime": {start.UTC().Format(time.RFC3339)},
+		"endDateTime":   {end.UTC().Format(time.RFC3339)},
+		"$select":       {"id,subject,showAs,isAllDay,isCancelled,start,end,responseStatus"},
+		"$top":          {"250"},
+	}
+	next := outlookCalendarPath(calendarID) + "/calendarView?" + params.Encode()
+
+	var events []outlookEvent
+	for next != "" {
+		var page struct {
+			Value    []outlookEvent `json:"value"`
+			NextLink string         `json:"@odata.nextLink"`
+		}
+		if err := s.graphRequest(ctx, conn, "GET", next, nil, &page); err != nil {
+			return nil, err
+		}
+		events = append(events, page.Value...)
+		next = page.NextLink
+	}
+	return events, nil
+}
+
+// outlookBusySlots applies the policy to calendarView events.
+func (p busyPolicy) outlookBusySlots(events []outlookEvent) []models.TimeSlot {
+	var slots []models.TimeSlot
+	for i := range events {
+		ev := &events[i]
+		if !p.blocks(classifyOutlookEvent(ev)) {
+			continue
+		}
+		start, end, ok := ev.span()
+		if !ok {
+			continue
+		}
+		slots = append(slots, p.slot(start, end, ev.IsAllDay))
+	}
+	return slots
+}
+
+// getOutlookEventBusyTimes lists the events of one Outlook calendar and
+// applies the busy policy to each.
+func (s *CalendarService) getOutlookEventBusyTimes(ctx context.Context, conn *models.CalendarConnection, calendarID string, policy busyPolicy, start, end time.Time) ([]models.TimeSlot, error) {
+	events, err := s.listOutlookCalendarView(ctx, conn, calendarID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return policy.outlookBusySlots(events), nil
+}
+
+// getOutlookScheduleBusyTimes asks getSchedule for the free/busy view of a
+// mailbox's default calendar. It is one cheap call, but only covers the
+// default calendar and hides declined meetings, so callers use it only when
+// the calendar's policy matches the provider's own free/busy view.
+func (s *CalendarService) getOutlookScheduleBusyTimes(ctx context.Context, conn *models.CalendarConnection, mailbox string, start, end time.Time) ([]models.TimeSlot, error) {
+	reqBody := map[string]interface{}{
+		"schedules": []string{mailbox},
+		"startTime": newGraphDateTime(start),
+		"endTime":   newGraphDateTime(end),
+	}
+	var result struct {
+		Value []struct {
+			ScheduleID    string `json:"scheduleId"`
+			ScheduleItems []struct {
+				Status string        `json:"status"`
+				Start  graphDateTime `json:"start"`
+				End    graphDateTime `json:"end"`
+			} `json:"scheduleItems"`
+			Error *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		} `json:"value"`
+	}
+	if err := s.graphRequest(ctx, conn, "POST", "/me/calendar/getSchedule", reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	policy := defaultBusyPolicy()
+	var slots []models.TimeSlot
+	for _, sched := range result.Value {
+		if sched.Error != nil {
+			return nil, fmt.Errorf("getSchedule failed for %s: %s", sched.ScheduleID, sched.Error.Message)
+		}
+		for _, item := range sched.ScheduleItems {
+			ev := outlookEvent{ShowAs: item.Status}
+			if !policy.blocks(classifyOutlookEvent(&ev)) {
+				continue
+			}
+			startTime, ok1 := item.Start.parse()
+			endTime, ok2 := item.End.parse()
+			if !ok1 || !ok2 {
+				continue
+			}
+			slots = append(slots, models.TimeSlot{Start: startTime, End: endTime})
+		}
+	}
+	return slots, nil
+}
+
+// outlookBusyTimes picks between getSchedule and calendarView for one
+// calendar. getSchedule is used for the default calendar when the policy
+// agrees with it and the account address is known.
+func (s *CalendarService) outlookBusyTimes(ctx context.Context, conn *models.CalendarConnection, pc *models.ProviderCalendar, policy busyPolicy, start, end time.Time) ([]models.TimeSlot, error) {
+	if pc.IsPrimary && policy.matchesFreeBusy() && isValidEmail(conn.Name) && end.Sub(start) <= outlookScheduleMaxRange {
+		return s.getOutlookScheduleBusyTimes(ctx, conn, conn.Name, start, end)
+	}
+	return s.getOutlookEventBusyTimes(ctx, conn, pc.ProviderCalendarID, policy, start, end)
+}
+
+// getOutlookAgendaEvents fetches events from an Outlook calendar for the
+// agenda view. Cancelled meetings that are still on the calendar are skipped.
+func (s *CalendarService) getOutlookAgendaEvents(ctx context.Context, cal *models.CalendarConnection, start, end time.Time) ([]AgendaEvent, error) {
+	items, err := s.listOutlookCalendarView(ctx, cal, cal.CalendarID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	calColor := cal.Color
+	if calColor == "" {
+		calColor = "#5F5E5A"
+	}
+	var events []AgendaEvent
+	for i := range items {
+		item := &items[i]
+		if item.IsCancelled {
+			continue
+		}
+		startTime, endTime, ok := item.span()
+		if !ok {
+			continue
+		}
+		events = append(events, AgendaEvent{
+			ID:            item.ID,
+			CalendarID:    cal.ID,
+			CalendarColor: calColor,
+			Title:         item.Subject,
+			Start:         startTime,
+			End:           endTime,
+			CalendarName:  cal.Name,
+			IsAllDay:      item.IsAllDay,
+		})
+	}
+	return events, nil
+}
+
+// outlookEventBody builds the Graph event payload shared by create and
+// update. Start/End and Summary are only included when set so a partial
+// update never blanks them.
+func outlookEventBody(input *CalendarEventInput) map[string]interface{} {
+	attendees := make([]map[string]interface{}, 0, len(input.Attendees))
+	for _, email := range input.Attendees {
+		if isValidEmail(email) {
+			attendees = append(attendees, map[string]interface{}{
+				"emailAddress": map[string]string{"address": email},
+				"type":         "required",
+			})
+		}
+	}
+
+	event := map[string]interface{}{
+		"body": map[string]string{
+			"contentType": "text",
+			"content":     input.Description,
+		},
+		"attendees": attendees,
+	}
+	if input.Summary != "" {
+		event["subject"] = input.Summary
+	}
+	if !input.Start.IsZero() && !input.End.IsZero() {
+		event["start"] = newGraphDateTime(input.Start)
+		event["end"] = newGraphDateTime(input.End)
+	}
+
+	location := ""
+	if input.LocationType == models.ConferencingProviderPhone {
+		if input.CustomLocation != "" {
+			location = "Call " + input.CustomLocation
+		}
+	} else if input.ConferenceLink != "" {
+		location = input.ConferenceLink
+	} else if input.CustomLocation != "" {
+		location = input.CustomLocation
+	}
+	if location != "" {
+		event["location"] = map[string]string{"displayName": location}
+	}
+	return event
+}
+
+// createOutlookEvent creates an event in cal.CalendarID. Graph sends the
+// invitations to attendees itself.
+func (s *CalendarService) createOutlookEvent(ctx context.Context, cal *models.CalendarConnection, input *CalendarEventInput) (string, error) {
+	var created struct {
+		ID string `json:"id"`
+	}
+	path := outlookCalendarPath(cal.CalendarID) + "/events"
+	if err := s.graphRequest(ctx, cal, "POST", path, outlookEventBody(input), &created); err != nil {
+		return "", fmt.Errorf("failed to create event: %w", err)
+	}
+	return created.ID, nil
+}
+
+// updateOutlookEvent PATCHes an existing event; attendees keep their
+// responses.
+func (s *CalendarService) updateOutlookEvent(ctx context.Context, cal *models.CalendarConnection, input *CalendarEventInput) (string, error) {
+	path := "/me/events/" + url.PathEscape(input.EventID)
+	if err := s.graphRequest(ctx, cal, "PATCH", path, outlookEventBody(input), nil); err != nil {
+		return "", fmt.Errorf("failed to update event: %w", err)
+	}
+	return input.EventID, nil
+}
+
+// getOutlookEvent reads back one event. Events a host deletes in Outlook
+// disappear (404); meetings they cancel keep existing with isCancelled set.
+func (s *CalendarService) getOutlookEvent(ctx context.Context, cal *models.CalendarConnection, eventID string) (*ExternalEvent, error) {
+	var ev outlookEvent
+	path := "/me/events/" + url.PathEscape(eventID) + "?$select=isCancelled,isAllDay,start,end"
+	if err := s.graphRequest(ctx, cal, "GET", path, nil, &ev); err != nil {
+		if errors.Is(err, errGraphNotFound) {
+			return &ExternalEvent{Deleted: true}, nil
+		}
+		return nil, err
+	}
+	if ev.IsCancelled {
+		return &ExternalEvent{Deleted: true}, nil
+	}
+	start, end, ok := ev.span()
+	if !ok {
+		return nil, fmt.Errorf("event %s has no usable start/end", eventID)
+	}
+	return &ExternalEvent{Start: start, End: end, IsAllDay: ev.IsAllDay}, nil
+}
+
+func (s *CalendarService) deleteOutlookEvent(ctx context.Context, cal *models.CalendarConnection, eventID string) error {
+	path := "/me/events/" + url.PathEscape(eventID)
+	if err := s.graphRequest(ctx, cal, "DELETE", path, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// graphStandIn is an httptest stand-in for Microsoft Graph and the Microsoft
+// identity token endpoint. Tests register handlers on mux; every request is
+// recorded as "METHOD path".
+type graphStandIn struct {
+	*httptest.Server
+	mux *http.ServeMux
+
+	mu       sync.Mutex
+	requests []string
+}
+
+func newGraphStandIn(t *testing.T, cal *CalendarService) *graphStandIn {
+	t.Helper()
+	g := &graphStandIn{mux: http.NewServeMux()}
+	g.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.mu.Lock()
+		g.requests = append(g.requests, r.Method+" "+r.URL.Path)
+		g.mu.Unlock()
+		if r.Header.Get("Authorization") == "" && !strings.HasSuffix(r.URL.Path, "/token") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		g.mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(g.Close)
+
+	cal.graphBaseURL = g.URL + "/v1.0"
+	cal.microsoftLoginURL = g.URL
+	cal.cfg.OAuth.Microsoft.ClientID = "client"
+	cal.cfg.OAuth.Microsoft.ClientSecret = "secret"
+	return g
+}
+
+func (g *graphStandIn) saw(request string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return slices.Contains(g.requests, request)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// TestConnectOutlookCalendar_ListsCalendars walks the whole connect flow:
+// code exchange, a paged calendar list, and the profile lookup that names the
+// connection.
+func TestConnectOutlookCalendar_ListsCalendars(t *testing.T) {
+	_, repos, cal := setupServiceTestDB(t)
+	host, _ := seedHostAndConnection(t, repos, models.CalendarProviderCalDAV, "", "http://unused/")
+	g := newGraphStandIn(t, cal)
+
+	g.mux.HandleFunc("POST /common/oauth2/v2.0/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("grant_type") != "authorization_code" || r.Form.Get("code") != "the-code" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"access_token": "at", "refresh_token": "rt", "expires_in": 3600})
+	})
+	g.mux.HandleFunc("GET /v1.0/me/calendars", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			writeJSON(w, map[string]interface{}{"value": []map[string]interface{}{
+				{"id": "shared", "name": "Team", "hexColor": "", "canEdit": false},
+			}})
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"value": []map[string]interface{}{
+				{"id": "primary", "name": "Calendar", "hexColor": "#1f77b4", "isDefaultCalendar": true, "canEdit": true},
+			},
+			"@odata.nextLink": g.URL + "/v1.0/me/calendars?page=2",
+		})
+	})
+	g.mux.HandleFunc("GET /v1.0/me", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{"mail": "sam@contoso.com", "userPrincipalName": "sam@contoso.onmicrosoft.com"})
+	})
+
+	conn, err := cal.ConnectOutlookCalendar(context.Background(), OutlookCalendarConnectInput{
+		HostID: host.ID, AuthCode: "the-code", RedirectURI: "http://test/auth/microsoft/callback",
+	})
+	if err != nil {
+		t.Fatalf("ConnectOutlookCalendar: %v", err)
+	}
+	if conn.Provider != models.CalendarProviderOutlook || conn.Name != "sam@contoso.com" || conn.CalendarID != "primary" {
+		t.Errorf("connection = %s/%q/%q, want outlook/sam@contoso.com/primary", conn.Provider, conn.Name, conn.CalendarID)
+	}
+
+	pcs, err := repos.ProviderCalendar.GetByConnectionID(context.Background(), conn.ID)
+	if err != nil {
+		t.Fatalf("list provider calendars: %v", err)
+	}
+	if len(pcs) != 2 {
+		t.Fatalf("expected 2 provider calendars, got %d", len(pcs))
+	}
+	for _, pc := range pcs {
+		switch pc.ProviderCalendarID {
+		case "primary":
+			if !pc.IsPrimary || !pc.IsWritable || pc.Color != "#1F77B4" {
+				t.Errorf("primary calendar = %+v", pc)
+			}
+		case "shared":
+			if pc.IsPrimary || pc.IsWritable || pc.Color == "" {
+				t.Errorf("shared calendar = %+v", pc)
+			}
+		default:
+			t.Errorf("unexpected calendar %q", pc.ProviderCalendarID)
+		}
+	}
+}
+
+// TestGetBusyTimes_OutlookScheduleAndCalendarView checks both busy paths: the
+// default calendar goes through getSchedule, and a calendar with a custom
+// policy is read through calendarView and classified locally.
+func TestGetBusyTimes_OutlookScheduleAndCalendarView(t *testing.T) {
+	_, repos, cal := setupServiceTestDB(t)
+	host, conn := seedHostAndConnection(t, repos, models.CalendarProviderOutlook, "at", "")
+	conn.Name = "sam@contoso.com"
+	if err := repos.Calendar.Update(context.Background(), conn); err != nil {
+		t.Fatalf("update connection: %v", err)
+	}
+	g := newGraphStandIn(t, cal)
+	ctx := context.Background()
+
+	var scheduled []string
+	g.mux.HandleFunc("POST /v1.0/me/calendar/getSchedule", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Schedules []string `json:"schedules"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		scheduled = body.Schedules
+		writeJSON(w, map[string]interface{}{"value": []map[string]interface{}{{
+			"scheduleId": "sam@contoso.com",
+			"scheduleItems": []map[string]interface{}{
+				{"status": "busy", "start": map[string]string{"dateTime": "2024-01-10T09:00:00.0000000", "timeZone": "UTC"}, "end": map[string]string{"dateTime": "2024-01-10T10:00:00.0000000", "timeZone": "UTC"}},
+				{"status": "free", "start": map[string]string{"dateTime": "2024-01-10T10:00:00.0000000", "timeZone": "UTC"}, "end": map[string]string{"dateTime": "2024-01-10T11:00:00.0000000", "timeZone": "UTC"}},
+				{"status": "tentative", "start": map[string]string{"dateTime": "2024-01-10T12:00:00.0000000", "timeZone": "UTC"}, "end": map[string]string{"dateTime": "2024-01-10T13:00:00.0000000", "timeZone": "UTC"}},
+			},
+		}}})
+	})
+	g.mux.HandleFunc("GET /v1.0/me/calendars/{id}/calendarView", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "side" || r.Header.Get("Prefer") != `outlook.timezone="UTC"` {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"value": []map[string]interface{}{
+			{"showAs": "busy", "start": map[string]string{"dateTime": "2024-01-10T14:00:00.0000000", "timeZone": "UTC"}, "end": map[string]string{"dateTime": "2024-01-10T15:00:00.0000000", "timeZone": "UTC"}},
+			{"showAs": "busy", "isCancelled": true, "start": map[string]string{"dateTime": "2024-01-10T15:00:00.0000000", "timeZone": "UTC"}, "end": map[string]string{"dateTime": "2024-01-10T16:00:00.0000000", "timeZone": "UTC"}},
+			{"showAs": "tentative", "start": map[string]string{"dateTime": "2024-01-10T16:00:00.0000000", "timeZone": "UTC"}, "end": map[string]string{"dateTime": "2024-01-10T17:00:00.0000000", "timeZone": "UTC"}},
+			{"showAs": "busy", "responseStatus": map[string]string{"response": "declined"}, "start": map[string]string{"dateTime": "2024-01-10T17:00:00.0000000", "timeZone": "UTC"}, "end": map[string]string{"dateTime": "2024-01-10T18:00:00.0000000", "timeZone": "UTC"}},
+			{"showAs": "free", "start": map[string]string{"dateTime": "2024-01-10T18:00:00.0000000", "timeZone": "UTC"}, "end": map[string]string{"dateTime": "2024-01-10T19:00:00.0000000", "timeZone": "UTC"}},
+		}})
+	})
+
+	if _, err := repos.ProviderCalendar.UpsertFromProvider(ctx, conn.ID, "main", "Calendar", "", true, true); err != nil {
+		t.Fatalf("upsert main: %v", err)
+	}
+	side, err := repos.ProviderCalendar.UpsertFromProvider(ctx, conn.ID, "side", "Side", "", false, true)
+	if err != nil {
+		t.Fatalf("upsert side: %v", err)
+	}
+	if err := repos.ProviderCalendar.UpdateBusyPolicy(ctx, host.ID, side.ID, false, true, false); err != nil {
+		t.Fatalf("update policy: %v", err)
+	}
+
+	start := mustUTC(t, "2024-01-10T00:00:00Z")
+	slots, err := cal.GetBusyTimes(ctx, host.ID, start, start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("GetBusyTimes: %v", err)
+	}
+	got := slotHours(slots)
+	slices.Sort(got)
+	if want := []int{9, 12, 14, 18}; !slices.Equal(got, want) {
+		t.Errorf("busy hours = %v, want %v", got, want)
+	}
+	if !slices.Equal(scheduled, []string{"sam@contoso.com"}) {
+		t.Errorf("getSchedule asked about %v", scheduled)
+	}
+	if g.saw("GET /v1.0/me/calendars/main/calendarView") {
+		t.Error("default calendar should not be listed through calendarView")
+	}
+}
+
+// TestOutlookEventWriter covers create, update and delete through the
+// calendarEventWriter entry points, plus the refresh of an expired token.
+func TestOutlookEventWriter(t *testing.T) {
+	_, repos, cal := setupServiceTestDB(t)
+	host, conn := seedHostAndConnection(t, repos, models.CalendarProviderOutlook, "stale", "")
+	conn.TokenExpiry = nil
+	if err := repos.Calendar.Update(context.Background(), conn); err != nil {
+		t.Fatalf("update connection: %v", err)
+	}
+	g := newGraphStandIn(t, cal)
+	ctx := context.Background()
+
+	g.mux.HandleFunc("POST /common/oauth2/v2.0/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "rt" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"access_token": "fresh", "refresh_token": "rt2", "expires_in": 3600})
+	})
+	var created, patched map[string]interface{}
+	g.mux.HandleFunc("POST /v1.0/me/calendars/{id}/events", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&created)
+		w.WriteHeader(http.StatusCreated)
+		writeJSON(w, map[string]string{"id": "evt-1"})
+	})
+	g.mux.HandleFunc("PATCH /v1.0/me/events/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&patched)
+		writeJSON(w, map[string]string{"id": r.PathValue("id")})
+	})
+	g.mux.HandleFunc("DELETE /v1.0/me/events/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	pc, err := repos.ProviderCalendar.UpsertFromProvider(ctx, conn.ID, "cal-1", "Calendar", "", true, true)
+	if err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+
+	var writer calendarEventWriter = cal
+	input := &CalendarEventInput{
+		Summary:      "Intro with Ada",
+		Description:  "Agenda",
+		Start:        mustUTC(t, "2024-01-10T09:00:00Z"),
+		End:          mustUTC(t, "2024-01-10T09:30:00Z"),
+		Attendees:    []string{"ada@example.com", "not-an-email"},
+		LocationType: models.ConferencingProviderPhone, CustomLocation: "+1 555 0100",
+	}
+	eventID, link, err := writer.CreateEventForHost(ctx, pc.ID, input)
+	if err != nil {
+		t.Fatalf("CreateEventForHost: %v", err)
+	}
+	if eventID != "evt-1" || link != "" {
+		t.Errorf("create returned %q, %q", eventID, link)
+	}
+	if created["subject"] != "Intro with Ada" {
+		t.Errorf("subject = %v", created["subject"])
+	}
+	if start, _ := created["start"].(map[string]interface{}); start["dateTime"] != "2024-01-10T09:00:00" || start["timeZone"] != "UTC" {
+		t.Errorf("start = %v", created["start"])
+	}
+	if attendees, _ := created["attendees"].([]interface{}); len(attendees) != 1 {
+		t.Errorf("attendees = %v", created["attendees"])
+	}
+	if loc, _ := created["location"].(map[string]interface{}); loc["displayName"] != "Call +1 555 0100" {
+		t.Errorf("location = %v", created["location"])
+	}
+
+	stored, _ := repos.Calendar.GetByID(ctx, conn.ID)
+	if stored.AccessToken != "fresh" || stored.RefreshToken != "rt2" {
+		t.Errorf("refreshed tokens not persisted: %q / %q", stored.AccessToken, stored.RefreshToken)
+	}
+
+	update := &CalendarEventInput{EventID: "evt-1", Description: "New agenda"}
+	if id, err := writer.UpdateEvent(ctx, pc.ID, update); err != nil || id != "evt-1" {
+		t.Fatalf("UpdateEvent = %q, %v", id, err)
+	}
+	if _, ok := patched["start"]; ok {
+		t.Error("partial update should not send start")
+	}
+	if _, ok := patched["subject"]; ok {
+		t.Error("partial update should not blank the subject")
+	}
+
+	if err := writer.DeleteEvent(ctx, host.ID, pc.ID, "evt-1"); err != nil {
+		t.Fatalf("DeleteEvent: %v", err)
+	}
+	if !g.saw("DELETE /v1.0/me/events/evt-1") {
+		t.Error("expected DELETE of evt-1")
+	}
+}
+
+// TestGraphDateTime_ParsesWindowsZones covers values Graph returns without
+// honouring the UTC preference.
+func TestGraphDateTime_ParsesWindowsZones(t *testing.T) {
+	mustLoadLocation(t, "Europe/Berlin")
+	got, ok := graphDateTime{DateTime: "2024-07-10T10:00:00.0000000", TimeZone: "W. Europe Standard Time"}.parse()
+	if !ok || !got.Equal(mustUTC(t, "2024-07-10T08:00:00Z")) {
+		t.Errorf("parse = %s, %v", got, ok)
+	}
+}
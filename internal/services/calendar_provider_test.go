@@ -87,7 +87,7 @@ func setupServiceTestDB(t *testing.T) (*sql.DB, *repository.Repositories, *Calen
 		_ = db.Close()
 		t.Fatalf("migrate: %v", err)
 	}
-	repos := repository.NewRepositories(db, dbCfg.Driver)
+	repos := repository.NewRepositories(db, dbCfg.Driver, nil)
 	cal := NewCalendarService(cfg, repos)
 	return db, repos, cal
 }
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/models"
+	"github.com/meet-when/meet-when/internal/repository"
+)
+
+// calendarEventReader is the slice of CalendarService the reconciler needs,
+// split out so tests can report edits without a provider.
+type calendarEventReader interface {
+	GetEvent(ctx context.Context, providerCalendarID, eventID string) (*ExternalEvent, error)
+}
+
+// CalendarReconciler compares the events MeetWhen wrote into host calendars
+// with what the calendars hold now, and hands any that were moved or deleted
+// outside MeetWhen to the owning service. Only upcoming items are checked;
+// an edit to a past meeting changes nothing for the invitee.
+type CalendarReconciler struct {
+	repos        *repository.Repositories
+	calendar     calendarEventReader
+	bookings     *BookingService
+	hostedEvents *HostedEventService
+}
+
+// NewCalendarReconciler creates a new calendar reconciler
+func NewCalendarReconciler(repos *repository.Repositories, calendar *CalendarService, bookings *BookingService, hostedEvents *HostedEventService) *CalendarReconciler {
+	return &CalendarReconciler{
+		repos:        repos,
+		calendar:     calendar,
+		bookings:     bookings,
+		hostedEvents: hostedEvents,
+	}
+}
+
+// ReconcileConnection reconciles every calendar under a connection.
+// ICS feeds are read-only and never hold MeetWhen events.
+func (r *CalendarReconciler) ReconcileConnection(ctx context.Context, conn *models.CalendarConnection) {
+	if conn.Provider == models.CalendarProviderICSFeed {
+		return
+	}
+	calendars, err := r.repos.ProviderCalendar.GetByConnectionID(ctx, conn.ID)
+	if err != nil {
+		log.Printf("[CALENDAR_SYNC] reconcile: failed to load calendars for connection %s: %v", conn.ID, err)
+		return
+	}
+	for _, pc := range calendars {
+		r.ReconcileCalendar(ctx, pc.ID)
+	}
+}
+
+// ReconcileCalendar checks the tracked events on one provider calendar.
+// An event that can't be read is skipped until the next pass: acting on an
+// unknown state could cancel a meeting over a network blip.
+func (r *CalendarReconciler) ReconcileCalendar(ctx context.Context, calendarID string) {
+	now := time.Now()
+
+	bookingRows, err := r.repos.BookingCalendarEvent.ListUpcomingByCalendarID(ctx, calendarID, now)
+	if err != nil {
+		log.Printf("[CALENDAR_SYNC] reconcile: failed to load booking events for calendar %s: %v", calendarID, err)
+	}
+	for _, row := range bookingRows {
+		ev, err := r.calendar.GetEvent(ctx, row.CalendarID, row.EventID)
+		if err != nil {
+			log.Printf("[CALENDAR_SYNC] reconcile: failed to read event %s for booking %s: %v", row.EventID, row.BookingID, err)
+			continue
+		}
+		if err := r.bookings.ReconcileCalendarEvent(ctx, row, ev); err != nil {
+			log.Printf("[CALENDAR_SYNC] reconcile: booking %s: %v", row.BookingID, err)
+		}
+	}
+
+	hostedRows, err := r.repos.HostedEventCalendarEvent.ListUpcomingByCalendarID(ctx, calendarID, now)
+	if err != nil {
+		log.Printf("[CALENDAR_SYNC] reconcile: failed to load hosted events for calendar %s: %v", calendarID, err)
+	}
+	for _, row := range hostedRows {
+		ev, err := r.calendar.GetEvent(ctx, row.CalendarID, row.EventID)
+		if err != nil {
+			log.Printf("[CALENDAR_SYNC] reconcile: failed to read event %s for hosted event %s: %v", row.EventID, row.HostedEventID, err)
+			continue
+		}
+		if err := r.hostedEvents.ReconcileCalendarEvent(ctx, row, ev); err != nil {
+			log.Printf("[CALENDAR_SYNC] reconcile: hosted event %s: %v", row.HostedEventID, err)
+		}
+	}
+}
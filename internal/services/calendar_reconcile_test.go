@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/config"
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// fakeEventReader reports what each event ID currently looks like in the
+// host's calendar. IDs it doesn't know fail to read.
+type fakeEventReader struct {
+	events map[string]*ExternalEvent
+}
+
+func (f *fakeEventReader) GetEvent(_ context.Context, _, eventID string) (*ExternalEvent, error) {
+	if ev, ok := f.events[eventID]; ok {
+		return ev, nil
+	}
+	return nil, errors.New("provider unavailable")
+}
+
+// reconcileHarness books the fixture's booking for tomorrow with an event in
+// every host's calendar, and wires a reconciler to a fake event reader.
+type reconcileHarness struct {
+	reconciler *CalendarReconciler
+	bookings   *BookingService
+	reader     *fakeEventReader
+	fake       *fakeCalendarWriter
+	fixture    *fixture
+	start      time.Time
+	eventIDs   map[string]string // hostID → event ID
+	cleanup    func()
+}
+
+func makeReconcileHarness(t *testing.T, hostCount int) *reconcileHarness {
+	t.Helper()
+	sh := makeSyncerHarness(t, hostCount)
+	ctx := context.Background()
+	repos := sh.syncer.repos
+	fix := sh.fixture
+
+	start := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+	booking, err := repos.Booking.GetByID(ctx, fix.bookingID)
+	if err != nil || booking == nil {
+		t.Fatalf("load booking: %v / %v", err, booking)
+	}
+	booking.StartTime = models.NewSQLiteTime(start)
+	booking.EndTime = models.NewSQLiteTime(start.Add(30 * time.Minute))
+	if err := repos.Booking.Update(ctx, booking); err != nil {
+		t.Fatalf("update booking: %v", err)
+	}
+
+	hosts := make([]HostTarget, 0, hostCount)
+	for i, id := range fix.hostIDs {
+		hosts = append(hosts, HostTarget{HostID: id, CalendarID: fix.calendars[id], IsOwner: i == 0})
+	}
+	if _, _, err := sh.syncer.Create(ctx, CalendarSyncRequest{
+		Kind: ItemKindBooking, ItemID: fix.bookingID, Input: basicInput(), Hosts: hosts,
+	}); err != nil {
+		t.Fatalf("syncer create: %v", err)
+	}
+	rows, _ := repos.BookingCalendarEvent.GetByBookingID(ctx, fix.bookingID)
+	eventIDs := map[string]string{}
+	for _, r := range rows {
+		eventIDs[r.HostID] = r.EventID
+	}
+	sh.fake.createCalls = nil
+
+	// Emails go to a closed SMTP port; the sends are async and only logged.
+	cfg := &config.Config{}
+	cfg.Server.BaseURL = "http://test.local"
+	cfg.Email.SMTPHost = "127.0.0.1"
+	cfg.Email.SMTPPort = 1
+	calendar := NewCalendarService(cfg, repos)
+	bookings := NewBookingService(cfg, repos, calendar, NewAvailabilityService(repos, calendar, nil), sh.syncer,
+		NewConferencingService(cfg, repos), NewEmailService(cfg, repos), NewAuditLogService(repos), NewContactService(repos), NewWebhookService(repos))
+
+	reader := &fakeEventReader{events: map[string]*ExternalEvent{}}
+	for _, id := range eventIDs {
+		reader.events[id] = &ExternalEvent{Start: start, End: start.Add(30 * time.Minute)}
+	}
+
+	return &reconcileHarness{
+		reconciler: &CalendarReconciler{repos: repos, calendar: reader, bookings: bookings},
+		bookings:   bookings,
+		reader:     reader,
+		fake:       sh.fake,
+		fixture:    fix,
+		start:      start,
+		eventIDs:   eventIDs,
+		cleanup:    sh.cleanup,
+	}
+}
+
+// reconcile runs the reconciler over the given hosts' calendars (all of
+// them by default, owner first) and returns the reloaded booking.
+func (h *reconcileHarness) reconcile(t *testing.T, hostIDs ...string) *models.Booking {
+	t.Helper()
+	ctx := context.Background()
+	if len(hostIDs) == 0 {
+		hostIDs = h.fixture.hostIDs
+	}
+	for _, id := range hostIDs {
+		h.reconciler.ReconcileCalendar(ctx, h.fixture.calendars[id])
+	}
+	booking, err := h.reconciler.repos.Booking.GetByID(ctx, h.fixture.bookingID)
+	if err != nil || booking == nil {
+		t.Fatalf("reload booking: %v / %v", err, booking)
+	}
+	return booking
+}
+
+func TestReconcileBooking_UnchangedEventsAreLeftAlone(t *testing.T) {
+	h := makeReconcileHarness(t, 2)
+	defer h.cleanup()
+
+	booking := h.reconcile(t)
+	if booking.Status != models.BookingStatusConfirmed || booking.ReviewReason != "" {
+		t.Fatalf("booking = %s / %q, want confirmed and unflagged", booking.Status, booking.ReviewReason)
+	}
+	if len(h.fake.updateCalls)+len(h.fake.deleteCalls) != 0 {
+		t.Fatalf("calendar writes = %d updates, %d deletes, want none", len(h.fake.updateCalls), len(h.fake.deleteCalls))
+	}
+}
+
+func TestReconcileBooking_OwnerMoveReschedules(t *testing.T) {
+	h := makeReconcileHarness(t, 2)
+	defer h.cleanup()
+	owner := h.fixture.hostIDs[0]
+
+	moved := h.start.Add(3 * time.Hour)
+	h.reader.events[h.eventIDs[owner]] = &ExternalEvent{Start: moved, End: moved.Add(45 * time.Minute)}
+
+	// Only the owner's calendar: the fake reader doesn't see the writes the
+	// move makes to the pooled host's copy.
+	booking := h.reconcile(t, owner)
+	if !booking.StartTime.Equal(moved) || !booking.EndTime.Equal(moved.Add(45*time.Minute)) {
+		t.Fatalf("booking = %v–%v, want the moved time", booking.StartTime.Time, booking.EndTime.Time)
+	}
+	if booking.Duration != 45 {
+		t.Errorf("duration = %d, want 45", booking.Duration)
+	}
+	if booking.Status != models.BookingStatusConfirmed || booking.ReviewReason != "" {
+		t.Errorf("booking = %s / %q, want confirmed and unflagged", booking.Status, booking.ReviewReason)
+	}
+	// The pooled host's copy follows the move.
+	if len(h.fake.updateCalls) != 2 {
+		t.Fatalf("update calls = %d, want one per host", len(h.fake.updateCalls))
+	}
+	for _, c := range h.fake.updateCalls {
+		if !c.Input.Start.Equal(moved) {
+			t.Errorf("update on %s starts %v, want %v", c.CalendarID, c.Input.Start, moved)
+		}
+	}
+
+	// The next pass sees the calendars agree with the booking.
+	h.reader.events[h.eventIDs[h.fixture.hostIDs[1]]] = &ExternalEvent{Start: moved, End: moved.Add(45 * time.Minute)}
+	h.fake.updateCalls = nil
+	h.reconcile(t)
+	if len(h.fake.updateCalls) != 0 {
+		t.Errorf("second pass rewrote %d events", len(h.fake.updateCalls))
+	}
+}
+
+func TestReconcileBooking_OwnerDeleteCancels(t *testing.T) {
+	h := makeReconcileHarness(t, 1)
+	defer h.cleanup()
+
+	h.reader.events[h.eventIDs[h.fixture.hostIDs[0]]] = &ExternalEvent{Deleted: true}
+
+	booking := h.reconcile(t)
+	if booking.Status != models.BookingStatusCancelled {
+		t.Fatalf("status = %s, want cancelled", booking.Status)
+	}
+	if booking.CancelledBy != "host" {
+		t.Errorf("cancelled_by = %q, want host", booking.CancelledBy)
+	}
+}
+
+func TestReconcileBooking_PooledHostDriftFlagsForReview(t *testing.T) {
+	h := makeReconcileHarness(t, 2)
+	defer h.cleanup()
+	ctx := context.Background()
+	sibling := h.fixture.hostIDs[1]
+
+	h.reader.events[h.eventIDs[sibling]] = &ExternalEvent{Deleted: true}
+
+	booking := h.reconcile(t)
+	if booking.Status != models.BookingStatusConfirmed {
+		t.Fatalf("status = %s, a pooled host must not cancel the booking", booking.Status)
+	}
+	if booking.ReviewReason != "Host deleted this meeting from their calendar" {
+		t.Fatalf("review reason = %q", booking.ReviewReason)
+	}
+	if !booking.StartTime.Equal(h.start) {
+		t.Errorf("start moved to %v", booking.StartTime.Time)
+	}
+
+	// Restoring rewrites every host's event and clears the flag.
+	if err := h.bookings.RestoreCalendarEvents(ctx, h.fixture.hostIDs[0], h.fixture.tenantID, booking.ID); err != nil {
+		t.Fatalf("RestoreCalendarEvents: %v", err)
+	}
+	restored, _ := h.reconciler.repos.Booking.GetByID(ctx, booking.ID)
+	if restored.ReviewReason != "" {
+		t.Errorf("review reason = %q after restore", restored.ReviewReason)
+	}
+	if len(h.fake.deleteCalls) != 2 || len(h.fake.createCalls) == 0 {
+		t.Errorf("restore made %d deletes and %d creates", len(h.fake.deleteCalls), len(h.fake.createCalls))
+	}
+	if err := h.bookings.RestoreCalendarEvents(ctx, sibling, h.fixture.tenantID, booking.ID); !errors.Is(err, ErrBookingNotFound) {
+		t.Errorf("restore by a non-owner: err = %v, want ErrBookingNotFound", err)
+	}
+}
+
+func TestReconcileBooking_AllDayAndUnreadableEvents(t *testing.T) {
+	h := makeReconcileHarness(t, 1)
+	defer h.cleanup()
+	owner := h.fixture.hostIDs[0]
+
+	// An event that can't be read is never acted on.
+	delete(h.reader.events, h.eventIDs[owner])
+	booking := h.reconcile(t)
+	if booking.Status != models.BookingStatusConfirmed || booking.ReviewReason != "" {
+		t.Fatalf("booking = %s / %q after a read error", booking.Status, booking.ReviewReason)
+	}
+
+	day := h.start.Truncate(24 * time.Hour)
+	h.reader.events[h.eventIDs[owner]] = &ExternalEvent{Start: day, End: day.Add(24 * time.Hour), IsAllDay: true}
+	booking = h.reconcile(t)
+	if booking.ReviewReason == "" || !booking.StartTime.Equal(h.start) {
+		t.Fatalf("all-day drift: reason %q, start %v; want flagged and unmoved", booking.ReviewReason, booking.StartTime.Time)
+	}
+}
+
+func TestReconcileHostedEvent_MoveAndDelete(t *testing.T) {
+	h := makeHostedEventHarness(t)
+	defer h.cleanup()
+	ctx := context.Background()
+
+	in := h.baseCreateInput()
+	in.Start = time.Now().UTC().Add(48 * time.Hour).Truncate(time.Hour)
+	out, err := h.svc.Create(ctx, in)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	rows, _ := h.repos.HostedEventCalendarEvent.GetByHostedEventID(ctx, out.Event.ID)
+	if len(rows) != 1 {
+		t.Fatalf("tracking rows = %d, want 1", len(rows))
+	}
+
+	reader := &fakeEventReader{events: map[string]*ExternalEvent{}}
+	r := &CalendarReconciler{repos: h.repos, calendar: reader, hostedEvents: h.svc}
+
+	moved := in.Start.Add(2 * time.Hour)
+	reader.events[rows[0].EventID] = &ExternalEvent{Start: moved, End: moved.Add(time.Hour)}
+	r.ReconcileCalendar(ctx, h.calID)
+
+	event, _ := h.repos.HostedEvent.GetByID(ctx, out.Event.ID)
+	if !event.StartTime.Equal(moved) || event.Duration != 60 {
+		t.Fatalf("event = %v for %d min, want %v for 60", event.StartTime.Time, event.Duration, moved)
+	}
+	if got := h.email.updatedCount(); got != len(in.Attendees) {
+		t.Errorf("updated emails = %d, want %d", got, len(in.Attendees))
+	}
+
+	reader.events[rows[0].EventID] = &ExternalEvent{Deleted: true}
+	r.ReconcileCalendar(ctx, h.calID)
+
+	event, _ = h.repos.HostedEvent.GetByID(ctx, out.Event.ID)
+	if event.Status != models.HostedEventStatusCancelled {
+		t.Fatalf("status = %s, want cancelled", event.Status)
+	}
+	if got := len(h.email.cancelledEmails()); got != len(in.Attendees) {
+		t.Errorf("cancelled emails = %d, want %d", got, len(in.Attendees))
+	}
+}
@@ -0,0 +1,795 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRecurrencePeriods bounds how many FREQ periods a single series is walked
+// through, so a malformed or unbounded RRULE cannot stall a busy-time request.
+const maxRecurrencePeriods = 5000
+
+// icsEvent is one parsed VEVENT component. Start/End keep the location the
+// event was declared in (TZID or UTC) so that recurrence expansion happens in
+// wall-clock time and a 09:00 standup stays at 09:00 across DST changes.
+type icsEvent struct {
+	UID          string
+	Summary      string
+	Start        time.Time
+	End          time.Time
+	IsAllDay     bool
+	RRule        string
+	RDates       []time.Time
+	ExDates      []time.Time
+	RecurrenceID time.Time // non-zero when this VEVENT overrides one instance of a series
+	Transp       string    // TRANSP, upper-cased; empty means OPAQUE
+	Status       string    // STATUS, upper-cased (CONFIRMED, TENTATIVE, CANCELLED)
+	Attendees    []icsAttendee
+}
+
+// icsAttendee is one ATTENDEE property: the calendar address without its
+// "mailto:" prefix and the participation status.
+type icsAttendee struct {
+	Email    string
+	PartStat string
+}
+
+// icsOccurrence is a single concrete instance of an icsEvent after recurrence
+// expansion. Start/End are in UTC.
+type icsOccurrence struct {
+	Event     *icsEvent
+	Start     time.Time
+	End       time.Time
+	Recurring bool // instance of a series (expanded, RDATE or RECURRENCE-ID override)
+}
+
+// instanceID returns a stable per-occurrence identifier. Single events keep
+// their UID; series instances get the UID suffixed with the instance start,
+// matching the "<id>_<start>" shape Google uses for singleEvents=true.
+func (o icsOccurrence) instanceID() string {
+	if !o.Recurring {
+		return o.Event.UID
+	}
+	if o.Event.IsAllDay {
+		return o.Event.UID + "_" + o.Start.UTC().Format("20060102")
+	}
+	return o.Event.UID + "_" + o.Start.UTC().Format("20060102T150405Z")
+}
+
+// parseICSEvents parses every VEVENT in the ICS data. Properties of nested
+// components (e.g. VALARM) are ignored. Events without a DTSTART are dropped,
+// as are timed events with neither DTEND nor DURATION; all-day events without
+// an end last one day (RFC 5545 §3.6.1).
+//
+// TZIDs are resolved against the VTIMEZONE components of the same data;
+// floating times (no Z, no TZID) are taken to be in the floating location,
+// normally the host's timezone. A nil floating location means UTC.
+func parseICSEvents(icsData string, floating *time.Location) []*icsEvent {
+	var events []*icsEvent
+
+	lines := unfoldICSLines(icsData)
+	zones := &icsZones{defined: parseVTimezones(lines), floating: floating}
+
+	var cur *icsEvent
+	var hasStart, hasEnd bool
+	var duration time.Duration
+	nested := 0
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		name, params, value := splitICSProperty(line)
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			cur = &icsEvent{}
+			hasStart, hasEnd = false, false
+			duration = 0
+			nested = 0
+			continue
+		case cur == nil:
+			continue
+		case name == "BEGIN":
+			nested++
+			continue
+		case name == "END" && nested > 0:
+			nested--
+			continue
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if hasStart {
+				if !hasEnd {
+					switch {
+					case duration > 0:
+						cur.End = cur.Start.Add(duration)
+						hasEnd = true
+					case cur.IsAllDay:
+						cur.End = cur.Start.AddDate(0, 0, 1)
+						hasEnd = true
+					}
+				}
+				if hasEnd {
+					events = append(events, cur)
+				}
+			}
+			cur = nil
+			continue
+		case nested > 0:
+			continue
+		}
+
+		switch name {
+		case "UID":
+			cur.UID = value
+		case "SUMMARY":
+			cur.Summary = value
+		case "DTSTART":
+			if t, isDate, ok := parseICSTime(value, params, zones); ok {
+				cur.Start = t
+				cur.IsAllDay = isDate
+				hasStart = true
+			}
+		case "DTEND":
+			if t, _, ok := parseICSTime(value, params, zones); ok {
+				cur.End = t
+				hasEnd = true
+			}
+		case "DURATION":
+			if d, ok := parseICSDuration(line); ok {
+				duration = d
+			}
+		case "RRULE":
+			cur.RRule = value
+		case "RDATE":
+			cur.RDates = append(cur.RDates, parseICSTimeList(value, params, zones)...)
+		case "EXDATE":
+			cur.ExDates = append(cur.ExDates, parseICSTimeList(value, params, zones)...)
+		case "TRANSP":
+			cur.Transp = strings.ToUpper(value)
+		case "STATUS":
+			cur.Status = strings.ToUpper(value)
+		case "ATTENDEE":
+			cur.Attendees = append(cur.Attendees, icsAttendee{
+				Email:    strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(value, "mailto:"), "MAILTO:")),
+				PartStat: strings.ToUpper(params["PARTSTAT"]),
+			})
+		case "RECURRENCE-ID":
+			if t, _, ok := parseICSTime(value, params, zones); ok {
+				cur.RecurrenceID = t
+			}
+		}
+	}
+
+	return events
+}
+
+// splitICSProperty splits an unfolded content line into its upper-cased name,
+// its parameters (keys upper-cased, surrounding quotes removed) and its value
+// (RFC 5545 §3.1). Quoted parameter values may contain ':' and ';'.
+func splitICSProperty(line string) (string, map[string]string, string) {
+	inQuote := false
+	nameEnd := -1
+	valueStart := -1
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case c == ';' && nameEnd == -1:
+			nameEnd = i
+		case c == ':':
+			if nameEnd == -1 {
+				nameEnd = i
+			}
+			valueStart = i + 1
+		}
+		if valueStart != -1 {
+			break
+		}
+	}
+	if valueStart == -1 {
+		return strings.ToUpper(line), nil, ""
+	}
+
+	name := strings.ToUpper(line[:nameEnd])
+	value := line[valueStart:]
+	if nameEnd == valueStart-1 {
+		return name, nil, value
+	}
+
+	params := make(map[string]string)
+	for _, p := range splitICSParams(line[nameEnd+1 : valueStart-1]) {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok {
+			continue
+		}
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return name, params, value
+}
+
+// splitICSParams splits a parameter list on ';' outside of quoted values.
+func splitICSParams(s string) []string {
+	var out []string
+	inQuote := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			inQuote = !inQuote
+		case ';':
+			if !inQuote {
+				out = append(out, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(out, s[start:])
+}
+
+// parseICSTime parses a DATE or DATE-TIME value. DATE values come back as UTC
+// midnight (the convention the agenda uses for all-day events); DATE-TIME
+// values come back in their declared location so callers can do wall-clock
+// arithmetic before converting to UTC. zones may be nil, in which case TZIDs
+// are resolved by name only and floating times are UTC.
+func parseICSTime(value string, params map[string]string, zones *icsZones) (time.Time, bool, bool) {
+	value = strings.TrimSpace(value)
+
+	if params["VALUE"] == "DATE" || len(value) == 8 {
+		if len(value) < 8 {
+			return time.Time{}, false, false
+		}
+		t, err := time.Parse("20060102", value[:8])
+		if err != nil {
+			return time.Time{}, false, false
+		}
+		return t, true, true
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, false, false
+		}
+		return t, false, true
+	}
+
+	if len(value) < 15 {
+		return time.Time{}, false, false
+	}
+	loc := zones.floatingLocation()
+	if tzid := params["TZID"]; tzid != "" {
+		loc = zones.location(tzid)
+	}
+	t, err := time.ParseInLocation("20060102T150405", value[:15], loc)
+	if err != nil {
+		return time.Time{}, false, false
+	}
+	return t, false, true
+}
+
+// parseICSTimeList parses a comma-separated RDATE/EXDATE value. PERIOD values
+// ("start/end" or "start/duration") contribute their start only.
+func parseICSTimeList(value string, params map[string]string, zones *icsZones) []time.Time {
+	var out []time.Time
+	for _, part := range strings.Split(value, ",") {
+		part, _, _ = strings.Cut(part, "/")
+		if t, _, ok := parseICSTime(part, params, zones); ok {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// expandICSEvents turns parsed VEVENTs into the concrete occurrences that
+// overlap [rangeStart, rangeEnd). Recurring masters are expanded via
+// RRULE/RDATE minus EXDATE; instances that have a RECURRENCE-ID override are
+// replaced by the override, which is emitted at its own (possibly moved) time.
+// Occurrences are returned in start order.
+func expandICSEvents(events []*icsEvent, rangeStart, rangeEnd time.Time) []icsOccurrence {
+	overridden := make(map[string]map[int64]bool)
+	for _, ev := range events {
+		if ev.RecurrenceID.IsZero() {
+			continue
+		}
+		if overridden[ev.UID] == nil {
+			overridden[ev.UID] = make(map[int64]bool)
+		}
+		overridden[ev.UID][ev.RecurrenceID.Unix()] = true
+	}
+
+	var out []icsOccurrence
+	emit := func(ev *icsEvent, start, end time.Time, recurring bool) {
+		if end.After(rangeStart) && start.Before(rangeEnd) {
+			out = append(out, icsOccurrence{Event: ev, Start: start.UTC(), End: end.UTC(), Recurring: recurring})
+		}
+	}
+
+	for _, ev := range events {
+		if !ev.RecurrenceID.IsZero() {
+			emit(ev, ev.Start, ev.End, true)
+			continue
+		}
+		if ev.RRule == "" && len(ev.RDates) == 0 {
+			emit(ev, ev.Start, ev.End, false)
+			continue
+		}
+
+		length := ev.End.Sub(ev.Start)
+		for _, start := range ev.instanceStarts(rangeStart.Add(-length), rangeEnd) {
+			if overridden[ev.UID][start.Unix()] {
+				continue
+			}
+			emit(ev, start, start.Add(length), true)
+		}
+	}
+
+	slices.SortStableFunc(out, func(a, b icsOccurrence) int { return a.Start.Compare(b.Start) })
+	return out
+}
+
+// instanceStarts returns the start of every instance of a recurring event that
+// begins before rangeEnd: DTSTART, the RRULE-generated set and any RDATEs,
+// minus EXDATEs. from is a hint that lets unbounded rules skip whole periods
+// that cannot reach the query range.
+func (ev *icsEvent) instanceStarts(from, rangeEnd time.Time) []time.Time {
+	var starts []time.Time
+	if ev.RRule != "" {
+		rule, err := parseRRule(ev.RRule)
+		if err == nil {
+			starts = rule.occurrences(ev.Start, from, rangeEnd)
+		} else {
+			starts = []time.Time{ev.Start}
+		}
+	} else {
+		starts = []time.Time{ev.Start}
+	}
+	starts = append(starts, ev.RDates...)
+
+	excluded := make(map[int64]bool, len(ev.ExDates))
+	for _, ex := range ev.ExDates {
+		excluded[ex.Unix()] = true
+	}
+
+	seen := make(map[int64]bool, len(starts))
+	out := starts[:0]
+	for _, s := range starts {
+		key := s.Unix()
+		if excluded[key] || seen[key] || !s.Before(rangeEnd) {
+			continue
+		}
+		seen[key] = true
+		out = append(out, s)
+	}
+	slices.SortFunc(out, func(a, b time.Time) int { return a.Compare(b) })
+	return out
+}
+
+// icsWeekdayNum is one BYDAY entry, e.g. "MO" (N=0), "2TU" or "-1FR".
+type icsWeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+// icsRecurrenceRule is a parsed RRULE (RFC 5545 §3.3.10). BYWEEKNO,
+// BYYEARDAY and the sub-daily BY* parts are not supported and are ignored.
+type icsRecurrenceRule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      time.Time
+	UntilDate  bool
+	ByDay      []icsWeekdayNum
+	ByMonthDay []int
+	ByMonth    []int
+	BySetPos   []int
+	WeekStart  time.Weekday
+}
+
+var icsWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// parseRRule parses an RRULE value such as
+// "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;UNTIL=20240630T000000Z".
+func parseRRule(value string) (*icsRecurrenceRule, error) {
+	rule := &icsRecurrenceRule{Interval: 1, WeekStart: time.Monday}
+	for _, part := range strings.Split(value, ";") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		v = strings.TrimSpace(v)
+		switch strings.ToUpper(strings.TrimSpace(k)) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(v)
+		case "INTERVAL":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", v)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", v)
+			}
+			rule.Count = n
+		case "UNTIL":
+			t, isDate, ok := parseICSTime(v, nil, nil)
+			if !ok {
+				return nil, fmt.Errorf("invalid UNTIL %q", v)
+			}
+			rule.Until = t
+			rule.UntilDate = isDate
+		case "BYDAY":
+			for _, d := range strings.Split(v, ",") {
+				d = strings.ToUpper(strings.TrimSpace(d))
+				if len(d) < 2 {
+					return nil, fmt.Errorf("invalid BYDAY %q", v)
+				}
+				wd, ok := icsWeekdays[d[len(d)-2:]]
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY %q", v)
+				}
+				n := 0
+				if prefix := d[:len(d)-2]; prefix != "" {
+					var err error
+					if n, err = strconv.Atoi(prefix); err != nil {
+						return nil, fmt.Errorf("invalid BYDAY %q", v)
+					}
+				}
+				rule.ByDay = append(rule.ByDay, icsWeekdayNum{N: n, Day: wd})
+			}
+		case "BYMONTHDAY":
+			ints, err := parseICSIntList(v, 31)
+			if err != nil {
+				return nil, fmt.Errorf("invalid BYMONTHDAY %q", v)
+			}
+			rule.ByMonthDay = ints
+		case "BYMONTH":
+			ints, err := parseICSIntList(v, 12)
+			if err != nil {
+				return nil, fmt.Errorf("invalid BYMONTH %q", v)
+			}
+			rule.ByMonth = ints
+		case "BYSETPOS":
+			ints, err := parseICSIntList(v, 366)
+			if err != nil {
+				return nil, fmt.Errorf("invalid BYSETPOS %q", v)
+			}
+			rule.BySetPos = ints
+		case "WKST":
+			if wd, ok := icsWeekdays[strings.ToUpper(v)]; ok {
+				rule.WeekStart = wd
+			}
+		}
+	}
+
+	switch rule.Freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return nil, fmt.Errorf("unsupported FREQ %q", rule.Freq)
+	}
+	return rule, nil
+}
+
+// parseICSIntList parses a comma-separated list of non-zero integers whose
+// absolute value does not exceed limit.
+func parseICSIntList(v string, limit int) ([]int, error) {
+	var out []int
+	for _, s := range strings.Split(v, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || n == 0 || n > limit || n < -limit {
+			return nil, fmt.Errorf("invalid value %q", s)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+// occurrences returns the instance starts generated by the rule for a series
+// beginning at dtstart, in dtstart's location, up to (excluding) rangeEnd.
+// DTSTART always counts as the first instance. When the rule has no COUNT,
+// periods that end before from are skipped without being generated.
+func (r *icsRecurrenceRule) occurrences(dtstart, from, rangeEnd time.Time) []time.Time {
+	loc := dtstart.Location()
+	hh, mm, ss := dtstart.Clock()
+	y, m, d := dtstart.Date()
+	anchor := time.Date(y, m, d, 0, 0, 0, 0, loc)
+
+	out := []time.Time{dtstart}
+	emitted := 1
+
+	first := 0
+	if r.Count == 0 && from.After(dtstart) {
+		first = r.periodsBefore(anchor, from.In(loc))
+	}
+
+	for k := first; k < first+maxRecurrencePeriods; k++ {
+		days := r.periodDays(anchor, k)
+		if len(days) == 0 {
+			if r.periodStart(anchor, k).After(rangeEnd) {
+				break
+			}
+			continue
+		}
+
+		candidates := make([]time.Time, 0, len(days))
+		for _, day := range days {
+			dy, dm, dd := day.Date()
+			candidates = append(candidates, time.Date(dy, dm, dd, hh, mm, ss, 0, loc))
+		}
+		slices.SortFunc(candidates, func(a, b time.Time) int { return a.Compare(b) })
+		candidates = applySetPos(candidates, r.BySetPos)
+
+		for _, c := range candidates {
+			if !c.After(dtstart) {
+				continue
+			}
+			if r.pastUntil(c) || !c.Before(rangeEnd) {
+				return out
+			}
+			out = append(out, c)
+			emitted++
+			if r.Count > 0 && emitted >= r.Count {
+				return out
+			}
+		}
+	}
+	return out
+}
+
+// pastUntil reports whether t lies after the rule's inclusive UNTIL bound.
+func (r *icsRecurrenceRule) pastUntil(t time.Time) bool {
+	if r.Until.IsZero() {
+		return false
+	}
+	if r.UntilDate {
+		y, m, d := r.Until.Date()
+		return !t.Before(time.Date(y, m, d+1, 0, 0, 0, 0, t.Location()))
+	}
+	return t.After(r.Until)
+}
+
+// periodStart returns the first day of the k-th FREQ period after anchor.
+func (r *icsRecurrenceRule) periodStart(anchor time.Time, k int) time.Time {
+	step := k * r.Interval
+	switch r.Freq {
+	case "DAILY":
+		return anchor.AddDate(0, 0, step)
+	case "WEEKLY":
+		offset := (int(anchor.Weekday()) - int(r.WeekStart) + 7) % 7
+		return anchor.AddDate(0, 0, 7*step-offset)
+	case "MONTHLY":
+		return time.Date(anchor.Year(), anchor.Month()+time.Month(step), 1, 0, 0, 0, 0, anchor.Location())
+	default:
+		return time.Date(anchor.Year()+step, time.January, 1, 0, 0, 0, 0, anchor.Location())
+	}
+}
+
+// periodsBefore returns how many whole periods can be skipped so that the
+// first generated period still starts no later than from.
+func (r *icsRecurrenceRule) periodsBefore(anchor, from time.Time) int {
+	var units int
+	switch r.Freq {
+	case "DAILY":
+		units = int(from.Sub(anchor).Hours() / 24)
+	case "WEEKLY":
+		units = int(from.Sub(anchor).Hours() / (24 * 7))
+	case "MONTHLY":
+		units = (from.Year()-anchor.Year())*12 + int(from.Month()-anchor.Month())
+	default:
+		units = from.Year() - anchor.Year()
+	}
+	// Step back one period to stay clear of DST and month-length rounding.
+	k := units/r.Interval - 1
+	if k < 0 {
+		return 0
+	}
+	return k
+}
+
+// periodDays returns the candidate days (local midnight) of the k-th period,
+// before BYSETPOS is applied.
+func (r *icsRecurrenceRule) periodDays(anchor time.Time, k int) []time.Time {
+	start := r.periodStart(anchor, k)
+	loc := anchor.Location()
+
+	switch r.Freq {
+	case "DAILY":
+		if r.matchesDay(start) {
+			return []time.Time{start}
+		}
+		return nil
+
+	case "WEEKLY":
+		var out []time.Time
+		for i := 0; i < 7; i++ {
+			day := start.AddDate(0, 0, i)
+			if len(r.ByDay) == 0 {
+				if day.Weekday() != anchor.Weekday() {
+					continue
+				}
+			} else if !r.hasWeekday(day.Weekday()) {
+				continue
+			}
+			if len(r.ByMonth) > 0 && !slices.Contains(r.ByMonth, int(day.Month())) {
+				continue
+			}
+			out = append(out, day)
+		}
+		return out
+
+	case "MONTHLY":
+		if len(r.ByMonth) > 0 && !slices.Contains(r.ByMonth, int(start.Month())) {
+			return nil
+		}
+		return r.monthDays(start.Year(), start.Month(), anchor.Day(), loc)
+
+	default: // YEARLY
+		year := start.Year()
+		if len(r.ByMonth) == 0 && len(r.ByDay) > 0 && len(r.ByMonthDay) == 0 {
+			return r.yearWeekdays(year, loc)
+		}
+		months := r.ByMonth
+		if len(months) == 0 {
+			if len(r.ByMonthDay) > 0 {
+				months = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+			} else {
+				months = []int{int(anchor.Month())}
+			}
+		}
+		var out []time.Time
+		for _, mo := range months {
+			out = append(out, r.monthDays(year, time.Month(mo), anchor.Day(), loc)...)
+		}
+		return out
+	}
+}
+
+// matchesDay applies BYDAY/BYMONTHDAY/BYMONTH as filters (used for DAILY).
+func (r *icsRecurrenceRule) matchesDay(day time.Time) bool {
+	if len(r.ByMonth) > 0 && !slices.Contains(r.ByMonth, int(day.Month())) {
+		return false
+	}
+	if len(r.ByDay) > 0 && !r.hasWeekday(day.Weekday()) {
+		return false
+	}
+	if len(r.ByMonthDay) > 0 {
+		last := daysIn(day.Year(), day.Month())
+		if !slices.ContainsFunc(r.ByMonthDay, func(md int) bool {
+			return resolveMonthDay(md, last) == day.Day()
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *icsRecurrenceRule) hasWeekday(wd time.Weekday) bool {
+	return slices.ContainsFunc(r.ByDay, func(b icsWeekdayNum) bool { return b.Day == wd })
+}
+
+// monthDays expands BYMONTHDAY and BYDAY within one month. When both are
+// present the result is their intersection; when neither is, the series'
+// own day-of-month is used (and months lacking that day are skipped).
+func (r *icsRecurrenceRule) monthDays(year int, month time.Month, defaultDay int, loc *time.Location) []time.Time {
+	last := daysIn(year, month)
+
+	var byMonthDay map[int]bool
+	if len(r.ByMonthDay) > 0 {
+		byMonthDay = make(map[int]bool, len(r.ByMonthDay))
+		for _, md := range r.ByMonthDay {
+			if d := resolveMonthDay(md, last); d > 0 {
+				byMonthDay[d] = true
+			}
+		}
+	}
+
+	var days []int
+	switch {
+	case len(r.ByDay) > 0:
+		for d := 1; d <= last; d++ {
+			if byMonthDay != nil && !byMonthDay[d] {
+				continue
+			}
+			wd := time.Date(year, month, d, 0, 0, 0, 0, loc).Weekday()
+			for _, b := range r.ByDay {
+				if b.Day != wd {
+					continue
+				}
+				if b.N == 0 || nthWeekdayOfSpan(d, last, b.N) {
+					days = append(days, d)
+					break
+				}
+			}
+		}
+	case byMonthDay != nil:
+		for d := 1; d <= last; d++ {
+			if byMonthDay[d] {
+				days = append(days, d)
+			}
+		}
+	default:
+		if defaultDay <= last {
+			days = append(days, defaultDay)
+		}
+	}
+
+	out := make([]time.Time, 0, len(days))
+	for _, d := range days {
+		out = append(out, time.Date(year, month, d, 0, 0, 0, 0, loc))
+	}
+	return out
+}
+
+// yearWeekdays expands BYDAY across a whole year (YEARLY without BYMONTH);
+// ordinals such as "20MO" count from the start or end of the year.
+func (r *icsRecurrenceRule) yearWeekdays(year int, loc *time.Location) []time.Time {
+	first := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	total := time.Date(year, time.December, 31, 0, 0, 0, 0, loc).YearDay()
+
+	var out []time.Time
+	for i := 0; i < total; i++ {
+		day := first.AddDate(0, 0, i)
+		for _, b := range r.ByDay {
+			if b.Day != day.Weekday() {
+				continue
+			}
+			if b.N == 0 || nthWeekdayOfSpan(i+1, total, b.N) {
+				out = append(out, day)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// nthWeekdayOfSpan reports whether position pos (1-based) within a span of
+// length total is the n-th occurrence of its weekday, counting from the end
+// when n is negative.
+func nthWeekdayOfSpan(pos, total, n int) bool {
+	if n > 0 {
+		return (pos-1)/7+1 == n
+	}
+	return (total-pos)/7+1 == -n
+}
+
+// resolveMonthDay maps a BYMONTHDAY value (negative counts from month end) to
+// a day of the month, or 0 if the month is too short.
+func resolveMonthDay(md, last int) int {
+	if md < 0 {
+		md = last + md + 1
+	}
+	if md < 1 || md > last {
+		return 0
+	}
+	return md
+}
+
+// daysIn returns the number of days in the given month.
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// applySetPos keeps only the BYSETPOS-selected entries of a sorted period set.
+func applySetPos(candidates []time.Time, setPos []int) []time.Time {
+	if len(setPos) == 0 || len(candidates) == 0 {
+		return candidates
+	}
+	var out []time.Time
+	for _, p := range setPos {
+		idx := p - 1
+		if p < 0 {
+			idx = len(candidates) + p
+		}
+		if idx >= 0 && idx < len(candidates) {
+			out = append(out, candidates[idx])
+		}
+	}
+	slices.SortFunc(out, func(a, b time.Time) int { return a.Compare(b) })
+	return slices.CompactFunc(out, func(a, b time.Time) bool { return a.Equal(b) })
+}
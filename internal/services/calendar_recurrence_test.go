@@ -0,0 +1,228 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// icsCalendar wraps VEVENT bodies in a minimal VCALENDAR with CRLF endings.
+func icsCalendar(events ...string) string {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\nVERSION:2.0\r\n")
+	for _, ev := range events {
+		b.WriteString("BEGIN:VEVENT\r\n")
+		b.WriteString(strings.ReplaceAll(strings.TrimSpace(ev), "\n", "\r\n"))
+		b.WriteString("\r\nEND:VEVENT\r\n")
+	}
+	b.WriteString("END:VCALENDAR\r\n")
+	return b.String()
+}
+
+func mustUTC(t *testing.T, s string) time.Time {
+	t.Helper()
+	ts, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatalf("parse %q: %v", s, err)
+	}
+	return ts
+}
+
+func slotStarts(t *testing.T, icsData, from, to string) []string {
+	t.Helper()
+	var out []string
+	for _, s := range parseVEvents(icsData, defaultBusyPolicy(), mustUTC(t, from), mustUTC(t, to)) {
+		out = append(out, s.Start.UTC().Format(time.RFC3339))
+	}
+	return out
+}
+
+func assertStarts(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("starts mismatch\n got: %v\nwant: %v", got, want)
+	}
+}
+
+// TestParseVEvents_WeeklyByDayExpandsInsideRange checks that a series created
+// long before the query window still yields instances inside it.
+func TestParseVEvents_WeeklyByDayExpandsInsideRange(t *testing.T) {
+	ics := icsCalendar(`
+UID:standup
+SUMMARY:Standup
+DTSTART:20230102T090000Z
+DTEND:20230102T091500Z
+RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR`)
+
+	got := slotStarts(t, ics, "2024-03-04T00:00:00Z", "2024-03-09T00:00:00Z")
+	assertStarts(t, got,
+		"2024-03-04T09:00:00Z",
+		"2024-03-06T09:00:00Z",
+		"2024-03-08T09:00:00Z",
+	)
+}
+
+// TestParseVEvents_CountAndUntilBoundTheSeries covers both RRULE terminators.
+func TestParseVEvents_CountAndUntilBoundTheSeries(t *testing.T) {
+	countICS := icsCalendar(`
+UID:count
+DTSTART:20240101T100000Z
+DTEND:20240101T110000Z
+RRULE:FREQ=DAILY;COUNT=3`)
+	assertStarts(t, slotStarts(t, countICS, "2024-01-01T00:00:00Z", "2024-02-01T00:00:00Z"),
+		"2024-01-01T10:00:00Z",
+		"2024-01-02T10:00:00Z",
+		"2024-01-03T10:00:00Z",
+	)
+
+	untilICS := icsCalendar(`
+UID:until
+DTSTART:20240101T100000Z
+DTEND:20240101T110000Z
+RRULE:FREQ=WEEKLY;INTERVAL=2;UNTIL=20240129T100000Z`)
+	assertStarts(t, slotStarts(t, untilICS, "2024-01-01T00:00:00Z", "2024-03-01T00:00:00Z"),
+		"2024-01-01T10:00:00Z",
+		"2024-01-15T10:00:00Z",
+		"2024-01-29T10:00:00Z",
+	)
+}
+
+// TestParseVEvents_ExDateAndRDate checks that EXDATE removes an instance and
+// RDATE adds one outside the rule.
+func TestParseVEvents_ExDateAndRDate(t *testing.T) {
+	ics := icsCalendar(`
+UID:gym
+DTSTART:20240108T180000Z
+DTEND:20240108T190000Z
+RRULE:FREQ=WEEKLY
+EXDATE:20240115T180000Z
+RDATE:20240117T180000Z`)
+
+	got := slotStarts(t, ics, "2024-01-08T00:00:00Z", "2024-01-23T00:00:00Z")
+	assertStarts(t, got,
+		"2024-01-08T18:00:00Z",
+		"2024-01-17T18:00:00Z",
+		"2024-01-22T18:00:00Z",
+	)
+}
+
+// TestParseVEvents_RecurrenceIDOverrideReplacesInstance verifies that a moved
+// instance is reported at its new time and not also at the original one.
+func TestParseVEvents_RecurrenceIDOverrideReplacesInstance(t *testing.T) {
+	ics := icsCalendar(`
+UID:sync
+DTSTART:20240101T150000Z
+DTEND:20240101T153000Z
+RRULE:FREQ=DAILY;COUNT=3`, `
+UID:sync
+RECURRENCE-ID:20240102T150000Z
+DTSTART:20240102T170000Z
+DTEND:20240102T173000Z`)
+
+	got := slotStarts(t, ics, "2024-01-01T00:00:00Z", "2024-01-05T00:00:00Z")
+	assertStarts(t, got,
+		"2024-01-01T15:00:00Z",
+		"2024-01-02T17:00:00Z",
+		"2024-01-03T15:00:00Z",
+	)
+}
+
+// TestParseVEvents_MonthlyLastWeekdayAndLastDay covers the negative forms:
+// BYDAY with BYSETPOS=-1 and BYMONTHDAY=-1.
+func TestParseVEvents_MonthlyLastWeekdayAndLastDay(t *testing.T) {
+	lastWeekday := icsCalendar(`
+UID:review
+DTSTART:20240131T140000Z
+DTEND:20240131T150000Z
+RRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1`)
+	assertStarts(t, slotStarts(t, lastWeekday, "2024-01-01T00:00:00Z", "2024-04-01T00:00:00Z"),
+		"2024-01-31T14:00:00Z",
+		"2024-02-29T14:00:00Z",
+		"2024-03-29T14:00:00Z",
+	)
+
+	lastDay := icsCalendar(`
+UID:invoice
+DTSTART:20240131T080000Z
+DTEND:20240131T083000Z
+RRULE:FREQ=MONTHLY;BYMONTHDAY=-1`)
+	assertStarts(t, slotStarts(t, lastDay, "2024-01-01T00:00:00Z", "2024-05-01T00:00:00Z"),
+		"2024-01-31T08:00:00Z",
+		"2024-02-29T08:00:00Z",
+		"2024-03-31T08:00:00Z",
+		"2024-04-30T08:00:00Z",
+	)
+}
+
+// TestParseVEvents_SecondTuesdayOfMonth covers ordinal BYDAY entries.
+func TestParseVEvents_SecondTuesdayOfMonth(t *testing.T) {
+	ics := icsCalendar(`
+UID:board
+DTSTART:20240109T160000Z
+DTEND:20240109T170000Z
+RRULE:FREQ=MONTHLY;BYDAY=2TU;COUNT=3`)
+
+	assertStarts(t, slotStarts(t, ics, "2024-01-01T00:00:00Z", "2024-12-31T00:00:00Z"),
+		"2024-01-09T16:00:00Z",
+		"2024-02-13T16:00:00Z",
+		"2024-03-12T16:00:00Z",
+	)
+}
+
+// TestParseVEvents_TZIDSeriesKeepsWallClockAcrossDST ensures a 09:00 New York
+// meeting stays at 09:00 local when DST starts (14:00Z → 13:00Z).
+func TestParseVEvents_TZIDSeriesKeepsWallClockAcrossDST(t *testing.T) {
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		t.Skip("tzdata not available")
+	}
+	ics := icsCalendar(`
+UID:ny
+DTSTART;TZID=America/New_York:20240308T090000
+DTEND;TZID=America/New_York:20240308T093000
+RRULE:FREQ=DAILY;COUNT=4`)
+
+	assertStarts(t, slotStarts(t, ics, "2024-03-01T00:00:00Z", "2024-03-31T00:00:00Z"),
+		"2024-03-08T14:00:00Z",
+		"2024-03-09T14:00:00Z",
+		"2024-03-10T13:00:00Z",
+		"2024-03-11T13:00:00Z",
+	)
+}
+
+// TestParseVEventsForAgenda_RecurringInstancesHaveDistinctIDs checks that the
+// agenda gets one entry per instance, each with a unique ID, and that all-day
+// series stay all-day.
+func TestParseVEventsForAgenda_RecurringInstancesHaveDistinctIDs(t *testing.T) {
+	ics := icsCalendar(`
+UID:one-off
+SUMMARY:Lunch
+DTSTART:20240102T120000Z
+DTEND:20240102T130000Z`, `
+UID:daily
+SUMMARY:Focus
+DTSTART;VALUE=DATE:20240101
+DTEND;VALUE=DATE:20240102
+RRULE:FREQ=DAILY;COUNT=3`)
+
+	events := parseVEventsForAgenda(ics, "Work", "cal-1", "#378ADD", nil,
+		mustUTC(t, "2024-01-01T00:00:00Z"), mustUTC(t, "2024-01-05T00:00:00Z"))
+	if len(events) != 4 {
+		t.Fatalf("expected 4 agenda events, got %d", len(events))
+	}
+
+	ids := map[string]bool{}
+	for _, ev := range events {
+		if ids[ev.ID] {
+			t.Errorf("duplicate agenda ID %q", ev.ID)
+		}
+		ids[ev.ID] = true
+		if ev.Title == "Focus" && !ev.IsAllDay {
+			t.Errorf("recurring all-day instance %q lost IsAllDay", ev.ID)
+		}
+	}
+	for _, want := range []string{"one-off", "daily_20240101", "daily_20240102", "daily_20240103"} {
+		if !ids[want] {
+			t.Errorf("missing agenda ID %q (got %v)", want, ids)
+		}
+	}
+}
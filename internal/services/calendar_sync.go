@@ -14,6 +14,7 @@ import (
 // CalendarSyncService handles background calendar synchronization
 type CalendarSyncService struct {
 	calendar     *CalendarService
+	reconciler   *CalendarReconciler
 	email        *EmailService
 	repos        *repository.Repositories
 	interval     time.Duration
@@ -25,9 +26,10 @@ type CalendarSyncService struct {
 }
 
 // NewCalendarSyncService creates a new calendar sync service
-func NewCalendarSyncService(calendar *CalendarService, email *EmailService, repos *repository.Repositories) *CalendarSyncService {
+func NewCalendarSyncService(calendar *CalendarService, reconciler *CalendarReconciler, email *EmailService, repos *repository.Repositories) *CalendarSyncService {
 	return &CalendarSyncService{
 		calendar:     calendar,
+		reconciler:   reconciler,
 		email:        email,
 		repos:        repos,
 		interval:     15 * time.Minute, // Busy-times sync every 15 minutes
@@ -106,6 +108,16 @@ func (s *CalendarSyncService) syncAllCalendars() {
 			log.Printf("[CALENDAR_SYNC] Failed to sync connection %s (%s): %v", conn.ID, conn.Name, err)
 		} else {
 			successCount++
+			// Pick up bookings the host moved or deleted in their calendar.
+			s.reconciler.ReconcileConnection(ctx, conn)
+		}
+
+		// Register missing push channels and renew expiring ones. Polling
+		// carries on regardless, so a failure here only costs latency.
+		if conn.Provider == models.CalendarProviderGoogle {
+			if err := s.calendar.EnsureGoogleWatchChannels(ctx, conn); err != nil {
+				log.Printf("[CALENDAR_SYNC] watch channels incomplete for connection %s: %v", conn.ID, err)
+			}
 		}
 	}
 
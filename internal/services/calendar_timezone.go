@@ -0,0 +1,504 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// vtimezoneHorizon is the last instant transitions are generated for when a
+// VTIMEZONE is compiled into a *time.Location. TZif v1 stores 32-bit times, so
+// nothing past January 2038 could be represented anyway.
+var vtimezoneHorizon = time.Date(2037, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// windowsZoneToIANA maps the Windows time zone names Exchange and Outlook put
+// in TZID parameters to their IANA equivalents (CLDR windowsZones, territory
+// "001").
+var windowsZoneToIANA = map[string]string{
+	"Dateline Standard Time":          "Etc/GMT+12",
+	"UTC-11":                          "Etc/GMT+11",
+	"Aleutian Standard Time":          "America/Adak",
+	"Hawaiian Standard Time":          "Pacific/Honolulu",
+	"Marquesas Standard Time":         "Pacific/Marquesas",
+	"Alaskan Standard Time":           "America/Anchorage",
+	"UTC-09":                          "Etc/GMT+9",
+	"Pacific Standard Time (Mexico)":  "America/Tijuana",
+	"UTC-08":                          "Etc/GMT+8",
+	"Pacific Standard Time":           "America/Los_Angeles",
+	"US Mountain Standard Time":       "America/Phoenix",
+	"Mountain Standard Time (Mexico)": "America/Mazatlan",
+	"Mountain Standard Time":          "America/Denver",
+	"Yukon Standard Time":             "America/Whitehorse",
+	"Central America Standard Time":   "America/Guatemala",
+	"Central Standard Time":           "America/Chicago",
+	"Easter Island Standard Time":     "Pacific/Easter",
+	"Central Standard Time (Mexico)":  "America/Mexico_City",
+	"Canada Central Standard Time":    "America/Regina",
+	"SA Pacific Standard Time":        "America/Bogota",
+	"Eastern Standard Time (Mexico)":  "America/Cancun",
+	"Eastern Standard Time":           "America/New_York",
+	"Haiti Standard Time":             "America/Port-au-Prince",
+	"Cuba Standard Time":              "America/Havana",
+	"US Eastern Standard Time":        "America/Indiana/Indianapolis",
+	"Turks And Caicos Standard Time":  "America/Grand_Turk",
+	"Paraguay Standard Time":          "America/Asuncion",
+	"Atlantic Standard Time":          "America/Halifax",
+	"Venezuela Standard Time":         "America/Caracas",
+	"Central Brazilian Standard Time": "America/Cuiaba",
+	"SA Western Standard Time":        "America/La_Paz",
+	"Pacific SA Standard Time":        "America/Santiago",
+	"Newfoundland Standard Time":      "America/St_Johns",
+	"Tocantins Standard Time":         "America/Araguaina",
+	"E. South America Standard Time":  "America/Sao_Paulo",
+	"SA Eastern Standard Time":        "America/Cayenne",
+	"Argentina Standard Time":         "America/Argentina/Buenos_Aires",
+	"Greenland Standard Time":         "America/Nuuk",
+	"Montevideo Standard Time":        "America/Montevideo",
+	"Magallanes Standard Time":        "America/Punta_Arenas",
+	"Saint Pierre Standard Time":      "America/Miquelon",
+	"Bahia Standard Time":             "America/Bahia",
+	"UTC-02":                          "Etc/GMT+2",
+	"Mid-Atlantic Standard Time":      "Etc/GMT+2",
+	"Azores Standard Time":            "Atlantic/Azores",
+	"Cape Verde Standard Time":        "Atlantic/Cape_Verde",
+	"UTC":                             "Etc/UTC",
+	"GMT Standard Time":               "Europe/London",
+	"Greenwich Standard Time":         "Atlantic/Reykjavik",
+	"Sao Tome Standard Time":          "Africa/Sao_Tome",
+	"Morocco Standard Time":           "Africa/Casablanca",
+	"W. Europe Standard Time":         "Europe/Berlin",
+	"Central Europe Standard Time":    "Europe/Budapest",
+	"Romance Standard Time":           "Europe/Paris",
+	"Central European Standard Time":  "Europe/Warsaw",
+	"W. Central Africa Standard Time": "Africa/Lagos",
+	"Jordan Standard Time":            "Asia/Amman",
+	"GTB Standard Time":               "Europe/Bucharest",
+	"Middle East Standard Time":       "Asia/Beirut",
+	"Egypt Standard Time":             "Africa/Cairo",
+	"E. Europe Standard Time":         "Europe/Chisinau",
+	"Syria Standard Time":             "Asia/Damascus",
+	"West Bank Standard Time":         "Asia/Hebron",
+	"South Africa Standard Time":      "Africa/Johannesburg",
+	"FLE Standard Time":               "Europe/Kiev",
+	"Israel Standard Time":            "Asia/Jerusalem",
+	"South Sudan Standard Time":       "Africa/Juba",
+	"Kaliningrad Standard Time":       "Europe/Kaliningrad",
+	"Sudan Standard Time":             "Africa/Khartoum",
+	"Libya Standard Time":             "Africa/Tripoli",
+	"Namibia Standard Time":           "Africa/Windhoek",
+	"Arabic Standard Time":            "Asia/Baghdad",
+	"Turkey Standard Time":            "Europe/Istanbul",
+	"Arab Standard Time":              "Asia/Riyadh",
+	"Belarus Standard Time":           "Europe/Minsk",
+	"Russian Standard Time":           "Europe/Moscow",
+	"E. Africa Standard Time":         "Africa/Nairobi",
+	"Volgograd Standard Time":         "Europe/Volgograd",
+	"Iran Standard Time":              "Asia/Tehran",
+	"Arabian Standard Time":           "Asia/Dubai",
+	"Astrakhan Standard Time":         "Europe/Astrakhan",
+	"Azerbaijan Standard Time":        "Asia/Baku",
+	"Russia Time Zone 3":              "Europe/Samara",
+	"Mauritius Standard Time":         "Indian/Mauritius",
+	"Saratov Standard Time":           "Europe/Saratov",
+	"Georgian Standard Time":          "Asia/Tbilisi",
+	"Caucasus Standard Time":          "Asia/Yerevan",
+	"Afghanistan Standard Time":       "Asia/Kabul",
+	"West Asia Standard Time":         "Asia/Tashkent",
+	"Ekaterinburg Standard Time":      "Asia/Yekaterinburg",
+	"Pakistan Standard Time":          "Asia/Karachi",
+	"Qyzylorda Standard Time":         "Asia/Qyzylorda",
+	"India Standard Time":             "Asia/Kolkata",
+	"Sri Lanka Standard Time":         "Asia/Colombo",
+	"Nepal Standard Time":             "Asia/Kathmandu",
+	"Central Asia Standard Time":      "Asia/Almaty",
+	"Bangladesh Standard Time":        "Asia/Dhaka",
+	"Omsk Standard Time":              "Asia/Omsk",
+	"Myanmar Standard Time":           "Asia/Yangon",
+	"SE Asia Standard Time":           "Asia/Bangkok",
+	"Altai Standard Time":             "Asia/Barnaul",
+	"W. Mongolia Standard Time":       "Asia/Hovd",
+	"North Asia Standard Time":        "Asia/Krasnoyarsk",
+	"N. Central Asia Standard Time":   "Asia/Novosibirsk",
+	"Tomsk Standard Time":             "Asia/Tomsk",
+	"China Standard Time":             "Asia/Shanghai",
+	"North Asia East Standard Time":   "Asia/Irkutsk",
+	"Singapore Standard Time":         "Asia/Singapore",
+	"W. Australia Standard Time":      "Australia/Perth",
+	"Taipei Standard Time":            "Asia/Taipei",
+	"Ulaanbaatar Standard Time":       "Asia/Ulaanbaatar",
+	"Aus Central W. Standard Time":    "Australia/Eucla",
+	"Transbaikal Standard Time":       "Asia/Chita",
+	"Tokyo Standard Time":             "Asia/Tokyo",
+	"North Korea Standard Time":       "Asia/Pyongyang",
+	"Korea Standard Time":             "Asia/Seoul",
+	"Yakutsk Standard Time":           "Asia/Yakutsk",
+	"Cen. Australia Standard Time":    "Australia/Adelaide",
+	"AUS Central Standard Time":       "Australia/Darwin",
+	"E. Australia Standard Time":      "Australia/Brisbane",
+	"AUS Eastern Standard Time":       "Australia/Sydney",
+	"West Pacific Standard Time":      "Pacific/Port_Moresby",
+	"Tasmania Standard Time":          "Australia/Hobart",
+	"Vladivostok Standard Time":       "Asia/Vladivostok",
+	"Lord Howe Standard Time":         "Australia/Lord_Howe",
+	"Bougainville Standard Time":      "Pacific/Bougainville",
+	"Russia Time Zone 10":             "Asia/Srednekolymsk",
+	"Magadan Standard Time":           "Asia/Magadan",
+	"Norfolk Standard Time":           "Pacific/Norfolk",
+	"Sakhalin Standard Time":          "Asia/Sakhalin",
+	"Central Pacific Standard Time":   "Pacific/Guadalcanal",
+	"Russia Time Zone 11":             "Asia/Kamchatka",
+	"New Zealand Standard Time":       "Pacific/Auckland",
+	"UTC+12":                          "Etc/GMT-12",
+	"Fiji Standard Time":              "Pacific/Fiji",
+	"Chatham Islands Standard Time":   "Pacific/Chatham",
+	"UTC+13":                          "Etc/GMT-13",
+	"Tonga Standard Time":             "Pacific/Tongatapu",
+	"Samoa Standard Time":             "Pacific/Apia",
+	"Line Islands Standard Time":      "Pacific/Kiritimati",
+}
+
+// tzCache memoizes name lookups and compiled VTIMEZONE blocks. CalDAV servers
+// return one VCALENDAR per event, each repeating the same VTIMEZONE, so
+// without it every event would reload zoneinfo or recompile the block.
+var tzCache = struct {
+	sync.Mutex
+	byName      map[string]*time.Location
+	byVTimezone map[string]*time.Location
+}{
+	byName:      make(map[string]*time.Location),
+	byVTimezone: make(map[string]*time.Location),
+}
+
+// maxCachedVTimezones bounds byVTimezone; distinct blocks are rare in
+// practice, so anything beyond this is simply compiled on every use.
+const maxCachedVTimezones = 256
+
+// icsZones resolves TZID parameters within one VCALENDAR.
+type icsZones struct {
+	defined  map[string]*time.Location // compiled VTIMEZONE components, by TZID
+	floating *time.Location            // for DATE-TIME values with neither Z nor TZID
+}
+
+// location resolves a TZID. Names that identify a zone on their own (IANA,
+// Windows, or paths ending in an IANA name such as
+// "/mozilla.org/20050126_1/Europe/Berlin") win over the VTIMEZONE block,
+// since zoneinfo also knows the zone's history. Unknown TZIDs without a
+// definition fall back to the floating zone.
+func (z *icsZones) location(tzid string) *time.Location {
+	if loc := locationByName(tzid); loc != nil {
+		return loc
+	}
+	if z != nil {
+		if loc, ok := z.defined[tzid]; ok {
+			return loc
+		}
+	}
+	return z.floatingLocation()
+}
+
+func (z *icsZones) floatingLocation() *time.Location {
+	if z == nil || z.floating == nil {
+		return time.UTC
+	}
+	return z.floating
+}
+
+// locationByName resolves a TZID by name alone, or returns nil.
+func locationByName(tzid string) *time.Location {
+	tzid = strings.TrimSpace(tzid)
+	if tzid == "" {
+		return nil
+	}
+
+	tzCache.Lock()
+	loc, ok := tzCache.byName[tzid]
+	tzCache.Unlock()
+	if ok {
+		return loc
+	}
+
+	loc = lookupZoneName(tzid)
+
+	tzCache.Lock()
+	tzCache.byName[tzid] = loc
+	tzCache.Unlock()
+	return loc
+}
+
+func lookupZoneName(tzid string) *time.Location {
+	candidates := []string{tzid}
+	if iana, ok := windowsZoneToIANA[tzid]; ok {
+		candidates = append(candidates, iana)
+	}
+	// Lightning and other clients prefix the IANA name with a vendor path.
+	if parts := strings.Split(strings.Trim(tzid, "/"), "/"); len(parts) > 2 {
+		candidates = append(candidates,
+			strings.Join(parts[len(parts)-2:], "/"),
+			strings.Join(parts[len(parts)-3:], "/"),
+		)
+	}
+
+	for _, name := range candidates {
+		// "Local" would silently resolve to the server's own zone.
+		if name == "" || name == "Local" {
+			continue
+		}
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	return nil
+}
+
+// icsObservance is one STANDARD or DAYLIGHT sub-component of a VTIMEZONE.
+type icsObservance struct {
+	isDST      bool
+	start      time.Time // DTSTART as wall clock (in UTC) in the offset being left
+	offsetFrom int       // seconds east of UTC before the onset
+	offsetTo   int       // seconds east of UTC from the onset
+	name       string
+	rrule      string
+	rdates     []time.Time
+}
+
+// parseVTimezones compiles every VTIMEZONE in the unfolded ICS lines into a
+// *time.Location keyed by TZID. Blocks that cannot be compiled are skipped.
+func parseVTimezones(lines []string) map[string]*time.Location {
+	zones := make(map[string]*time.Location)
+
+	var tzid string
+	var block strings.Builder
+	var observances []icsObservance
+	var cur *icsObservance
+	inTZ := false
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		name, params, value := splitICSProperty(line)
+
+		if name == "BEGIN" && strings.EqualFold(value, "VTIMEZONE") {
+			inTZ = true
+			tzid = ""
+			block.Reset()
+			observances = nil
+			cur = nil
+			continue
+		}
+		if !inTZ {
+			continue
+		}
+		block.WriteString(line)
+		block.WriteByte('\n')
+
+		switch {
+		case name == "END" && strings.EqualFold(value, "VTIMEZONE"):
+			inTZ = false
+			if tzid != "" && len(observances) > 0 {
+				if loc := compileVTimezone(tzid, block.String(), observances); loc != nil {
+					zones[tzid] = loc
+				}
+			}
+		case name == "BEGIN" && (strings.EqualFold(value, "STANDARD") || strings.EqualFold(value, "DAYLIGHT")):
+			cur = &icsObservance{isDST: strings.EqualFold(value, "DAYLIGHT")}
+		case name == "END" && cur != nil:
+			if !cur.start.IsZero() {
+				observances = append(observances, *cur)
+			}
+			cur = nil
+		case name == "TZID" && cur == nil:
+			tzid = value
+		case cur == nil:
+		case name == "DTSTART":
+			if t, _, ok := parseICSTime(value, nil, nil); ok {
+				cur.start = t
+			}
+		case name == "TZOFFSETFROM":
+			cur.offsetFrom, _ = parseUTCOffset(value)
+		case name == "TZOFFSETTO":
+			cur.offsetTo, _ = parseUTCOffset(value)
+		case name == "TZNAME":
+			cur.name = value
+		case name == "RRULE":
+			cur.rrule = value
+		case name == "RDATE":
+			cur.rdates = append(cur.rdates, parseICSTimeList(value, params, nil)...)
+		}
+	}
+
+	return zones
+}
+
+// parseUTCOffset parses a UTC-OFFSET value such as "+0100", "-0500" or
+// "+053000" into seconds east of UTC.
+func parseUTCOffset(value string) (int, bool) {
+	value = strings.TrimSpace(value)
+	if len(value) != 5 && len(value) != 7 {
+		return 0, false
+	}
+	sign := 1
+	switch value[0] {
+	case '+':
+	case '-':
+		sign = -1
+	default:
+		return 0, false
+	}
+	h, err1 := strconv.Atoi(value[1:3])
+	m, err2 := strconv.Atoi(value[3:5])
+	if err1 != nil || err2 != nil {
+		return 0, false
+	}
+	secs := h*3600 + m*60
+	if len(value) == 7 {
+		s, err := strconv.Atoi(value[5:7])
+		if err != nil {
+			return 0, false
+		}
+		secs += s
+	}
+	return sign * secs, true
+}
+
+// compileVTimezone turns a VTIMEZONE's observances into a *time.Location by
+// expanding every onset up to vtimezoneHorizon and encoding the result as
+// TZif data, so the rest of the parser can treat it like any other zone.
+func compileVTimezone(tzid, block string, observances []icsObservance) *time.Location {
+	tzCache.Lock()
+	loc, ok := tzCache.byVTimezone[block]
+	tzCache.Unlock()
+	if ok {
+		return loc
+	}
+
+	type transition struct {
+		at  int64
+		obs int
+	}
+	var transitions []transition
+	for i, o := range observances {
+		onsets := []time.Time{o.start}
+		if o.rrule != "" {
+			if rule, err := parseRRule(o.rrule); err == nil {
+				onsets = rule.occurrences(o.start, o.start, vtimezoneHorizon)
+			}
+		}
+		onsets = append(onsets, o.rdates...)
+		for _, wall := range onsets {
+			transitions = append(transitions, transition{at: wall.Unix() - int64(o.offsetFrom), obs: i})
+		}
+	}
+	slices.SortFunc(transitions, func(a, b transition) int {
+		switch {
+		case a.at < b.at:
+			return -1
+		case a.at > b.at:
+			return 1
+		}
+		return 0
+	})
+
+	// The zone in effect before the first onset is the one that onset leaves.
+	first := observances[transitions[0].obs]
+	initial := icsObservance{offsetTo: first.offsetFrom, name: first.name}
+	for _, o := range observances {
+		if o.offsetTo == first.offsetFrom {
+			initial = o
+			break
+		}
+	}
+
+	at := make([]int64, len(transitions))
+	obs := make([]int, len(transitions))
+	for i, tr := range transitions {
+		at[i], obs[i] = tr.at, tr.obs
+	}
+	data, err := encodeTZif(initial, observances, at, obs)
+	if err == nil {
+		loc, err = time.LoadLocationFromTZData(tzid, data)
+	}
+	if err != nil {
+		loc = nil
+	}
+
+	tzCache.Lock()
+	if len(tzCache.byVTimezone) < maxCachedVTimezones {
+		tzCache.byVTimezone[block] = loc
+	}
+	tzCache.Unlock()
+	return loc
+}
+
+// encodeTZif writes a minimal version-1 TZif file (RFC 8536). Local time type
+// 0 is the initial zone; each observance gets its own type. Transitions
+// outside the 32-bit range are dropped. at and obs are parallel slices of
+// onset instants and the index of the observance starting there.
+func encodeTZif(initial icsObservance, observances []icsObservance, at []int64, obs []int) ([]byte, error) {
+	type zoneType struct {
+		offset int32
+		isDST  byte
+		abbr   byte
+	}
+
+	var abbrevs bytes.Buffer
+	abbrIndex := make(map[string]byte)
+	addAbbr := func(name string) byte {
+		if name == "" {
+			name = "LMT"
+		}
+		if idx, ok := abbrIndex[name]; ok {
+			return idx
+		}
+		idx := byte(abbrevs.Len())
+		abbrevs.WriteString(name)
+		abbrevs.WriteByte(0)
+		abbrIndex[name] = idx
+		return idx
+	}
+	isDST := func(o icsObservance) byte {
+		if o.isDST {
+			return 1
+		}
+		return 0
+	}
+
+	types := []zoneType{{offset: int32(initial.offsetTo), isDST: isDST(initial), abbr: addAbbr(initial.name)}}
+	for _, o := range observances {
+		types = append(types, zoneType{offset: int32(o.offsetTo), isDST: isDST(o), abbr: addAbbr(o.name)})
+	}
+	if len(types) > 255 || abbrevs.Len() > 255 {
+		return nil, fmt.Errorf("VTIMEZONE too large to encode")
+	}
+
+	var times []int32
+	var idx []byte
+	for i, t := range at {
+		if t < -1<<31 || t > 1<<31-1 {
+			continue
+		}
+		times = append(times, int32(t))
+		idx = append(idx, byte(obs[i]+1))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("TZif")
+	buf.Write(make([]byte, 16)) // version 1 + reserved
+	for _, n := range []int{0, 0, 0, len(times), len(types), abbrevs.Len()} {
+		_ = binary.Write(&buf, binary.BigEndian, uint32(n))
+	}
+	for _, t := range times {
+		_ = binary.Write(&buf, binary.BigEndian, t)
+	}
+	buf.Write(idx)
+	for _, zt := range types {
+		_ = binary.Write(&buf, binary.BigEndian, zt.offset)
+		buf.WriteByte(zt.isDST)
+		buf.WriteByte(zt.abbr)
+	}
+	buf.Write(abbrevs.Bytes())
+	return buf.Bytes(), nil
+}
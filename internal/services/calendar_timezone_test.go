@@ -0,0 +1,122 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readICSFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "ics", name))
+	if err != nil {
+		t.Fatalf("read fixture %s: %v", name, err)
+	}
+	return string(data)
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("tzdata for %s not available: %v", name, err)
+	}
+	return loc
+}
+
+// occurrencesByUID expands a fixture over 2024 and returns the UTC starts of
+// each event's occurrences.
+func occurrencesByUID(t *testing.T, icsData string, floating *time.Location) map[string][]string {
+	t.Helper()
+	out := make(map[string][]string)
+	events := parseICSEvents(icsData, floating)
+	for _, occ := range expandICSEvents(events, mustUTC(t, "2024-01-01T00:00:00Z"), mustUTC(t, "2025-01-01T00:00:00Z")) {
+		out[occ.Event.UID] = append(out[occ.Event.UID], occ.Start.Format(time.RFC3339))
+	}
+	return out
+}
+
+// TestParseICS_ExchangeWindowsTZID checks that Exchange's Windows zone names
+// resolve to the right IANA zone in both summer and winter.
+func TestParseICS_ExchangeWindowsTZID(t *testing.T) {
+	mustLoadLocation(t, "Europe/Berlin")
+	got := occurrencesByUID(t, readICSFixture(t, "exchange_windows_tzid.ics"), nil)
+
+	assertStarts(t, got["040000008200E00074C5B7101A82E008"], "2024-07-10T08:00:00Z")
+	assertStarts(t, got["040000008200E00074C5B7101A82E009"], "2024-01-15T09:00:00Z")
+}
+
+// TestParseICS_CustomVTimezoneDrivesDST uses an invented TZID whose only
+// definition is its VTIMEZONE block. A weekly series straddling the March
+// DST change must keep its 09:00 wall-clock time.
+func TestParseICS_CustomVTimezoneDrivesDST(t *testing.T) {
+	got := occurrencesByUID(t, readICSFixture(t, "custom_vtimezone.ics"), nil)
+
+	assertStarts(t, got["weekly-sync@example.com"],
+		"2024-03-04T14:00:00Z",
+		"2024-03-11T13:00:00Z",
+		"2024-03-18T13:00:00Z",
+	)
+}
+
+// TestParseICS_FloatingAndNamedZones covers floating times (host timezone),
+// vendor-prefixed IANA names, and TZIDs with no definition at all, which
+// fall back to the host timezone as well.
+func TestParseICS_FloatingAndNamedZones(t *testing.T) {
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+	mustLoadLocation(t, "America/New_York")
+	got := occurrencesByUID(t, readICSFixture(t, "floating_and_named.ics"), berlin)
+
+	assertStarts(t, got["floating-1"], "2024-01-10T08:00:00Z")
+	assertStarts(t, got["mozilla-path-1"], "2024-01-10T14:00:00Z")
+	assertStarts(t, got["unknown-tzid-1"], "2024-01-10T13:00:00Z")
+
+	// Without a host timezone, floating times are read as UTC.
+	got = occurrencesByUID(t, readICSFixture(t, "floating_and_named.ics"), nil)
+	assertStarts(t, got["floating-1"], "2024-01-10T09:00:00Z")
+}
+
+// TestParseVTimezones_MatchesZoneinfo compiles the Exchange VTIMEZONE block
+// on its own and compares its offsets against Europe/Berlin around both 2024
+// transitions.
+func TestParseVTimezones_MatchesZoneinfo(t *testing.T) {
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+	zones := parseVTimezones(unfoldICSLines(readICSFixture(t, "exchange_windows_tzid.ics")))
+	loc := zones["W. Europe Standard Time"]
+	if loc == nil {
+		t.Fatal("VTIMEZONE was not compiled")
+	}
+
+	for _, instant := range []string{
+		"2024-03-31T00:59:59Z", "2024-03-31T01:00:00Z",
+		"2024-10-27T00:59:59Z", "2024-10-27T01:00:00Z",
+		"2024-07-01T12:00:00Z", "2024-12-01T12:00:00Z",
+	} {
+		ts := mustUTC(t, instant)
+		_, want := ts.In(berlin).Zone()
+		_, got := ts.In(loc).Zone()
+		if got != want {
+			t.Errorf("offset at %s = %d, want %d", instant, got, want)
+		}
+	}
+}
+
+// TestParseUTCOffset covers the UTC-OFFSET value forms used by VTIMEZONE.
+func TestParseUTCOffset(t *testing.T) {
+	cases := map[string]int{
+		"+0100":   3600,
+		"-0500":   -5 * 3600,
+		"+0530":   5*3600 + 30*60,
+		"+053045": 5*3600 + 30*60 + 45,
+	}
+	for in, want := range cases {
+		got, ok := parseUTCOffset(in)
+		if !ok || got != want {
+			t.Errorf("parseUTCOffset(%q) = %d, %v; want %d", in, got, ok, want)
+		}
+	}
+	if _, ok := parseUTCOffset("0100"); ok {
+		t.Error("offset without sign should be rejected")
+	}
+}
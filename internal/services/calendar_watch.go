@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// Google push notifications: every polled Google calendar gets an
+// events.watch channel pointing at /webhooks/google/calendar. A notification
+// carries no event data, only "something changed", so it just triggers the
+// same incremental busy-cache sync the ticker runs. The ticker keeps running
+// for every provider and covers any notification Google fails to deliver.
+
+const (
+	// googleWatchTTL is the lifetime requested for a channel. Google caps
+	// events channels at about a week and may grant less.
+	googleWatchTTL = 7 * 24 * time.Hour
+
+	// googleWatchRenewBefore is how close to expiry a channel is replaced.
+	// Comfortably more than one sync interval, so a renewal is never missed.
+	googleWatchRenewBefore = 24 * time.Hour
+
+	googleWebhookPath = "/webhooks/google/calendar"
+)
+
+var ErrWatchChannelNotFound = errors.New("watch channel not found")
+
+// googleWebhookURL is the address Google should deliver notifications to,
+// or "" when the server has no public HTTPS URL (Google only delivers to
+// HTTPS), in which case calendars are only polled.
+func (s *CalendarService) googleWebhookURL() string {
+	if s.cfg == nil || !strings.HasPrefix(s.cfg.Server.BaseURL, "https://") {
+		return ""
+	}
+	return strings.TrimRight(s.cfg.Server.BaseURL, "/") + googleWebhookPath
+}
+
+// EnsureGoogleWatchChannels makes every polled calendar under a Google
+// connection hold a live watch channel: missing ones are registered, ones
+// close to expiry are replaced, and channels on calendars that are no longer
+// polled are stopped. A calendar Google refuses to watch is logged and left
+// to polling; the first such error is returned once all calendars are done.
+func (s *CalendarService) EnsureGoogleWatchChannels(ctx context.Context, conn *models.CalendarConnection) error {
+	if conn.Provider != models.CalendarProviderGoogle {
+		return nil
+	}
+	address := s.googleWebhookURL()
+	if address == "" {
+		return nil
+	}
+
+	now := time.Now()
+	if err := s.repos.CalendarWatchChannel.DeleteExpired(ctx, now); err != nil {
+		return err
+	}
+
+	calendars, err := s.repos.ProviderCalendar.GetByConnectionID(ctx, conn.ID)
+	if err != nil {
+		return err
+	}
+	existing, err := s.repos.CalendarWatchChannel.GetByConnectionID(ctx, conn.ID)
+	if err != nil {
+		return err
+	}
+	byCalendar := make(map[string][]*models.CalendarWatchChannel)
+	for _, ch := range existing {
+		byCalendar[ch.CalendarID] = append(byCalendar[ch.CalendarID], ch)
+	}
+
+	var firstErr error
+	for _, pc := range calendars {
+		channels := byCalendar[pc.ID]
+
+		var keep *models.CalendarWatchChannel
+		if pc.PollBusy {
+			for _, ch := range channels {
+				if ch.ExpiresAt.After(now.Add(googleWatchRenewBefore)) && (keep == nil || ch.ExpiresAt.After(keep.ExpiresAt.Time)) {
+					keep = ch
+				}
+			}
+			if keep == nil {
+				// Register the replacement before stopping the old channel
+				// so there is no gap in coverage.
+				keep, err = s.watchGoogleCalendar(ctx, conn, pc, address)
+				if err != nil {
+					log.Printf("[CALENDAR] watch failed for calendar %s (%s): %v", pc.ID, pc.Name, err)
+					if firstErr == nil {
+						firstErr = err
+					}
+					continue
+				}
+			}
+		}
+
+		for _, ch := range channels {
+			if keep != nil && ch.ID == keep.ID {
+				continue
+			}
+			if err := s.stopGoogleChannel(ctx, conn, ch); err != nil {
+				log.Printf("[CALENDAR] failed to stop watch channel %s: %v", ch.ID, err)
+			}
+		}
+	}
+	return firstErr
+}
+
+// StopGoogleWatchChannels stops every channel under a connection, e.g.
+// before it is disconnected. Failures are logged; Google stops delivering
+// on its own once a channel expires.
+func (s *CalendarService) StopGoogleWatchChannels(ctx context.Context, conn *models.CalendarConnection) {
+	channels, err := s.repos.CalendarWatchChannel.GetByConnectionID(ctx, conn.ID)
+	if err != nil {
+		log.Printf("[CALENDAR] failed to load watch channels for connection %s: %v", conn.ID, err)
+		return
+	}
+	for _, ch := range channels {
+		if err := s.stopGoogleChannel(ctx, conn, ch); err != nil {
+			log.Printf("[CALENDAR] failed to stop watch channel %s: %v", ch.ID, err)
+		}
+	}
+}
+
+// watchGoogleCalendar registers a channel on pc's events and stores it.
+func (s *CalendarService) watchGoogleCalendar(ctx context.Context, conn *models.CalendarConnection, pc *models.ProviderCalendar, address string) (*models.CalendarWatchChannel, error) {
+	if err := s.refreshGoogleToken(conn); err != nil {
+		return nil, err
+	}
+
+	token, err := generateToken(32)
+	if err != nil {
+		return nil, err
+	}
+	ch := &models.CalendarWatchChannel{
+		ID:         uuid.New().String(),
+		CalendarID: pc.ID,
+		Token:      token,
+		CreatedAt:  models.Now(),
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"id":      ch.ID,
+		"type":    "web_hook",
+		"address": address,
+		"token":   ch.Token,
+		"params": map[string]string{
+			"ttl": strconv.Itoa(int(googleWatchTTL.Seconds())),
+		},
+	})
+	watchURL := fmt.Sprintf("https://www.googleapis.com/calendar/v3/calendars/%s/events/watch", url.PathEscape(pc.ProviderCalendarID))
+
+	req, _ := http.NewRequestWithContext(ctx, "POST", watchURL, strings.NewReader(string(body)))
+	req.Header.Set("Authorization", "Bearer "+conn.AccessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("Error closing response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrCalendarAuth
+	}
+	if resp.StatusCode != http.StatusOK {
+		raw, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("watch request failed (%d): %s", resp.StatusCode, string(raw))
+	}
+
+	var result struct {
+		ResourceID string `json:"resourceId"`
+		Expiration string `json:"expiration"` // Unix milliseconds, as a string
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	expires := time.Now().Add(googleWatchTTL)
+	if ms, err := strconv.ParseInt(result.Expiration, 10, 64); err == nil && ms > 0 {
+		expires = time.UnixMilli(ms)
+	}
+	ch.ResourceID = result.ResourceID
+	ch.ExpiresAt = models.NewSQLiteTime(expires)
+
+	if err := s.repos.CalendarWatchChannel.Create(ctx, ch); err != nil {
+		// Google would deliver to a channel we can't verify; stop it.
+		_ = s.stopGoogleChannel(ctx, conn, ch)
+		return nil, err
+	}
+	return ch, nil
+}
+
+// stopGoogleChannel stops a channel at Google and forgets it. The row is
+// removed even if Google rejects the stop (it usually means the channel is
+// already gone); notifications for an unknown channel are refused anyway.
+func (s *CalendarService) stopGoogleChannel(ctx context.Context, conn *models.CalendarConnection, ch *models.CalendarWatchChannel) error {
+	stopErr := s.postGoogleChannelStop(ctx, conn, ch)
+	if err := s.repos.CalendarWatchChannel.Delete(ctx, ch.ID); err != nil {
+		return err
+	}
+	return stopErr
+}
+
+func (s *CalendarService) postGoogleChannelStop(ctx context.Context, conn *models.CalendarConnection, ch *models.CalendarWatchChannel) error {
+	if err := s.refreshGoogleToken(conn); err != nil {
+		return err
+	}
+
+	body, _ := json.Marshal(map[string]string{
+		"id":         ch.ID,
+		"resourceId": ch.ResourceID,
+	})
+	req, _ := http.NewRequestWithContext(ctx, "POST", "https://www.googleapis.com/calendar/v3/channels/stop", strings.NewReader(string(body)))
+	req.Header.Set("Authorization", "Bearer "+conn.AccessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("Error closing response body: %v", err)
+		}
+	}()
+
+	// 404 means Google has already dropped the channel.
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		raw, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("channel stop failed (%d): %s", resp.StatusCode, string(raw))
+	}
+	return nil
+}
+
+// VerifyGoogleNotification returns the channel a push notification was sent
+// on, or ErrWatchChannelNotFound unless the channel is known and unexpired
+// and the token and resource id match what was registered.
+func (s *CalendarService) VerifyGoogleNotification(ctx context.Context, channelID, token, resourceID string) (*models.CalendarWatchChannel, error) {
+	if channelID == "" || token == "" {
+		return nil, ErrWatchChannelNotFound
+	}
+	ch, err := s.repos.CalendarWatchChannel.GetByID(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if ch == nil || ch.ExpiresAt.Before(time.Now()) {
+		return nil, ErrWatchChannelNotFound
+	}
+	if subtle.ConstantTimeCompare([]byte(ch.Token), []byte(token)) != 1 {
+		return nil, ErrWatchChannelNotFound
+	}
+	if ch.ResourceID != "" && resourceID != ch.ResourceID {
+		return nil, ErrWatchChannelNotFound
+	}
+	return ch, nil
+}
+
+// SyncWatchedCalendar re-syncs the calendar behind a verified channel after
+// Google reported a change on it.
+func (s *CalendarService) SyncWatchedCalendar(ctx context.Context, ch *models.CalendarWatchChannel) error {
+	pc, err := s.repos.ProviderCalendar.GetByID(ctx, ch.CalendarID)
+	if err != nil {
+		return err
+	}
+	if pc == nil {
+		return ErrCalendarNotFound
+	}
+	conn, err := s.repos.Calendar.GetByID(ctx, pc.ConnectionID)
+	if err != nil {
+		return err
+	}
+	if conn == nil {
+		return ErrCalendarNotFound
+	}
+
+	host, err := s.repos.Host.GetByID(ctx, conn.HostID)
+	if err != nil {
+		log.Printf("[CALENDAR] sync: failed to load host %s: %v", conn.HostID, err)
+	}
+	owner := ownerForConnection(host, conn)
+
+	start := time.Now()
+	return s.syncProviderCalendar(ctx, conn, pc, owner, start, start.Add(24*time.Hour))
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// googleWatchStandIn serves events.watch and channels.stop, and hands
+// events.list on to the embedded events stand-in.
+type googleWatchStandIn struct {
+	*googleEventsStandIn
+	mu       sync.Mutex
+	watches  []map[string]interface{}
+	stopped  []string
+	lifetime []time.Duration // granted per watch in order; the last repeats
+}
+
+func (g *googleWatchStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/calendar/v3/calendars/primary/events/watch":
+		var body map[string]interface{}
+		_ = json.Unmarshal([]byte(readReqBody(r)), &body)
+		g.mu.Lock()
+		g.watches = append(g.watches, body)
+		lifetime := g.lifetime[min(len(g.watches), len(g.lifetime))-1]
+		g.mu.Unlock()
+		writeJSON(w, map[string]string{
+			"id":         body["id"].(string),
+			"resourceId": "res-" + body["id"].(string),
+			"expiration": strconv.FormatInt(time.Now().Add(lifetime).UnixMilli(), 10),
+		})
+	case "/calendar/v3/channels/stop":
+		var body map[string]string
+		_ = json.Unmarshal([]byte(readReqBody(r)), &body)
+		g.mu.Lock()
+		g.stopped = append(g.stopped, body["id"])
+		g.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		g.googleEventsStandIn.ServeHTTP(w, r)
+	}
+}
+
+// TestGoogleWatchChannels covers the channel lifecycle (register, keep,
+// renew, stop when polling is switched off), notification verification, and
+// the incremental sync a notification triggers.
+func TestGoogleWatchChannels(t *testing.T) {
+	day := busyCacheDay()
+	google := &googleWatchStandIn{
+		googleEventsStandIn: &googleEventsStandIn{items: []map[string]interface{}{
+			googleTimedItem("a", day.Add(9*time.Hour), 1, "confirmed"),
+		}},
+		lifetime: []time.Duration{time.Hour, 7 * 24 * time.Hour},
+	}
+	srv := httptest.NewServer(google)
+	defer srv.Close()
+	target, _ := url.Parse(srv.URL)
+	http.DefaultClient = &http.Client{Transport: &hostRewriteTransport{base: http.DefaultTransport, host: "www.googleapis.com", target: target}}
+	defer func() { http.DefaultClient = &http.Client{} }()
+
+	_, repos, cal := setupServiceTestDB(t)
+	host, conn := seedHostAndConnection(t, repos, models.CalendarProviderGoogle, "tok", "")
+	ctx := context.Background()
+	pc, err := repos.ProviderCalendar.UpsertFromProvider(ctx, conn.ID, "primary", "Primary", "", true, true)
+	if err != nil {
+		t.Fatalf("upsert calendar: %v", err)
+	}
+
+	channels := func() []*models.CalendarWatchChannel {
+		t.Helper()
+		got, err := repos.CalendarWatchChannel.GetByCalendarID(ctx, pc.ID)
+		if err != nil {
+			t.Fatalf("GetByCalendarID: %v", err)
+		}
+		return got
+	}
+	ensure := func() {
+		t.Helper()
+		if err := cal.EnsureGoogleWatchChannels(ctx, conn); err != nil {
+			t.Fatalf("EnsureGoogleWatchChannels: %v", err)
+		}
+	}
+
+	// Without a public HTTPS URL Google can't reach us, so nothing is watched.
+	ensure()
+	if len(google.watches) != 0 {
+		t.Fatalf("watched %d calendars over plain http", len(google.watches))
+	}
+
+	cal.cfg.Server.BaseURL = "https://book.example.com"
+	ensure()
+	first := channels()
+	if len(google.watches) != 1 || len(first) != 1 {
+		t.Fatalf("watches = %d, channels = %d, want 1 and 1", len(google.watches), len(first))
+	}
+	if addr := google.watches[0]["address"]; addr != "https://book.example.com/webhooks/google/calendar" {
+		t.Errorf("address = %v", addr)
+	}
+	if first[0].Token == "" || google.watches[0]["token"] != first[0].Token || first[0].ResourceID != "res-"+first[0].ID {
+		t.Errorf("stored channel %+v doesn't match registration %v", first[0], google.watches[0])
+	}
+
+	// The first channel was only granted an hour, so it is renewed: new
+	// channel first, then the old one stopped.
+	ensure()
+	renewed := channels()
+	if len(google.watches) != 2 || len(renewed) != 1 || renewed[0].ID == first[0].ID {
+		t.Fatalf("after renewal: watches = %d, channels = %+v", len(google.watches), renewed)
+	}
+	if !slices.Equal(google.stopped, []string{first[0].ID}) {
+		t.Errorf("stopped = %v, want the expiring channel", google.stopped)
+	}
+	ensure()
+	if len(google.watches) != 2 {
+		t.Errorf("a fresh channel was re-registered")
+	}
+
+	ch := renewed[0]
+	for name, tc := range map[string][3]string{
+		"unknown channel": {"nope", ch.Token, ch.ResourceID},
+		"wrong token":     {ch.ID, "forged", ch.ResourceID},
+		"wrong resource":  {ch.ID, ch.Token, "res-other"},
+		"missing token":   {ch.ID, "", ch.ResourceID},
+	} {
+		if _, err := cal.VerifyGoogleNotification(ctx, tc[0], tc[1], tc[2]); !errors.Is(err, ErrWatchChannelNotFound) {
+			t.Errorf("%s: err = %v, want ErrWatchChannelNotFound", name, err)
+		}
+	}
+	verified, err := cal.VerifyGoogleNotification(ctx, ch.ID, ch.Token, ch.ResourceID)
+	if err != nil || verified.CalendarID != pc.ID {
+		t.Fatalf("VerifyGoogleNotification = %+v, %v", verified, err)
+	}
+
+	// A notification re-syncs incrementally from the stored syncToken.
+	if err := cal.RefreshCalendarSync(ctx, host.ID, conn.ID); err != nil {
+		t.Fatalf("RefreshCalendarSync: %v", err)
+	}
+	google.googleEventsStandIn.mu.Lock()
+	google.changes = []map[string]interface{}{googleTimedItem("b", day.Add(14*time.Hour), 1, "confirmed")}
+	google.googleEventsStandIn.mu.Unlock()
+	if err := cal.SyncWatchedCalendar(ctx, verified); err != nil {
+		t.Fatalf("SyncWatchedCalendar: %v", err)
+	}
+	if last := google.queries[len(google.queries)-1]; last.Get("syncToken") == "" {
+		t.Errorf("notification sync should be incremental, got %v", last)
+	}
+	slots, err := cal.GetBusyTimes(ctx, host.ID, day, day.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("GetBusyTimes: %v", err)
+	}
+	slices.SortFunc(slots, func(a, b models.TimeSlot) int { return a.Start.Compare(b.Start) })
+	if got, want := slotHours(slots), []int{9, 14}; !slices.Equal(got, want) {
+		t.Errorf("busy hours after notification = %v, want %v", got, want)
+	}
+
+	// Once the calendar is no longer polled its channel is stopped.
+	if err := cal.SetProviderCalendarPollBusy(ctx, host.ID, pc.ID, false); err != nil {
+		t.Fatalf("SetProviderCalendarPollBusy: %v", err)
+	}
+	ensure()
+	if got := channels(); len(got) != 0 {
+		t.Errorf("channels after disabling poll = %+v", got)
+	}
+	if len(google.stopped) != 2 || google.stopped[1] != ch.ID {
+		t.Errorf("stopped = %v, want %s last", google.stopped, ch.ID)
+	}
+}
@@ -6,13 +6,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"io"
 	"log"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/http"
 	"net/smtp"
+	"net/textproto"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/meet-when/meet-when/internal/config"
 	"github.com/meet-when/meet-when/internal/models"
+	"github.com/meet-when/meet-when/internal/repository"
 )
 
 // EmailTemplate represents a custom email template with subject and body
@@ -21,7 +29,9 @@ type EmailTemplate struct {
 	Body    string `json:"body"`
 }
 
-// EmailTemplateData contains the data available for email template placeholders
+// EmailTemplateData contains the data available for email template
+// placeholders. The fields after RescheduleLink have no placeholder; only
+// the HTML emails use them, each email type the ones it needs.
 type EmailTemplateData struct {
 	InviteeName    string
 	HostName       string
@@ -31,16 +41,55 @@ type EmailTemplateData struct {
 	Location       string
 	CancelLink     string
 	RescheduleLink string
+	TimeUntil      string // reminders only, e.g. "tomorrow" or "in 1 hour"
+
+	RecipientName   string
+	InviteeEmail    string
+	PreviousTime    string
+	Agenda          string
+	Notes           string
+	Changes         string
+	Reason          string
+	Description     string
+	Attendees       []string
+	BookingPageLink string
+	DashboardLink   string
+	Provider        string
+	CalendarName    string
+	ErrorMessage    string
+	NeedsReconnect  bool
 }
 
 // EmailService handles email sending
 type EmailService struct {
-	cfg *config.Config
+	cfg           *config.Config
+	repos         *repository.Repositories
+	httpClient    *http.Client
+	htmlTemplates map[string]*template.Template
+	outboxWake    chan struct{} // signals the outbox worker that an email was queued
+	mailgunTokens *mailgunTokenCache
 }
 
 // NewEmailService creates a new email service
-func NewEmailService(cfg *config.Config) *EmailService {
-	return &EmailService{cfg: cfg}
+func NewEmailService(cfg *config.Config, repos *repository.Repositories) *EmailService {
+	return &EmailService{
+		cfg:           cfg,
+		repos:         repos,
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		htmlTemplates: loadEmailTemplates("templates/emails"),
+		outboxWake:    make(chan struct{}, 1),
+		mailgunTokens: newMailgunTokenCache(),
+	}
+}
+
+// outgoingEmail is one email to send: a plain-text body, with an HTML
+// alternative and an ICS invite when there are ones
+type outgoingEmail struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	ICS     string
 }
 
 // parseEmailTemplate parses a JSON email template string
@@ -71,6 +120,7 @@ func renderEmailTemplate(templateStr string, data *EmailTemplateData) string {
 	result = strings.ReplaceAll(result, "{{location}}", data.Location)
 	result = strings.ReplaceAll(result, "{{cancel_link}}", data.CancelLink)
 	result = strings.ReplaceAll(result, "{{reschedule_link}}", data.RescheduleLink)
+	result = strings.ReplaceAll(result, "{{time_until}}", data.TimeUntil)
 	return result
 }
 
@@ -141,11 +191,17 @@ func (s *EmailService) SendBookingRequested(ctx context.Context, details *Bookin
 	hostLoc, _ := time.LoadLocation(details.Host.Timezone)
 	startTime := details.Booking.StartTime.In(hostLoc)
 
+	data := s.buildEmailTemplateData(details, hostLoc)
+	data.RecipientName = details.Host.Name
+	data.InviteeEmail = details.Booking.InviteeEmail
+	data.DashboardLink = s.cfg.Server.BaseURL + "/dashboard/bookings"
+
 	// Extract agenda if provided
 	agendaSection := ""
 	if details.Booking.Answers != nil {
 		if agenda, ok := details.Booking.Answers["agenda"].(string); ok && agenda != "" {
 			agendaSection = fmt.Sprintf("\nAgenda:\n%s\n", agenda)
+			data.Agenda = agenda
 		}
 	}
 
@@ -172,12 +228,9 @@ Meet When`,
 		agendaSection,
 		s.cfg.Server.BaseURL,
 	)
+	html := s.renderHTML(details.Tenant, "booking_requested", subject, data, "")
 
-	go func() {
-		if err := s.sendEmail(details.Host.Email, subject, body, ""); err != nil {
-			log.Printf("Error sending email to host %s: %v", details.Host.Email, err)
-		}
-	}()
+	s.enqueue(ctx, details.Host, &outgoingEmail{To: details.Host.Email, Subject: subject, Text: body, HTML: html})
 }
 
 // SendBookingConfirmed sends confirmation to both host and invitee
@@ -198,8 +251,9 @@ func (s *EmailService) sendInviteeConfirmation(ctx context.Context, details *Boo
 
 	// Build template data for custom templates
 	templateData := s.buildEmailTemplateData(details, inviteeLoc)
+	templateData.RecipientName = details.Booking.InviteeName
 
-	var subject, body string
+	var subject, body, customBody string
 
 	// Check for custom confirmation email template
 	customTemplate := parseEmailTemplate(details.Template.ConfirmationEmail)
@@ -212,6 +266,7 @@ func (s *EmailService) sendInviteeConfirmation(ctx context.Context, details *Boo
 		}
 		if customTemplate.Body != "" {
 			body = renderEmailTemplate(customTemplate.Body, templateData)
+			customBody = body
 		} else {
 			body = s.defaultInviteeConfirmationBody(templateData)
 		}
@@ -221,14 +276,12 @@ func (s *EmailService) sendInviteeConfirmation(ctx context.Context, details *Boo
 		body = s.defaultInviteeConfirmationBody(templateData)
 	}
 
+	html := s.renderHTML(details.Tenant, "booking_confirmed", subject, templateData, customBody)
+
 	// Generate ICS attachment
 	ics := s.generateICS(details)
 
-	go func() {
-		if err := s.sendEmail(details.Booking.InviteeEmail, subject, body, ics); err != nil {
-			log.Printf("Error sending email to invitee %s: %v", details.Booking.InviteeEmail, err)
-		}
-	}()
+	s.enqueue(ctx, details.Host, &outgoingEmail{To: details.Booking.InviteeEmail, Subject: subject, Text: body, HTML: html, ICS: ics})
 }
 
 func (s *EmailService) sendHostConfirmation(ctx context.Context, details *BookingWithDetails) {
@@ -237,6 +290,11 @@ func (s *EmailService) sendHostConfirmation(ctx context.Context, details *Bookin
 	hostLoc, _ := time.LoadLocation(details.Host.Timezone)
 	startTime := details.Booking.StartTime.In(hostLoc)
 
+	data := s.buildEmailTemplateData(details, hostLoc)
+	data.RecipientName = details.Host.Name
+	data.InviteeEmail = details.Booking.InviteeEmail
+	data.DashboardLink = s.cfg.Server.BaseURL + "/dashboard/bookings"
+
 	location := "To be determined"
 	if details.Template.LocationType == models.ConferencingProviderPhone {
 		if details.Template.CustomLocation != "" {
@@ -253,6 +311,7 @@ func (s *EmailService) sendHostConfirmation(ctx context.Context, details *Bookin
 	if details.Booking.Answers != nil {
 		if agenda, ok := details.Booking.Answers["agenda"].(string); ok && agenda != "" {
 			agendaSection = fmt.Sprintf("\nAgenda:\n%s\n", agenda)
+			data.Agenda = agenda
 		}
 	}
 
@@ -280,12 +339,9 @@ Meet When`,
 		agendaSection,
 		s.cfg.Server.BaseURL,
 	)
+	html := s.renderHTML(details.Tenant, "booking_confirmed_host", subject, data, "")
 
-	go func() {
-		if err := s.sendEmail(details.Host.Email, subject, body, ""); err != nil {
-			log.Printf("Error sending email to host %s: %v", details.Host.Email, err)
-		}
-	}()
+	s.enqueue(ctx, details.Host, &outgoingEmail{To: details.Host.Email, Subject: subject, Text: body, HTML: html})
 }
 
 // SendBookingCancelled sends cancellation notice
@@ -324,11 +380,14 @@ Meet When`,
 		formatCancelReason(details.Booking.CancelReason),
 	)
 
-	go func() {
-		if err := s.sendEmail(details.Host.Email, subject, body, ""); err != nil {
-			log.Printf("Error sending email to host %s: %v", details.Host.Email, err)
-		}
-	}()
+	data := s.buildEmailTemplateData(details, hostLoc)
+	data.RecipientName = details.Host.Name
+	data.InviteeEmail = details.Booking.InviteeEmail
+	data.Reason = details.Booking.CancelReason
+	data.DashboardLink = s.cfg.Server.BaseURL + "/dashboard/bookings"
+	html := s.renderHTML(details.Tenant, "booking_cancelled_host", subject, data, "")
+
+	s.enqueue(ctx, details.Host, &outgoingEmail{To: details.Host.Email, Subject: subject, Text: body, HTML: html})
 }
 
 func (s *EmailService) sendCancellationToInvitee(ctx context.Context, details *BookingWithDetails) {
@@ -365,11 +424,13 @@ Meet When`,
 		details.Host.Slug,
 	)
 
-	go func() {
-		if err := s.sendEmail(details.Booking.InviteeEmail, subject, body, ""); err != nil {
-			log.Printf("Error sending email to invitee %s: %v", details.Booking.InviteeEmail, err)
-		}
-	}()
+	data := s.buildEmailTemplateData(details, inviteeLoc)
+	data.RecipientName = details.Booking.InviteeName
+	data.Reason = details.Booking.CancelReason
+	data.BookingPageLink = fmt.Sprintf("%s/%s/%s", s.cfg.Server.BaseURL, details.Tenant.Slug, details.Host.Slug)
+	html := s.renderHTML(details.Tenant, "booking_cancelled", subject, data, "")
+
+	s.enqueue(ctx, details.Host, &outgoingEmail{To: details.Booking.InviteeEmail, Subject: subject, Text: body, HTML: html})
 }
 
 // SendBookingRejected sends rejection notice to invitee
@@ -407,11 +468,13 @@ Meet When`,
 		details.Host.Slug,
 	)
 
-	go func() {
-		if err := s.sendEmail(details.Booking.InviteeEmail, subject, body, ""); err != nil {
-			log.Printf("Error sending email to invitee %s: %v", details.Booking.InviteeEmail, err)
-		}
-	}()
+	data := s.buildEmailTemplateData(details, inviteeLoc)
+	data.RecipientName = details.Booking.InviteeName
+	data.Reason = details.Booking.CancelReason
+	data.BookingPageLink = fmt.Sprintf("%s/%s/%s", s.cfg.Server.BaseURL, details.Tenant.Slug, details.Host.Slug)
+	html := s.renderHTML(details.Tenant, "booking_rejected", subject, data, "")
+
+	s.enqueue(ctx, details.Host, &outgoingEmail{To: details.Booking.InviteeEmail, Subject: subject, Text: body, HTML: html})
 }
 
 // SendBookingRescheduled sends reschedule notification to both host and invitee
@@ -481,14 +544,15 @@ Meet When`,
 		details.Booking.ID,
 	)
 
+	data := s.buildEmailTemplateData(details, inviteeLoc)
+	data.RecipientName = details.Booking.InviteeName
+	data.PreviousTime = oldTimeFormatted
+	html := s.renderHTML(details.Tenant, "booking_rescheduled", subject, data, "")
+
 	// Generate ICS attachment with updated time
 	ics := s.generateICS(details)
 
-	go func() {
-		if err := s.sendEmail(details.Booking.InviteeEmail, subject, body, ics); err != nil {
-			log.Printf("Error sending reschedule email to invitee %s: %v", details.Booking.InviteeEmail, err)
-		}
-	}()
+	s.enqueue(ctx, details.Host, &outgoingEmail{To: details.Booking.InviteeEmail, Subject: subject, Text: body, HTML: html, ICS: ics})
 }
 
 // SendBookingUpdated notifies the invitee (and any additional guests) that the
@@ -515,10 +579,14 @@ func (s *EmailService) SendBookingUpdated(ctx context.Context, details *BookingW
 		location = details.Template.CustomLocation
 	}
 
+	data := s.buildEmailTemplateData(details, inviteeLoc)
+	data.RecipientName = details.Booking.InviteeName
+
 	notes := ""
 	if details.Booking.Answers != nil {
 		if hn, ok := details.Booking.Answers["host_notes"].(string); ok && hn != "" {
 			notes = "\n\nNotes from host:\n" + hn
+			data.Notes = hn
 		}
 	}
 
@@ -526,6 +594,7 @@ func (s *EmailService) SendBookingUpdated(ctx context.Context, details *BookingW
 	if len(changedFields) > 0 {
 		changes = strings.Join(changedFields, ", ")
 	}
+	data.Changes = changes
 
 	body := fmt.Sprintf(`Hello %s,
 
@@ -563,16 +632,12 @@ Meet When`,
 		details.Booking.ID,
 	)
 
+	html := s.renderHTML(details.Tenant, "booking_updated", subject, data, "")
 	ics := s.generateICS(details)
 
 	recipients := append([]string{details.Booking.InviteeEmail}, details.Booking.AdditionalGuests...)
 	for _, addr := range recipients {
-		addr := addr
-		go func() {
-			if err := s.sendEmail(addr, subject, body, ics); err != nil {
-				log.Printf("Error sending booking-updated email to %s: %v", addr, err)
-			}
-		}()
+		s.enqueue(ctx, details.Host, &outgoingEmail{To: addr, Subject: subject, Text: body, HTML: html, ICS: ics})
 	}
 }
 
@@ -607,8 +672,30 @@ Meet When`,
 	)
 }
 
-// SendBookingReminder sends a reminder email to the invitee before their meeting
-func (s *EmailService) SendBookingReminder(ctx context.Context, details *BookingWithDetails) {
+// timeUntil describes a reminder's lead time the way its email puts it:
+// "tomorrow" a day ahead, otherwise "in 3 days", "in 1 hour" or "in 10 minutes"
+func timeUntil(minutes int) string {
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("in 1 %s", unit)
+		}
+		return fmt.Sprintf("in %d %ss", n, unit)
+	}
+	switch {
+	case minutes == 24*60:
+		return "tomorrow"
+	case minutes%(24*60) == 0:
+		return plural(minutes/(24*60), "day")
+	case minutes%60 == 0:
+		return plural(minutes/60, "hour")
+	default:
+		return plural(minutes, "minute")
+	}
+}
+
+// SendBookingReminder sends a reminder email to the invitee, offsetMinutes
+// before their meeting
+func (s *EmailService) SendBookingReminder(ctx context.Context, details *BookingWithDetails, offsetMinutes int) {
 	// Format time in invitee's timezone
 	inviteeLoc, _ := time.LoadLocation(details.Booking.InviteeTimezone)
 	if inviteeLoc == nil {
@@ -617,8 +704,10 @@ func (s *EmailService) SendBookingReminder(ctx context.Context, details *Booking
 
 	// Build template data for custom templates
 	templateData := s.buildEmailTemplateData(details, inviteeLoc)
+	templateData.RecipientName = details.Booking.InviteeName
+	templateData.TimeUntil = timeUntil(offsetMinutes)
 
-	var subject, body string
+	var subject, body, customBody string
 
 	// Check for custom reminder email template
 	customTemplate := parseEmailTemplate(details.Template.ReminderEmail)
@@ -627,27 +716,74 @@ func (s *EmailService) SendBookingReminder(ctx context.Context, details *Booking
 		if customTemplate.Subject != "" {
 			subject = renderEmailTemplate(customTemplate.Subject, templateData)
 		} else {
-			subject = fmt.Sprintf("Reminder: %s with %s tomorrow", details.Template.Name, details.Host.Name)
+			subject = fmt.Sprintf("Reminder: %s with %s %s", details.Template.Name, details.Host.Name, templateData.TimeUntil)
 		}
 		if customTemplate.Body != "" {
 			body = renderEmailTemplate(customTemplate.Body, templateData)
+			customBody = body
 		} else {
 			body = s.defaultReminderBody(templateData)
 		}
 	} else {
 		// Use default template
-		subject = fmt.Sprintf("Reminder: %s with %s tomorrow", details.Template.Name, details.Host.Name)
+		subject = fmt.Sprintf("Reminder: %s with %s %s", details.Template.Name, details.Host.Name, templateData.TimeUntil)
 		body = s.defaultReminderBody(templateData)
 	}
 
+	html := s.renderHTML(details.Tenant, "booking_reminder", subject, templateData, customBody)
+
 	// Generate ICS attachment
 	ics := s.generateICS(details)
 
-	go func() {
-		if err := s.sendEmail(details.Booking.InviteeEmail, subject, body, ics); err != nil {
-			log.Printf("Error sending reminder email to invitee %s: %v", details.Booking.InviteeEmail, err)
-		}
-	}()
+	s.enqueue(ctx, details.Host, &outgoingEmail{To: details.Booking.InviteeEmail, Subject: subject, Text: body, HTML: html, ICS: ics})
+}
+
+// SendHostBookingReminder sends a reminder email to the host, offsetMinutes
+// before a meeting booked with them. The template's custom reminder is
+// written for invitees, so hosts always get the standard one.
+func (s *EmailService) SendHostBookingReminder(ctx context.Context, details *BookingWithDetails, offsetMinutes int) {
+	hostLoc, _ := time.LoadLocation(details.Host.Timezone)
+	if hostLoc == nil {
+		hostLoc = time.UTC
+	}
+
+	data := s.buildEmailTemplateData(details, hostLoc)
+	data.RecipientName = details.Host.Name
+	data.InviteeEmail = details.Booking.InviteeEmail
+	data.TimeUntil = timeUntil(offsetMinutes)
+	data.DashboardLink = s.cfg.Server.BaseURL + "/dashboard/bookings"
+	if agenda, ok := details.Booking.Answers["agenda"].(string); ok {
+		data.Agenda = agenda
+	}
+
+	subject := fmt.Sprintf("Reminder: %s with %s %s", details.Template.Name, details.Booking.InviteeName, data.TimeUntil)
+	body := fmt.Sprintf(`Hello %s,
+
+This is a reminder about a meeting booked with you:
+
+Meeting: %s
+With: %s (%s)
+When: %s
+Duration: %d minutes
+Location: %s
+
+View all bookings: %s
+
+Best regards,
+Meet When`,
+		data.RecipientName,
+		data.MeetingName,
+		data.InviteeName,
+		data.InviteeEmail,
+		data.MeetingTime,
+		data.Duration,
+		data.Location,
+		data.DashboardLink,
+	)
+
+	html := s.renderHTML(details.Tenant, "booking_reminder_host", subject, data, "")
+
+	s.enqueue(ctx, details.Host, &outgoingEmail{To: details.Host.Email, Subject: subject, Text: body, HTML: html})
 }
 
 func (s *EmailService) sendHostRescheduleNotification(ctx context.Context, details *BookingWithDetails, oldStartTime time.Time) {
@@ -699,11 +835,14 @@ Meet When`,
 		s.cfg.Server.BaseURL,
 	)
 
-	go func() {
-		if err := s.sendEmail(details.Host.Email, subject, body, ""); err != nil {
-			log.Printf("Error sending reschedule email to host %s: %v", details.Host.Email, err)
-		}
-	}()
+	data := s.buildEmailTemplateData(details, hostLoc)
+	data.RecipientName = details.Host.Name
+	data.InviteeEmail = details.Booking.InviteeEmail
+	data.PreviousTime = oldTimeFormatted
+	data.DashboardLink = s.cfg.Server.BaseURL + "/dashboard/bookings"
+	html := s.renderHTML(details.Tenant, "booking_rescheduled_host", subject, data, "")
+
+	s.enqueue(ctx, details.Host, &outgoingEmail{To: details.Host.Email, Subject: subject, Text: body, HTML: html})
 }
 
 // generateICS creates an ICS calendar attachment
@@ -775,69 +914,162 @@ func (s *EmailService) GenerateICS(details *BookingWithDetails) string {
 	return s.generateICS(details)
 }
 
-// sendEmail sends an email (supports both SMTP and Mailgun)
-func (s *EmailService) sendEmail(to, subject, body, icsAttachment string) error {
+// sendEmail sends an email (supports both SMTP and Mailgun) and records it
+// once the provider has accepted it
+func (s *EmailService) sendEmail(email *outgoingEmail) error {
+	provider, messageID := "smtp", ""
+	var err error
 	if s.cfg.Email.Provider == "mailgun" {
-		return s.sendMailgun(to, subject, body, icsAttachment)
+		provider = "mailgun"
+		messageID, err = s.sendMailgun(email)
+	} else {
+		err = s.sendSMTP(email)
+	}
+	if err != nil {
+		return err
 	}
-	return s.sendSMTP(to, subject, body, icsAttachment)
+	s.recordSent(provider, messageID, email.To, email.Subject)
+	return nil
 }
 
-func (s *EmailService) sendSMTP(to, subject, body, icsAttachment string) error {
+// recordSent stores an email the provider accepted. Failing to store it is
+// only logged: the email has already gone.
+func (s *EmailService) recordSent(provider, messageID, to, subject string) {
+	now := models.Now()
+	err := s.repos.EmailMessage.Create(context.Background(), &models.EmailMessage{
+		ID:                uuid.New().String(),
+		Provider:          provider,
+		ProviderMessageID: messageID,
+		Recipient:         to,
+		Subject:           subject,
+		Status:            models.EmailMessageStatusSent,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	})
+	if err != nil {
+		log.Printf("Error recording email to %s: %v", to, err)
+	}
+}
+
+func (s *EmailService) sendSMTP(email *outgoingEmail) error {
 	from := s.cfg.Email.FromAddress
 	host := s.cfg.Email.SMTPHost
 	port := s.cfg.Email.SMTPPort
 
+	msg, err := s.buildSMTPMessage(email)
+	if err != nil {
+		return err
+	}
+
+	addr := fmt.Sprintf("%s:%d", host, port)
+
+	var auth smtp.Auth
+	if s.cfg.Email.SMTPUser != "" {
+		auth = smtp.PlainAuth("", s.cfg.Email.SMTPUser, s.cfg.Email.SMTPPassword, host)
+	}
+
+	return smtp.SendMail(addr, auth, from, []string{email.To}, msg)
+}
+
+// buildSMTPMessage builds the MIME message for an email. A text-only email
+// is a single text/plain body. With HTML, the text and HTML bodies are a
+// multipart/alternative; with an ICS invite, the body and the invite are a
+// multipart/mixed.
+func (s *EmailService) buildSMTPMessage(email *outgoingEmail) ([]byte, error) {
 	var msg bytes.Buffer
 
 	// Headers
-	msg.WriteString(fmt.Sprintf("From: %s <%s>\r\n", s.cfg.Email.FromName, from))
-	msg.WriteString(fmt.Sprintf("To: %s\r\n", to))
-	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
-
-	if icsAttachment != "" {
-		// Multipart message with attachment
-		boundary := "----=_Part_0_123456789"
-		msg.WriteString("MIME-Version: 1.0\r\n")
-		msg.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=\"%s\"\r\n", boundary))
-		msg.WriteString("\r\n")
+	msg.WriteString(fmt.Sprintf("From: %s <%s>\r\n", s.cfg.Email.FromName, s.cfg.Email.FromAddress))
+	msg.WriteString(fmt.Sprintf("To: %s\r\n", email.To))
+	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", email.Subject))
 
-		// Text part
-		msg.WriteString(fmt.Sprintf("--%s\r\n", boundary))
+	if email.HTML == "" && email.ICS == "" {
 		msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
 		msg.WriteString("\r\n")
-		msg.WriteString(body)
-		msg.WriteString("\r\n")
+		msg.WriteString(email.Text)
+		return msg.Bytes(), nil
+	}
 
-		// ICS attachment
-		msg.WriteString(fmt.Sprintf("--%s\r\n", boundary))
-		msg.WriteString("Content-Type: text/calendar; charset=utf-8; method=REQUEST\r\n")
-		msg.WriteString("Content-Disposition: attachment; filename=\"invite.ics\"\r\n")
-		msg.WriteString("\r\n")
-		msg.WriteString(icsAttachment)
-		msg.WriteString("\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	if email.ICS == "" {
+		alt := multipart.NewWriter(&msg)
+		msg.WriteString(fmt.Sprintf("Content-Type: multipart/alternative; boundary=\"%s\"\r\n\r\n", alt.Boundary()))
+		if err := writeAlternativeParts(alt, email); err != nil {
+			return nil, err
+		}
+		if err := alt.Close(); err != nil {
+			return nil, err
+		}
+		return msg.Bytes(), nil
+	}
 
-		msg.WriteString(fmt.Sprintf("--%s--\r\n", boundary))
+	mixed := multipart.NewWriter(&msg)
+	msg.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=\"%s\"\r\n\r\n", mixed.Boundary()))
+	if email.HTML == "" {
+		if err := writeQuotedPart(mixed, "text/plain; charset=utf-8", email.Text); err != nil {
+			return nil, err
+		}
 	} else {
-		msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
-		msg.WriteString("\r\n")
-		msg.WriteString(body)
+		var body bytes.Buffer
+		alt := multipart.NewWriter(&body)
+		if err := writeAlternativeParts(alt, email); err != nil {
+			return nil, err
+		}
+		if err := alt.Close(); err != nil {
+			return nil, err
+		}
+		part, err := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type": {fmt.Sprintf("multipart/alternative; boundary=\"%s\"", alt.Boundary())},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := part.Write(body.Bytes()); err != nil {
+			return nil, err
+		}
 	}
 
-	addr := fmt.Sprintf("%s:%d", host, port)
-
-	var auth smtp.Auth
-	if s.cfg.Email.SMTPUser != "" {
-		auth = smtp.PlainAuth("", s.cfg.Email.SMTPUser, s.cfg.Email.SMTPPassword, host)
+	// ICS attachment
+	part, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type":        {"text/calendar; charset=utf-8; method=REQUEST"},
+		"Content-Disposition": {`attachment; filename="invite.ics"`},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(part, email.ICS); err != nil {
+		return nil, err
 	}
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+	return msg.Bytes(), nil
+}
 
-	return smtp.SendMail(addr, auth, from, []string{to}, msg.Bytes())
+// writeAlternativeParts writes an email's text and HTML bodies, plainest
+// first as multipart/alternative requires
+func writeAlternativeParts(w *multipart.Writer, email *outgoingEmail) error {
+	if err := writeQuotedPart(w, "text/plain; charset=utf-8", email.Text); err != nil {
+		return err
+	}
+	return writeQuotedPart(w, "text/html; charset=utf-8", email.HTML)
 }
 
-func (s *EmailService) sendMailgun(to, subject, body, icsAttachment string) error {
-	// Mailgun API implementation
-	// For MVP, we'll use the SMTP relay which is simpler
-	return s.sendSMTP(to, subject, body, icsAttachment)
+// writeQuotedPart writes a quoted-printable body part, which keeps long HTML
+// lines within SMTP's line length limit
+func writeQuotedPart(w *multipart.Writer, contentType, body string) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	qp := quotedprintable.NewWriter(part)
+	if _, err := io.WriteString(qp, body); err != nil {
+		return err
+	}
+	return qp.Close()
 }
 
 // SendConferencingFailed notifies the host that creating a conference link
@@ -870,11 +1102,17 @@ Meet When`,
 		provider,
 	)
 
-	go func() {
-		if err := s.sendEmail(host.Email, subject, body, ""); err != nil {
-			log.Printf("[EMAIL] Error sending conferencing failure notification to %s: %v", host.Email, err)
-		}
-	}()
+	data := &EmailTemplateData{
+		RecipientName:  host.Name,
+		HostName:       host.Name,
+		Provider:       provider,
+		ErrorMessage:   errMsg,
+		NeedsReconnect: reason == "reauth_required",
+		DashboardLink:  s.cfg.Server.BaseURL + "/dashboard/calendars",
+	}
+	html := s.renderHTML(s.hostTenant(ctx, host), "conferencing_failed", subject, data, "")
+
+	s.enqueue(ctx, host, &outgoingEmail{To: host.Email, Subject: subject, Text: body, HTML: html})
 }
 
 // SendCalendarSyncFailed notifies the host that their calendar sync has failed
@@ -900,11 +1138,16 @@ Meet When`,
 		s.cfg.Server.BaseURL,
 	)
 
-	go func() {
-		if err := s.sendEmail(host.Email, subject, body, ""); err != nil {
-			log.Printf("[EMAIL] Error sending calendar sync failure notification to %s: %v", host.Email, err)
-		}
-	}()
+	data := &EmailTemplateData{
+		RecipientName: host.Name,
+		HostName:      host.Name,
+		CalendarName:  calendarName,
+		ErrorMessage:  errMsg,
+		DashboardLink: s.cfg.Server.BaseURL + "/dashboard/calendars",
+	}
+	html := s.renderHTML(s.hostTenant(ctx, host), "calendar_sync_failed", subject, data, "")
+
+	s.enqueue(ctx, host, &outgoingEmail{To: host.Email, Subject: subject, Text: body, HTML: html})
 }
 
 func formatCancelReason(reason string) string {
@@ -947,6 +1190,21 @@ func hostedEventLocationLabel(event *models.HostedEvent) string {
 	return "To be determined"
 }
 
+// hostedEventTemplateData builds the HTML email data for a hosted event,
+// addressed to recipientName
+func hostedEventTemplateData(event *models.HostedEvent, host *models.Host, recipientName string) *EmailTemplateData {
+	return &EmailTemplateData{
+		RecipientName: recipientName,
+		HostName:      host.Name,
+		MeetingName:   event.Title,
+		MeetingTime:   hostedEventTime(event),
+		Duration:      event.Duration,
+		Location:      hostedEventLocationLabel(event),
+		Description:   event.Description,
+		Reason:        event.CancelReason,
+	}
+}
+
 // SendHostedEventInvited sends an invitation to a single attendee. Used both
 // at create time (every attendee) and on update when an attendee is added.
 func (s *EmailService) SendHostedEventInvited(ctx context.Context, event *models.HostedEvent, attendee *models.HostedEventAttendee, host *models.Host, tenant *models.Tenant) {
@@ -989,17 +1247,14 @@ Meet When`,
 
 	ics := s.generateICSForHostedEvent(event, host, attendee, "REQUEST", "CONFIRMED")
 
-	go func() {
-		if err := s.sendEmail(attendee.Email, subject, body, ics); err != nil {
-			log.Printf("[EMAIL] Error sending hosted-event invitation to %s: %v", attendee.Email, err)
-		}
-	}()
+	html := s.renderHTML(tenant, "hosted_event_invited", subject, hostedEventTemplateData(event, host, attendee.Name), "")
+
+	s.enqueue(ctx, host, &outgoingEmail{To: attendee.Email, Subject: subject, Text: body, HTML: html, ICS: ics})
 
 	// Notify the host as well, but only for create-time invitations the host
 	// will have a list of all attendees in their calendar — skip a per-host
 	// duplicate to avoid noise. (Mirrors booking flow which sends one host
 	// email per booking, not per guest.)
-	_ = tenant
 }
 
 // SendHostedEventUpdated notifies a retained attendee that material event
@@ -1044,13 +1299,11 @@ Meet When`,
 
 	ics := s.generateICSForHostedEvent(event, host, attendee, "REQUEST", "CONFIRMED")
 
-	go func() {
-		if err := s.sendEmail(attendee.Email, subject, body, ics); err != nil {
-			log.Printf("[EMAIL] Error sending hosted-event update to %s: %v", attendee.Email, err)
-		}
-	}()
+	data := hostedEventTemplateData(event, host, attendee.Name)
+	data.Changes = strings.Join(changedFields, ", ")
+	html := s.renderHTML(tenant, "hosted_event_updated", subject, data, "")
 
-	_ = tenant
+	s.enqueue(ctx, host, &outgoingEmail{To: attendee.Email, Subject: subject, Text: body, HTML: html, ICS: ics})
 }
 
 // SendHostedEventUpdatedToHost notifies the host (organizer) that their own
@@ -1070,16 +1323,16 @@ func (s *EmailService) SendHostedEventUpdatedToHost(ctx context.Context, event *
 	}
 
 	attendeeList := "None"
-	if len(attendees) > 0 {
-		names := make([]string, 0, len(attendees))
-		for _, a := range attendees {
-			if a.Name != "" {
-				names = append(names, fmt.Sprintf("%s <%s>", a.Name, a.Email))
-			} else {
-				names = append(names, a.Email)
-			}
+	attendeeNames := make([]string, 0, len(attendees))
+	for _, a := range attendees {
+		if a.Name != "" {
+			attendeeNames = append(attendeeNames, fmt.Sprintf("%s <%s>", a.Name, a.Email))
+		} else {
+			attendeeNames = append(attendeeNames, a.Email)
 		}
-		attendeeList = strings.Join(names, ", ")
+	}
+	if len(attendeeNames) > 0 {
+		attendeeList = strings.Join(attendeeNames, ", ")
 	}
 
 	body := fmt.Sprintf(`Hello %s,
@@ -1110,13 +1363,12 @@ Meet When`,
 	hostParty := &models.HostedEventAttendee{Email: host.Email, Name: host.Name}
 	ics := s.generateICSForHostedEvent(event, host, hostParty, "REQUEST", "CONFIRMED")
 
-	go func() {
-		if err := s.sendEmail(host.Email, subject, body, ics); err != nil {
-			log.Printf("[EMAIL] Error sending hosted-event update to host %s: %v", host.Email, err)
-		}
-	}()
+	data := hostedEventTemplateData(event, host, host.Name)
+	data.Attendees = attendeeNames
+	data.Changes = strings.Join(changedFields, ", ")
+	html := s.renderHTML(tenant, "hosted_event_updated_host", subject, data, "")
 
-	_ = tenant
+	s.enqueue(ctx, host, &outgoingEmail{To: host.Email, Subject: subject, Text: body, HTML: html, ICS: ics})
 }
 
 // SendHostedEventCancelled notifies an attendee that the entire event was
@@ -1154,13 +1406,9 @@ Meet When`,
 
 	ics := s.generateICSForHostedEvent(event, host, attendee, "CANCEL", "CANCELLED")
 
-	go func() {
-		if err := s.sendEmail(attendee.Email, subject, body, ics); err != nil {
-			log.Printf("[EMAIL] Error sending hosted-event cancellation to %s: %v", attendee.Email, err)
-		}
-	}()
+	html := s.renderHTML(tenant, "hosted_event_cancelled", subject, hostedEventTemplateData(event, host, attendee.Name), "")
 
-	_ = tenant
+	s.enqueue(ctx, host, &outgoingEmail{To: attendee.Email, Subject: subject, Text: body, HTML: html, ICS: ics})
 }
 
 // SendHostedEventCancelledToHost confirms to the host (organizer) that their
@@ -1173,16 +1421,16 @@ func (s *EmailService) SendHostedEventCancelledToHost(ctx context.Context, event
 	subject := fmt.Sprintf("Cancelled: %s", event.Title)
 
 	attendeeList := "None"
-	if len(attendees) > 0 {
-		names := make([]string, 0, len(attendees))
-		for _, a := range attendees {
-			if a.Name != "" {
-				names = append(names, fmt.Sprintf("%s <%s>", a.Name, a.Email))
-			} else {
-				names = append(names, a.Email)
-			}
+	attendeeNames := make([]string, 0, len(attendees))
+	for _, a := range attendees {
+		if a.Name != "" {
+			attendeeNames = append(attendeeNames, fmt.Sprintf("%s <%s>", a.Name, a.Email))
+		} else {
+			attendeeNames = append(attendeeNames, a.Email)
 		}
-		attendeeList = strings.Join(names, ", ")
+	}
+	if len(attendeeNames) > 0 {
+		attendeeList = strings.Join(attendeeNames, ", ")
 	}
 
 	body := fmt.Sprintf(`Hello %s,
@@ -1211,13 +1459,11 @@ Meet When`,
 	hostParty := &models.HostedEventAttendee{Email: host.Email, Name: host.Name}
 	ics := s.generateICSForHostedEvent(event, host, hostParty, "CANCEL", "CANCELLED")
 
-	go func() {
-		if err := s.sendEmail(host.Email, subject, body, ics); err != nil {
-			log.Printf("[EMAIL] Error sending hosted-event cancellation to host %s: %v", host.Email, err)
-		}
-	}()
+	data := hostedEventTemplateData(event, host, host.Name)
+	data.Attendees = attendeeNames
+	html := s.renderHTML(tenant, "hosted_event_cancelled_host", subject, data, "")
 
-	_ = tenant
+	s.enqueue(ctx, host, &outgoingEmail{To: host.Email, Subject: subject, Text: body, HTML: html, ICS: ics})
 }
 
 // SendHostedEventCancelledForAttendee notifies an attendee that they were
@@ -1253,13 +1499,9 @@ Meet When`,
 	// CANCEL ICS scoped to this attendee — removes only their copy.
 	ics := s.generateICSForHostedEvent(event, host, attendee, "CANCEL", "CANCELLED")
 
-	go func() {
-		if err := s.sendEmail(attendee.Email, subject, body, ics); err != nil {
-			log.Printf("[EMAIL] Error sending hosted-event removal to %s: %v", attendee.Email, err)
-		}
-	}()
+	html := s.renderHTML(tenant, "hosted_event_removed", subject, hostedEventTemplateData(event, host, attendee.Name), "")
 
-	_ = tenant
+	s.enqueue(ctx, host, &outgoingEmail{To: attendee.Email, Subject: subject, Text: body, HTML: html, ICS: ics})
 }
 
 // SendHostedEventReminder sends a 24-hour reminder to a single attendee.
@@ -1295,13 +1537,9 @@ Meet When`,
 		hostedEventLocationLabel(event),
 	)
 
-	go func() {
-		if err := s.sendEmail(attendee.Email, subject, body, ""); err != nil {
-			log.Printf("[EMAIL] Error sending hosted-event reminder to %s: %v", attendee.Email, err)
-		}
-	}()
+	html := s.renderHTML(tenant, "hosted_event_reminder", subject, hostedEventTemplateData(event, host, attendee.Name), "")
 
-	_ = tenant
+	s.enqueue(ctx, host, &outgoingEmail{To: attendee.Email, Subject: subject, Text: body, HTML: html})
 }
 
 // generateICSForHostedEvent emits an ICS payload for a hosted event addressed
@@ -1365,16 +1603,3 @@ func escapeICS(s string) string {
 	s = strings.ReplaceAll(s, "\n", "\\n")
 	return s
 }
-
-// HTMLEmail represents an HTML email template
-type HTMLEmail struct {
-	Subject string
-	Body    template.HTML
-}
-
-// RenderHTMLEmail renders an HTML email template
-func (s *EmailService) RenderHTMLEmail(templateName string, data interface{}) (*HTMLEmail, error) {
-	// For MVP, we use plain text emails
-	// HTML email templates can be added later
-	return nil, nil
-}
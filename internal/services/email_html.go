@@ -0,0 +1,281 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// defaultEmailAccent is the app's accent color, used for tenants that
+// haven't picked their own
+const defaultEmailAccent = "#E85D40"
+
+// ErrInvalidBranding is returned when email branding fails validation
+var ErrInvalidBranding = errors.New("invalid email branding")
+
+var accentColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// HTMLEmail is a rendered HTML email
+type HTMLEmail struct {
+	Subject string
+	Body    template.HTML
+}
+
+// EmailBranding is how a tenant's emails look
+type EmailBranding struct {
+	Name        string // shown in place of a logo
+	LogoURL     string
+	AccentColor string
+	Footer      string
+}
+
+// EmailView is what the templates under templates/emails render: the
+// email's data, its subject and the tenant's branding. Custom holds the
+// paragraphs of a host-written body, which replaces the standard content.
+type EmailView struct {
+	*EmailTemplateData
+	Subject string
+	Brand   EmailBranding
+	Custom  [][]string
+}
+
+// EmailPreview names an email type for the dashboard preview
+type EmailPreview struct {
+	Type  string
+	Label string
+}
+
+// EmailPreviews lists every HTML email, in the order the preview page shows
+// them. Type is the template's file name without .html.
+var EmailPreviews = []EmailPreview{
+	{"booking_requested", "Booking request (to host)"},
+	{"booking_confirmed", "Booking confirmed"},
+	{"booking_confirmed_host", "Booking confirmed (to host)"},
+	{"booking_rejected", "Booking declined"},
+	{"booking_rescheduled", "Booking rescheduled"},
+	{"booking_rescheduled_host", "Booking rescheduled (to host)"},
+	{"booking_updated", "Booking updated"},
+	{"booking_cancelled", "Booking cancelled"},
+	{"booking_cancelled_host", "Booking cancelled (to host)"},
+	{"booking_reminder", "Booking reminder"},
+	{"booking_reminder_host", "Booking reminder (to host)"},
+	{"hosted_event_invited", "Event invitation"},
+	{"hosted_event_updated", "Event updated"},
+	{"hosted_event_updated_host", "Event updated (to host)"},
+	{"hosted_event_cancelled", "Event cancelled"},
+	{"hosted_event_cancelled_host", "Event cancelled (to host)"},
+	{"hosted_event_removed", "Removed from event"},
+	{"hosted_event_reminder", "Event reminder"},
+	{"conferencing_failed", "Conference link failed (to host)"},
+	{"calendar_sync_failed", "Calendar sync failed (to host)"},
+}
+
+func emailTemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"dict": func(kvs ...interface{}) map[string]interface{} {
+			m := make(map[string]interface{}, len(kvs)/2)
+			for i := 0; i+1 < len(kvs); i += 2 {
+				if k, ok := kvs[i].(string); ok {
+					m[k] = kvs[i+1]
+				}
+			}
+			return m
+		},
+		"isURL": func(s string) bool {
+			return strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")
+		},
+		"lines": func(s string) []string {
+			return strings.Split(strings.TrimSpace(s), "\n")
+		},
+	}
+}
+
+// loadEmailTemplates parses each email in dir together with the shared
+// layout.html, keyed by file name. Like the page templates, an email that
+// fails to parse is logged and left out; sending it falls back to plain text.
+func loadEmailTemplates(dir string) map[string]*template.Template {
+	templates := make(map[string]*template.Template)
+	layout := filepath.Join(dir, "layout.html")
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.html"))
+	for _, file := range files {
+		name := filepath.Base(file)
+		if name == "layout.html" {
+			continue
+		}
+		tmpl, err := template.New(name).Funcs(emailTemplateFuncs()).ParseFiles(file, layout)
+		if err != nil {
+			log.Printf("Error parsing email template %s: %v", name, err)
+			continue
+		}
+		templates[name] = tmpl
+	}
+	return templates
+}
+
+// brandingFor returns the tenant's email branding with defaults filled in.
+// tenant may be nil.
+func brandingFor(tenant *models.Tenant) EmailBranding {
+	brand := EmailBranding{Name: "Meet When", AccentColor: defaultEmailAccent}
+	if tenant == nil {
+		brand.Footer = "Sent by Meet When."
+		return brand
+	}
+	if tenant.Name != "" {
+		brand.Name = tenant.Name
+	}
+	brand.LogoURL = tenant.LogoURL
+	if tenant.AccentColor != "" {
+		brand.AccentColor = tenant.AccentColor
+	}
+	brand.Footer = tenant.EmailFooter
+	if brand.Footer == "" {
+		brand.Footer = fmt.Sprintf("Sent by Meet When on behalf of %s.", brand.Name)
+	}
+	return brand
+}
+
+// paragraphs splits a plain-text body into paragraphs of lines
+func paragraphs(body string) [][]string {
+	var result [][]string
+	for _, p := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			result = append(result, strings.Split(p, "\n"))
+		}
+	}
+	return result
+}
+
+// RenderHTMLEmail renders the email type name (e.g. "booking_confirmed")
+// with the given view
+func (s *EmailService) RenderHTMLEmail(name string, view *EmailView) (*HTMLEmail, error) {
+	tmpl, ok := s.htmlTemplates[name+".html"]
+	if !ok {
+		return nil, fmt.Errorf("email template %s not found", name)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name+".html", view); err != nil {
+		return nil, err
+	}
+	return &HTMLEmail{Subject: view.Subject, Body: template.HTML(buf.String())}, nil
+}
+
+// renderHTML renders the HTML alternative of an email in the tenant's
+// branding. customBody, when set, is a host-written plain-text body shown
+// in place of the standard content. A failure is logged and returns "", so
+// the email goes out as plain text only.
+func (s *EmailService) renderHTML(tenant *models.Tenant, name, subject string, data *EmailTemplateData, customBody string) string {
+	view := &EmailView{
+		EmailTemplateData: data,
+		Subject:           subject,
+		Brand:             brandingFor(tenant),
+		Custom:            paragraphs(customBody),
+	}
+	email, err := s.RenderHTMLEmail(name, view)
+	if err != nil {
+		log.Printf("[EMAIL] Error rendering %s email: %v", name, err)
+		return ""
+	}
+	return string(email.Body)
+}
+
+// hostTenant returns the tenant a host belongs to, for branding emails that
+// only have the host to go on. A failed lookup is logged and returns nil.
+func (s *EmailService) hostTenant(ctx context.Context, host *models.Host) *models.Tenant {
+	tenant, err := s.repos.Tenant.GetByID(ctx, host.TenantID)
+	if err != nil {
+		log.Printf("[EMAIL] Error loading tenant for host %s: %v", host.ID, err)
+	}
+	return tenant
+}
+
+// UpdateBranding validates and saves a tenant's email branding. The logo
+// must be an http(s) URL and the accent color a #rrggbb hex color; either
+// may be empty to use the default.
+func (s *EmailService) UpdateBranding(ctx context.Context, tenant *models.Tenant, logoURL, accentColor, footer string) error {
+	logoURL = strings.TrimSpace(logoURL)
+	accentColor = strings.TrimSpace(accentColor)
+	footer = strings.TrimSpace(footer)
+
+	if logoURL != "" {
+		u, err := url.Parse(logoURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("%w: logo must be an http or https URL", ErrInvalidBranding)
+		}
+	}
+	if accentColor != "" && !accentColorPattern.MatchString(accentColor) {
+		return fmt.Errorf("%w: accent color must look like #E85D40", ErrInvalidBranding)
+	}
+	if len(footer) > 500 {
+		return fmt.Errorf("%w: footer is limited to 500 characters", ErrInvalidBranding)
+	}
+
+	updated := *tenant
+	updated.LogoURL = logoURL
+	updated.AccentColor = strings.ToUpper(accentColor)
+	updated.EmailFooter = footer
+	if err := s.repos.Tenant.UpdateBranding(ctx, &updated); err != nil {
+		return err
+	}
+	*tenant = updated
+	return nil
+}
+
+// PreviewEmail renders an email type with sample data, as the host would
+// send it, in the tenant's branding
+func (s *EmailService) PreviewEmail(host *models.Host, tenant *models.Tenant, name string) (*HTMLEmail, error) {
+	base := s.cfg.Server.BaseURL
+	data := &EmailTemplateData{
+		RecipientName:   "Alex Morgan",
+		InviteeName:     "Alex Morgan",
+		InviteeEmail:    "alex.morgan@example.com",
+		HostName:        host.Name,
+		MeetingName:     "Intro call",
+		MeetingTime:     "Tuesday, June 3, 2025 at 10:00 AM EDT",
+		PreviousTime:    "Monday, June 2, 2025 at 2:30 PM EDT",
+		TimeUntil:       "tomorrow",
+		Duration:        30,
+		Location:        "https://meet.google.com/abc-defg-hij",
+		CancelLink:      base + "/booking/sample",
+		RescheduleLink:  base + "/m/sample/reschedule",
+		BookingPageLink: base + "/" + tenant.Slug + "/" + host.Slug,
+		DashboardLink:   base + "/dashboard/bookings",
+		Agenda:          "Walk through the proposal and agree next steps.",
+		Notes:           "I've added the draft to the shared folder.",
+		Changes:         "location, notes",
+		Reason:          "Something urgent came up, sorry.",
+		Description:     "Quarterly planning with the wider team.",
+		Attendees:       []string{"Alex Morgan <alex.morgan@example.com>", "sam.lee@example.com"},
+		Provider:        "Zoom",
+		CalendarName:    "Work",
+		ErrorMessage:    "token has been expired or revoked",
+		NeedsReconnect:  true,
+	}
+	switch {
+	case strings.HasSuffix(name, "_host") || name == "booking_requested":
+		data.RecipientName = host.Name
+	case name == "conferencing_failed" || name == "calendar_sync_failed":
+		data.RecipientName = host.Name
+		data.DashboardLink = base + "/dashboard/calendars"
+	case strings.HasPrefix(name, "hosted_event_"):
+		data.MeetingName = "Quarterly planning"
+	}
+
+	label := name
+	for _, p := range EmailPreviews {
+		if p.Type == name {
+			label = p.Label
+		}
+	}
+	view := &EmailView{EmailTemplateData: data, Subject: label, Brand: brandingFor(tenant)}
+	return s.RenderHTMLEmail(name, view)
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/meet-when/meet-when/internal/config"
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+func TestEmailPreviews_CoverEveryTemplate(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Server.BaseURL = "https://meet.example.com"
+	svc := &EmailService{cfg: cfg, htmlTemplates: loadEmailTemplates("../../templates/emails")}
+
+	files, _ := filepath.Glob("../../templates/emails/*.html")
+	if len(svc.htmlTemplates) != len(files)-1 || len(EmailPreviews) != len(svc.htmlTemplates) {
+		t.Fatalf("%d template files, %d parsed, %d previews", len(files)-1, len(svc.htmlTemplates), len(EmailPreviews))
+	}
+
+	host := &models.Host{Name: "Jo Host", Slug: "jo"}
+	tenant := &models.Tenant{
+		Name: "Acme", Slug: "acme",
+		LogoURL: "https://cdn.example.com/logo.png", AccentColor: "#123ABC",
+		EmailFooter: "Acme Ltd\n1 <Main> St",
+	}
+	for _, p := range EmailPreviews {
+		email, err := svc.PreviewEmail(host, tenant, p.Type)
+		if err != nil {
+			t.Errorf("%s: %v", p.Type, err)
+			continue
+		}
+		body := string(email.Body)
+		for _, want := range []string{`src="https://cdn.example.com/logo.png"`, "#123ABC", "Acme Ltd<br>1 &lt;Main&gt; St", "Best regards,<br>Acme"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("%s: body missing %q", p.Type, want)
+			}
+		}
+	}
+}
+
+func TestEmailService_RenderHTML(t *testing.T) {
+	svc := &EmailService{htmlTemplates: loadEmailTemplates("../../templates/emails")}
+	data := &EmailTemplateData{
+		RecipientName: "Ann <b>", HostName: "Jo", MeetingName: "Intro",
+		MeetingTime: "Tuesday, June 3, 2025 at 10:00 AM UTC", Duration: 30,
+		Location: "https://meet.example.com/abc", RescheduleLink: "https://app.example.com/r", CancelLink: "https://app.example.com/c",
+	}
+
+	// No tenant: default branding, standard content
+	html := svc.renderHTML(nil, "booking_confirmed", "Confirmed: Intro", data, "")
+	for _, want := range []string{
+		"<title>Confirmed: Intro</title>", defaultEmailAccent, ">Meet When</span>",
+		"Hello Ann &lt;b&gt;,", `<a href="https://meet.example.com/abc"`, "30 minutes",
+		`href="https://app.example.com/r"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("rendered email missing %q", want)
+		}
+	}
+
+	// A host-written body replaces the standard content, paragraph by paragraph
+	html = svc.renderHTML(nil, "booking_confirmed", "Confirmed", data, "Hi Ann,\nSee you <soon>.\n\nJo")
+	if !strings.Contains(html, "Hi Ann,<br>See you &lt;soon&gt;.</p>") || !strings.Contains(html, ">Jo</p>") {
+		t.Errorf("custom body not rendered as paragraphs:\n%s", html)
+	}
+	if strings.Contains(html, "Your meeting has been confirmed") {
+		t.Error("custom body rendered alongside the standard content")
+	}
+
+	// An unknown template falls back to plain text
+	if html := svc.renderHTML(nil, "no_such_email", "Hi", data, ""); html != "" {
+		t.Errorf("unknown template rendered %q, want empty", html)
+	}
+}
+
+// readMIMEParts returns the content types of a multipart body's parts, and
+// the decoded bodies of its leaf parts keyed by content type, descending
+// into nested multiparts.
+func readMIMEParts(t *testing.T, contentType string, body io.Reader, leaves map[string]string) []string {
+	t.Helper()
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("parse %q: %v", contentType, err)
+	}
+	var types []string
+	mr := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return types
+		}
+		if err != nil {
+			t.Fatalf("next part: %v", err)
+		}
+		partType := part.Header.Get("Content-Type")
+		mediaType, _, _ := mime.ParseMediaType(partType)
+		types = append(types, mediaType)
+		if strings.HasPrefix(mediaType, "multipart/") {
+			types = append(types, readMIMEParts(t, partType, part, leaves)...)
+			continue
+		}
+		data, _ := io.ReadAll(part) // quoted-printable parts are decoded by the reader
+		leaves[mediaType] = string(data)
+	}
+}
+
+func TestEmailService_BuildSMTPMessage(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Email.FromName = "Meet When"
+	cfg.Email.FromAddress = "noreply@example.com"
+	svc := &EmailService{cfg: cfg}
+	longLine := strings.Repeat("<td style=\"padding:0\">x</td>", 60)
+
+	tests := []struct {
+		name  string
+		email *outgoingEmail
+		want  []string
+	}{
+		{"text only", &outgoingEmail{Text: "Hello"}, nil},
+		{"html", &outgoingEmail{Text: "Hello", HTML: longLine}, []string{"text/plain", "text/html"}},
+		{"ics", &outgoingEmail{Text: "Hello", ICS: "BEGIN:VCALENDAR"}, []string{"text/plain", "text/calendar"}},
+		{"html and ics", &outgoingEmail{Text: "Hello", HTML: longLine, ICS: "BEGIN:VCALENDAR"},
+			[]string{"multipart/alternative", "text/plain", "text/html", "text/calendar"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.email.To, tt.email.Subject = "ann@example.com", "Confirmed"
+			raw, err := svc.buildSMTPMessage(tt.email)
+			if err != nil {
+				t.Fatalf("buildSMTPMessage: %v", err)
+			}
+			for _, line := range bytes.Split(raw, []byte("\r\n")) {
+				if len(line) > 998 {
+					t.Fatalf("line of %d characters exceeds SMTP's limit", len(line))
+				}
+			}
+			msg, err := mail.ReadMessage(bytes.NewReader(raw))
+			if err != nil {
+				t.Fatalf("read message: %v", err)
+			}
+			if msg.Header.Get("To") != "ann@example.com" || msg.Header.Get("Subject") != "Confirmed" {
+				t.Errorf("headers = %v", msg.Header)
+			}
+
+			contentType := msg.Header.Get("Content-Type")
+			if tt.want == nil {
+				body, _ := io.ReadAll(msg.Body)
+				if !strings.HasPrefix(contentType, "text/plain") || string(body) != "Hello" {
+					t.Errorf("text-only message: %s %q", contentType, body)
+				}
+				return
+			}
+			leaves := make(map[string]string)
+			got := readMIMEParts(t, contentType, msg.Body, leaves)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("parts = %v, want %v", got, tt.want)
+			}
+			if leaves["text/plain"] != "Hello" {
+				t.Errorf("text part = %q", leaves["text/plain"])
+			}
+			if tt.email.HTML != "" && leaves["text/html"] != longLine {
+				t.Errorf("html part did not round-trip")
+			}
+		})
+	}
+}
+
+func TestEmailService_UpdateBranding(t *testing.T) {
+	_, repos, cleanup := setupTestRepos(t)
+	defer cleanup()
+	ctx := context.Background()
+	svc := NewEmailService(&config.Config{}, repos)
+
+	tenant := &models.Tenant{ID: uuid.New().String(), Slug: "acme-" + uuid.New().String()[:6], Name: "Acme", CreatedAt: models.Now(), UpdatedAt: models.Now()}
+	if err := repos.Tenant.Create(ctx, tenant); err != nil {
+		t.Fatalf("create tenant: %v", err)
+	}
+
+	for _, bad := range []struct{ logo, color string }{
+		{"javascript:alert(1)", ""},
+		{"/logo.png", ""},
+		{"", "red"},
+		{"", "#12345"},
+		{"", "#123456;background:url(x)"},
+	} {
+		if err := svc.UpdateBranding(ctx, tenant, bad.logo, bad.color, ""); !errors.Is(err, ErrInvalidBranding) {
+			t.Errorf("UpdateBranding(%q, %q) = %v, want ErrInvalidBranding", bad.logo, bad.color, err)
+		}
+	}
+	if tenant.LogoURL != "" || tenant.AccentColor != "" {
+		t.Fatalf("rejected branding applied: %+v", tenant)
+	}
+
+	if err := svc.UpdateBranding(ctx, tenant, " https://cdn.example.com/logo.png ", "#12ab3c", "Acme Ltd"); err != nil {
+		t.Fatalf("UpdateBranding: %v", err)
+	}
+	saved, err := repos.Tenant.GetByID(ctx, tenant.ID)
+	if err != nil || saved == nil {
+		t.Fatalf("get tenant: %v", err)
+	}
+	if saved.LogoURL != "https://cdn.example.com/logo.png" || saved.AccentColor != "#12AB3C" || saved.EmailFooter != "Acme Ltd" {
+		t.Errorf("saved branding = %q %q %q", saved.LogoURL, saved.AccentColor, saved.EmailFooter)
+	}
+	if tenant.AccentColor != "#12AB3C" {
+		t.Errorf("tenant not updated in place: %+v", tenant)
+	}
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// ErrInvalidMailgunWebhook is returned for a Mailgun webhook that can't be
+// parsed, whose signature doesn't check out, or that is stale or a replay.
+// With no signing key configured every webhook is rejected.
+var ErrInvalidMailgunWebhook = errors.New("invalid mailgun webhook")
+
+// mailgunWebhookMaxSkew is how far a webhook's signed timestamp may be from
+// now. Older ones are refused as replays; within it, tokens already seen
+// are.
+const mailgunWebhookMaxSkew = 5 * time.Minute
+
+// mailgunTokenCache remembers the tokens of webhooks accepted within
+// mailgunWebhookMaxSkew, so each signed webhook is handled once.
+type mailgunTokenCache struct {
+	mu        sync.Mutex
+	seen      map[string]time.Time // token -> signed timestamp
+	lastSweep time.Time
+}
+
+func newMailgunTokenCache() *mailgunTokenCache {
+	return &mailgunTokenCache{seen: make(map[string]time.Time)}
+}
+
+// claim records token, signed at signedAt, reporting false if it was
+// already recorded. Tokens that have aged out of the window are swept out
+// as new ones arrive.
+func (c *mailgunTokenCache) claim(token string, signedAt, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.seen[token]; ok {
+		return false
+	}
+	c.seen[token] = signedAt
+
+	if now.Sub(c.lastSweep) >= time.Minute {
+		c.lastSweep = now
+		for t, at := range c.seen {
+			if now.Sub(at) > mailgunWebhookMaxSkew {
+				delete(c.seen, t)
+			}
+		}
+	}
+	return true
+}
+
+// release forgets token, so Mailgun's retry of a webhook we failed to
+// handle is accepted.
+func (c *mailgunTokenCache) release(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.seen, token)
+}
+
+// mailgunSendResponse is the messages API's reply to a send.
+type mailgunSendResponse struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+}
+
+// sendMailgun sends an email through the Mailgun messages API, with the HTML
+// body and ICS invite when there are ones, and returns Mailgun's message ID.
+func (s *EmailService) sendMailgun(email *outgoingEmail) (string, error) {
+	var form bytes.Buffer
+	mw := multipart.NewWriter(&form)
+	fields := [][2]string{
+		{"from", fmt.Sprintf("%s <%s>", s.cfg.Email.FromName, s.cfg.Email.FromAddress)},
+		{"to", email.To},
+		{"subject", email.Subject},
+		{"text", email.Text},
+	}
+	if email.HTML != "" {
+		fields = append(fields, [2]string{"html", email.HTML})
+	}
+	for _, field := range fields {
+		if err := mw.WriteField(field[0], field[1]); err != nil {
+			return "", err
+		}
+	}
+	if email.ICS != "" {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", `form-data; name="attachment"; filename="invite.ics"`)
+		header.Set("Content-Type", "text/calendar; charset=utf-8; method=REQUEST")
+		part, err := mw.CreatePart(header)
+		if err != nil {
+			return "", err
+		}
+		if _, err := io.WriteString(part, email.ICS); err != nil {
+			return "", err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return "", err
+	}
+
+	endpoint := strings.TrimRight(s.cfg.Email.MailgunAPIBase, "/") + "/" + url.PathEscape(s.cfg.Email.MailgunDomain) + "/messages"
+	req, err := http.NewRequest(http.MethodPost, endpoint, &form)
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth("api", s.cfg.Email.MailgunAPIKey)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("mailgun: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("mailgun: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	var sent mailgunSendResponse
+	if err := json.NewDecoder(resp.Body).Decode(&sent); err != nil {
+		return "", fmt.Errorf("mailgun: decoding response: %w", err)
+	}
+	return mailgunMessageID(sent.ID), nil
+}
+
+// mailgunMessageID normalizes a Message-Id: the send response wraps it in
+// angle brackets, webhooks don't.
+func mailgunMessageID(id string) string {
+	return strings.Trim(strings.TrimSpace(id), "<>")
+}
+
+// mailgunWebhook is the part of a Mailgun webhook payload we use.
+type mailgunWebhook struct {
+	Signature struct {
+		Timestamp string `json:"timestamp"`
+		Token     string `json:"token"`
+		Signature string `json:"signature"`
+	} `json:"signature"`
+	EventData struct {
+		Event          string `json:"event"`
+		Severity       string `json:"severity"` // failed events: permanent or temporary
+		Reason         string `json:"reason"`
+		DeliveryStatus struct {
+			Message     string `json:"message"`
+			Description string `json:"description"`
+		} `json:"delivery-status"`
+		Message struct {
+			Headers struct {
+				MessageID string `json:"message-id"`
+			} `json:"headers"`
+		} `json:"message"`
+	} `json:"event-data"`
+}
+
+// verifyMailgunSignature checks the webhook's signature: the hex HMAC-SHA256
+// of timestamp and token, keyed with the webhook signing key.
+func (s *EmailService) verifyMailgunSignature(hook *mailgunWebhook) bool {
+	key := s.cfg.Email.MailgunSigningKey
+	if key == "" || hook.Signature.Signature == "" {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(hook.Signature.Timestamp + hook.Signature.Token))
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(hook.Signature.Signature)))
+}
+
+// HandleMailgunWebhook records a delivered, bounced (permanently failed) or
+// complained event against the email it is about. Other events, and events
+// for emails we have no record of, are ignored. A complaint is kept over
+// anything reported after it. Webhooks signed more than
+// mailgunWebhookMaxSkew from now, or whose token was already seen, are
+// refused as replays.
+func (s *EmailService) HandleMailgunWebhook(ctx context.Context, payload []byte) error {
+	var hook mailgunWebhook
+	if err := json.Unmarshal(payload, &hook); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMailgunWebhook, err)
+	}
+	if !s.verifyMailgunSignature(&hook) {
+		return fmt.Errorf("%w: bad signature", ErrInvalidMailgunWebhook)
+	}
+	unix, err := strconv.ParseInt(hook.Signature.Timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: bad timestamp", ErrInvalidMailgunWebhook)
+	}
+	now := time.Now()
+	signedAt := time.Unix(unix, 0)
+	if skew := now.Sub(signedAt); skew > mailgunWebhookMaxSkew || skew < -mailgunWebhookMaxSkew {
+		return fmt.Errorf("%w: timestamp %s is too far from now", ErrInvalidMailgunWebhook, signedAt.UTC().Format(time.RFC3339))
+	}
+	if !s.mailgunTokens.claim(hook.Signature.Token, signedAt, now) {
+		return fmt.Errorf("%w: token already used", ErrInvalidMailgunWebhook)
+	}
+
+	if err := s.recordMailgunEvent(ctx, &hook); err != nil {
+		s.mailgunTokens.release(hook.Signature.Token)
+		return err
+	}
+	return nil
+}
+
+// recordMailgunEvent sets the status a verified webhook reports on the email
+// it is about
+func (s *EmailService) recordMailgunEvent(ctx context.Context, hook *mailgunWebhook) error {
+	event := hook.EventData
+	var status models.EmailMessageStatus
+	detail := ""
+	switch {
+	case event.Event == "delivered":
+		status = models.EmailMessageStatusDelivered
+	case event.Event == "failed" && event.Severity == "permanent":
+		status = models.EmailMessageStatusBounced
+		detail = event.DeliveryStatus.Description
+		if detail == "" {
+			detail = event.DeliveryStatus.Message
+		}
+		if detail == "" {
+			detail = event.Reason
+		}
+	case event.Event == "complained":
+		status = models.EmailMessageStatusComplained
+	default:
+		return nil
+	}
+
+	messageID := mailgunMessageID(event.Message.Headers.MessageID)
+	if messageID == "" {
+		return nil
+	}
+	msg, err := s.repos.EmailMessage.GetByProviderMessageID(ctx, "mailgun", messageID)
+	if err != nil {
+		return err
+	}
+	if msg == nil {
+		log.Printf("Mailgun %s event for unknown message %s", event.Event, messageID)
+		return nil
+	}
+	if msg.Status == models.EmailMessageStatusComplained {
+		return nil
+	}
+	return s.repos.EmailMessage.UpdateStatus(ctx, msg.ID, status, detail)
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-when/meet-when/internal/config"
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// mailgunStandIn is an httptest server answering the messages API the way
+// Mailgun does, keeping what it was sent.
+type mailgunStandIn struct {
+	*httptest.Server
+	path       string
+	user, key  string
+	fields     map[string]string
+	attachment string
+}
+
+func newMailgunStandIn(t *testing.T) *mailgunStandIn {
+	t.Helper()
+	mg := &mailgunStandIn{fields: make(map[string]string)}
+	mg.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mg.path = r.URL.Path
+		mg.user, mg.key, _ = r.BasicAuth()
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for name, values := range r.MultipartForm.Value {
+			mg.fields[name] = values[0]
+		}
+		if files := r.MultipartForm.File["attachment"]; len(files) == 1 {
+			f, _ := files[0].Open()
+			data, _ := io.ReadAll(f)
+			f.Close()
+			mg.attachment = files[0].Filename + ":" + files[0].Header.Get("Content-Type") + ":" + string(data)
+		}
+		if mg.fields["to"] == "reject@example.com" {
+			http.Error(w, `{"message": "'to' parameter is not a valid address"}`, http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id": "<20250602.1@mg.example.com>", "message": "Queued. Thank you."})
+	}))
+	t.Cleanup(mg.Close)
+	return mg
+}
+
+func mailgunTestConfig(apiBase string) *config.Config {
+	cfg := &config.Config{}
+	cfg.Email.Provider = "mailgun"
+	cfg.Email.FromAddress = "noreply@mg.example.com"
+	cfg.Email.FromName = "Meet When"
+	cfg.Email.MailgunDomain = "mg.example.com"
+	cfg.Email.MailgunAPIKey = "key-123"
+	cfg.Email.MailgunAPIBase = apiBase
+	cfg.Email.MailgunSigningKey = "signing-key"
+	return cfg
+}
+
+// signedMailgunEvent builds a webhook payload signed with key just now,
+// with a token of its own.
+func signedMailgunEvent(key, event, severity, messageID string) []byte {
+	return signMailgunEvent(key, time.Now(), uuid.New().String(), event, severity, messageID)
+}
+
+// signMailgunEvent builds a webhook payload signed with key at signedAt.
+func signMailgunEvent(key string, signedAt time.Time, token, event, severity, messageID string) []byte {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(timestamp + token))
+	payload := map[string]interface{}{
+		"signature": map[string]string{
+			"timestamp": timestamp,
+			"token":     token,
+			"signature": hex.EncodeToString(mac.Sum(nil)),
+		},
+		"event-data": map[string]interface{}{
+			"event":           event,
+			"severity":        severity,
+			"delivery-status": map[string]string{"description": "Mailbox does not exist"},
+			"message":         map[string]interface{}{"headers": map[string]string{"message-id": messageID}},
+		},
+	}
+	data, _ := json.Marshal(payload)
+	return data
+}
+
+func TestEmailService_SendMailgun(t *testing.T) {
+	_, repos, cleanup := setupTestRepos(t)
+	defer cleanup()
+	ctx := context.Background()
+	mg := newMailgunStandIn(t)
+	svc := NewEmailService(mailgunTestConfig(mg.URL+"/v3"), repos)
+
+	if err := svc.sendEmail(&outgoingEmail{To: "ann@example.com", Subject: "Confirmed: Intro", Text: "See you then", HTML: "<p>See you then</p>", ICS: "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"}); err != nil {
+		t.Fatalf("sendEmail: %v", err)
+	}
+	if mg.path != "/v3/mg.example.com/messages" || mg.user != "api" || mg.key != "key-123" {
+		t.Errorf("request to %s as %s:%s", mg.path, mg.user, mg.key)
+	}
+	if mg.fields["from"] != "Meet When <noreply@mg.example.com>" || mg.fields["to"] != "ann@example.com" ||
+		mg.fields["subject"] != "Confirmed: Intro" || mg.fields["text"] != "See you then" || mg.fields["html"] != "<p>See you then</p>" {
+		t.Errorf("fields = %v", mg.fields)
+	}
+	if !strings.HasPrefix(mg.attachment, "invite.ics:text/calendar; charset=utf-8; method=REQUEST:BEGIN:VCALENDAR") {
+		t.Errorf("attachment = %q", mg.attachment)
+	}
+
+	msg, err := repos.EmailMessage.GetByProviderMessageID(ctx, "mailgun", "20250602.1@mg.example.com")
+	if err != nil || msg == nil {
+		t.Fatalf("sent email not recorded: %v", err)
+	}
+	if msg.Recipient != "ann@example.com" || msg.Status != models.EmailMessageStatusSent {
+		t.Errorf("recorded %+v", msg)
+	}
+
+	if err := svc.sendEmail(&outgoingEmail{To: "reject@example.com", Subject: "Hi", Text: "Hello"}); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("rejected send: err = %v, want the API's 400", err)
+	}
+}
+
+func TestEmailService_HandleMailgunWebhook(t *testing.T) {
+	_, repos, cleanup := setupTestRepos(t)
+	defer cleanup()
+	ctx := context.Background()
+	mg := newMailgunStandIn(t)
+	svc := NewEmailService(mailgunTestConfig(mg.URL), repos)
+
+	if err := svc.sendEmail(&outgoingEmail{To: "ann@example.com", Subject: "Confirmed: Intro", Text: "See you then"}); err != nil {
+		t.Fatalf("sendEmail: %v", err)
+	}
+	const messageID = "20250602.1@mg.example.com"
+	status := func() (models.EmailMessageStatus, string) {
+		t.Helper()
+		msg, err := repos.EmailMessage.GetByProviderMessageID(ctx, "mailgun", messageID)
+		if err != nil || msg == nil {
+			t.Fatalf("get email: %v", err)
+		}
+		return msg.Status, msg.StatusDetail
+	}
+
+	for _, payload := range [][]byte{
+		signedMailgunEvent("wrong-key", "delivered", "", messageID),
+		[]byte(`{"signature": `),
+	} {
+		if err := svc.HandleMailgunWebhook(ctx, payload); !errors.Is(err, ErrInvalidMailgunWebhook) {
+			t.Errorf("HandleMailgunWebhook: err = %v, want ErrInvalidMailgunWebhook", err)
+		}
+	}
+	if got, _ := status(); got != models.EmailMessageStatusSent {
+		t.Fatalf("unverified webhook changed status to %s", got)
+	}
+
+	// A temporary failure is Mailgun retrying, not a bounce
+	for _, step := range []struct {
+		event, severity string
+		want            models.EmailMessageStatus
+	}{
+		{"failed", "temporary", models.EmailMessageStatusSent},
+		{"delivered", "", models.EmailMessageStatusDelivered},
+		{"failed", "permanent", models.EmailMessageStatusBounced},
+		{"complained", "", models.EmailMessageStatusComplained},
+		{"delivered", "", models.EmailMessageStatusComplained},
+	} {
+		if err := svc.HandleMailgunWebhook(ctx, signedMailgunEvent("signing-key", step.event, step.severity, messageID)); err != nil {
+			t.Fatalf("%s/%s: %v", step.event, step.severity, err)
+		}
+		if got, _ := status(); got != step.want {
+			t.Errorf("after %s/%s status = %s, want %s", step.event, step.severity, got, step.want)
+		}
+	}
+
+	if err := svc.HandleMailgunWebhook(ctx, signedMailgunEvent("signing-key", "delivered", "", "unknown@mg.example.com")); err != nil {
+		t.Errorf("event for an unknown message: %v", err)
+	}
+}
+
+func TestEmailService_HandleMailgunWebhookRefusesReplays(t *testing.T) {
+	_, repos, cleanup := setupTestRepos(t)
+	defer cleanup()
+	ctx := context.Background()
+	mg := newMailgunStandIn(t)
+	svc := NewEmailService(mailgunTestConfig(mg.URL), repos)
+
+	const messageID = "20250602.1@mg.example.com"
+	for name, signedAt := range map[string]time.Time{
+		"stale":  time.Now().Add(-mailgunWebhookMaxSkew - time.Minute),
+		"future": time.Now().Add(mailgunWebhookMaxSkew + time.Minute),
+	} {
+		payload := signMailgunEvent("signing-key", signedAt, uuid.New().String(), "delivered", "", messageID)
+		if err := svc.HandleMailgunWebhook(ctx, payload); !errors.Is(err, ErrInvalidMailgunWebhook) {
+			t.Errorf("%s timestamp: err = %v, want ErrInvalidMailgunWebhook", name, err)
+		}
+	}
+
+	payload := signMailgunEvent("signing-key", time.Now().Add(-time.Minute), "token-abc", "delivered", "", messageID)
+	if err := svc.HandleMailgunWebhook(ctx, payload); err != nil {
+		t.Fatalf("first delivery: %v", err)
+	}
+	if err := svc.HandleMailgunWebhook(ctx, payload); !errors.Is(err, ErrInvalidMailgunWebhook) {
+		t.Errorf("replayed webhook: err = %v, want ErrInvalidMailgunWebhook", err)
+	}
+}
+
+func TestMailgunTokenCache_SweepsExpiredTokens(t *testing.T) {
+	c := newMailgunTokenCache()
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	if !c.claim("a", now, now) || c.claim("a", now, now) {
+		t.Fatal("a token was not claimed exactly once")
+	}
+	later := now.Add(mailgunWebhookMaxSkew + time.Minute)
+	if !c.claim("b", later, later) {
+		t.Fatal("b not claimed")
+	}
+	if _, ok := c.seen["a"]; ok || len(c.seen) != 1 {
+		t.Errorf("seen after sweep = %v; want just b", c.seen)
+	}
+
+	c.release("b")
+	if !c.claim("b", later, later) {
+		t.Error("released token not claimable again")
+	}
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/meet-when/meet-when/internal/models"
+	"github.com/meet-when/meet-when/internal/repository"
+)
+
+const (
+	// outboxMaxAttempts is how many times an email is tried before it's dead
+	outboxMaxAttempts = 8
+	// outboxBaseBackoff is the wait after the first failed attempt; each
+	// further failure doubles it, up to outboxMaxBackoff
+	outboxBaseBackoff = time.Minute
+	outboxMaxBackoff  = 2 * time.Hour
+	// outboxLease is how long a claimed email is left to its worker before
+	// another may try it
+	outboxLease = 5 * time.Minute
+	// outboxBatchSize is how many due emails one pass sends
+	outboxBatchSize = 50
+)
+
+// ErrOutboxEmailNotFound is returned when resending an email that isn't a
+// dead email the caller can see
+var ErrOutboxEmailNotFound = errors.New("failed email not found")
+
+// outboxBackoff returns the wait before the next attempt after attempts
+// failed ones
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxBaseBackoff
+	for i := 1; i < attempts && backoff < outboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, outboxMaxBackoff)
+}
+
+// enqueue puts an email in the outbox for the outbox worker to send, on
+// behalf of host (who may be nil). If the outbox can't take it, the email
+// is sent directly instead, as it was before there was an outbox.
+func (s *EmailService) enqueue(ctx context.Context, host *models.Host, email *outgoingEmail) {
+	now := models.Now()
+	row := &models.OutboxEmail{
+		ID:            uuid.New().String(),
+		Recipient:     email.To,
+		Subject:       email.Subject,
+		TextBody:      email.Text,
+		HTMLBody:      email.HTML,
+		ICS:           email.ICS,
+		Status:        models.OutboxEmailStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if host != nil {
+		row.TenantID, row.HostID = &host.TenantID, &host.ID
+	}
+
+	// The email outlives the request that sent it
+	if err := s.repos.EmailOutbox.Create(context.WithoutCancel(ctx), row); err != nil {
+		log.Printf("[EMAIL] Error queueing email to %s, sending directly: %v", email.To, err)
+		go func() {
+			if err := s.sendEmail(email); err != nil {
+				log.Printf("[EMAIL] Error sending email to %s: %v", email.To, err)
+			}
+		}()
+		return
+	}
+
+	// Wake the worker; if it's already due to run, it will pick this up
+	select {
+	case s.outboxWake <- struct{}{}:
+	default:
+	}
+}
+
+// EmailOutboxService sends the emails in the outbox in the background,
+// retrying failures with exponential backoff until they go dead
+type EmailOutboxService struct {
+	repos    *repository.Repositories
+	email    *EmailService
+	interval time.Duration
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewEmailOutboxService creates a new outbox worker
+func NewEmailOutboxService(repos *repository.Repositories, email *EmailService) *EmailOutboxService {
+	return &EmailOutboxService{
+		repos:    repos,
+		email:    email,
+		interval: 30 * time.Second, // Retries come due between enqueues
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start begins the background sending loop
+func (s *EmailOutboxService) Start() {
+	s.wg.Add(1)
+	go s.run()
+	log.Printf("[OUTBOX] Service started, checking every %v", s.interval)
+}
+
+// Stop stops the background sending loop once the current pass is done
+func (s *EmailOutboxService) Stop() {
+	close(s.stopCh)
+	s.wg.Wait()
+	log.Printf("[OUTBOX] Service stopped")
+}
+
+func (s *EmailOutboxService) run() {
+	defer s.wg.Done()
+
+	// Run immediately on startup, for whatever was queued before a restart
+	s.sendDue(context.Background())
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.sendDue(context.Background())
+		case <-s.email.outboxWake:
+			s.sendDue(context.Background())
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// sendDue sends the emails that are due, a batch at a time until none are
+// left, and returns how many went out
+func (s *EmailOutboxService) sendDue(ctx context.Context) int {
+	sent := 0
+	for {
+		now := time.Now().UTC()
+		due, err := s.repos.EmailOutbox.GetDue(ctx, now, outboxBatchSize)
+		if err != nil {
+			log.Printf("[OUTBOX] Error loading due emails: %v", err)
+			return sent
+		}
+		for _, e := range due {
+			if s.attempt(ctx, e, now) {
+				sent++
+			}
+		}
+		if len(due) < outboxBatchSize {
+			return sent
+		}
+	}
+}
+
+// attempt claims and tries to send one email, then deletes it if it went
+// out, schedules a retry if it didn't, or marks it dead after the last
+// attempt. It reports whether the email was sent.
+func (s *EmailOutboxService) attempt(ctx context.Context, e *models.OutboxEmail, now time.Time) bool {
+	claimed, err := s.repos.EmailOutbox.Claim(ctx, e.ID, now, now.Add(outboxLease))
+	if err != nil {
+		log.Printf("[OUTBOX] Error claiming email %s: %v", e.ID, err)
+		return false
+	}
+	if !claimed {
+		return false
+	}
+
+	sendErr := s.email.sendEmail(&outgoingEmail{
+		To:      e.Recipient,
+		Subject: e.Subject,
+		Text:    e.TextBody,
+		HTML:    e.HTMLBody,
+		ICS:     e.ICS,
+	})
+	if sendErr == nil {
+		if err := s.repos.EmailOutbox.Delete(ctx, e.ID); err != nil {
+			// Left in place, it would be sent again once the lease runs out
+			log.Printf("[OUTBOX] Error removing sent email %s: %v", e.ID, err)
+		}
+		return true
+	}
+
+	attempts := e.Attempts + 1
+	if attempts >= outboxMaxAttempts {
+		log.Printf("[OUTBOX] Giving up on email to %s after %d attempts: %v", e.Recipient, attempts, sendErr)
+		if err := s.repos.EmailOutbox.MarkDead(ctx, e.ID, attempts, sendErr.Error()); err != nil {
+			log.Printf("[OUTBOX] Error marking email %s dead: %v", e.ID, err)
+		}
+		return false
+	}
+	next := time.Now().UTC().Add(outboxBackoff(attempts))
+	log.Printf("[OUTBOX] Error sending email to %s (attempt %d), retrying at %s: %v",
+		e.Recipient, attempts, next.Format(time.RFC3339), sendErr)
+	if err := s.repos.EmailOutbox.Retry(ctx, e.ID, attempts, next, sendErr.Error()); err != nil {
+		log.Printf("[OUTBOX] Error scheduling retry of email %s: %v", e.ID, err)
+	}
+	return false
+}
+
+// GetFailed returns a tenant's emails that failed every attempt. With hostID
+// set, only the ones sent for that host.
+func (s *EmailOutboxService) GetFailed(ctx context.Context, tenantID, hostID string) ([]*models.OutboxEmail, error) {
+	return s.repos.EmailOutbox.GetDead(ctx, tenantID, hostID)
+}
+
+// CountFailed returns how many emails GetFailed would return
+func (s *EmailOutboxService) CountFailed(ctx context.Context, tenantID, hostID string) (int, error) {
+	return s.repos.EmailOutbox.CountDead(ctx, tenantID, hostID)
+}
+
+// Resend puts a failed email back in the outbox to be sent now. The email
+// must be one of the tenant's and, with hostID set, that host's.
+func (s *EmailOutboxService) Resend(ctx context.Context, tenantID, hostID, id string) (*models.OutboxEmail, error) {
+	e, err := s.repos.EmailOutbox.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil || e.Status != models.OutboxEmailStatusDead ||
+		e.TenantID == nil || *e.TenantID != tenantID ||
+		(hostID != "" && (e.HostID == nil || *e.HostID != hostID)) {
+		return nil, ErrOutboxEmailNotFound
+	}
+	if err := s.repos.EmailOutbox.Requeue(ctx, id); err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.email.outboxWake <- struct{}{}:
+	default:
+	}
+	return e, nil
+}
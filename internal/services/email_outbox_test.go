@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+func TestOutboxBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{4, 8 * time.Minute},
+		{7, 64 * time.Minute},
+		{8, 2 * time.Hour},
+		{20, 2 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := outboxBackoff(tt.attempts); got != tt.want {
+			t.Errorf("outboxBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestEmailOutbox_RetryDeadAndResend(t *testing.T) {
+	_, repos, cleanup := setupTestRepos(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	tenant := &models.Tenant{ID: uuid.New().String(), Slug: "acme-" + uuid.New().String()[:6], Name: "Acme", CreatedAt: models.Now(), UpdatedAt: models.Now()}
+	if err := repos.Tenant.Create(ctx, tenant); err != nil {
+		t.Fatalf("create tenant: %v", err)
+	}
+	host := &models.Host{
+		ID: uuid.New().String(), TenantID: tenant.ID,
+		Email: "h-" + uuid.New().String()[:4] + "@example.com", PasswordHash: "x",
+		Name: "Jo Host", Slug: "jo-" + uuid.New().String()[:6],
+		Timezone: "UTC", CreatedAt: models.Now(), UpdatedAt: models.Now(),
+	}
+	if err := repos.Host.Create(ctx, host); err != nil {
+		t.Fatalf("create host: %v", err)
+	}
+
+	mg := newMailgunStandIn(t)
+	email := NewEmailService(mailgunTestConfig(mg.URL), repos)
+	outbox := NewEmailOutboxService(repos, email)
+
+	email.enqueue(ctx, host, &outgoingEmail{To: "ann@example.com", Subject: "Confirmed", Text: "Hello"})
+	email.enqueue(ctx, host, &outgoingEmail{To: "reject@example.com", Subject: "Confirmed", Text: "Hello"})
+
+	// The good address goes out and leaves the outbox; the bad one waits
+	if sent := outbox.sendDue(ctx); sent != 1 {
+		t.Fatalf("first pass sent %d, want 1", sent)
+	}
+	left, err := repos.EmailOutbox.GetDue(ctx, time.Now().UTC().Add(time.Hour), 10)
+	if err != nil || len(left) != 1 {
+		t.Fatalf("left in outbox: %d (%v), want 1", len(left), err)
+	}
+	failed := left[0]
+	if failed.Recipient != "reject@example.com" || failed.Attempts != 1 || failed.LastError == "" {
+		t.Errorf("after one failure: %+v", failed)
+	}
+	if wait := time.Until(failed.NextAttemptAt.Time); wait < 50*time.Second || wait > outboxBaseBackoff {
+		t.Errorf("retry in %v, want about %v", wait, outboxBaseBackoff)
+	}
+	if sent := outbox.sendDue(ctx); sent != 0 {
+		t.Errorf("retry sent %d before it was due", sent)
+	}
+
+	// The last attempt fails too, and the email goes dead
+	if err := repos.EmailOutbox.Retry(ctx, failed.ID, outboxMaxAttempts-1, time.Now().UTC().Add(-time.Second), "earlier"); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	outbox.sendDue(ctx)
+	dead, err := repos.EmailOutbox.GetByID(ctx, failed.ID)
+	if err != nil || dead == nil || dead.Status != models.OutboxEmailStatusDead || dead.Attempts != outboxMaxAttempts {
+		t.Fatalf("after last attempt: %+v (%v)", dead, err)
+	}
+	for _, scope := range []struct {
+		hostID string
+		want   int
+	}{{"", 1}, {host.ID, 1}, {uuid.New().String(), 0}} {
+		if n, _ := outbox.CountFailed(ctx, tenant.ID, scope.hostID); n != scope.want {
+			t.Errorf("CountFailed(host %q) = %d, want %d", scope.hostID, n, scope.want)
+		}
+	}
+
+	// Resend is scoped to the tenant and, when given, the host
+	if _, err := outbox.Resend(ctx, uuid.New().String(), "", failed.ID); !errors.Is(err, ErrOutboxEmailNotFound) {
+		t.Errorf("other tenant resend = %v, want ErrOutboxEmailNotFound", err)
+	}
+	if _, err := outbox.Resend(ctx, tenant.ID, uuid.New().String(), failed.ID); !errors.Is(err, ErrOutboxEmailNotFound) {
+		t.Errorf("other host resend = %v, want ErrOutboxEmailNotFound", err)
+	}
+	if _, err := outbox.Resend(ctx, tenant.ID, host.ID, failed.ID); err != nil {
+		t.Fatalf("resend: %v", err)
+	}
+	requeued, _ := repos.EmailOutbox.GetByID(ctx, failed.ID)
+	if requeued.Status != models.OutboxEmailStatusPending || requeued.Attempts != 0 || requeued.NextAttemptAt.After(time.Now().UTC()) {
+		t.Errorf("after resend: %+v", requeued)
+	}
+	if _, err := outbox.Resend(ctx, tenant.ID, "", failed.ID); !errors.Is(err, ErrOutboxEmailNotFound) {
+		t.Errorf("resending a pending email = %v, want ErrOutboxEmailNotFound", err)
+	}
+}
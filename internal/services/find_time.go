@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// Find-a-time suggests slots for a hosted event that suit the organizer and
+// the attendees who are hosts in the same tenant. Every participant must be
+// within their working hours; among those slots, the ones that leave the
+// fewest participants double-booked and strand the least free time rank
+// first.
+
+const (
+	// findTimeMaxDays is how far ahead FindTimes looks at most.
+	findTimeMaxDays = 14
+	// findTimeStep is the grid suggestions start on.
+	findTimeStep = 15 * time.Minute
+	// strandedGapLimit is the shortest free gap worth keeping; a slot that
+	// leaves less than this before or after it wastes the gap.
+	strandedGapLimit = 30 * time.Minute
+	// busyPenalty outweighs any amount of stranded time, so a slot with one
+	// participant double-booked always ranks below one with none.
+	busyPenalty = 24 * 60
+	// perDaySuggestions caps suggestions on any one day so they spread out.
+	perDaySuggestions = 2
+)
+
+// ErrInvalidFindTime is returned when a find-a-time request has no
+// duration or an empty date range.
+var ErrInvalidFindTime = errors.New("find a time needs a duration and a date range")
+
+// FindTimesInput asks for times a hosted event could take place.
+type FindTimesInput struct {
+	HostID         string
+	TenantID       string
+	AttendeeEmails []string // attendees that are hosts in the tenant take part
+	Duration       int      // minutes
+	From           time.Time
+	To             time.Time
+	Timezone       string // groups suggestions into days; defaults to UTC
+	ExcludeEventID string // the event being edited, so it isn't its own conflict
+	Limit          int
+}
+
+// TimeSuggestion is a candidate time for a hosted event.
+type TimeSuggestion struct {
+	Start time.Time
+	End   time.Time
+	// Busy are the participants who already have something on then.
+	Busy []*models.Host
+	// Disruption is the ranking score: lower is better.
+	Disruption int
+}
+
+// FindTimesResult holds the ranked suggestions and who they were worked
+// out for, organizer first.
+type FindTimesResult struct {
+	Participants []*models.Host
+	Suggestions  []TimeSuggestion
+}
+
+// findTimeParticipant is a host's working time and busy time in the range.
+type findTimeParticipant struct {
+	host    *models.Host
+	working []models.TimeSlot
+	busy    []models.TimeSlot
+}
+
+// FindTimes ranks slots between From and To when the organizer and the
+// attendees who are colleagues in the same tenant are all working. Slots
+// where someone is busy are still offered, ranked below all free ones, so
+// there is something to pick when calendars are full.
+func (s *HostedEventService) FindTimes(ctx context.Context, input FindTimesInput) (*FindTimesResult, error) {
+	if input.Duration <= 0 || !input.From.Before(input.To) {
+		return nil, ErrInvalidFindTime
+	}
+	if maxTo := input.From.AddDate(0, 0, findTimeMaxDays); input.To.After(maxTo) {
+		input.To = maxTo
+	}
+	if input.Limit <= 0 {
+		input.Limit = 5
+	}
+	loc, err := time.LoadLocation(input.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	hosts, err := s.findTimeHosts(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	participants := make([]findTimeParticipant, 0, len(hosts))
+	for _, host := range hosts {
+		working, err := s.availability.WorkingWindows(ctx, host.ID, input.From, input.To)
+		if err != nil {
+			return nil, err
+		}
+		busy, err := s.DetectBusyConflicts(ctx, host.ID, input.From, input.To, input.ExcludeEventID)
+		if err != nil {
+			return nil, err
+		}
+		participants = append(participants, findTimeParticipant{host: host, working: working, busy: mergeTimeSlots(busy)})
+	}
+
+	common := participants[0].working
+	for _, p := range participants[1:] {
+		common = overlapWindows(common, p.working)
+	}
+
+	duration := time.Duration(input.Duration) * time.Minute
+	grid := slotGrid{interval: findTimeStep, align: true}
+	var candidates []TimeSuggestion
+	for _, window := range common {
+		for start := grid.first(window.Start.In(loc)); !start.Add(duration).After(window.End); start = grid.next(start) {
+			candidates = append(candidates, scoreFindTime(participants, start.UTC(), start.Add(duration).UTC()))
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Disruption != candidates[j].Disruption {
+			return candidates[i].Disruption < candidates[j].Disruption
+		}
+		return candidates[i].Start.Before(candidates[j].Start)
+	})
+
+	result := &FindTimesResult{Participants: hosts}
+	perDay := make(map[string]int)
+	for _, c := range candidates {
+		if len(result.Suggestions) == input.Limit {
+			break
+		}
+		day := c.Start.In(loc).Format("2006-01-02")
+		if perDay[day] == perDaySuggestions || slices.ContainsFunc(result.Suggestions, func(picked TimeSuggestion) bool {
+			return picked.Start.Before(c.End) && c.Start.Before(picked.End)
+		}) {
+			continue
+		}
+		perDay[day]++
+		result.Suggestions = append(result.Suggestions, c)
+	}
+	return result, nil
+}
+
+// findTimeHosts returns the organizer followed by the hosts in the tenant
+// whose email is among the attendees.
+func (s *HostedEventService) findTimeHosts(ctx context.Context, input FindTimesInput) ([]*models.Host, error) {
+	tenantHosts, err := s.repos.Host.GetByTenantID(ctx, input.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	var organizer *models.Host
+	var colleagues []*models.Host
+	for _, h := range tenantHosts {
+		if h.ID == input.HostID {
+			organizer = h
+			continue
+		}
+		for _, email := range input.AttendeeEmails {
+			if strings.EqualFold(strings.TrimSpace(email), h.Email) {
+				colleagues = append(colleagues, h)
+				break
+			}
+		}
+	}
+	if organizer == nil {
+		return nil, errors.New("host not found")
+	}
+	return append([]*models.Host{organizer}, colleagues...), nil
+}
+
+// scoreFindTime works out who is busy between start and end and how
+// disruptive the slot is: busyPenalty for each busy participant plus the
+// minutes of free time it strands for everyone else.
+func scoreFindTime(participants []findTimeParticipant, start, end time.Time) TimeSuggestion {
+	suggestion := TimeSuggestion{Start: start, End: end}
+	for _, p := range participants {
+		if slotOverlapsBusy(start, end, p.busy) {
+			suggestion.Busy = append(suggestion.Busy, p.host)
+			suggestion.Disruption += busyPenalty
+			continue
+		}
+		for _, window := range p.working {
+			if start.Before(window.Start) || end.After(window.End) {
+				continue
+			}
+			freeStart, freeEnd := window.Start, window.End
+			for _, b := range p.busy {
+				if !b.End.After(start) && b.End.After(freeStart) {
+					freeStart = b.End
+				}
+				if !b.Start.Before(end) && b.Start.Before(freeEnd) {
+					freeEnd = b.Start
+				}
+			}
+			suggestion.Disruption += strandedMinutes(start.Sub(freeStart)) + strandedMinutes(freeEnd.Sub(end))
+			break
+		}
+	}
+	return suggestion
+}
+
+// strandedMinutes is the length of a free gap in minutes when it is too
+// short to be of use, and zero otherwise.
+func strandedMinutes(gap time.Duration) int {
+	if gap <= 0 || gap >= strandedGapLimit {
+		return 0
+	}
+	return int(gap / time.Minute)
+}
+
+// overlapWindows returns the time covered by both a and b. Both must be
+// sorted and merged, as mergeTimeSlots leaves them.
+func overlapWindows(a, b []models.TimeSlot) []models.TimeSlot {
+	var out []models.TimeSlot
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		start, end := a[i].Start, a[i].End
+		if b[j].Start.After(start) {
+			start = b[j].Start
+		}
+		if b[j].End.Before(end) {
+			end = b[j].End
+		}
+		if start.Before(end) {
+			out = append(out, models.TimeSlot{Start: start, End: end})
+		}
+		if a[i].End.Before(b[j].End) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return out
+}
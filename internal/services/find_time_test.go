@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+func TestOverlapWindows(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2025, 6, 2, h, m, 0, 0, time.UTC) }
+	a := []models.TimeSlot{{Start: at(9, 0), End: at(12, 0)}, {Start: at(13, 0), End: at(17, 0)}}
+	b := []models.TimeSlot{{Start: at(10, 0), End: at(14, 0)}, {Start: at(16, 30), End: at(18, 0)}}
+
+	got := overlapWindows(a, b)
+	want := []models.TimeSlot{
+		{Start: at(10, 0), End: at(12, 0)},
+		{Start: at(13, 0), End: at(14, 0)},
+		{Start: at(16, 30), End: at(17, 0)},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("overlapWindows = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Start.Equal(want[i].Start) || !got[i].End.Equal(want[i].End) {
+			t.Errorf("window %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestHostedEventFindTimes_IntersectsColleagues(t *testing.T) {
+	h := makeHostedEventHarness(t)
+	defer h.cleanup()
+	ctx := context.Background()
+
+	colleague := &models.Host{
+		ID: uuid.New().String(), TenantID: h.tenant.ID,
+		Email: "ana-" + uuid.New().String()[:4] + "@example.com", PasswordHash: "x",
+		Name: "Ana", Slug: "ana-" + uuid.New().String()[:6],
+		Timezone: "UTC", CreatedAt: models.Now(), UpdatedAt: models.Now(),
+	}
+	if err := h.repos.Host.Create(ctx, colleague); err != nil {
+		t.Fatalf("create colleague: %v", err)
+	}
+	setHours := func(hostID, from, to string) {
+		var hours []*models.WorkingHours
+		for day := 0; day < 7; day++ {
+			hours = append(hours, &models.WorkingHours{ID: uuid.New().String(), HostID: hostID, DayOfWeek: day, StartTime: from, EndTime: to, IsEnabled: true})
+		}
+		if err := h.repos.WorkingHours.SetForHost(ctx, hostID, hours); err != nil {
+			t.Fatalf("set working hours: %v", err)
+		}
+	}
+	setHours(h.host.ID, "09:00", "12:00")
+	setHours(colleague.ID, "10:00", "17:00")
+
+	day := time.Now().UTC().AddDate(0, 0, 3).Truncate(24 * time.Hour)
+	busy := &models.HostedEvent{
+		ID: uuid.New().String(), TenantID: h.tenant.ID, HostID: colleague.ID, Title: "Standup",
+		StartTime: models.NewSQLiteTime(day.Add(10 * time.Hour)), EndTime: models.NewSQLiteTime(day.Add(10*time.Hour + 45*time.Minute)),
+		Duration: 45, Timezone: "UTC", LocationType: models.ConferencingProviderGoogleMeet,
+		Status: models.HostedEventStatusScheduled, CreatedAt: models.Now(), UpdatedAt: models.Now(),
+	}
+	if err := h.repos.HostedEvent.Create(ctx, busy); err != nil {
+		t.Fatalf("create colleague event: %v", err)
+	}
+
+	result, err := h.svc.FindTimes(ctx, FindTimesInput{
+		HostID: h.host.ID, TenantID: h.tenant.ID,
+		AttendeeEmails: []string{strings.ToUpper(colleague.Email), "client@example.com"},
+		Duration:       30, From: day, To: day.Add(24 * time.Hour), Timezone: "UTC",
+	})
+	if err != nil {
+		t.Fatalf("FindTimes: %v", err)
+	}
+	if len(result.Participants) != 2 || result.Participants[1].ID != colleague.ID {
+		t.Fatalf("participants = %v, want the host and Ana", result.Participants)
+	}
+
+	// Only 10:00-12:00 suits both. Ana is in a meeting until 10:45, so
+	// 10:45 and 11:30 are free and strand nobody's time; 11:00 and 11:15
+	// would leave a 15-minute gap.
+	var got []string
+	for _, s := range result.Suggestions {
+		if len(s.Busy) != 0 {
+			t.Errorf("suggestion at %s has busy participants", s.Start.Format("15:04"))
+		}
+		got = append(got, s.Start.Format("15:04"))
+	}
+	if strings.Join(got, ",") != "10:45,11:30" {
+		t.Errorf("suggestions = %v, want [10:45 11:30]", got)
+	}
+}
+
+func TestHostedEventFindTimes_OffersBusySlotsLast(t *testing.T) {
+	h := makeHostedEventHarness(t)
+	defer h.cleanup()
+	ctx := context.Background()
+
+	var hours []*models.WorkingHours
+	for day := 0; day < 7; day++ {
+		hours = append(hours, &models.WorkingHours{ID: uuid.New().String(), HostID: h.host.ID, DayOfWeek: day, StartTime: "09:00", EndTime: "10:00", IsEnabled: true})
+	}
+	if err := h.repos.WorkingHours.SetForHost(ctx, h.host.ID, hours); err != nil {
+		t.Fatalf("set working hours: %v", err)
+	}
+	day := time.Now().UTC().AddDate(0, 0, 3).Truncate(24 * time.Hour)
+	event := &models.HostedEvent{
+		ID: uuid.New().String(), TenantID: h.tenant.ID, HostID: h.host.ID, Title: "Planning",
+		StartTime: models.NewSQLiteTime(day.Add(9 * time.Hour)), EndTime: models.NewSQLiteTime(day.Add(10 * time.Hour)),
+		Duration: 60, Timezone: "UTC", LocationType: models.ConferencingProviderGoogleMeet,
+		Status: models.HostedEventStatusScheduled, CreatedAt: models.Now(), UpdatedAt: models.Now(),
+	}
+	if err := h.repos.HostedEvent.Create(ctx, event); err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+
+	input := FindTimesInput{HostID: h.host.ID, TenantID: h.tenant.ID, Duration: 30, From: day, To: day.Add(24 * time.Hour)}
+	result, err := h.svc.FindTimes(ctx, input)
+	if err != nil {
+		t.Fatalf("FindTimes: %v", err)
+	}
+	if len(result.Suggestions) == 0 || len(result.Suggestions[0].Busy) != 1 {
+		t.Fatalf("suggestions = %+v, want busy slots offered", result.Suggestions)
+	}
+
+	// Editing the event itself, its time is free again
+	input.ExcludeEventID = event.ID
+	result, err = h.svc.FindTimes(ctx, input)
+	if err != nil {
+		t.Fatalf("FindTimes excluding the event: %v", err)
+	}
+	if len(result.Suggestions) == 0 || len(result.Suggestions[0].Busy) != 0 {
+		t.Errorf("suggestions = %+v, want free slots when the event is excluded", result.Suggestions)
+	}
+}
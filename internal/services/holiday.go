@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/repository"
+)
+
+var (
+	ErrUnknownHolidaySet = errors.New("unknown holiday set")
+	ErrNotAHoliday       = errors.New("date is not a holiday in the host's set")
+)
+
+// Holiday is one public holiday in a HolidaySet.
+type Holiday struct {
+	Date string `json:"date"` // YYYY-MM-DD
+	Name string `json:"name"`
+}
+
+// HolidaySet is a country or region's public holidays, read from a JSON
+// file in the holidays directory. Holidays are sorted by date.
+type HolidaySet struct {
+	Code     string    `json:"code"` // e.g. "DE", "DE-BY"
+	Name     string    `json:"name"`
+	Holidays []Holiday `json:"holidays"`
+}
+
+// LastDate returns the date the set's data runs to, or "" if it is empty.
+func (hs *HolidaySet) LastDate() string {
+	if len(hs.Holidays) == 0 {
+		return ""
+	}
+	return hs.Holidays[len(hs.Holidays)-1].Date
+}
+
+// HostHoliday is a holiday from the host's set as it applies to them.
+type HostHoliday struct {
+	Date    string
+	Name    string
+	Blocked bool // false once the host has un-blocked it to take bookings
+}
+
+// HolidayService serves the bundled holiday sets and each host's choice of
+// set. The data is read once at startup so no outside service is needed;
+// updating it means replacing files in the directory and restarting.
+type HolidayService struct {
+	repos *repository.Repositories
+	sets  map[string]*HolidaySet
+}
+
+// NewHolidayService creates a holiday service with the sets found in dir.
+// Files that fail to load are logged and skipped.
+func NewHolidayService(repos *repository.Repositories, dir string) *HolidayService {
+	sets, err := LoadHolidaySets(dir)
+	if err != nil {
+		log.Printf("[HOLIDAYS] %v", err)
+	}
+	log.Printf("[HOLIDAYS] Loaded %d holiday set(s) from %s", len(sets), dir)
+	return &HolidayService{repos: repos, sets: sets}
+}
+
+// LoadHolidaySets reads every *.json holiday set in dir. It returns the sets
+// that loaded along with an error naming any that did not. When two files
+// use the same code, the first by file name wins.
+func LoadHolidaySets(dir string) (map[string]*HolidaySet, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	sets := make(map[string]*HolidaySet, len(files))
+	var errs []error
+	for _, file := range files {
+		set, err := loadHolidaySet(file)
+		if err == nil {
+			if _, dup := sets[set.Code]; dup {
+				err = fmt.Errorf("code %q is already used by another file", set.Code)
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(file), err))
+			continue
+		}
+		sets[set.Code] = set
+	}
+	return sets, errors.Join(errs...)
+}
+
+func loadHolidaySet(file string) (*HolidaySet, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var set HolidaySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	set.Code = strings.TrimSpace(set.Code)
+	if set.Code == "" || set.Name == "" {
+		return nil, errors.New("code and name are required")
+	}
+	for _, h := range set.Holidays {
+		if _, err := time.Parse("2006-01-02", h.Date); err != nil || h.Name == "" {
+			return nil, fmt.Errorf("invalid holiday %q on %q", h.Name, h.Date)
+		}
+	}
+	slices.SortStableFunc(set.Holidays, func(a, b Holiday) int {
+		return strings.Compare(a.Date, b.Date)
+	})
+	return &set, nil
+}
+
+// Sets returns every loaded holiday set, sorted by name.
+func (s *HolidayService) Sets() []*HolidaySet {
+	sets := make([]*HolidaySet, 0, len(s.sets))
+	for _, set := range s.sets {
+		sets = append(sets, set)
+	}
+	slices.SortFunc(sets, func(a, b *HolidaySet) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return sets
+}
+
+// GetHostSet returns the holiday set the host follows, or nil if they follow
+// none or it is no longer installed.
+func (s *HolidayService) GetHostSet(ctx context.Context, hostID string) (*HolidaySet, error) {
+	code, err := s.repos.Holiday.GetSetCode(ctx, hostID)
+	if err != nil || code == "" {
+		return nil, err
+	}
+	set := s.sets[code]
+	if set == nil {
+		log.Printf("[HOLIDAYS] Host %s follows holiday set %q, which is not installed", hostID, code)
+	}
+	return set, nil
+}
+
+// SetHostSet switches the host to the set with the given code, or turns
+// holidays off when code is empty. Switching sets forgets the holidays the
+// host had un-blocked.
+func (s *HolidayService) SetHostSet(ctx context.Context, hostID, code string) error {
+	if code != "" && s.sets[code] == nil {
+		return ErrUnknownHolidaySet
+	}
+	current, err := s.repos.Holiday.GetSetCode(ctx, hostID)
+	if err != nil {
+		return err
+	}
+	if current == code {
+		return nil
+	}
+	return s.repos.Holiday.SetSetCode(ctx, hostID, code)
+}
+
+// GetHostHolidays returns the holidays in the host's set from from to to
+// (YYYY-MM-DD, inclusive), marking the ones the host has un-blocked.
+func (s *HolidayService) GetHostHolidays(ctx context.Context, hostID, from, to string) ([]HostHoliday, error) {
+	set, err := s.GetHostSet(ctx, hostID)
+	if err != nil || set == nil {
+		return nil, err
+	}
+
+	var holidays []HostHoliday
+	for _, h := range set.Holidays {
+		if h.Date >= from && h.Date <= to {
+			holidays = append(holidays, HostHoliday{Date: h.Date, Name: h.Name, Blocked: true})
+		}
+	}
+	if len(holidays) == 0 {
+		return nil, nil
+	}
+
+	unblocked, err := s.repos.Holiday.GetUnblockedDates(ctx, hostID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for i := range holidays {
+		if slices.Contains(unblocked, holidays[i].Date) {
+			holidays[i].Blocked = false
+		}
+	}
+	return holidays, nil
+}
+
+// SetHolidayBlocked blocks or un-blocks one holiday in the host's set.
+func (s *HolidayService) SetHolidayBlocked(ctx context.Context, hostID, date string, blocked bool) error {
+	set, err := s.GetHostSet(ctx, hostID)
+	if err != nil {
+		return err
+	}
+	if set == nil || !slices.ContainsFunc(set.Holidays, func(h Holiday) bool { return h.Date == date }) {
+		return ErrNotAHoliday
+	}
+	if blocked {
+		return s.repos.Holiday.Reblock(ctx, hostID, date)
+	}
+	return s.repos.Holiday.Unblock(ctx, hostID, date)
+}
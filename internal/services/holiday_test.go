@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// TestLoadHolidaySets_Bundled guards the holiday data shipped in holidays/
+// against typos when it is updated by hand.
+func TestLoadHolidaySets_Bundled(t *testing.T) {
+	sets, err := LoadHolidaySets("../../holidays")
+	if err != nil {
+		t.Fatalf("LoadHolidaySets: %v", err)
+	}
+	for _, code := range []string{"DE", "DE-BY", "US", "SG"} {
+		if sets[code] == nil {
+			t.Errorf("set %s missing", code)
+		}
+	}
+	lastYear := map[string]string{}
+	for code, set := range sets {
+		seen := map[string]bool{}
+		for _, h := range set.Holidays {
+			if seen[h.Date] {
+				t.Errorf("%s lists %s twice", code, h.Date)
+			}
+			seen[h.Date] = true
+			lastYear[code] = max(lastYear[code], h.Date[:4])
+		}
+	}
+	// Every set runs as far ahead as the others, so no country's hosts lose
+	// holiday blocking early
+	for code, year := range lastYear {
+		if year != lastYear["US"] {
+			t.Errorf("%s runs to %s, the US set to %s", code, year, lastYear["US"])
+		}
+	}
+}
+
+func TestLoadHolidaySets_SkipsBadFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, body string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("xx.json", `{"code": "XX", "name": "Testland", "holidays": [
+		{"date": "2030-05-01", "name": "Second"}, {"date": "2030-01-01", "name": "First"}]}`)
+	write("xy.json", `{"code": "XX", "name": "Testland again", "holidays": []}`)
+	write("bad-date.json", `{"code": "YY", "name": "Bad", "holidays": [{"date": "2030-02-30", "name": "Nope"}]}`)
+	write("broken.json", `{"code": `)
+	write("notes.txt", `not a holiday set`)
+
+	sets, err := LoadHolidaySets(dir)
+	if err == nil {
+		t.Fatal("expected an error naming the files that failed")
+	}
+	for _, name := range []string{"xy.json", "bad-date.json", "broken.json"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q does not name %s", err, name)
+		}
+	}
+	if len(sets) != 1 || sets["XX"] == nil {
+		t.Fatalf("sets = %v, want only XX", sets)
+	}
+	if got := sets["XX"].Holidays[0].Name; got != "First" {
+		t.Errorf("holidays not sorted by date: first is %q", got)
+	}
+	if got := sets["XX"].LastDate(); got != "2030-05-01" {
+		t.Errorf("LastDate = %q", got)
+	}
+}
+
+func TestHolidays_BlockAvailability(t *testing.T) {
+	bookings, fix, cleanup := makeCreateBookingService(t)
+	defer cleanup()
+	ctx := context.Background()
+	owner := fix.hostIDs[0]
+	repos := bookings.repos
+
+	day := time.Now().UTC().AddDate(0, 0, 3).Truncate(24 * time.Hour)
+	date := day.Format("2006-01-02")
+	dir := t.TempDir()
+	set := fmt.Sprintf(`{"code": "XX", "name": "Testland", "holidays": [{"date": %q, "name": "Founders' Day"}]}`, date)
+	if err := os.WriteFile(filepath.Join(dir, "xx.json"), []byte(set), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	holidays := NewHolidayService(repos, dir)
+	svc := NewAvailabilityService(repos, bookings.calendar, holidays)
+
+	slotsOn := func() int {
+		t.Helper()
+		slots, err := svc.GetAvailableSlots(ctx, GetAvailableSlotsInput{
+			HostID: owner, TemplateID: fix.templateID,
+			StartDate: day, EndDate: day.Add(24 * time.Hour), Duration: 30, Timezone: "UTC",
+		})
+		if err != nil {
+			t.Fatalf("GetAvailableSlots: %v", err)
+		}
+		return len(slots)
+	}
+	open := slotsOn()
+	if open == 0 {
+		t.Fatal("no slots before choosing a holiday set")
+	}
+
+	if err := holidays.SetHostSet(ctx, owner, "ZZ"); !errors.Is(err, ErrUnknownHolidaySet) {
+		t.Errorf("SetHostSet(unknown): err = %v", err)
+	}
+	if err := holidays.SetHostSet(ctx, owner, "XX"); err != nil {
+		t.Fatalf("SetHostSet: %v", err)
+	}
+	if got := slotsOn(); got != 0 {
+		t.Errorf("holiday left %d slots open", got)
+	}
+
+	if err := holidays.SetHolidayBlocked(ctx, owner, day.AddDate(0, 0, 1).Format("2006-01-02"), false); !errors.Is(err, ErrNotAHoliday) {
+		t.Errorf("un-blocking a non-holiday: err = %v", err)
+	}
+	if err := holidays.SetHolidayBlocked(ctx, owner, date, false); err != nil {
+		t.Fatalf("un-block: %v", err)
+	}
+	if got := slotsOn(); got != open {
+		t.Errorf("un-blocked holiday: %d slots, want %d", got, open)
+	}
+	if err := holidays.SetHolidayBlocked(ctx, owner, date, true); err != nil {
+		t.Fatalf("re-block: %v", err)
+	}
+	if got := slotsOn(); got != 0 {
+		t.Errorf("re-blocked holiday left %d slots open", got)
+	}
+
+	// The host's own hours for the date beat the holiday
+	if _, err := svc.CreateOverride(ctx, owner, CreateOverrideInput{
+		StartDate: date, Intervals: []models.TimeRange{{Start: "10:00", End: "11:00"}},
+	}); err != nil {
+		t.Fatalf("CreateOverride: %v", err)
+	}
+	if got := slotsOn(); got != 3 {
+		t.Errorf("custom hours on a holiday: %d slots, want 3", got)
+	}
+
+	// Switching sets forgets un-blocked holidays
+	if err := holidays.SetHolidayBlocked(ctx, owner, date, false); err != nil {
+		t.Fatalf("un-block: %v", err)
+	}
+	if err := holidays.SetHostSet(ctx, owner, ""); err != nil {
+		t.Fatalf("SetHostSet(none): %v", err)
+	}
+	if err := holidays.SetHostSet(ctx, owner, "XX"); err != nil {
+		t.Fatalf("SetHostSet: %v", err)
+	}
+	got, err := holidays.GetHostHolidays(ctx, owner, date, date)
+	if err != nil {
+		t.Fatalf("GetHostHolidays: %v", err)
+	}
+	if len(got) != 1 || !got[0].Blocked || got[0].Name != "Founders' Day" {
+		t.Errorf("GetHostHolidays = %+v, want the holiday blocked again", got)
+	}
+}
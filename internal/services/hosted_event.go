@@ -86,16 +86,18 @@ type hostedEventEmailSender interface {
 // HostedEventService orchestrates host-driven event scheduling. The shared
 // per-host calendar fan-out lives in CalendarEventSyncer (PR #43); this
 // service owns the entity-specific orchestration (validation, conferencing,
-// attendee diff, contact upsert, audit, email).
+// attendee diff, contact upsert, audit, email, webhooks).
 type HostedEventService struct {
 	cfg          *config.Config
 	repos        *repository.Repositories
 	calendar     *CalendarService
+	availability *AvailabilityService
 	conferencing *ConferencingService
 	syncer       *CalendarEventSyncer
 	email        hostedEventEmailSender
 	contact      *ContactService
 	audit        *AuditLogService
+	webhooks     *WebhookService
 }
 
 // NewHostedEventService constructs a HostedEventService.
@@ -103,21 +105,25 @@ func NewHostedEventService(
 	cfg *config.Config,
 	repos *repository.Repositories,
 	calendar *CalendarService,
+	availability *AvailabilityService,
 	conferencing *ConferencingService,
 	syncer *CalendarEventSyncer,
 	email hostedEventEmailSender,
 	contact *ContactService,
 	audit *AuditLogService,
+	webhooks *WebhookService,
 ) *HostedEventService {
 	return &HostedEventService{
 		cfg:          cfg,
 		repos:        repos,
 		calendar:     calendar,
+		availability: availability,
 		conferencing: conferencing,
 		syncer:       syncer,
 		email:        email,
 		contact:      contact,
 		audit:        audit,
+		webhooks:     webhooks,
 	}
 }
 
@@ -320,12 +326,14 @@ func (s *HostedEventService) Create(ctx context.Context, input CreateHostedEvent
 		"attendees": attendeeEmails(attendees),
 	}, "")
 
-	return &HostedEventWithDetails{
+	out := &HostedEventWithDetails{
 		Event:     event,
 		Host:      host,
 		Tenant:    tenant,
 		Attendees: attendees,
-	}, nil
+	}
+	s.webhooks.EmitHostedEvent(ctx, models.WebhookEventHostedEventCreated, out, time.Time{}, nil)
+	return out, nil
 }
 
 // ---------------------------------------------------------------------------
@@ -460,6 +468,10 @@ func (s *HostedEventService) Update(ctx context.Context, input UpdateHostedEvent
 		if len(addedAttendeeRows) > 0 || len(removedAttendees) > 0 {
 			changed = append(changed, "attendees")
 		}
+	} else {
+		// Attendee list untouched: everyone is retained and hears about
+		// material changes, e.g. a time-only edit.
+		retainedAttendees = prevAttendees
 	}
 
 	// Nothing changed — fast exit.
@@ -563,6 +575,11 @@ func (s *HostedEventService) Update(ctx context.Context, input UpdateHostedEvent
 		Template:  details.Template,
 		Attendees: finalAttendees,
 	}
+	if startChanged {
+		s.webhooks.EmitHostedEvent(ctx, models.WebhookEventHostedEventRescheduled, out, previousStart.Time, changed)
+	} else if len(changed) > 0 {
+		s.webhooks.EmitHostedEvent(ctx, models.WebhookEventHostedEventUpdated, out, time.Time{}, changed)
+	}
 	return out, changed, nil
 }
 
@@ -640,10 +657,44 @@ func (s *HostedEventService) Cancel(ctx context.Context, hostID, tenantID, event
 	s.audit.Log(ctx, tenantID, &hID, "hosted_event.cancelled", "hosted_event", eventID, models.JSONMap{
 		"reason": reason,
 	}, "")
+	s.webhooks.EmitHostedEvent(ctx, models.WebhookEventHostedEventCancelled, details, time.Time{}, nil)
 
 	return nil
 }
 
+// ReconcileCalendarEvent applies an edit the host made to a hosted event
+// directly in their calendar: deleting the event cancels it and moving it
+// updates the time, each with the usual attendee emails. Hosted events are
+// single-host, so there is no copy to disagree with.
+func (s *HostedEventService) ReconcileCalendarEvent(ctx context.Context, row *models.HostedEventCalendarEvent, ev *ExternalEvent) error {
+	event, err := s.repos.HostedEvent.GetByID(ctx, row.HostedEventID)
+	if err != nil {
+		return err
+	}
+	if event == nil || event.Status != models.HostedEventStatusScheduled || row.HostID != event.HostID {
+		return nil
+	}
+
+	if ev.Deleted {
+		log.Printf("[HOSTED_EVENT] Calendar event for %s was deleted by the host, cancelling", event.ID)
+		return s.Cancel(ctx, event.HostID, event.TenantID, event.ID, "Removed from the host's calendar")
+	}
+	if ev.IsAllDay || (ev.Start.Equal(event.StartTime.Time) && ev.End.Equal(event.EndTime.Time)) {
+		return nil
+	}
+
+	start := ev.Start
+	duration := int(ev.End.Sub(ev.Start) / time.Minute)
+	_, _, err = s.Update(ctx, UpdateHostedEventInput{
+		HostID:   event.HostID,
+		TenantID: event.TenantID,
+		EventID:  event.ID,
+		Start:    &start,
+		Duration: &duration,
+	})
+	return err
+}
+
 // ---------------------------------------------------------------------------
 // Archive / Unarchive / Retry
 // ---------------------------------------------------------------------------
@@ -217,7 +217,7 @@ func makeHostedEventHarness(t *testing.T) *hostedEventHarness {
 	}
 
 	emailSpy := &spyEmailSender{}
-	svc := NewHostedEventService(cfg, repos, calendarSvc, conferencingSvc, syncer, emailSpy, contactSvc, auditSvc)
+	svc := NewHostedEventService(cfg, repos, calendarSvc, NewAvailabilityService(repos, calendarSvc, nil), conferencingSvc, syncer, emailSpy, contactSvc, auditSvc, NewWebhookService(repos))
 
 	return &hostedEventHarness{
 		svc:      svc,
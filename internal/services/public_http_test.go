@@ -0,0 +1,49 @@
+package services
+
+import (
+	"net/netip"
+	"net/url"
+	"testing"
+)
+
+func TestIsPublicAddress(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"93.184.215.14":          true,
+		"2606:4700::1111":        true,
+		"127.0.0.1":              false,
+		"10.20.30.40":            false,
+		"172.16.0.1":             false,
+		"192.168.1.1":            false,
+		"169.254.169.254":        false,
+		"100.64.0.1":             false,
+		"0.0.0.0":                false,
+		"255.255.255.255":        false,
+		"::1":                    false,
+		"fd00::1":                false,
+		"fe80::1":                false,
+		"::ffff:127.0.0.1":       false,
+		"::ffff:169.254.169.254": false,
+		"64:ff9b::a00:1":         false,
+	} {
+		if got := isPublicAddress(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("isPublicAddress(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestCheckPublicURLHost(t *testing.T) {
+	for raw, public := range map[string]bool{
+		"https://hooks.example.com/x":     true,
+		"http://93.184.215.14:8080/x":     true,
+		"http://localhost:3000/x":         false,
+		"http://api.localhost/x":          false,
+		"http://LOCALHOST./x":             false,
+		"http://[::1]/x":                  false,
+		"http://169.254.169.254/latest/x": false,
+	} {
+		u, _ := url.Parse(raw)
+		if err := checkPublicURLHost(u); (err == nil) != public {
+			t.Errorf("checkPublicURLHost(%s) = %v, want public %v", raw, err, public)
+		}
+	}
+}
@@ -2,17 +2,72 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"slices"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
+
+	"github.com/meet-when/meet-when/internal/models"
 	"github.com/meet-when/meet-when/internal/repository"
 )
 
-// ReminderService handles sending booking reminder emails
+const (
+	// MaxReminders is how many reminders a template may have
+	MaxReminders = 5
+	// MaxReminderOffset is the furthest ahead of a booking, in minutes, a
+	// reminder may be sent
+	MaxReminderOffset = 7 * 24 * 60
+	// reminderGrace is how late a reminder may still go out, e.g. after the
+	// server was down when it was due
+	reminderGrace = time.Hour
+)
+
+// normalizeReminders keeps the reminders with an offset in range and a known
+// channel and recipient, without repeats, furthest ahead first and at most
+// MaxReminders of them
+func normalizeReminders(reminders []models.Reminder) models.Reminders {
+	out := models.Reminders{}
+	for _, r := range reminders {
+		switch {
+		case r.OffsetMinutes < 1 || r.OffsetMinutes > MaxReminderOffset:
+		case r.Channel != models.ReminderChannelEmail && r.Channel != models.ReminderChannelSMS:
+		case r.Recipient != models.ReminderRecipientInvitee && r.Recipient != models.ReminderRecipientHost &&
+			r.Recipient != models.ReminderRecipientBoth:
+		case slices.Contains(out, r):
+		default:
+			out = append(out, r)
+		}
+	}
+	slices.SortStableFunc(out, func(a, b models.Reminder) int { return b.OffsetMinutes - a.OffsetMinutes })
+	if len(out) > MaxReminders {
+		out = out[:MaxReminders]
+	}
+	return out
+}
+
+// SMSReminder is an "sms" reminder that is due, for an SMSSender to deliver
+type SMSReminder struct {
+	Details *BookingWithDetails
+	To      models.ReminderRecipient // invitee or host
+	Phone   string                   // the invitee's, when they gave one; hosts' numbers are the sender's to look up
+	Text    string
+}
+
+// SMSSender delivers "sms" reminders. Meet When has no SMS provider of its
+// own: without one set on ReminderService.SMS, sms reminders are logged and
+// skipped.
+type SMSSender interface {
+	SendReminderSMS(ctx context.Context, reminder *SMSReminder) error
+}
+
+// ReminderService handles sending booking reminders
 type ReminderService struct {
 	repos    *repository.Repositories
 	email    *EmailService
+	SMS      SMSSender // optional
 	interval time.Duration
 	stopCh   chan struct{}
 	wg       sync.WaitGroup
@@ -23,7 +78,7 @@ func NewReminderService(repos *repository.Repositories, email *EmailService) *Re
 	return &ReminderService{
 		repos:    repos,
 		email:    email,
-		interval: 15 * time.Minute, // Check every 15 minutes
+		interval: time.Minute, // Reminders can be as little as minutes ahead
 		stopCh:   make(chan struct{}),
 	}
 }
@@ -66,33 +121,66 @@ func (s *ReminderService) run() {
 // sub-funcs so a partial failure in one source does not abort the other.
 func (s *ReminderService) checkAndSendReminders() {
 	ctx := context.Background()
-
-	// Window: 23-25 hours from now (1-hour cushion either side of "tomorrow"
-	// so we don't miss any items between ticks).
 	now := time.Now().UTC()
-	windowStart := now.Add(23 * time.Hour)
-	windowEnd := now.Add(25 * time.Hour)
 
-	s.processBookingReminders(ctx, windowStart, windowEnd)
-	s.processHostedEventReminders(ctx, windowStart, windowEnd)
+	s.processBookingReminders(ctx, now)
+
+	// Hosted events have one reminder a day ahead. Window: 23-25 hours from
+	// now (1-hour cushion either side of "tomorrow").
+	s.processHostedEventReminders(ctx, now.Add(23*time.Hour), now.Add(25*time.Hour))
 }
 
-func (s *ReminderService) processBookingReminders(ctx context.Context, windowStart, windowEnd time.Time) {
-	bookings, err := s.repos.Booking.GetBookingsNeedingReminder(ctx, windowStart, windowEnd)
-	if err != nil {
-		log.Printf("[REMINDER] Error fetching bookings needing reminders: %v", err)
-		return
+// dueReminder is one reminder of a booking's template, to one recipient
+type dueReminder struct {
+	reminder models.Reminder
+	to       models.ReminderRecipient
+}
+
+// dueReminders returns the booking's reminders whose time has come as of
+// now and that are less than reminderGrace late. Reminders that fell due
+// before the booking was made are skipped: its confirmation has just gone
+// out.
+func dueReminders(reminders models.Reminders, booking *models.Booking, now time.Time) []dueReminder {
+	var due []dueReminder
+	for _, r := range reminders {
+		sendAt := booking.StartTime.Add(-time.Duration(r.OffsetMinutes) * time.Minute)
+		if now.Before(sendAt) || !now.Before(sendAt.Add(reminderGrace)) ||
+			!now.Before(booking.StartTime.Time) || sendAt.Before(booking.CreatedAt.Time) {
+			continue
+		}
+		for _, to := range r.Recipients() {
+			due = append(due, dueReminder{reminder: r, to: to})
+		}
 	}
-	if len(bookings) == 0 {
+	return due
+}
+
+// processBookingReminders sends the reminders of confirmed bookings that
+// are due. Each is claimed in the sent-reminder ledger first, by its key
+// and the booking's current start, so it goes out once per start time.
+func (s *ReminderService) processBookingReminders(ctx context.Context, now time.Time) {
+	bookings, err := s.repos.Booking.GetBookingsStartingBetween(ctx, now, now.Add(MaxReminderOffset*time.Minute))
+	if err != nil {
+		log.Printf("[REMINDER] Error fetching upcoming bookings: %v", err)
 		return
 	}
 
-	log.Printf("[REMINDER] Found %d bookings needing reminders", len(bookings))
-
+	templates := make(map[string]*models.MeetingTemplate)
+	// The seats of a slot are one meeting, so its host is reminded once
+	hostReminded := make(map[string]bool)
 	for _, booking := range bookings {
-		template, err := s.repos.Template.GetByID(ctx, booking.TemplateID)
-		if err != nil || template == nil {
-			log.Printf("[REMINDER] Error fetching template %s: %v", booking.TemplateID, err)
+		template, ok := templates[booking.TemplateID]
+		if !ok {
+			template, err = s.repos.Template.GetByID(ctx, booking.TemplateID)
+			if err != nil || template == nil {
+				log.Printf("[REMINDER] Error fetching template %s: %v", booking.TemplateID, err)
+				continue
+			}
+			templates[booking.TemplateID] = template
+		}
+
+		due := dueReminders(template.Reminders, booking, now)
+		if len(due) == 0 {
 			continue
 		}
 
@@ -115,15 +203,71 @@ func (s *ReminderService) processBookingReminders(ctx context.Context, windowSta
 			Tenant:   tenant,
 		}
 
-		s.email.SendBookingReminder(ctx, details)
+		for _, d := range due {
+			key := d.reminder.Key(d.to)
+			sent := &models.BookingReminder{
+				ID:          uuid.New().String(),
+				BookingID:   booking.ID,
+				ReminderKey: key,
+				StartTime:   booking.StartTime,
+				SentAt:      models.Now(),
+			}
+			claimed, err := s.repos.BookingReminder.Claim(ctx, sent)
+			if err != nil {
+				log.Printf("[REMINDER] Error claiming reminder %s for booking %s: %v", key, booking.ID, err)
+				continue
+			}
+			if !claimed {
+				continue // already sent
+			}
 
-		if err := s.repos.Booking.MarkReminderSent(ctx, booking.ID); err != nil {
-			log.Printf("[REMINDER] Error marking reminder sent for booking %s: %v", booking.ID, err)
+			if d.to == models.ReminderRecipientHost && template.HasSeats() {
+				slot := booking.HostID + "/" + booking.StartTime.Format(time.RFC3339) + "/" + key
+				if hostReminded[slot] {
+					continue
+				}
+				hostReminded[slot] = true
+			}
+
+			if err := s.sendReminder(ctx, details, d); err != nil {
+				log.Printf("[REMINDER] Error sending reminder %s for booking %s, will retry: %v", key, booking.ID, err)
+				if err := s.repos.BookingReminder.Release(ctx, sent.ID); err != nil {
+					log.Printf("[REMINDER] Error releasing reminder %s for booking %s: %v", key, booking.ID, err)
+				}
+				continue
+			}
+			log.Printf("[REMINDER] Sent reminder %s for booking %s (meeting: %s at %s)",
+				key, booking.ID, template.Name, booking.StartTime.Format(time.RFC3339))
+		}
+	}
+}
+
+// sendReminder sends one due reminder to its recipient. Emails are queued
+// in the outbox, which retries them itself; an error is an sms the SMS
+// sender couldn't deliver.
+func (s *ReminderService) sendReminder(ctx context.Context, details *BookingWithDetails, d dueReminder) error {
+	offset := d.reminder.OffsetMinutes
+	if d.reminder.Channel == models.ReminderChannelEmail {
+		if d.to == models.ReminderRecipientHost {
+			s.email.SendHostBookingReminder(ctx, details, offset)
 		} else {
-			log.Printf("[REMINDER] Sent reminder for booking %s (invitee: %s, meeting: %s at %s)",
-				booking.ID, booking.InviteeEmail, template.Name, booking.StartTime.Format(time.RFC3339))
+			s.email.SendBookingReminder(ctx, details, offset)
 		}
+		return nil
+	}
+
+	if s.SMS == nil {
+		log.Printf("[REMINDER] No SMS sender configured, skipping sms reminder for booking %s", details.Booking.ID)
+		return nil
+	}
+	sms := &SMSReminder{Details: details, To: d.to}
+	if d.to == models.ReminderRecipientHost {
+		sms.Text = fmt.Sprintf("Reminder: %s with %s %s", details.Template.Name, details.Booking.InviteeName, timeUntil(offset))
+	} else {
+		sms.Phone = details.Booking.InviteePhone
+		sms.Text = fmt.Sprintf("Reminder: %s with %s %s", details.Template.Name, details.Host.Name, timeUntil(offset))
 	}
+	return s.SMS.SendReminderSMS(ctx, sms)
 }
 
 func (s *ReminderService) processHostedEventReminders(ctx context.Context, windowStart, windowEnd time.Time) {
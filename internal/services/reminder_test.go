@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"slices"
 	"testing"
 	"time"
 
@@ -80,7 +81,7 @@ func TestReminder_processHostedEventReminders_FiresForEachAttendeeAndMarksSent(t
 	cfg.Email.SMTPHost = "127.0.0.1"
 	cfg.Email.SMTPPort = 1 // guaranteed-closed port
 	cfg.Email.FromAddress = "noreply@test.local"
-	emailSvc := NewEmailService(cfg)
+	emailSvc := NewEmailService(cfg, repos)
 
 	reminder := NewReminderService(repos, emailSvc)
 	reminder.processHostedEventReminders(ctx, time.Now().UTC().Add(23*time.Hour), time.Now().UTC().Add(25*time.Hour))
@@ -104,3 +105,209 @@ func TestReminder_processHostedEventReminders_FiresForEachAttendeeAndMarksSent(t
 		}
 	}
 }
+
+func TestNormalizeReminders(t *testing.T) {
+	email := func(offset int, to models.ReminderRecipient) models.Reminder {
+		return models.Reminder{OffsetMinutes: offset, Channel: models.ReminderChannelEmail, Recipient: to}
+	}
+	got := normalizeReminders([]models.Reminder{
+		email(60, models.ReminderRecipientInvitee),
+		email(0, models.ReminderRecipientInvitee),
+		email(MaxReminderOffset+1, models.ReminderRecipientInvitee),
+		{OffsetMinutes: 30, Channel: "fax", Recipient: models.ReminderRecipientInvitee},
+		{OffsetMinutes: 30, Channel: models.ReminderChannelSMS, Recipient: "everyone"},
+		email(1440, models.ReminderRecipientBoth),
+		email(60, models.ReminderRecipientInvitee),
+		{OffsetMinutes: 10, Channel: models.ReminderChannelSMS, Recipient: models.ReminderRecipientInvitee},
+	})
+	want := models.Reminders{
+		email(1440, models.ReminderRecipientBoth),
+		email(60, models.ReminderRecipientInvitee),
+		{OffsetMinutes: 10, Channel: models.ReminderChannelSMS, Recipient: models.ReminderRecipientInvitee},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("normalizeReminders = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("reminder %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	var many []models.Reminder
+	for i := 1; i <= MaxReminders+2; i++ {
+		many = append(many, email(i*60, models.ReminderRecipientHost))
+	}
+	if got := normalizeReminders(many); len(got) != MaxReminders || got[0].OffsetMinutes != (MaxReminders+2)*60 {
+		t.Errorf("capped reminders = %+v", got)
+	}
+}
+
+func TestTimeUntil(t *testing.T) {
+	for minutes, want := range map[int]string{
+		1: "in 1 minute", 10: "in 10 minutes", 60: "in 1 hour", 90: "in 90 minutes",
+		120: "in 2 hours", 1440: "tomorrow", 2880: "in 2 days", 1500: "in 25 hours",
+	} {
+		if got := timeUntil(minutes); got != want {
+			t.Errorf("timeUntil(%d) = %q, want %q", minutes, got, want)
+		}
+	}
+}
+
+// smsSpy records the sms reminders handed to it
+type smsSpy struct {
+	sent []*SMSReminder
+}
+
+func (s *smsSpy) SendReminderSMS(ctx context.Context, reminder *SMSReminder) error {
+	s.sent = append(s.sent, reminder)
+	return nil
+}
+
+// TestReminder_processBookingReminders_LedgerRearmsOnChange walks one
+// booking through its template's reminders: each goes out once when it
+// falls due, rescheduling arms them again, and a reminder added to the
+// schedule is sent without repeating the others.
+func TestReminder_processBookingReminders_LedgerRearmsOnChange(t *testing.T) {
+	_, repos, cleanup := setupTestRepos(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	tenant := &models.Tenant{
+		ID: uuid.New().String(), Slug: "t-" + uuid.New().String()[:6], Name: "Tenant",
+		CreatedAt: models.Now(), UpdatedAt: models.Now(),
+	}
+	if err := repos.Tenant.Create(ctx, tenant); err != nil {
+		t.Fatalf("create tenant: %v", err)
+	}
+	host := &models.Host{
+		ID: uuid.New().String(), TenantID: tenant.ID,
+		Email: "host@example.com", PasswordHash: "x", Name: "Host", Slug: "host",
+		Timezone: "UTC", CreatedAt: models.Now(), UpdatedAt: models.Now(),
+	}
+	if err := repos.Host.Create(ctx, host); err != nil {
+		t.Fatalf("create host: %v", err)
+	}
+	tmpl := &models.MeetingTemplate{
+		ID: uuid.New().String(), HostID: host.ID, Slug: "intro", Name: "Intro",
+		Durations: models.IntSlice{30}, LocationType: models.ConferencingProviderGoogleMeet,
+		MaxScheduleDays: 30, IsActive: true,
+		Reminders: models.Reminders{
+			{OffsetMinutes: 1440, Channel: models.ReminderChannelEmail, Recipient: models.ReminderRecipientInvitee},
+			{OffsetMinutes: 60, Channel: models.ReminderChannelEmail, Recipient: models.ReminderRecipientBoth},
+		},
+		CreatedAt: models.Now(), UpdatedAt: models.Now(),
+	}
+	if err := repos.Template.Create(ctx, tmpl); err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+
+	base := time.Now().UTC().Truncate(time.Second)
+	start := base.Add(72 * time.Hour)
+	booking := &models.Booking{
+		ID: uuid.New().String(), TemplateID: tmpl.ID, HostID: host.ID,
+		Token: uuid.New().String(), Status: models.BookingStatusConfirmed,
+		StartTime: models.NewSQLiteTime(start), EndTime: models.NewSQLiteTime(start.Add(30 * time.Minute)), Duration: 30,
+		InviteeName: "Ann", InviteeEmail: "ann@example.com", InviteeTimezone: "UTC", InviteePhone: "+15550100",
+		CreatedAt: models.NewSQLiteTime(base), UpdatedAt: models.NewSQLiteTime(base),
+	}
+	if err := repos.Booking.Create(ctx, booking); err != nil {
+		t.Fatalf("create booking: %v", err)
+	}
+
+	sms := &smsSpy{}
+	reminder := NewReminderService(repos, NewEmailService(&config.Config{}, repos))
+	reminder.SMS = sms
+
+	// sentKeys runs a pass at now and returns the booking's ledger keys
+	sentKeys := func(now time.Time) []string {
+		t.Helper()
+		reminder.processBookingReminders(ctx, now)
+		sent, err := repos.BookingReminder.ListByBooking(ctx, booking.ID)
+		if err != nil {
+			t.Fatalf("list sent reminders: %v", err)
+		}
+		var keys []string
+		for _, s := range sent {
+			keys = append(keys, s.ReminderKey+"@"+s.StartTime.Format("Jan 2 15:04"))
+		}
+		return keys
+	}
+	queued := func() int {
+		t.Helper()
+		due, err := repos.EmailOutbox.GetDue(ctx, base.Add(30*24*time.Hour), 100)
+		if err != nil {
+			t.Fatalf("outbox: %v", err)
+		}
+		return len(due)
+	}
+	at := func(tm time.Time) string { return tm.Format("Jan 2 15:04") }
+
+	// Nothing is due two days out
+	if keys := sentKeys(start.Add(-48 * time.Hour)); len(keys) != 0 {
+		t.Fatalf("sent too early: %v", keys)
+	}
+
+	// The day-before reminder goes out once, however often the service looks
+	dayBefore := start.Add(-24*time.Hour + time.Minute)
+	sentKeys(dayBefore)
+	if keys := sentKeys(dayBefore.Add(5 * time.Minute)); len(keys) != 1 || keys[0] != "invitee:email:1440@"+at(start) {
+		t.Fatalf("after day-before passes: %v", keys)
+	}
+	if n := queued(); n != 1 {
+		t.Fatalf("%d emails queued, want 1", n)
+	}
+
+	// The hour-before reminder goes to both the invitee and the host
+	if keys := sentKeys(start.Add(-50 * time.Minute)); len(keys) != 3 {
+		t.Fatalf("after hour-before pass: %v", keys)
+	}
+	if n := queued(); n != 3 {
+		t.Fatalf("%d emails queued, want 3", n)
+	}
+
+	// Rescheduled: the day-before reminder is due again for the new start
+	newStart := start.Add(24 * time.Hour)
+	booking.StartTime = models.NewSQLiteTime(newStart)
+	booking.EndTime = models.NewSQLiteTime(newStart.Add(30 * time.Minute))
+	if err := repos.Booking.Update(ctx, booking); err != nil {
+		t.Fatalf("reschedule: %v", err)
+	}
+	keys := sentKeys(newStart.Add(-24*time.Hour + time.Minute))
+	if len(keys) != 4 || !slices.Contains(keys, "invitee:email:1440@"+at(newStart)) {
+		t.Fatalf("after reschedule: %v", keys)
+	}
+
+	// A text two hours ahead is added to the schedule; only it is new
+	tmpl.Reminders = append(tmpl.Reminders, models.Reminder{
+		OffsetMinutes: 120, Channel: models.ReminderChannelSMS, Recipient: models.ReminderRecipientInvitee,
+	})
+	if err := repos.Template.Update(ctx, tmpl); err != nil {
+		t.Fatalf("update template: %v", err)
+	}
+	if keys := sentKeys(newStart.Add(-119 * time.Minute)); len(keys) != 5 {
+		t.Fatalf("after schedule change: %v", keys)
+	}
+	if len(sms.sent) != 1 || sms.sent[0].Phone != "+15550100" || sms.sent[0].Text != "Reminder: Intro with Host in 2 hours" {
+		t.Fatalf("sms reminders = %+v", sms.sent)
+	}
+	if n := queued(); n != 4 {
+		t.Errorf("%d emails queued, want 4", n)
+	}
+}
+
+func TestDueReminders_SkipsRemindersBeforeBooking(t *testing.T) {
+	now := time.Now().UTC()
+	booking := &models.Booking{
+		StartTime: models.NewSQLiteTime(now.Add(30 * time.Minute)),
+		CreatedAt: models.NewSQLiteTime(now.Add(-time.Minute)),
+	}
+	reminders := models.Reminders{
+		{OffsetMinutes: 60, Channel: models.ReminderChannelEmail, Recipient: models.ReminderRecipientInvitee},
+		{OffsetMinutes: 31, Channel: models.ReminderChannelEmail, Recipient: models.ReminderRecipientHost},
+	}
+	due := dueReminders(reminders, booking, now)
+	if len(due) != 1 || due[0].to != models.ReminderRecipientHost {
+		t.Errorf("due = %+v, want only the host's 31-minute reminder", due)
+	}
+}
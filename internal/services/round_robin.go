@@ -0,0 +1,219 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/models"
+	"github.com/meet-when/meet-when/internal/repository"
+)
+
+// roundRobinWindowDays is how far back the weighted strategy looks when
+// comparing each host's share of a template's bookings.
+const roundRobinWindowDays = 30
+
+// normalizeScheduling falls back to collective scheduling and the
+// least-recently-booked strategy for empty or unknown values.
+func normalizeScheduling(st models.SchedulingType, strategy models.RoundRobinStrategy) (models.SchedulingType, models.RoundRobinStrategy) {
+	if st != models.SchedulingTypeRoundRobin {
+		st = models.SchedulingTypeCollective
+	}
+	switch strategy {
+	case models.RoundRobinWeighted, models.RoundRobinMaximizeAvailability:
+	default:
+		strategy = models.RoundRobinLeastRecentlyBooked
+	}
+	return st, strategy
+}
+
+// isRoundRobin reports whether bookings of the template go to one of several
+// pooled hosts rather than to all of them.
+func isRoundRobin(template *models.MeetingTemplate, pooled []*models.TemplateHost) bool {
+	return template.SchedulingType == models.SchedulingTypeRoundRobin && len(pooled) > 1
+}
+
+// getRoundRobinSlots returns the union of the hosts' available slots, each
+// host's within their own booking caps.
+func (s *AvailabilityService) getRoundRobinSlots(ctx context.Context, input GetAvailableSlotsInput, template *models.MeetingTemplate, hosts []*models.TemplateHost, earliestStart time.Time) ([]models.TimeSlot, error) {
+	seen := make(map[string]bool)
+	var union []models.TimeSlot
+	for _, th := range hosts {
+		hostInput := input
+		hostInput.HostID = th.HostID
+		hostInput.Timezone = "UTC"
+		slots, err := s.getSingleHostSlots(ctx, hostInput, template, earliestStart)
+		if err != nil {
+			return nil, err
+		}
+		slots, err = s.applyBookingCaps(ctx, th.HostID, template, slots, input.Duration)
+		if err != nil {
+			return nil, err
+		}
+		for _, slot := range slots {
+			key := slot.Start.UTC().Format(time.RFC3339) + "|" + slot.End.UTC().Format(time.RFC3339)
+			if !seen[key] {
+				seen[key] = true
+				union = append(union, slot)
+			}
+		}
+	}
+	sort.Slice(union, func(i, j int) bool {
+		return union[i].Start.Before(union[j].Start)
+	})
+	return union, nil
+}
+
+// RoundRobinHosts returns the pooled hosts who could take a booking of the
+// template for duration minutes at start.
+func (s *AvailabilityService) RoundRobinHosts(ctx context.Context, template *models.MeetingTemplate, pooled []*models.TemplateHost, start time.Time, duration int) ([]*models.TemplateHost, error) {
+	input := GetAvailableSlotsInput{
+		TemplateID: template.ID,
+		StartDate:  start.Add(-24 * time.Hour),
+		EndDate:    start.Add(time.Duration(duration)*time.Minute + 24*time.Hour),
+		Duration:   duration,
+		Timezone:   "UTC",
+	}
+	earliestStart := clampToBookingWindow(&input, template)
+
+	var free []*models.TemplateHost
+	for _, th := range pooled {
+		slots, err := s.getRoundRobinSlots(ctx, input, template, []*models.TemplateHost{th}, earliestStart)
+		if err != nil {
+			return nil, err
+		}
+		if slices.ContainsFunc(slots, func(slot models.TimeSlot) bool { return slot.Start.Equal(start) }) {
+			free = append(free, th)
+		}
+	}
+	return free, nil
+}
+
+// roundRobinCandidate is what the strategies know about a host who is free
+// for a round-robin booking.
+type roundRobinCandidate struct {
+	Host          *models.TemplateHost
+	Weight        int
+	Count         int // the template's bookings assigned in the last roundRobinWindowDays
+	LastBookedAt  time.Time
+	BookedMinutes int // all of the host's bookings on the booking's day
+}
+
+// rankRoundRobinCandidates orders candidates best first. Every strategy falls
+// back to least recently booked, then to the pool's display order.
+//   - weighted: fewest recent bookings for the host's weight
+//   - maximize_availability: fewest minutes already booked that day
+func rankRoundRobinCandidates(strategy models.RoundRobinStrategy, candidates []roundRobinCandidate) {
+	slices.SortStableFunc(candidates, func(a, b roundRobinCandidate) int {
+		switch strategy {
+		case models.RoundRobinWeighted:
+			if c := cmp.Compare(a.Count*b.Weight, b.Count*a.Weight); c != 0 {
+				return c
+			}
+		case models.RoundRobinMaximizeAvailability:
+			if c := cmp.Compare(a.BookedMinutes, b.BookedMinutes); c != 0 {
+				return c
+			}
+		}
+		if c := a.LastBookedAt.Compare(b.LastBookedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Host.DisplayOrder, b.Host.DisplayOrder)
+	})
+}
+
+// rankRoundRobinHosts orders the free hosts for a round-robin booking at
+// start by the template's strategy, best first.
+func (s *BookingService) rankRoundRobinHosts(ctx context.Context, template *models.MeetingTemplate, free []*models.TemplateHost, start time.Time) ([]roundRobinCandidate, error) {
+	stats, err := s.repos.Booking.GetAssignmentStats(ctx, template.ID, time.Now().AddDate(0, 0, -roundRobinWindowDays))
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]roundRobinCandidate, 0, len(free))
+	for _, th := range free {
+		c := roundRobinCandidate{Host: th, Weight: max(th.Weight, 1)}
+		if stat := stats[th.HostID]; stat != nil {
+			c.Count = stat.Count
+			c.LastBookedAt = stat.LastBookedAt
+		}
+		if template.RoundRobinStrategy == models.RoundRobinMaximizeAvailability {
+			host, err := s.repos.Host.GetByID(ctx, th.HostID)
+			if err != nil {
+				return nil, err
+			}
+			loc := time.UTC
+			if host != nil {
+				if l, err := time.LoadLocation(host.Timezone); err == nil {
+					loc = l
+				}
+			}
+			dayStart, dayEnd := localDay(start, loc)
+			bookings, err := s.repos.Booking.GetByHostIDAndTimeRange(ctx, th.HostID, dayStart, dayEnd)
+			if err != nil {
+				return nil, err
+			}
+			for _, b := range bookings {
+				c.BookedMinutes += b.Duration
+			}
+		}
+		candidates = append(candidates, c)
+	}
+	rankRoundRobinCandidates(template.RoundRobinStrategy, candidates)
+	return candidates, nil
+}
+
+// createRoundRobinBooking assigns the booking to the best-ranked pooled host
+// who is free and inserts it. If another booking takes that host's slot
+// first, the next host is tried. It returns the assigned host.
+func (s *BookingService) createRoundRobinBooking(ctx context.Context, tenantID string, booking *models.Booking, template *models.MeetingTemplate, pooled []*models.TemplateHost) (*models.Host, error) {
+	start := booking.StartTime.Time
+	free, err := s.availability.RoundRobinHosts(ctx, template, pooled, start, booking.Duration)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := s.rankRoundRobinHosts(ctx, template, free, start)
+	if err != nil {
+		return nil, err
+	}
+
+	for rank, c := range candidates {
+		host, err := s.repos.Host.GetByID(ctx, c.Host.HostID)
+		if err != nil {
+			return nil, err
+		}
+		if host == nil {
+			continue
+		}
+		booking.HostID = host.ID
+		check := repository.SlotCheck{
+			HostIDs: []string{host.ID},
+			Before:  time.Duration(max(template.PreBufferMinutes, host.MinGapMinutes)) * time.Minute,
+			After:   time.Duration(max(template.PostBufferMinutes, host.MinGapMinutes)) * time.Minute,
+			Caps:    bookingCaps(host, template, start),
+		}
+		err = s.repos.Booking.CreateIfSlotFree(ctx, booking, check)
+		if errors.Is(err, repository.ErrBookingConflict) || errors.Is(err, repository.ErrBookingCapReached) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		log.Printf("[BOOKING] Assigned round-robin booking %s to host %s (%s)", booking.ID, host.ID, template.RoundRobinStrategy)
+		s.auditLog.Log(ctx, tenantID, &host.ID, "booking.assigned", "booking", booking.ID, models.JSONMap{
+			"template_id":  template.ID,
+			"host_id":      host.ID,
+			"strategy":     string(template.RoundRobinStrategy),
+			"rank":         rank + 1,
+			"free_hosts":   len(candidates),
+			"recent_count": c.Count,
+		}, "")
+		return host, nil
+	}
+	return nil, ErrSlotNotAvailable
+}
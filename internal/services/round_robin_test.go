@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+func TestRankRoundRobinCandidates(t *testing.T) {
+	now := time.Now()
+	host := func(id string, order int) *models.TemplateHost {
+		return &models.TemplateHost{HostID: id, DisplayOrder: order}
+	}
+	candidates := func() []roundRobinCandidate {
+		return []roundRobinCandidate{
+			{Host: host("a", 0), Weight: 3, Count: 4, LastBookedAt: now.Add(-time.Hour), BookedMinutes: 120},
+			{Host: host("b", 1), Weight: 1, Count: 2, LastBookedAt: now.Add(-48 * time.Hour), BookedMinutes: 60},
+			{Host: host("c", 2), Weight: 1, Count: 1, LastBookedAt: now.Add(-2 * time.Hour), BookedMinutes: 0},
+			{Host: host("d", 3), Weight: 1, Count: 1, LastBookedAt: now.Add(-2 * time.Hour), BookedMinutes: 60},
+		}
+	}
+	for _, tc := range []struct {
+		strategy models.RoundRobinStrategy
+		want     string
+	}{
+		{models.RoundRobinLeastRecentlyBooked, "bcda"},
+		// a has 4/3 bookings per weight, b 2, c and d 1
+		{models.RoundRobinWeighted, "cdab"},
+		{models.RoundRobinMaximizeAvailability, "cbda"},
+	} {
+		cs := candidates()
+		rankRoundRobinCandidates(tc.strategy, cs)
+		got := ""
+		for _, c := range cs {
+			got += c.Host.HostID
+		}
+		if got != tc.want {
+			t.Errorf("%s: order %s, want %s", tc.strategy, got, tc.want)
+		}
+	}
+
+	// Hosts never booked go first
+	cs := append(candidates(), roundRobinCandidate{Host: host("e", 4), Weight: 1})
+	rankRoundRobinCandidates(models.RoundRobinLeastRecentlyBooked, cs)
+	if cs[0].Host.HostID != "e" {
+		t.Errorf("never-booked host ranked %s first", cs[0].Host.HostID)
+	}
+}
+
+func TestRoundRobin_AssignsOneFreeHost(t *testing.T) {
+	bookings, fix, cleanup := makeCreateBookingService(t)
+	defer cleanup()
+	ctx := context.Background()
+	repos := bookings.repos
+	owner := fix.hostIDs[0]
+
+	// A second host, open around the clock with no calendars
+	other := &models.Host{
+		ID: uuid.New().String(), TenantID: fix.tenantID, Email: "rr-" + uuid.New().String()[:6] + "@example.com",
+		PasswordHash: "x", Name: "Other", Slug: "other-" + uuid.New().String()[:6], Timezone: "UTC",
+		CreatedAt: models.Now(), UpdatedAt: models.Now(),
+	}
+	if err := repos.Host.Create(ctx, other); err != nil {
+		t.Fatalf("create host: %v", err)
+	}
+	var hours []*models.WorkingHours
+	for day := 0; day < 7; day++ {
+		hours = append(hours, &models.WorkingHours{ID: uuid.New().String(), HostID: other.ID, DayOfWeek: day, StartTime: "00:00", EndTime: "23:45", IsEnabled: true})
+	}
+	if err := repos.WorkingHours.SetForHost(ctx, other.ID, hours); err != nil {
+		t.Fatalf("set working hours: %v", err)
+	}
+	for i, id := range []string{owner, other.ID} {
+		role := models.TemplateHostRoleSibling
+		if id == owner {
+			role = models.TemplateHostRoleOwner
+		}
+		if err := repos.TemplateHost.Create(ctx, &models.TemplateHost{
+			ID: uuid.New().String(), TemplateID: fix.templateID, HostID: id, Role: role,
+			DisplayOrder: i, Weight: 1, CreatedAt: models.Now(), UpdatedAt: models.Now(),
+		}); err != nil {
+			t.Fatalf("pool host: %v", err)
+		}
+	}
+	tmpl, _ := repos.Template.GetByID(ctx, fix.templateID)
+	tmpl.SchedulingType = models.SchedulingTypeRoundRobin
+	tmpl.RoundRobinStrategy = models.RoundRobinLeastRecentlyBooked
+	if err := repos.Template.Update(ctx, tmpl); err != nil {
+		t.Fatalf("update template: %v", err)
+	}
+
+	start := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Hour)
+	offered := func(at time.Time) bool {
+		t.Helper()
+		ok, err := bookings.availability.IsSlotAvailable(ctx, owner, fix.templateID, at, 30)
+		if err != nil {
+			t.Fatalf("IsSlotAvailable: %v", err)
+		}
+		return ok
+	}
+
+	// The fixture's owner booking makes the other host least recently
+	// booked, then the owner, then nobody.
+	for i, want := range []string{other.ID, owner, ""} {
+		details, err := bookings.CreateBooking(ctx, createInput(fix, start, "rr@example.com"))
+		if want == "" {
+			if !errors.Is(err, ErrSlotNotAvailable) {
+				t.Errorf("third booking: err = %v, want ErrSlotNotAvailable", err)
+			}
+			if offered(start) {
+				t.Error("slot still offered with every host booked")
+			}
+			break
+		}
+		if err != nil {
+			t.Fatalf("booking %d: %v", i+1, err)
+		}
+		if details.Booking.HostID != want || details.Host.ID != want {
+			t.Errorf("booking %d assigned to %s, want %s", i+1, details.Booking.HostID, want)
+		}
+		if want == other.ID && !offered(start) {
+			t.Error("slot hidden while the owner is still free")
+		}
+		targets := bookings.bookingHostTargets(ctx, details)
+		if len(targets) != 1 || targets[0].HostID != want {
+			t.Errorf("calendar targets = %+v, want only the assigned host", targets)
+		}
+	}
+
+	stats, err := repos.Booking.GetAssignmentStats(ctx, fix.templateID, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("GetAssignmentStats: %v", err)
+	}
+	if stats[other.ID] == nil || stats[other.ID].Count != 1 || stats[owner] == nil || stats[owner].Count != 2 {
+		t.Errorf("stats = other %+v, owner %+v", stats[other.ID], stats[owner])
+	}
+}
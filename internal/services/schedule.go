@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/meet-when/meet-when/internal/models"
+	"github.com/meet-when/meet-when/internal/repository"
+)
+
+var (
+	ErrScheduleNotFound = errors.New("availability schedule not found")
+	ErrInvalidSchedule  = errors.New("invalid availability schedule")
+)
+
+// ScheduleService manages hosts' named availability schedules. A template
+// set to a schedule is offered on the schedule's weekly hours, read in its
+// timezone, instead of the host's working hours.
+type ScheduleService struct {
+	repos *repository.Repositories
+}
+
+// NewScheduleService creates a new schedule service
+func NewScheduleService(repos *repository.Repositories) *ScheduleService {
+	return &ScheduleService{repos: repos}
+}
+
+// ScheduleInput is a schedule's editable fields
+type ScheduleInput struct {
+	Name        string
+	Timezone    string
+	WeeklyHours models.WeeklyHours
+}
+
+// normalize trims the name, defaults the timezone to UTC and checks the
+// weekly hours, sorting them by day and start time.
+func (in *ScheduleInput) normalize() error {
+	in.Name = strings.TrimSpace(in.Name)
+	if in.Name == "" || len(in.Name) > 255 {
+		return fmt.Errorf("%w: name is required", ErrInvalidSchedule)
+	}
+	if in.Timezone == "" {
+		in.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(in.Timezone); err != nil {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalidSchedule, in.Timezone)
+	}
+
+	hours := make(models.WeeklyHours, 0, len(in.WeeklyHours))
+	for _, iv := range in.WeeklyHours {
+		from, err1 := time.Parse("15:04", iv.Start)
+		to, err2 := time.Parse("15:04", iv.End)
+		if iv.Day < 0 || iv.Day > 6 || err1 != nil || err2 != nil || !from.Before(to) {
+			return fmt.Errorf("%w: bad interval %s-%s on day %d", ErrInvalidSchedule, iv.Start, iv.End, iv.Day)
+		}
+		hours = append(hours, models.WeeklyInterval{Day: iv.Day, Start: from.Format("15:04"), End: to.Format("15:04")})
+	}
+	sort.Slice(hours, func(i, j int) bool {
+		if hours[i].Day != hours[j].Day {
+			return hours[i].Day < hours[j].Day
+		}
+		return hours[i].Start < hours[j].Start
+	})
+	in.WeeklyHours = hours
+	return nil
+}
+
+// GetSchedules returns the host's schedules by name
+func (s *ScheduleService) GetSchedules(ctx context.Context, hostID string) ([]*models.AvailabilitySchedule, error) {
+	return s.repos.AvailabilitySchedule.GetByHostID(ctx, hostID)
+}
+
+// GetSchedule returns one of the host's schedules
+func (s *ScheduleService) GetSchedule(ctx context.Context, hostID, id string) (*models.AvailabilitySchedule, error) {
+	schedule, err := s.repos.AvailabilitySchedule.GetByID(ctx, hostID, id)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, ErrScheduleNotFound
+	}
+	return schedule, nil
+}
+
+// CreateSchedule adds a schedule for the host
+func (s *ScheduleService) CreateSchedule(ctx context.Context, hostID string, input ScheduleInput) (*models.AvailabilitySchedule, error) {
+	if err := input.normalize(); err != nil {
+		return nil, err
+	}
+	now := models.Now()
+	schedule := &models.AvailabilitySchedule{
+		ID:          uuid.New().String(),
+		HostID:      hostID,
+		Name:        input.Name,
+		Timezone:    input.Timezone,
+		WeeklyHours: input.WeeklyHours,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repos.AvailabilitySchedule.Create(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// UpdateSchedule saves a schedule's name, timezone and weekly hours
+func (s *ScheduleService) UpdateSchedule(ctx context.Context, hostID, id string, input ScheduleInput) (*models.AvailabilitySchedule, error) {
+	schedule, err := s.GetSchedule(ctx, hostID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := input.normalize(); err != nil {
+		return nil, err
+	}
+	schedule.Name = input.Name
+	schedule.Timezone = input.Timezone
+	schedule.WeeklyHours = input.WeeklyHours
+	schedule.UpdatedAt = models.Now()
+	if err := s.repos.AvailabilitySchedule.Update(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// DeleteSchedule removes a schedule and its date overrides. Templates that
+// used it go back to the host's working hours.
+func (s *ScheduleService) DeleteSchedule(ctx context.Context, hostID, id string) error {
+	if _, err := s.GetSchedule(ctx, hostID, id); err != nil {
+		return err
+	}
+	return s.repos.AvailabilitySchedule.Delete(ctx, hostID, id)
+}
+
+// GetUpcomingOverrides returns the schedule's date overrides that haven't
+// ended yet in its timezone.
+func (s *ScheduleService) GetUpcomingOverrides(ctx context.Context, schedule *models.AvailabilitySchedule) ([]*models.AvailabilityOverride, error) {
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	all, err := s.repos.AvailabilityOverride.GetUpcomingByHostID(ctx, schedule.HostID, time.Now().In(loc).Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	var overrides []*models.AvailabilityOverride
+	for _, o := range all {
+		if o.ScheduleID != nil && *o.ScheduleID == schedule.ID {
+			overrides = append(overrides, o)
+		}
+	}
+	return overrides, nil
+}
+
+// ConvertAvailabilityRules moves templates off the availability_rules they
+// carried before schedules existed. Enabled rules become a schedule named
+// after the template, in its host's timezone, which the template is then
+// set to. Disabled rules had no effect and are dropped. It runs at startup
+// and returns how many schedules it created. A template that can't be
+// converted is logged and left as it is, so it can't keep the server from
+// starting.
+func (s *ScheduleService) ConvertAvailabilityRules(ctx context.Context) (int, error) {
+	ids, err := s.repos.AvailabilitySchedule.GetTemplateIDsWithRules(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	converted := 0
+	for _, id := range ids {
+		template, err := s.repos.Template.GetByID(ctx, id)
+		if err != nil {
+			log.Printf("[SCHEDULE] Not converting availability rules of template %s: %v", id, err)
+			continue
+		}
+		if template == nil {
+			continue
+		}
+
+		var schedule *models.AvailabilitySchedule
+		if rules := parseAvailabilityRules(template.AvailabilityRules); rules != nil && rules.Enabled {
+			host, err := s.repos.Host.GetByID(ctx, template.HostID)
+			if err != nil {
+				log.Printf("[SCHEDULE] Not converting availability rules of template %s: %v", template.ID, err)
+				continue
+			}
+			input := ScheduleInput{Name: template.Name, Timezone: "UTC"}
+			if host != nil {
+				input.Timezone = host.Timezone
+			}
+			input.WeeklyHours = weeklyHoursFromRules(rules)
+			if err := input.normalize(); err != nil {
+				// Keep the rules rather than guess at hours
+				log.Printf("[SCHEDULE] Not converting availability rules of template %s: %v", template.ID, err)
+				continue
+			}
+			now := models.Now()
+			schedule = &models.AvailabilitySchedule{
+				ID:          uuid.New().String(),
+				HostID:      template.HostID,
+				Name:        input.Name,
+				Timezone:    input.Timezone,
+				WeeklyHours: input.WeeklyHours,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			}
+		}
+
+		if err := s.repos.AvailabilitySchedule.ConvertTemplateRules(ctx, template.ID, schedule); err != nil {
+			log.Printf("[SCHEDULE] Failed to convert availability rules of template %s: %v", template.ID, err)
+			continue
+		}
+		if schedule != nil {
+			log.Printf("[SCHEDULE] Converted availability rules of template %s to schedule %s", template.ID, schedule.ID)
+			converted++
+		}
+	}
+	return converted, nil
+}
+
+// weeklyHoursFromRules lists the intervals of the rules' enabled days.
+func weeklyHoursFromRules(rules *TemplateAvailabilityRules) models.WeeklyHours {
+	hours := models.WeeklyHours{}
+	for day, d := range rules.Days {
+		if !d.Enabled {
+			continue
+		}
+		for _, iv := range d.Intervals {
+			hours = append(hours, models.WeeklyInterval{Day: day, Start: iv.Start, End: iv.End})
+		}
+	}
+	return hours
+}
+
+// scheduleWorkingHours returns the schedule's weekly hours in the form slot
+// generation takes working hours.
+func scheduleWorkingHours(schedule *models.AvailabilitySchedule) []*models.WorkingHours {
+	hours := make([]*models.WorkingHours, 0, len(schedule.WeeklyHours))
+	for _, iv := range schedule.WeeklyHours {
+		hours = append(hours, &models.WorkingHours{
+			HostID:    schedule.HostID,
+			DayOfWeek: iv.Day,
+			StartTime: iv.Start,
+			EndTime:   iv.End,
+			IsEnabled: true,
+		})
+	}
+	return hours
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+func TestScheduleInputNormalize(t *testing.T) {
+	in := ScheduleInput{
+		Name: "  Summer hours ",
+		WeeklyHours: models.WeeklyHours{
+			{Day: 3, Start: "13:00", End: "17:00"},
+			{Day: 1, Start: "09:00", End: "12:00"},
+			{Day: 3, Start: "09:00", End: "12:00"},
+		},
+	}
+	if err := in.normalize(); err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if in.Name != "Summer hours" || in.Timezone != "UTC" {
+		t.Errorf("name %q, timezone %q", in.Name, in.Timezone)
+	}
+	want := models.WeeklyHours{
+		{Day: 1, Start: "09:00", End: "12:00"},
+		{Day: 3, Start: "09:00", End: "12:00"},
+		{Day: 3, Start: "13:00", End: "17:00"},
+	}
+	if !reflect.DeepEqual(in.WeeklyHours, want) {
+		t.Errorf("weekly hours = %v, want %v", in.WeeklyHours, want)
+	}
+
+	for _, bad := range []ScheduleInput{
+		{Name: " "},
+		{Name: "x", Timezone: "Mars/Olympus"},
+		{Name: "x", WeeklyHours: models.WeeklyHours{{Day: 7, Start: "09:00", End: "17:00"}}},
+		{Name: "x", WeeklyHours: models.WeeklyHours{{Day: 1, Start: "17:00", End: "09:00"}}},
+	} {
+		if err := bad.normalize(); !errors.Is(err, ErrInvalidSchedule) {
+			t.Errorf("normalize(%+v): err = %v, want ErrInvalidSchedule", bad, err)
+		}
+	}
+}
+
+func TestSchedule_DrivesAvailableSlots(t *testing.T) {
+	bookings, fix, cleanup := makeCreateBookingService(t)
+	defer cleanup()
+	ctx := context.Background()
+	repos := bookings.repos
+	svc := bookings.availability
+	schedules := NewScheduleService(repos)
+	owner := fix.hostIDs[0]
+
+	// 09:00-10:00 in Tokyo every day is 00:00-01:00 UTC
+	var hours models.WeeklyHours
+	for day := 0; day < 7; day++ {
+		hours = append(hours, models.WeeklyInterval{Day: day, Start: "09:00", End: "10:00"})
+	}
+	schedule, err := schedules.CreateSchedule(ctx, owner, ScheduleInput{Name: "Tokyo support", Timezone: "Asia/Tokyo", WeeklyHours: hours})
+	if err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+	tmpl, _ := repos.Template.GetByID(ctx, fix.templateID)
+	tmpl.ScheduleID = schedule.ID
+	if err := repos.Template.Update(ctx, tmpl); err != nil {
+		t.Fatalf("update template: %v", err)
+	}
+
+	day := time.Now().UTC().AddDate(0, 0, 3).Truncate(24 * time.Hour)
+	date := day.Format("2006-01-02")
+	slotsOn := func() []models.TimeSlot {
+		t.Helper()
+		slots, err := svc.GetAvailableSlots(ctx, GetAvailableSlotsInput{
+			HostID: owner, TemplateID: fix.templateID,
+			StartDate: day, EndDate: day.Add(24 * time.Hour), Duration: 30, Timezone: "UTC",
+		})
+		if err != nil {
+			t.Fatalf("GetAvailableSlots: %v", err)
+		}
+		return slots
+	}
+
+	slots := slotsOn()
+	if len(slots) != 3 || !slots[0].Start.Equal(day) || !slots[2].End.Equal(day.Add(time.Hour)) {
+		t.Fatalf("slots = %v, want 00:00 to 01:00 UTC", slots)
+	}
+
+	// The schedule's own time off applies, a template override beats it
+	if _, err := svc.CreateOverride(ctx, owner, CreateOverrideInput{ScheduleID: schedule.ID, StartDate: date}); err != nil {
+		t.Fatalf("CreateOverride on schedule: %v", err)
+	}
+	if got := len(slotsOn()); got != 0 {
+		t.Errorf("schedule time off left %d slots", got)
+	}
+	if _, err := svc.CreateOverride(ctx, owner, CreateOverrideInput{
+		TemplateID: fix.templateID, StartDate: date, Intervals: []models.TimeRange{{Start: "10:00", End: "11:00"}},
+	}); err != nil {
+		t.Fatalf("CreateOverride on template: %v", err)
+	}
+	slots = slotsOn()
+	if len(slots) != 3 || !slots[0].Start.Equal(day.Add(time.Hour)) {
+		t.Errorf("slots = %v, want 01:00 to 02:00 UTC", slots)
+	}
+
+	if _, err := svc.CreateOverride(ctx, owner, CreateOverrideInput{TemplateID: fix.templateID, ScheduleID: schedule.ID, StartDate: date}); !errors.Is(err, ErrInvalidOverride) {
+		t.Errorf("override on both a template and a schedule: err = %v, want ErrInvalidOverride", err)
+	}
+
+	// Deleting the schedule puts the template back on working hours
+	if err := schedules.DeleteSchedule(ctx, owner, schedule.ID); err != nil {
+		t.Fatalf("DeleteSchedule: %v", err)
+	}
+	tmpl, _ = repos.Template.GetByID(ctx, fix.templateID)
+	if tmpl.ScheduleID != "" {
+		t.Errorf("template still on deleted schedule %s", tmpl.ScheduleID)
+	}
+}
+
+func TestConvertAvailabilityRules(t *testing.T) {
+	bookings, fix, cleanup := makeCreateBookingService(t)
+	defer cleanup()
+	ctx := context.Background()
+	repos := bookings.repos
+	schedules := NewScheduleService(repos)
+
+	tmpl, _ := repos.Template.GetByID(ctx, fix.templateID)
+	tmpl.AvailabilityRules = models.JSONMap{
+		"enabled": true,
+		"days": map[string]interface{}{
+			"1": map[string]interface{}{"enabled": true, "intervals": []interface{}{
+				map[string]interface{}{"start": "13:00", "end": "17:00"},
+				map[string]interface{}{"start": "09:00", "end": "12:00"},
+			}},
+			"2": map[string]interface{}{"enabled": false, "start": "09:00", "end": "17:00"},
+		},
+	}
+	if err := repos.Template.Update(ctx, tmpl); err != nil {
+		t.Fatalf("update template: %v", err)
+	}
+
+	n, err := schedules.ConvertAvailabilityRules(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("ConvertAvailabilityRules = %d, %v; want 1", n, err)
+	}
+	tmpl, _ = repos.Template.GetByID(ctx, fix.templateID)
+	if tmpl.AvailabilityRules != nil || tmpl.ScheduleID == "" {
+		t.Fatalf("template rules %v, schedule %q", tmpl.AvailabilityRules, tmpl.ScheduleID)
+	}
+	schedule, err := schedules.GetSchedule(ctx, tmpl.HostID, tmpl.ScheduleID)
+	if err != nil {
+		t.Fatalf("GetSchedule: %v", err)
+	}
+	host, _ := repos.Host.GetByID(ctx, tmpl.HostID)
+	want := models.WeeklyHours{{Day: 1, Start: "09:00", End: "12:00"}, {Day: 1, Start: "13:00", End: "17:00"}}
+	if schedule.Name != tmpl.Name || schedule.Timezone != host.Timezone || !reflect.DeepEqual(schedule.WeeklyHours, want) {
+		t.Errorf("schedule = %+v", schedule)
+	}
+
+	if n, err := schedules.ConvertAvailabilityRules(ctx); err != nil || n != 0 {
+		t.Errorf("second run = %d, %v; want 0", n, err)
+	}
+}
+
+func TestConvertAvailabilityRules_SkipsUnreadableTemplates(t *testing.T) {
+	db, repos, cleanup := setupTestRepos(t)
+	defer cleanup()
+	fix := seedFixture(t, db, repos, 1)
+	ctx := context.Background()
+	schedules := NewScheduleService(repos)
+
+	rules := models.JSONMap{
+		"enabled": true,
+		"days": map[string]interface{}{
+			"1": map[string]interface{}{"enabled": true, "start": "09:00", "end": "17:00"},
+		},
+	}
+	tmpl, _ := repos.Template.GetByID(ctx, fix.templateID)
+	tmpl.AvailabilityRules = rules
+	if err := repos.Template.Update(ctx, tmpl); err != nil {
+		t.Fatalf("update template: %v", err)
+	}
+	broken := *tmpl
+	broken.ID = uuid.New().String()
+	broken.Slug = "broken-" + broken.ID[:8]
+	if err := repos.Template.Create(ctx, &broken); err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `UPDATE meeting_templates SET durations = 'not json' WHERE id = ?`, broken.ID); err != nil {
+		t.Fatalf("break template: %v", err)
+	}
+
+	n, err := schedules.ConvertAvailabilityRules(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("ConvertAvailabilityRules = %d, %v; want 1", n, err)
+	}
+	if tmpl, _ = repos.Template.GetByID(ctx, fix.templateID); tmpl.ScheduleID == "" {
+		t.Errorf("readable template not converted")
+	}
+	ids, _ := repos.AvailabilitySchedule.GetTemplateIDsWithRules(ctx)
+	if len(ids) != 1 || ids[0] != broken.ID {
+		t.Errorf("templates still with rules = %v; want just the unreadable one", ids)
+	}
+}
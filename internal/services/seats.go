@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// MaxSeats is the most invitees a template's slots can take
+const MaxSeats = 500
+
+// normalizeSeats limits a template's seats to MaxSeats. One seat is the same
+// as none, and round-robin templates book one host per invitee, so they
+// can't have seats.
+func normalizeSeats(seats int, st models.SchedulingType) int {
+	if seats <= 1 || st == models.SchedulingTypeRoundRobin {
+		return 0
+	}
+	return min(seats, MaxSeats)
+}
+
+// seatedSlots is what a template with seats has booked in a stretch of time,
+// for slots of one duration.
+type seatedSlots struct {
+	taken map[int64]int     // seats taken, by slot start in Unix seconds
+	open  []models.TimeSlot // booked meeting times of the slots with seats left
+	ids   map[string]bool   // the bookings holding seats in those slots
+}
+
+// loadSeatedSlots counts the seats taken in the template's slots of duration
+// minutes that start between start and end. Bookings of other durations
+// aren't seats of these slots and block time as usual.
+func (s *AvailabilityService) loadSeatedSlots(ctx context.Context, template *models.MeetingTemplate, start, end time.Time, duration int) (*seatedSlots, error) {
+	bookings, err := s.repos.Booking.GetByTemplateIDAndTimeRange(ctx, template.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	seated := &seatedSlots{taken: make(map[int64]int), ids: make(map[string]bool)}
+	meetings := make(map[int64]models.TimeSlot)
+	for _, b := range bookings {
+		if b.Duration != duration {
+			continue
+		}
+		key := b.StartTime.Unix()
+		seated.taken[key]++
+		meetings[key] = models.TimeSlot{Start: b.StartTime.Time, End: b.EndTime.Time}
+	}
+	for _, b := range bookings {
+		if b.Duration == duration && seated.taken[b.StartTime.Unix()] < template.Seats {
+			seated.ids[b.ID] = true
+		}
+	}
+	for key, m := range meetings {
+		if seated.taken[key] < template.Seats {
+			seated.open = append(seated.open, m)
+		}
+	}
+	return seated, nil
+}
+
+// free leaves the seats of slots that aren't full out of a host's bookings
+// and calendars' busy times, the latter being the slots' shared calendar
+// events, so the slots themselves are still offered.
+func (ss *seatedSlots) free(bookings []*models.Booking, calendars []CalendarBusy) ([]*models.Booking, []CalendarBusy) {
+	var kept []*models.Booking
+	for _, b := range bookings {
+		if !ss.ids[b.ID] {
+			kept = append(kept, b)
+		}
+	}
+	freed := make([]CalendarBusy, 0, len(calendars))
+	for _, cb := range calendars {
+		busy := make([]models.TimeSlot, 0, len(cb.Busy))
+		for _, slot := range cb.Busy {
+			if !ss.isOpen(slot) {
+				busy = append(busy, slot)
+			}
+		}
+		freed = append(freed, CalendarBusy{Calendar: cb.Calendar, Busy: busy})
+	}
+	return kept, freed
+}
+
+func (ss *seatedSlots) isOpen(slot models.TimeSlot) bool {
+	for _, m := range ss.open {
+		if m.Start.Equal(slot.Start) && m.End.Equal(slot.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// keep drops the slots that overlap a booked slot with seats left, padded by
+// the template's buffers and the host's minimum gap. A booked slot is only
+// exempt from its own meeting, not from the others.
+func (ss *seatedSlots) keep(slots []models.TimeSlot, host *models.Host, template *models.MeetingTemplate) []models.TimeSlot {
+	if len(ss.open) == 0 {
+		return slots
+	}
+	before := time.Duration(max(template.PreBufferMinutes, host.MinGapMinutes)) * time.Minute
+	after := time.Duration(max(template.PostBufferMinutes, host.MinGapMinutes)) * time.Minute
+	var kept []models.TimeSlot
+	for _, slot := range slots {
+		ok := true
+		for _, m := range ss.open {
+			if slot.Start.Equal(m.Start) {
+				continue
+			}
+			if slot.Start.Before(m.End.Add(after)) && slot.End.After(m.Start.Add(-before)) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			kept = append(kept, slot)
+		}
+	}
+	return kept
+}
+
+// countSeatsLeft sets how many seats each of the template's slots has left.
+func (s *AvailabilityService) countSeatsLeft(ctx context.Context, template *models.MeetingTemplate, slots []models.TimeSlot, duration int) error {
+	if len(slots) == 0 {
+		return nil
+	}
+	seated, err := s.loadSeatedSlots(ctx, template, slots[0].Start, slots[len(slots)-1].Start.Add(time.Second), duration)
+	if err != nil {
+		return err
+	}
+	for i := range slots {
+		slots[i].SeatsLeft = template.Seats - seated.taken[slots[i].Start.Unix()]
+	}
+	return nil
+}
+
+// GetSeats returns the pending and confirmed bookings holding seats in the
+// booking's slot, in booking order. It is empty for templates without seats.
+func (s *BookingService) GetSeats(ctx context.Context, booking *models.Booking) ([]*models.Booking, error) {
+	template, err := s.repos.Template.GetByID(ctx, booking.TemplateID)
+	if err != nil || template == nil || !template.HasSeats() {
+		return nil, err
+	}
+	return s.slotSeats(ctx, booking.TemplateID, booking.StartTime.Time, booking.Duration)
+}
+
+// slotSeats returns the pending and confirmed bookings of the template's
+// slot of duration minutes at start, in booking order.
+func (s *BookingService) slotSeats(ctx context.Context, templateID string, start time.Time, duration int) ([]*models.Booking, error) {
+	bookings, err := s.repos.Booking.GetByTemplateIDAndTimeRange(ctx, templateID, start, start.Add(time.Second))
+	if err != nil {
+		return nil, err
+	}
+	var seats []*models.Booking
+	for _, b := range bookings {
+		if b.StartTime.Equal(start) && b.Duration == duration {
+			seats = append(seats, b)
+		}
+	}
+	return seats, nil
+}
+
+// sharedSeatLink returns the meeting link of the confirmed seats in the
+// booking's slot, if they have one.
+func (s *BookingService) sharedSeatLink(ctx context.Context, booking *models.Booking) string {
+	seats, err := s.slotSeats(ctx, booking.TemplateID, booking.StartTime.Time, booking.Duration)
+	if err != nil {
+		log.Printf("[BOOKING] Error loading seats of booking %s: %v", booking.ID, err)
+		return ""
+	}
+	for _, b := range seats {
+		if b.ID != booking.ID && b.Status == models.BookingStatusConfirmed && b.ConferenceLink != "" {
+			return b.ConferenceLink
+		}
+	}
+	return ""
+}
+
+// syncSeatEvent brings the calendar event shared by the confirmed seats of a
+// slot in line with them, after details.Booking joined the slot at start for
+// duration minutes or left it. The event is tracked against one seat, at
+// first the one it was created for. When that seat leaves, the event passes
+// to the next, and when the last confirmed seat leaves it is deleted. Every
+// confirmed seat carries the event's ID and the slot's meeting link.
+func (s *BookingService) syncSeatEvent(ctx context.Context, details *BookingWithDetails, start time.Time, duration int) error {
+	changed := details.Booking
+	seats, err := s.slotSeats(ctx, changed.TemplateID, start, duration)
+	if err != nil {
+		return err
+	}
+	var confirmed []*models.Booking
+	seated := false // changed is a confirmed seat of the slot
+	for _, b := range seats {
+		if b.ID == changed.ID {
+			b = changed
+		}
+		if b.Status == models.BookingStatusConfirmed {
+			confirmed = append(confirmed, b)
+			seated = seated || b == changed
+		}
+	}
+
+	// The seat holding the event may be the one that just left
+	var holder *models.Booking
+	for _, b := range append([]*models.Booking{changed}, confirmed...) {
+		rows, err := s.repos.BookingCalendarEvent.GetByBookingID(ctx, b.ID)
+		if err != nil {
+			return err
+		}
+		if len(rows) > 0 {
+			holder = b
+			break
+		}
+	}
+
+	if len(confirmed) == 0 {
+		if holder != nil {
+			if _, err := s.syncer.Delete(ctx, ItemKindBooking, holder.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if holder == changed && !seated {
+		if err := s.repos.BookingCalendarEvent.MoveToBooking(ctx, holder.ID, confirmed[0].ID); err != nil {
+			return err
+		}
+		holder = confirmed[0]
+	}
+
+	link := ""
+	for _, b := range confirmed {
+		if b.ConferenceLink != "" {
+			link = b.ConferenceLink
+			break
+		}
+	}
+	lead := holder
+	if lead == nil {
+		lead = confirmed[0]
+	}
+	leadDetails := &BookingWithDetails{
+		Booking:  lead,
+		Template: details.Template,
+		Host:     details.Host,
+		Tenant:   details.Tenant,
+		Seats:    confirmed,
+	}
+	input := s.calendar.BuildCalendarEventInputForBooking(leadDetails)
+	input.ConferenceLink = link
+
+	eventID := lead.CalendarEventID
+	if holder == nil {
+		firstID, meetLink, err := s.syncer.Create(ctx, CalendarSyncRequest{
+			Kind:   ItemKindBooking,
+			ItemID: lead.ID,
+			Input:  *input,
+			Hosts:  s.bookingHostTargets(ctx, leadDetails),
+		})
+		if err != nil {
+			return fmt.Errorf("create shared calendar event: %w", err)
+		}
+		eventID = firstID
+		if link == "" {
+			link = meetLink
+		}
+	} else if err := s.syncer.Update(ctx, CalendarSyncRequest{
+		Kind:   ItemKindBooking,
+		ItemID: holder.ID,
+		Input:  *input,
+	}); err != nil {
+		return fmt.Errorf("update shared calendar event: %w", err)
+	}
+
+	for _, b := range confirmed {
+		dirty := false
+		if eventID != "" && b.CalendarEventID != eventID {
+			b.CalendarEventID = eventID
+			dirty = true
+		}
+		if link != "" && b.ConferenceLink == "" {
+			b.ConferenceLink = link
+			dirty = true
+		}
+		if !dirty {
+			continue
+		}
+		if err := s.repos.Booking.Update(ctx, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+func TestNormalizeSeats(t *testing.T) {
+	for _, tc := range []struct {
+		seats int
+		st    models.SchedulingType
+		want  int
+	}{
+		{0, models.SchedulingTypeCollective, 0},
+		{1, models.SchedulingTypeCollective, 0},
+		{-3, models.SchedulingTypeCollective, 0},
+		{12, models.SchedulingTypeCollective, 12},
+		{12, models.SchedulingTypeRoundRobin, 0},
+		{MaxSeats + 1, models.SchedulingTypeCollective, MaxSeats},
+	} {
+		if got := normalizeSeats(tc.seats, tc.st); got != tc.want {
+			t.Errorf("normalizeSeats(%d, %s) = %d, want %d", tc.seats, tc.st, got, tc.want)
+		}
+	}
+}
+
+// TestSeatedSlotsKeep_ChecksEveryOpenMeeting has two open seated meetings,
+// 09:00 and 09:40, close enough that each overlaps the other's 15-minute
+// buffer, as they can after the buffers change. Neither meeting's own slot
+// is offered, whichever order the meetings are checked in.
+func TestSeatedSlotsKeep_ChecksEveryOpenMeeting(t *testing.T) {
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time { return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+	first := models.TimeSlot{Start: at(9, 0), End: at(9, 30)}
+	second := models.TimeSlot{Start: at(9, 40), End: at(10, 10)}
+	host := &models.Host{}
+	template := &models.MeetingTemplate{PreBufferMinutes: 15, PostBufferMinutes: 15}
+	slots := []models.TimeSlot{first, second, {Start: at(10, 30), End: at(11, 0)}}
+
+	for _, open := range [][]models.TimeSlot{{first, second}, {second, first}} {
+		ss := &seatedSlots{open: open}
+		kept := ss.keep(slots, host, template)
+		if len(kept) != 1 || !kept[0].Start.Equal(at(10, 30)) {
+			t.Errorf("open %v: kept %v; want just the 10:30 slot", open, kept)
+		}
+	}
+}
+
+func TestSeats_FillSlotThenClose(t *testing.T) {
+	bookings, fix, cleanup := makeCreateBookingService(t)
+	defer cleanup()
+	ctx := context.Background()
+	repos := bookings.repos
+	owner := fix.hostIDs[0]
+
+	tmpl, _ := repos.Template.GetByID(ctx, fix.templateID)
+	tmpl.Seats = 3
+	tmpl.MaxBookingsPerDay = 1
+	if err := repos.Template.Update(ctx, tmpl); err != nil {
+		t.Fatalf("update template: %v", err)
+	}
+
+	day := time.Now().UTC().AddDate(0, 0, 3).Truncate(24 * time.Hour)
+	start := day.Add(10 * time.Hour)
+	slotsOn := func() map[int64]int {
+		t.Helper()
+		slots, err := bookings.availability.GetAvailableSlots(ctx, GetAvailableSlotsInput{
+			HostID: owner, TemplateID: fix.templateID,
+			StartDate: day, EndDate: day.Add(24 * time.Hour), Duration: 30, Timezone: "UTC",
+		})
+		if err != nil {
+			t.Fatalf("GetAvailableSlots: %v", err)
+		}
+		left := make(map[int64]int, len(slots))
+		for _, s := range slots {
+			left[s.Start.Unix()] = s.SeatsLeft
+		}
+		return left
+	}
+
+	if got := slotsOn()[start.Unix()]; got != 3 {
+		t.Fatalf("empty slot has %d seats left, want 3", got)
+	}
+
+	first, err := bookings.CreateBooking(ctx, createInput(fix, start, "first@example.com"))
+	if err != nil {
+		t.Fatalf("first seat: %v", err)
+	}
+
+	// The daily cap counts the slot once, so it stays open while every
+	// other slot that day closes.
+	left := slotsOn()
+	if len(left) != 1 || left[start.Unix()] != 2 {
+		t.Fatalf("after one seat: slots = %v, want only 10:00 with 2 left", left)
+	}
+	if _, err := bookings.CreateBooking(ctx, createInput(fix, start, "second@example.com")); err != nil {
+		t.Fatalf("second seat: %v", err)
+	}
+	if _, err := bookings.CreateBooking(ctx, createInput(fix, start, "third@example.com")); err != nil {
+		t.Fatalf("third seat: %v", err)
+	}
+	if left := slotsOn(); len(left) != 0 {
+		t.Errorf("full slot still offered: %v", left)
+	}
+	if _, err := bookings.CreateBooking(ctx, createInput(fix, start, "fourth@example.com")); !errors.Is(err, ErrSlotNotAvailable) {
+		t.Errorf("booking a full slot: err = %v, want ErrSlotNotAvailable", err)
+	}
+
+	seats, err := bookings.GetSeats(ctx, first.Booking)
+	if err != nil || len(seats) != 3 {
+		t.Fatalf("GetSeats = %d seats, %v; want 3", len(seats), err)
+	}
+
+	// A cancelled seat can be taken again
+	if err := bookings.CancelBooking(ctx, first.Booking.ID, "invitee", ""); err != nil {
+		t.Fatalf("CancelBooking: %v", err)
+	}
+	if got := slotsOn()[start.Unix()]; got != 1 {
+		t.Errorf("after a cancellation the slot has %d seats left, want 1", got)
+	}
+}
+
+func TestSeats_ShareOneCalendarEvent(t *testing.T) {
+	bookings, fix, cleanup := makeCreateBookingService(t)
+	defer cleanup()
+	ctx := context.Background()
+	repos := bookings.repos
+	owner := fix.hostIDs[0]
+	fake := bookings.syncer.calendar.(*fakeCalendarWriter)
+
+	tmpl, _ := repos.Template.GetByID(ctx, fix.templateID)
+	tmpl.Seats = 5
+	if err := repos.Template.Update(ctx, tmpl); err != nil {
+		t.Fatalf("update template: %v", err)
+	}
+
+	start := time.Now().UTC().Add(72 * time.Hour).Truncate(time.Hour)
+	var seats []*models.Booking
+	for _, email := range []string{"a@example.com", "b@example.com"} {
+		created, err := bookings.CreateBooking(ctx, createInput(fix, start, email))
+		if err != nil {
+			t.Fatalf("seat for %s: %v", email, err)
+		}
+		if _, err := bookings.ApproveBooking(ctx, owner, fix.tenantID, created.Booking.ID); err != nil {
+			t.Fatalf("approve %s: %v", email, err)
+		}
+		seats = append(seats, created.Booking)
+	}
+
+	if len(fake.createCalls) != 1 || len(fake.updateCalls) != 1 {
+		t.Fatalf("%d creates, %d updates; want one event created then updated", len(fake.createCalls), len(fake.updateCalls))
+	}
+	if got := fake.updateCalls[0].Input.Attendees; len(got) != 2 {
+		t.Errorf("updated event attendees = %v, want both seats", got)
+	}
+	a, _ := repos.Booking.GetByID(ctx, seats[0].ID)
+	b, _ := repos.Booking.GetByID(ctx, seats[1].ID)
+	if a.CalendarEventID == "" || a.CalendarEventID != b.CalendarEventID {
+		t.Errorf("event IDs %q and %q, want one shared", a.CalendarEventID, b.CalendarEventID)
+	}
+
+	// The seat the event was created for leaves, and the other one keeps it
+	if err := bookings.CancelBooking(ctx, a.ID, "invitee", ""); err != nil {
+		t.Fatalf("cancel first seat: %v", err)
+	}
+	if len(fake.deleteCalls) != 0 || len(fake.updateCalls) != 2 {
+		t.Errorf("%d deletes, %d updates after one seat left; want the event updated", len(fake.deleteCalls), len(fake.updateCalls))
+	}
+	rows, _ := repos.BookingCalendarEvent.GetByBookingID(ctx, b.ID)
+	if len(rows) == 0 {
+		t.Error("event not passed to the remaining seat")
+	}
+
+	if err := bookings.CancelBooking(ctx, b.ID, "invitee", ""); err != nil {
+		t.Fatalf("cancel last seat: %v", err)
+	}
+	if len(fake.deleteCalls) != 1 {
+		t.Errorf("%d deletes after the last seat left, want 1", len(fake.deleteCalls))
+	}
+}
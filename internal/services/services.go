@@ -14,10 +14,15 @@ type Services struct {
 	Template     *TemplateService
 	Booking      *BookingService
 	Availability *AvailabilityService
+	Holiday      *HolidayService
+	Schedule     *ScheduleService
 	Email        *EmailService
+	Outbox       *EmailOutboxService
+	Webhook      *WebhookService
 	AuditLog     *AuditLogService
 	Reminder     *ReminderService
 	CalendarSync *CalendarSyncService
+	Reconciler   *CalendarReconciler
 	Timezone     *TimezoneService
 	Agenda       *AgendaService
 	Contact      *ContactService
@@ -26,24 +31,29 @@ type Services struct {
 
 // New creates all services
 func New(cfg *config.Config, repos *repository.Repositories) *Services {
-	emailSvc := NewEmailService(cfg)
+	emailSvc := NewEmailService(cfg, repos)
 	calendarSvc := NewCalendarService(cfg, repos)
 	conferencingSvc := NewConferencingService(cfg, repos)
-	availabilitySvc := NewAvailabilityService(repos, calendarSvc)
+	holidaySvc := NewHolidayService(repos, cfg.App.HolidaysPath)
+	availabilitySvc := NewAvailabilityService(repos, calendarSvc, holidaySvc)
+	scheduleSvc := NewScheduleService(repos)
 	auditLogSvc := NewAuditLogService(repos)
+	webhookSvc := NewWebhookService(repos)
 
 	contactSvc := NewContactService(repos)
 	syncerSvc := NewCalendarEventSyncer(repos, calendarSvc)
-	bookingSvc := NewBookingService(cfg, repos, calendarSvc, syncerSvc, conferencingSvc, emailSvc, auditLogSvc, contactSvc)
+	bookingSvc := NewBookingService(cfg, repos, calendarSvc, availabilitySvc, syncerSvc, conferencingSvc, emailSvc, auditLogSvc, contactSvc, webhookSvc)
 	templateSvc := NewTemplateService(repos, auditLogSvc)
 	sessionSvc := NewSessionService(cfg, repos)
 	authSvc := NewAuthService(cfg, repos, sessionSvc, auditLogSvc)
 	reminderSvc := NewReminderService(repos, emailSvc)
-	calendarSyncSvc := NewCalendarSyncService(calendarSvc, emailSvc, repos)
+	outboxSvc := NewEmailOutboxService(repos, emailSvc)
 
 	timezoneSvc := NewTimezoneService()
-	agendaSvc := NewAgendaService(repos, calendarSvc)
-	hostedEventSvc := NewHostedEventService(cfg, repos, calendarSvc, conferencingSvc, syncerSvc, emailSvc, contactSvc, auditLogSvc)
+	agendaSvc := NewAgendaService(repos, calendarSvc, holidaySvc)
+	hostedEventSvc := NewHostedEventService(cfg, repos, calendarSvc, availabilitySvc, conferencingSvc, syncerSvc, emailSvc, contactSvc, auditLogSvc, webhookSvc)
+	reconcilerSvc := NewCalendarReconciler(repos, calendarSvc, bookingSvc, hostedEventSvc)
+	calendarSyncSvc := NewCalendarSyncService(calendarSvc, reconcilerSvc, emailSvc, repos)
 
 	return &Services{
 		Auth:         authSvc,
@@ -53,10 +63,15 @@ func New(cfg *config.Config, repos *repository.Repositories) *Services {
 		Template:     templateSvc,
 		Booking:      bookingSvc,
 		Availability: availabilitySvc,
+		Holiday:      holidaySvc,
+		Schedule:     scheduleSvc,
 		Email:        emailSvc,
+		Outbox:       outboxSvc,
+		Webhook:      webhookSvc,
 		AuditLog:     auditLogSvc,
 		Reminder:     reminderSvc,
 		CalendarSync: calendarSyncSvc,
+		Reconciler:   reconcilerSvc,
 		Timezone:     timezoneSvc,
 		Agenda:       agendaSvc,
 		Contact:      contactSvc,
@@ -40,5 +40,5 @@ func setupTestRepos(t *testing.T) (*sql.DB, *repository.Repositories, func()) {
 		_ = db.Close()
 	}
 
-	return db, repository.NewRepositories(db, "sqlite"), cleanup
+	return db, repository.NewRepositories(db, "sqlite", nil), cleanup
 }
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"slices"
+	"time"
+
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+// SlotIntervals are the minutes between start times a template can choose.
+var SlotIntervals = []int{5, 10, 15, 20, 30, 45, 60, 90, 120}
+
+// normalizeSlotSettings falls back to the default interval for one that isn't
+// in SlotIntervals, and sorts and deduplicates the start minutes, dropping any
+// outside 0-59.
+func normalizeSlotSettings(interval int, startMinutes []int) (int, models.IntSlice) {
+	if !slices.Contains(SlotIntervals, interval) {
+		interval = 0
+	}
+	minutes := models.IntSlice{}
+	for _, m := range startMinutes {
+		if m >= 0 && m < 60 && !slices.Contains(minutes, m) {
+			minutes = append(minutes, m)
+		}
+	}
+	slices.Sort(minutes)
+	return interval, minutes
+}
+
+// slotGrid is where slots may start within a stretch of open time. With
+// neither align nor minutes set, starts step by interval from the start of
+// the stretch.
+type slotGrid struct {
+	interval time.Duration
+	align    bool  // starts on multiples of interval from midnight, in the stretch's timezone
+	minutes  []int // sorted; when set, starts only at these minutes past the hour
+}
+
+// templateSlotGrid returns the start-time grid the template's settings ask for.
+func templateSlotGrid(template *models.MeetingTemplate) slotGrid {
+	return slotGrid{
+		interval: time.Duration(template.EffectiveSlotInterval()) * time.Minute,
+		align:    template.AlignSlots,
+		minutes:  template.StartMinutes,
+	}
+}
+
+// first returns the earliest start at or after t.
+func (g slotGrid) first(t time.Time) time.Time {
+	if len(g.minutes) > 0 {
+		hour := t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+		for {
+			for _, m := range g.minutes {
+				if start := hour.Add(time.Duration(m) * time.Minute); !start.Before(t) {
+					return start
+				}
+			}
+			hour = hour.Add(time.Hour)
+		}
+	}
+	if g.align && g.interval > 0 {
+		// Count in wall-clock minutes so starts stay on :00 and :30 across
+		// a DST change
+		step := int(g.interval / time.Minute)
+		elapsed := t.Hour()*60 + t.Minute()
+		if t.Second() > 0 || t.Nanosecond() > 0 {
+			elapsed++
+		}
+		y, m, d := t.Date()
+		start := time.Date(y, m, d, 0, (elapsed+step-1)/step*step, 0, 0, t.Location())
+		if start.Before(t) {
+			// A repeated hour resolved to its first occurrence
+			return t
+		}
+		return start
+	}
+	return t
+}
+
+// next returns the start after start.
+func (g slotGrid) next(start time.Time) time.Time {
+	if len(g.minutes) > 0 || g.align {
+		return g.first(start.Add(time.Minute))
+	}
+	return start.Add(g.interval)
+}
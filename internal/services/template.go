@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/meet-when/meet-when/internal/models"
@@ -21,7 +22,9 @@ var (
 	ErrPooledHostLimit      = errors.New("maximum 5 hosts per template")
 	ErrHostNotInTenant      = errors.New("host must be in the same tenant")
 	ErrInvalidDuration      = errors.New("invalid duration")
+	ErrInvalidHostWeight    = errors.New("host weight must be between 1 and 10")
 	MaxPooledHostsPerTemplate = 5
+	MaxPooledHostWeight       = 10
 )
 
 // validateDurations validates, deduplicates, and sorts durations.
@@ -63,27 +66,51 @@ func NewTemplateService(repos *repository.Repositories, auditLog *AuditLogServic
 	}
 }
 
+// checkSchedule makes sure a template's schedule belongs to its host.
+func (s *TemplateService) checkSchedule(ctx context.Context, hostID, scheduleID string) error {
+	if scheduleID == "" {
+		return nil
+	}
+	schedule, err := s.repos.AvailabilitySchedule.GetByID(ctx, hostID, scheduleID)
+	if err != nil {
+		return err
+	}
+	if schedule == nil {
+		return ErrScheduleNotFound
+	}
+	return nil
+}
+
 // CreateTemplateInput represents the input for creating a template
 type CreateTemplateInput struct {
-	HostID            string
-	TenantID          string
-	Slug              string
-	Name              string
-	Description       string
-	Durations         []int
-	LocationType      models.ConferencingProvider
-	CustomLocation    string
-	CalendarID        string
-	RequiresApproval  bool
-	MinNoticeMinutes  int
-	MaxScheduleDays   int
-	PreBufferMinutes  int
-	PostBufferMinutes int
-	AvailabilityRules models.JSONMap
-	InviteeQuestions  models.JSONArray
-	ConfirmationEmail string
-	ReminderEmail     string
-	IsPrivate         bool
+	HostID             string
+	TenantID           string
+	Slug               string
+	Name               string
+	Description        string
+	Durations          []int
+	LocationType       models.ConferencingProvider
+	CustomLocation     string
+	CalendarID         string
+	RequiresApproval   bool
+	MinNoticeMinutes   int
+	MaxScheduleDays    int
+	MaxBookingsPerDay  int
+	MaxBookingsPerWeek int
+	SchedulingType     models.SchedulingType
+	RoundRobinStrategy models.RoundRobinStrategy
+	SlotInterval       int
+	AlignSlots         bool
+	StartMinutes       []int
+	Seats              int               // invitees per slot, 0 for one
+	Reminders          []models.Reminder // nil for the default reminders
+	PreBufferMinutes   int
+	PostBufferMinutes  int
+	ScheduleID         string // a schedule of the host's, empty for their working hours
+	InviteeQuestions   models.JSONArray
+	ConfirmationEmail  string
+	ReminderEmail      string
+	IsPrivate          bool
 }
 
 // CreateTemplate creates a new meeting template
@@ -114,31 +141,50 @@ func (s *TemplateService) CreateTemplate(ctx context.Context, input CreateTempla
 		return nil, err
 	}
 	input.Durations = validDurations
+	input.SchedulingType, input.RoundRobinStrategy = normalizeScheduling(input.SchedulingType, input.RoundRobinStrategy)
+	slotInterval, startMinutes := normalizeSlotSettings(input.SlotInterval, input.StartMinutes)
+	reminders := models.DefaultReminders
+	if input.Reminders != nil {
+		reminders = normalizeReminders(input.Reminders)
+	}
+	input.Seats = normalizeSeats(input.Seats, input.SchedulingType)
+	if err := s.checkSchedule(ctx, input.HostID, input.ScheduleID); err != nil {
+		return nil, err
+	}
 
 	now := models.Now()
 	template := &models.MeetingTemplate{
-		ID:                uuid.New().String(),
-		HostID:            input.HostID,
-		Slug:              input.Slug,
-		Name:              input.Name,
-		Description:       input.Description,
-		Durations:         input.Durations,
-		LocationType:      input.LocationType,
-		CustomLocation:    input.CustomLocation,
-		CalendarID:        input.CalendarID,
-		RequiresApproval:  input.RequiresApproval,
-		MinNoticeMinutes:  input.MinNoticeMinutes,
-		MaxScheduleDays:   input.MaxScheduleDays,
-		PreBufferMinutes:  input.PreBufferMinutes,
-		PostBufferMinutes: input.PostBufferMinutes,
-		AvailabilityRules: input.AvailabilityRules,
-		InviteeQuestions:  input.InviteeQuestions,
-		ConfirmationEmail: input.ConfirmationEmail,
-		ReminderEmail:     input.ReminderEmail,
-		IsActive:          true,
-		IsPrivate:         input.IsPrivate,
-		CreatedAt:         now,
-		UpdatedAt:         now,
+		ID:                 uuid.New().String(),
+		HostID:             input.HostID,
+		Slug:               input.Slug,
+		Name:               input.Name,
+		Description:        input.Description,
+		Durations:          input.Durations,
+		LocationType:       input.LocationType,
+		CustomLocation:     input.CustomLocation,
+		CalendarID:         input.CalendarID,
+		RequiresApproval:   input.RequiresApproval,
+		MinNoticeMinutes:   input.MinNoticeMinutes,
+		MaxScheduleDays:    input.MaxScheduleDays,
+		MaxBookingsPerDay:  max(input.MaxBookingsPerDay, 0),
+		MaxBookingsPerWeek: max(input.MaxBookingsPerWeek, 0),
+		SchedulingType:     input.SchedulingType,
+		RoundRobinStrategy: input.RoundRobinStrategy,
+		SlotInterval:       slotInterval,
+		AlignSlots:         input.AlignSlots,
+		StartMinutes:       startMinutes,
+		Seats:              input.Seats,
+		Reminders:          reminders,
+		PreBufferMinutes:   input.PreBufferMinutes,
+		PostBufferMinutes:  input.PostBufferMinutes,
+		ScheduleID:         input.ScheduleID,
+		InviteeQuestions:   input.InviteeQuestions,
+		ConfirmationEmail:  input.ConfirmationEmail,
+		ReminderEmail:      input.ReminderEmail,
+		IsActive:           true,
+		IsPrivate:          input.IsPrivate,
+		CreatedAt:          now,
+		UpdatedAt:          now,
 	}
 
 	if err := s.repos.Template.Create(ctx, template); err != nil {
@@ -159,27 +205,36 @@ func (s *TemplateService) CreateTemplate(ctx context.Context, input CreateTempla
 
 // UpdateTemplateInput represents the input for updating a template
 type UpdateTemplateInput struct {
-	ID                string
-	HostID            string
-	TenantID          string
-	Slug              string
-	Name              string
-	Description       string
-	Durations         []int
-	LocationType      models.ConferencingProvider
-	CustomLocation    string
-	CalendarID        string
-	RequiresApproval  bool
-	MinNoticeMinutes  int
-	MaxScheduleDays   int
-	PreBufferMinutes  int
-	PostBufferMinutes int
-	AvailabilityRules models.JSONMap
-	InviteeQuestions  models.JSONArray
-	ConfirmationEmail string
-	ReminderEmail     string
-	IsActive          bool
-	IsPrivate         bool
+	ID                 string
+	HostID             string
+	TenantID           string
+	Slug               string
+	Name               string
+	Description        string
+	Durations          []int
+	LocationType       models.ConferencingProvider
+	CustomLocation     string
+	CalendarID         string
+	RequiresApproval   bool
+	MinNoticeMinutes   int
+	MaxScheduleDays    int
+	MaxBookingsPerDay  int
+	MaxBookingsPerWeek int
+	SchedulingType     models.SchedulingType
+	RoundRobinStrategy models.RoundRobinStrategy
+	SlotInterval       int
+	AlignSlots         bool
+	StartMinutes       []int
+	Seats              int               // invitees per slot, 0 for one
+	Reminders          []models.Reminder // nil to keep the template's
+	PreBufferMinutes   int
+	PostBufferMinutes  int
+	ScheduleID         string // a schedule of the host's, empty for their working hours
+	InviteeQuestions   models.JSONArray
+	ConfirmationEmail  string
+	ReminderEmail      string
+	IsActive           bool
+	IsPrivate          bool
 }
 
 // UpdateTemplate updates an existing template
@@ -209,6 +264,12 @@ func (s *TemplateService) UpdateTemplate(ctx context.Context, input UpdateTempla
 		return nil, err
 	}
 	input.Durations = validDurations
+	input.SchedulingType, input.RoundRobinStrategy = normalizeScheduling(input.SchedulingType, input.RoundRobinStrategy)
+	slotInterval, startMinutes := normalizeSlotSettings(input.SlotInterval, input.StartMinutes)
+	input.Seats = normalizeSeats(input.Seats, input.SchedulingType)
+	if err := s.checkSchedule(ctx, input.HostID, input.ScheduleID); err != nil {
+		return nil, err
+	}
 
 	template.Slug = input.Slug
 	template.Name = input.Name
@@ -220,9 +281,20 @@ func (s *TemplateService) UpdateTemplate(ctx context.Context, input UpdateTempla
 	template.RequiresApproval = input.RequiresApproval
 	template.MinNoticeMinutes = input.MinNoticeMinutes
 	template.MaxScheduleDays = input.MaxScheduleDays
+	template.MaxBookingsPerDay = max(input.MaxBookingsPerDay, 0)
+	template.MaxBookingsPerWeek = max(input.MaxBookingsPerWeek, 0)
+	template.SchedulingType = input.SchedulingType
+	template.RoundRobinStrategy = input.RoundRobinStrategy
+	template.SlotInterval = slotInterval
+	template.AlignSlots = input.AlignSlots
+	template.StartMinutes = startMinutes
+	template.Seats = input.Seats
+	if input.Reminders != nil {
+		template.Reminders = normalizeReminders(input.Reminders)
+	}
 	template.PreBufferMinutes = input.PreBufferMinutes
 	template.PostBufferMinutes = input.PostBufferMinutes
-	template.AvailabilityRules = input.AvailabilityRules
+	template.ScheduleID = input.ScheduleID
 	template.InviteeQuestions = input.InviteeQuestions
 	template.ConfirmationEmail = input.ConfirmationEmail
 	template.ReminderEmail = input.ReminderEmail
@@ -312,28 +384,38 @@ func (s *TemplateService) DuplicateTemplate(ctx context.Context, hostID, tenantI
 
 	now := models.Now()
 	duplicate := &models.MeetingTemplate{
-		ID:                uuid.New().String(),
-		HostID:            original.HostID,
-		Slug:              slug,
-		Name:              original.Name + " (Copy)",
-		Description:       original.Description,
-		Durations:         original.Durations,
-		LocationType:      original.LocationType,
-		CustomLocation:    original.CustomLocation,
-		CalendarID:        original.CalendarID,
-		RequiresApproval:  original.RequiresApproval,
-		MinNoticeMinutes:  original.MinNoticeMinutes,
-		MaxScheduleDays:   original.MaxScheduleDays,
-		PreBufferMinutes:  original.PreBufferMinutes,
-		PostBufferMinutes: original.PostBufferMinutes,
-		AvailabilityRules: original.AvailabilityRules,
-		InviteeQuestions:  original.InviteeQuestions,
-		ConfirmationEmail: original.ConfirmationEmail,
-		ReminderEmail:     original.ReminderEmail,
-		IsActive:          false, // New copies are inactive by default
-		IsPrivate:         original.IsPrivate,
-		CreatedAt:         now,
-		UpdatedAt:         now,
+		ID:                 uuid.New().String(),
+		HostID:             original.HostID,
+		Slug:               slug,
+		Name:               original.Name + " (Copy)",
+		Description:        original.Description,
+		Durations:          original.Durations,
+		LocationType:       original.LocationType,
+		CustomLocation:     original.CustomLocation,
+		CalendarID:         original.CalendarID,
+		RequiresApproval:   original.RequiresApproval,
+		MinNoticeMinutes:   original.MinNoticeMinutes,
+		MaxScheduleDays:    original.MaxScheduleDays,
+		MaxBookingsPerDay:  original.MaxBookingsPerDay,
+		MaxBookingsPerWeek: original.MaxBookingsPerWeek,
+		SchedulingType:     original.SchedulingType,
+		RoundRobinStrategy: original.RoundRobinStrategy,
+		SlotInterval:       original.SlotInterval,
+		AlignSlots:         original.AlignSlots,
+		StartMinutes:       original.StartMinutes,
+		Seats:              original.Seats,
+		Reminders:          original.Reminders,
+		PreBufferMinutes:   original.PreBufferMinutes,
+		PostBufferMinutes:  original.PostBufferMinutes,
+		AvailabilityRules:  original.AvailabilityRules,
+		ScheduleID:         original.ScheduleID,
+		InviteeQuestions:   original.InviteeQuestions,
+		ConfirmationEmail:  original.ConfirmationEmail,
+		ReminderEmail:      original.ReminderEmail,
+		IsActive:           false, // New copies are inactive by default
+		IsPrivate:          original.IsPrivate,
+		CreatedAt:          now,
+		UpdatedAt:          now,
 	}
 
 	if err := s.repos.Template.Create(ctx, duplicate); err != nil {
@@ -414,6 +496,7 @@ func (s *TemplateService) AddPooledHost(ctx context.Context, tenantID, templateI
 		Role:         role,
 		IsOptional:   isOptional,
 		DisplayOrder: count, // Add at the end
+		Weight:       1,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
@@ -498,6 +581,49 @@ func (s *TemplateService) UpdatePooledHost(ctx context.Context, tenantID, templa
 	return nil
 }
 
+// SetPooledHostWeight sets a pooled host's share of round-robin bookings
+// under the weighted strategy
+func (s *TemplateService) SetPooledHostWeight(ctx context.Context, tenantID, templateID, hostID string, weight int) error {
+	if weight < 1 || weight > MaxPooledHostWeight {
+		return ErrInvalidHostWeight
+	}
+
+	template, err := s.repos.Template.GetByID(ctx, templateID)
+	if err != nil || template == nil {
+		return ErrTemplateNotFound
+	}
+
+	templateHost, err := s.repos.TemplateHost.GetByTemplateAndHost(ctx, templateID, hostID)
+	if err != nil {
+		return err
+	}
+	if templateHost == nil {
+		return ErrPooledHostNotFound
+	}
+
+	templateHost.Weight = weight
+	templateHost.UpdatedAt = models.Now()
+
+	if err := s.repos.TemplateHost.Update(ctx, templateHost); err != nil {
+		return err
+	}
+
+	// Audit log
+	s.auditLog.Log(ctx, tenantID, &template.HostID, "template.host_updated", "template", templateID, map[string]interface{}{
+		"host_id": hostID,
+		"weight":  weight,
+	}, "")
+
+	return nil
+}
+
+// GetAssignmentStats returns how the template's bookings have been assigned
+// to its pooled hosts: bookings made in the last 30 days and when each host
+// was last booked, keyed by host ID
+func (s *TemplateService) GetAssignmentStats(ctx context.Context, templateID string) (map[string]*repository.AssignmentStat, error) {
+	return s.repos.Booking.GetAssignmentStats(ctx, templateID, time.Now().AddDate(0, 0, -roundRobinWindowDays))
+}
+
 // GetPooledHosts returns all pooled hosts for a template
 func (s *TemplateService) GetPooledHosts(ctx context.Context, templateID string) ([]*models.TemplateHost, error) {
 	return s.repos.TemplateHost.GetByTemplateIDWithHost(ctx, templateID)
@@ -524,6 +650,7 @@ func (s *TemplateService) EnsureOwnerInPool(ctx context.Context, template *model
 		Role:         models.TemplateHostRoleOwner,
 		IsOptional:   false,
 		DisplayOrder: 0,
+		Weight:       1,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
@@ -0,0 +1,690 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/meet-when/meet-when/internal/models"
+	"github.com/meet-when/meet-when/internal/repository"
+)
+
+const (
+	// webhookMaxAttempts is how many times a delivery is tried before it fails
+	webhookMaxAttempts = 8
+	// webhookBaseBackoff is the wait after the first failed attempt; each
+	// further failure doubles it, up to webhookMaxBackoff
+	webhookBaseBackoff = time.Minute
+	webhookMaxBackoff  = 6 * time.Hour
+	// webhookTimeout is how long a receiver has to answer
+	webhookTimeout = 10 * time.Second
+	// webhookLease is how long a claimed delivery is left to its worker
+	// before another may try it
+	webhookLease = 2 * time.Minute
+	// webhookBatchSize is how many due deliveries one pass posts
+	webhookBatchSize = 50
+	// webhookLogRetention is how long finished deliveries stay in the log
+	webhookLogRetention = 30 * 24 * time.Hour
+	// webhookLogLimit is how many deliveries the log shows per webhook
+	webhookLogLimit = 100
+)
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrInvalidWebhookURL       = errors.New("webhook URL must be an http or https URL on the public internet")
+)
+
+// WebhookEventOption is an event type a webhook can subscribe to
+type WebhookEventOption struct {
+	Type  models.WebhookEventType
+	Label string
+}
+
+// WebhookEventOptions lists every event type, in the order the webhooks
+// page shows them
+var WebhookEventOptions = []WebhookEventOption{
+	{models.WebhookEventBookingCreated, "Booking created"},
+	{models.WebhookEventBookingApproved, "Booking approved"},
+	{models.WebhookEventBookingRejected, "Booking declined"},
+	{models.WebhookEventBookingRescheduled, "Booking rescheduled"},
+	{models.WebhookEventBookingCancelled, "Booking cancelled"},
+	{models.WebhookEventBookingUpdated, "Booking edited"},
+	{models.WebhookEventHostedEventCreated, "Event scheduled"},
+	{models.WebhookEventHostedEventRescheduled, "Event rescheduled"},
+	{models.WebhookEventHostedEventCancelled, "Event cancelled"},
+	{models.WebhookEventHostedEventUpdated, "Event edited"},
+}
+
+// WebhookEvent is the JSON body posted to a webhook. ID stays the same
+// across retries and redeliveries, so receivers can drop duplicates.
+type WebhookEvent struct {
+	ID        string                  `json:"id"`
+	Type      models.WebhookEventType `json:"type"`
+	CreatedAt time.Time               `json:"created_at"`
+	Data      interface{}             `json:"data"`
+}
+
+// WebhookInput is the host-editable part of a webhook
+type WebhookInput struct {
+	URL        string
+	EventTypes []string // empty for every event, including ones added later
+	IsActive   bool
+}
+
+// webhookBackoff returns the wait before the next attempt after attempts
+// failed ones
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := 1; i < attempts && backoff < webhookMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, webhookMaxBackoff)
+}
+
+// signWebhook returns the X-MeetWhen-Signature header for posting body at
+// t: the unix time and the hex HMAC-SHA256 of "<unix time>.<body>" keyed
+// with the webhook's secret. Receivers recompute it to check a post came
+// from us, and turn away old times so a captured post can't be replayed.
+func signWebhook(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookService manages webhook subscriptions, records the booking and
+// hosted event events they want, and posts them in the background,
+// retrying failures with exponential backoff
+type WebhookService struct {
+	repos    *repository.Repositories
+	client   *http.Client
+	interval time.Duration
+	wake     chan struct{}
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(repos *repository.Repositories) *WebhookService {
+	client := newPublicHTTPClient(webhookTimeout)
+	// A redirect is an answer other than 2xx, not somewhere to post
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &WebhookService{
+		repos:    repos,
+		client:   client,
+		interval: 30 * time.Second, // Retries come due between events
+		wake:     make(chan struct{}, 1),
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// normalizeWebhookInput trims the URL and checks it's http(s) on a public
+// host, and keeps the known event types, once each, in the order of
+// WebhookEventOptions
+func normalizeWebhookInput(input WebhookInput) (string, models.StringSlice, error) {
+	rawURL := strings.TrimSpace(input.URL)
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", nil, ErrInvalidWebhookURL
+	}
+	if checkPublicURLHost(u) != nil {
+		return "", nil, ErrInvalidWebhookURL
+	}
+
+	wanted := make(map[string]bool, len(input.EventTypes))
+	for _, t := range input.EventTypes {
+		wanted[t] = true
+	}
+	eventTypes := models.StringSlice{}
+	for _, opt := range WebhookEventOptions {
+		if wanted[string(opt.Type)] {
+			eventTypes = append(eventTypes, string(opt.Type))
+		}
+	}
+	return rawURL, eventTypes, nil
+}
+
+// CreateWebhook adds a webhook for a tenant's bookings and events or, with
+// hostID set, only that host's. It gets a fresh signing secret.
+func (s *WebhookService) CreateWebhook(ctx context.Context, tenantID string, hostID *string, input WebhookInput) (*models.WebhookSubscription, error) {
+	rawURL, eventTypes, err := normalizeWebhookInput(input)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := generateToken(24)
+	if err != nil {
+		return nil, err
+	}
+
+	now := models.Now()
+	sub := &models.WebhookSubscription{
+		ID:         uuid.New().String(),
+		TenantID:   tenantID,
+		HostID:     hostID,
+		URL:        rawURL,
+		Secret:     "whsec_" + secret,
+		EventTypes: eventTypes,
+		IsActive:   input.IsActive,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.repos.WebhookSubscription.Create(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// GetWebhook returns one of a tenant's webhooks. With hostID set, it must
+// be that host's own.
+func (s *WebhookService) GetWebhook(ctx context.Context, tenantID, hostID, id string) (*models.WebhookSubscription, error) {
+	sub, err := s.repos.WebhookSubscription.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil || sub.TenantID != tenantID || (hostID != "" && (sub.HostID == nil || *sub.HostID != hostID)) {
+		return nil, ErrWebhookNotFound
+	}
+	return sub, nil
+}
+
+// ListWebhooks returns a tenant's webhooks. With hostID set, only that
+// host's own.
+func (s *WebhookService) ListWebhooks(ctx context.Context, tenantID, hostID string) ([]*models.WebhookSubscription, error) {
+	return s.repos.WebhookSubscription.GetByTenantID(ctx, tenantID, hostID)
+}
+
+// UpdateWebhook changes a webhook's URL, event types and whether it's
+// active. The webhook must be one GetWebhook would return.
+func (s *WebhookService) UpdateWebhook(ctx context.Context, tenantID, hostID, id string, input WebhookInput) (*models.WebhookSubscription, error) {
+	sub, err := s.GetWebhook(ctx, tenantID, hostID, id)
+	if err != nil {
+		return nil, err
+	}
+	rawURL, eventTypes, err := normalizeWebhookInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	sub.URL = rawURL
+	sub.EventTypes = eventTypes
+	sub.IsActive = input.IsActive
+	sub.UpdatedAt = models.Now()
+	if err := s.repos.WebhookSubscription.Update(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// DeleteWebhook removes a webhook and its delivery log. The webhook must be
+// one GetWebhook would return.
+func (s *WebhookService) DeleteWebhook(ctx context.Context, tenantID, hostID, id string) (*models.WebhookSubscription, error) {
+	sub, err := s.GetWebhook(ctx, tenantID, hostID, id)
+	if err != nil {
+		return nil, err
+	}
+	return sub, s.repos.WebhookSubscription.Delete(ctx, id)
+}
+
+// ListDeliveries returns a webhook's most recent deliveries, newest first
+func (s *WebhookService) ListDeliveries(ctx context.Context, subscriptionID string) ([]*models.WebhookDelivery, error) {
+	return s.repos.WebhookDelivery.GetBySubscriptionID(ctx, subscriptionID, webhookLogLimit)
+}
+
+// Redeliver posts a finished delivery's event again, as a new delivery with
+// a fresh set of attempts. The delivery's webhook must be one GetWebhook
+// would return.
+func (s *WebhookService) Redeliver(ctx context.Context, tenantID, hostID, id string) (*models.WebhookDelivery, error) {
+	d, err := s.repos.WebhookDelivery.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil || d.Status == models.WebhookDeliveryStatusPending {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	if _, err := s.GetWebhook(ctx, tenantID, hostID, d.SubscriptionID); err != nil {
+		if errors.Is(err, ErrWebhookNotFound) {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	now := models.Now()
+	again := &models.WebhookDelivery{
+		ID:             uuid.New().String(),
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         models.WebhookDeliveryStatusPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.repos.WebhookDelivery.Create(ctx, again); err != nil {
+		return nil, err
+	}
+	s.notify()
+	return again, nil
+}
+
+// Emit records an event about one of hostID's bookings or events for each
+// active webhook that hears about the host and wants the event type, for
+// the delivery worker to post. It doesn't wait for the posts, and failing
+// to record is logged rather than returned, so it never holds up the
+// change it reports.
+func (s *WebhookService) Emit(ctx context.Context, tenantID, hostID string, eventType models.WebhookEventType, data interface{}) {
+	// The event outlives the request that caused it
+	ctx = context.WithoutCancel(ctx)
+
+	subs, err := s.repos.WebhookSubscription.GetActiveForHost(ctx, tenantID, hostID)
+	if err != nil {
+		log.Printf("[WEBHOOK] Error loading webhooks for %s: %v", eventType, err)
+		return
+	}
+	var wanted []*models.WebhookSubscription
+	for _, sub := range subs {
+		if sub.Wants(eventType) {
+			wanted = append(wanted, sub)
+		}
+	}
+	if len(wanted) == 0 {
+		return
+	}
+
+	event := WebhookEvent{
+		ID:        uuid.New().String(),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Data:      data,
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("[WEBHOOK] Error encoding %s event: %v", eventType, err)
+		return
+	}
+
+	now := models.Now()
+	for _, sub := range wanted {
+		d := &models.WebhookDelivery{
+			ID:             uuid.New().String(),
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      eventType,
+			Payload:        string(payload),
+			Status:         models.WebhookDeliveryStatusPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		if err := s.repos.WebhookDelivery.Create(ctx, d); err != nil {
+			log.Printf("[WEBHOOK] Error queueing %s for webhook %s: %v", eventType, sub.ID, err)
+		}
+	}
+	s.notify()
+}
+
+// EmitBooking records a booking event. previousStart is where a
+// rescheduled booking started before, and changed the fields an edit
+// changed; either may be left empty.
+func (s *WebhookService) EmitBooking(ctx context.Context, eventType models.WebhookEventType, details *BookingWithDetails, previousStart time.Time, changed []string) {
+	tenantID := ""
+	if details.Host != nil {
+		tenantID = details.Host.TenantID
+	} else if details.Tenant != nil {
+		tenantID = details.Tenant.ID
+	}
+	if tenantID == "" {
+		return
+	}
+
+	data := webhookBookingData{
+		Booking:       newWebhookBooking(details),
+		ChangedFields: changed,
+	}
+	if !previousStart.IsZero() {
+		data.PreviousStartTime = &previousStart
+	}
+	s.Emit(ctx, tenantID, details.Booking.HostID, eventType, data)
+}
+
+// EmitHostedEvent records a hosted event event. previousStart is where a
+// rescheduled event started before, and changed the fields an edit
+// changed; either may be left empty.
+func (s *WebhookService) EmitHostedEvent(ctx context.Context, eventType models.WebhookEventType, details *HostedEventWithDetails, previousStart time.Time, changed []string) {
+	data := webhookHostedEventData{
+		HostedEvent:   newWebhookHostedEvent(details),
+		ChangedFields: changed,
+	}
+	if !previousStart.IsZero() {
+		data.PreviousStartTime = &previousStart
+	}
+	s.Emit(ctx, details.Event.TenantID, details.Event.HostID, eventType, data)
+}
+
+func (s *WebhookService) notify() {
+	// Wake the worker; if it's already due to run, it will pick this up
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start begins the background delivery loop
+func (s *WebhookService) Start() {
+	s.wg.Add(1)
+	go s.run()
+	log.Printf("[WEBHOOK] Service started, checking every %v", s.interval)
+}
+
+// Stop stops the background delivery loop once the current pass is done
+func (s *WebhookService) Stop() {
+	close(s.stopCh)
+	s.wg.Wait()
+	log.Printf("[WEBHOOK] Service stopped")
+}
+
+func (s *WebhookService) run() {
+	defer s.wg.Done()
+
+	// Run immediately on startup, for whatever was queued before a restart
+	s.deliverDue(context.Background())
+	s.pruneLog(context.Background())
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	pruneTicker := time.NewTicker(24 * time.Hour)
+	defer pruneTicker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.deliverDue(context.Background())
+		case <-s.wake:
+			s.deliverDue(context.Background())
+		case <-pruneTicker.C:
+			s.pruneLog(context.Background())
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// deliverDue posts the deliveries that are due, a batch at a time until
+// none are left, and returns how many were delivered
+func (s *WebhookService) deliverDue(ctx context.Context) int {
+	delivered := 0
+	for {
+		now := time.Now().UTC()
+		due, err := s.repos.WebhookDelivery.GetDue(ctx, now, webhookBatchSize)
+		if err != nil {
+			log.Printf("[WEBHOOK] Error loading due deliveries: %v", err)
+			return delivered
+		}
+		for _, d := range due {
+			if s.attempt(ctx, d, now) {
+				delivered++
+			}
+		}
+		if len(due) < webhookBatchSize {
+			return delivered
+		}
+	}
+}
+
+// attempt claims and posts one delivery, then marks it delivered if the
+// receiver answered 2xx, schedules a retry if not, or marks it failed
+// after the last attempt. It reports whether the delivery went through.
+func (s *WebhookService) attempt(ctx context.Context, d *models.WebhookDelivery, now time.Time) bool {
+	claimed, err := s.repos.WebhookDelivery.Claim(ctx, d.ID, now, now.Add(webhookLease))
+	if err != nil {
+		log.Printf("[WEBHOOK] Error claiming delivery %s: %v", d.ID, err)
+		return false
+	}
+	if !claimed {
+		return false
+	}
+
+	sub, err := s.repos.WebhookSubscription.GetByID(ctx, d.SubscriptionID)
+	if err != nil || sub == nil {
+		// Tried again once the lease runs out
+		log.Printf("[WEBHOOK] Error loading webhook %s for delivery %s: %v", d.SubscriptionID, d.ID, err)
+		return false
+	}
+	if !sub.IsActive {
+		if err := s.repos.WebhookDelivery.Finish(ctx, d.ID, models.WebhookDeliveryStatusFailed, d.Attempts, 0, "Webhook turned off"); err != nil {
+			log.Printf("[WEBHOOK] Error finishing delivery %s: %v", d.ID, err)
+		}
+		return false
+	}
+
+	attempts := d.Attempts + 1
+	status, postErr := s.post(ctx, sub, d)
+	if postErr == nil {
+		if err := s.repos.WebhookDelivery.Finish(ctx, d.ID, models.WebhookDeliveryStatusDelivered, attempts, status, ""); err != nil {
+			// Left pending, it would be posted again once the lease runs out
+			log.Printf("[WEBHOOK] Error marking delivery %s delivered: %v", d.ID, err)
+		}
+		return true
+	}
+
+	if attempts >= webhookMaxAttempts {
+		log.Printf("[WEBHOOK] Giving up on %s to %s after %d attempts: %v", d.EventType, sub.URL, attempts, postErr)
+		if err := s.repos.WebhookDelivery.Finish(ctx, d.ID, models.WebhookDeliveryStatusFailed, attempts, status, postErr.Error()); err != nil {
+			log.Printf("[WEBHOOK] Error marking delivery %s failed: %v", d.ID, err)
+		}
+		return false
+	}
+	next := time.Now().UTC().Add(webhookBackoff(attempts))
+	log.Printf("[WEBHOOK] Error posting %s to %s (attempt %d), retrying at %s: %v",
+		d.EventType, sub.URL, attempts, next.Format(time.RFC3339), postErr)
+	if err := s.repos.WebhookDelivery.Retry(ctx, d.ID, attempts, next, status, postErr.Error()); err != nil {
+		log.Printf("[WEBHOOK] Error scheduling retry of delivery %s: %v", d.ID, err)
+	}
+	return false
+}
+
+// post sends a delivery's payload to its webhook, signed with the
+// webhook's secret, and returns the HTTP status (0 without a response) and
+// an error unless the receiver answered 2xx. The error, which the delivery
+// log shows, carries the status only: a response body would let a host
+// read whatever the URL serves.
+func (s *WebhookService) post(ctx context.Context, sub *models.WebhookSubscription, d *models.WebhookDelivery) (int, error) {
+	body := []byte(d.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, strings.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "MeetWhen-Webhooks/1.0")
+	req.Header.Set("X-MeetWhen-Event", string(d.EventType))
+	req.Header.Set("X-MeetWhen-Delivery", d.ID)
+	req.Header.Set("X-MeetWhen-Signature", signWebhook(sub.Secret, time.Now(), body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrPrivateAddress) {
+			return 0, ErrPrivateAddress
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, nil
+	}
+	return resp.StatusCode, errors.New(resp.Status)
+}
+
+// pruneLog drops finished deliveries older than webhookLogRetention
+func (s *WebhookService) pruneLog(ctx context.Context) {
+	n, err := s.repos.WebhookDelivery.DeleteFinishedBefore(ctx, time.Now().UTC().Add(-webhookLogRetention))
+	if err != nil {
+		log.Printf("[WEBHOOK] Error pruning delivery log: %v", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("[WEBHOOK] Pruned %d old deliveries", n)
+	}
+}
+
+// webhookHost is a host as webhook payloads describe them
+type webhookHost struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// webhookMeetingType is a meeting template as webhook payloads describe it
+type webhookMeetingType struct {
+	ID   string `json:"id"`
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// webhookBooking is a booking as webhook payloads describe it
+type webhookBooking struct {
+	ID               string                      `json:"id"`
+	Status           models.BookingStatus        `json:"status"`
+	StartTime        time.Time                   `json:"start_time"`
+	EndTime          time.Time                   `json:"end_time"`
+	Duration         int                         `json:"duration"`
+	MeetingType      *webhookMeetingType         `json:"meeting_type,omitempty"`
+	Host             *webhookHost                `json:"host,omitempty"`
+	InviteeName      string                      `json:"invitee_name"`
+	InviteeEmail     string                      `json:"invitee_email"`
+	InviteeTimezone  string                      `json:"invitee_timezone"`
+	InviteePhone     string                      `json:"invitee_phone,omitempty"`
+	AdditionalGuests []string                    `json:"additional_guests"`
+	Answers          models.JSONMap              `json:"answers"`
+	LocationType     models.ConferencingProvider `json:"location_type,omitempty"`
+	ConferenceLink   string                      `json:"conference_link,omitempty"`
+	CancelledBy      string                      `json:"cancelled_by,omitempty"`
+	CancelReason     string                      `json:"cancel_reason,omitempty"`
+	CreatedAt        time.Time                   `json:"created_at"`
+}
+
+// webhookBookingData is the data of a booking event
+type webhookBookingData struct {
+	Booking           webhookBooking `json:"booking"`
+	PreviousStartTime *time.Time     `json:"previous_start_time,omitempty"`
+	ChangedFields     []string       `json:"changed_fields,omitempty"`
+}
+
+func newWebhookBooking(details *BookingWithDetails) webhookBooking {
+	b := details.Booking
+	out := webhookBooking{
+		ID:               b.ID,
+		Status:           b.Status,
+		StartTime:        b.StartTime.Time,
+		EndTime:          b.EndTime.Time,
+		Duration:         b.Duration,
+		InviteeName:      b.InviteeName,
+		InviteeEmail:     b.InviteeEmail,
+		InviteeTimezone:  b.InviteeTimezone,
+		InviteePhone:     b.InviteePhone,
+		AdditionalGuests: b.AdditionalGuests,
+		Answers:          models.JSONMap{},
+		ConferenceLink:   b.ConferenceLink,
+		CancelledBy:      b.CancelledBy,
+		CancelReason:     b.CancelReason,
+		CreatedAt:        b.CreatedAt.Time,
+	}
+	if out.AdditionalGuests == nil {
+		out.AdditionalGuests = []string{}
+	}
+	// Keys starting with _ are our own bookkeeping, not answers
+	for k, v := range b.Answers {
+		if !strings.HasPrefix(k, "_") {
+			out.Answers[k] = v
+		}
+	}
+	if t := details.Template; t != nil {
+		out.MeetingType = &webhookMeetingType{ID: t.ID, Slug: t.Slug, Name: t.Name}
+		out.LocationType = t.LocationType
+	}
+	if h := details.Host; h != nil {
+		out.Host = &webhookHost{ID: h.ID, Name: h.Name, Email: h.Email}
+	}
+	return out
+}
+
+// webhookAttendee is a hosted event attendee as webhook payloads describe them
+type webhookAttendee struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+// webhookHostedEvent is a hosted event as webhook payloads describe it
+type webhookHostedEvent struct {
+	ID             string                      `json:"id"`
+	Status         models.HostedEventStatus    `json:"status"`
+	Title          string                      `json:"title"`
+	Description    string                      `json:"description"`
+	StartTime      time.Time                   `json:"start_time"`
+	EndTime        time.Time                   `json:"end_time"`
+	Duration       int                         `json:"duration"`
+	Timezone       string                      `json:"timezone"`
+	MeetingType    *webhookMeetingType         `json:"meeting_type,omitempty"`
+	Host           *webhookHost                `json:"host,omitempty"`
+	Attendees      []webhookAttendee           `json:"attendees"`
+	LocationType   models.ConferencingProvider `json:"location_type,omitempty"`
+	CustomLocation string                      `json:"custom_location,omitempty"`
+	ConferenceLink string                      `json:"conference_link,omitempty"`
+	CancelReason   string                      `json:"cancel_reason,omitempty"`
+	CreatedAt      time.Time                   `json:"created_at"`
+}
+
+// webhookHostedEventData is the data of a hosted event event
+type webhookHostedEventData struct {
+	HostedEvent       webhookHostedEvent `json:"hosted_event"`
+	PreviousStartTime *time.Time         `json:"previous_start_time,omitempty"`
+	ChangedFields     []string           `json:"changed_fields,omitempty"`
+}
+
+func newWebhookHostedEvent(details *HostedEventWithDetails) webhookHostedEvent {
+	e := details.Event
+	out := webhookHostedEvent{
+		ID:             e.ID,
+		Status:         e.Status,
+		Title:          e.Title,
+		Description:    e.Description,
+		StartTime:      e.StartTime.Time,
+		EndTime:        e.EndTime.Time,
+		Duration:       e.Duration,
+		Timezone:       e.Timezone,
+		Attendees:      make([]webhookAttendee, 0, len(details.Attendees)),
+		LocationType:   e.LocationType,
+		CustomLocation: e.CustomLocation,
+		ConferenceLink: e.ConferenceLink,
+		CancelReason:   e.CancelReason,
+		CreatedAt:      e.CreatedAt.Time,
+	}
+	for _, a := range details.Attendees {
+		out.Attendees = append(out.Attendees, webhookAttendee{Email: a.Email, Name: a.Name})
+	}
+	if t := details.Template; t != nil {
+		out.MeetingType = &webhookMeetingType{ID: t.ID, Slug: t.Slug, Name: t.Name}
+	}
+	if h := details.Host; h != nil {
+		out.Host = &webhookHost{ID: h.ID, Name: h.Name, Email: h.Email}
+	}
+	return out
+}
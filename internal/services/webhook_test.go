@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/meet-when/meet-when/internal/models"
+)
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{4, 8 * time.Minute},
+		{7, 64 * time.Minute},
+		{9, 256 * time.Minute},
+		{20, 6 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := webhookBackoff(tt.attempts); got != tt.want {
+			t.Errorf("webhookBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	body := []byte(`{"id":"evt"}`)
+	at := time.Unix(1700000000, 0)
+
+	mac := hmac.New(sha256.New, []byte("whsec_test"))
+	mac.Write([]byte("1700000000." + string(body)))
+	want := "t=1700000000,v1=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := signWebhook("whsec_test", at, body); got != want {
+		t.Errorf("signWebhook = %q, want %q", got, want)
+	}
+	if signWebhook("whsec_other", at, body) == want {
+		t.Error("signature doesn't depend on the secret")
+	}
+}
+
+// webhookReceiver records the posts it gets and answers with status
+type webhookReceiver struct {
+	*httptest.Server
+	mu     sync.Mutex
+	status int
+	posts  []*http.Request
+	bodies [][]byte
+}
+
+func newWebhookReceiver(t *testing.T) *webhookReceiver {
+	rec := &webhookReceiver{status: http.StatusOK}
+	rec.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rec.mu.Lock()
+		rec.posts = append(rec.posts, r)
+		rec.bodies = append(rec.bodies, body)
+		status := rec.status
+		rec.mu.Unlock()
+		w.WriteHeader(status)
+		if status >= 300 {
+			_, _ = w.Write([]byte("receiver down"))
+		}
+	}))
+	t.Cleanup(rec.Close)
+	return rec
+}
+
+func TestWebhook_EmitDeliverRetryAndRedeliver(t *testing.T) {
+	_, repos, cleanup := setupTestRepos(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	tenant := &models.Tenant{ID: uuid.New().String(), Slug: "acme-" + uuid.New().String()[:6], Name: "Acme", CreatedAt: models.Now(), UpdatedAt: models.Now()}
+	if err := repos.Tenant.Create(ctx, tenant); err != nil {
+		t.Fatalf("create tenant: %v", err)
+	}
+	var hosts []*models.Host
+	for _, name := range []string{"Jo Host", "Kim Host"} {
+		host := &models.Host{
+			ID: uuid.New().String(), TenantID: tenant.ID,
+			Email: "h-" + uuid.New().String()[:4] + "@example.com", PasswordHash: "x",
+			Name: name, Slug: "h-" + uuid.New().String()[:6],
+			Timezone: "UTC", CreatedAt: models.Now(), UpdatedAt: models.Now(),
+		}
+		if err := repos.Host.Create(ctx, host); err != nil {
+			t.Fatalf("create host: %v", err)
+		}
+		hosts = append(hosts, host)
+	}
+	jo, kim := hosts[0], hosts[1]
+
+	rec := newWebhookReceiver(t)
+	webhooks := NewWebhookService(repos)
+	webhooks.client = standInClient(rec.Server)
+
+	// Jo's own webhook for cancellations, and a tenant-wide one for everything
+	mine, err := webhooks.CreateWebhook(ctx, tenant.ID, &jo.ID, WebhookInput{
+		URL:        "http://hooks.example.com/jo",
+		EventTypes: []string{"booking.cancelled", "no.such_event"},
+		IsActive:   true,
+	})
+	if err != nil {
+		t.Fatalf("create host webhook: %v", err)
+	}
+	if len(mine.EventTypes) != 1 || !strings.HasPrefix(mine.Secret, "whsec_") {
+		t.Errorf("host webhook = %v, secret %q", mine.EventTypes, mine.Secret)
+	}
+	all, err := webhooks.CreateWebhook(ctx, tenant.ID, nil, WebhookInput{URL: "http://hooks.example.com/all", IsActive: true})
+	if err != nil {
+		t.Fatalf("create tenant webhook: %v", err)
+	}
+	for _, u := range []string{"ftp://example.com", "http://127.0.0.1:8080/hook", "http://169.254.169.254/latest/meta-data", "http://[::1]/hook", "http://localhost/hook"} {
+		if _, err := webhooks.CreateWebhook(ctx, tenant.ID, nil, WebhookInput{URL: u}); err != ErrInvalidWebhookURL {
+			t.Errorf("%s: err = %v, want ErrInvalidWebhookURL", u, err)
+		}
+	}
+
+	// Only the tenant-wide webhook wants a new booking; neither of Jo's
+	// webhooks hears about Kim's cancellation except the tenant-wide one
+	webhooks.Emit(ctx, tenant.ID, jo.ID, models.WebhookEventBookingCreated, map[string]string{"booking": "b1"})
+	webhooks.Emit(ctx, tenant.ID, jo.ID, models.WebhookEventBookingCancelled, map[string]string{"booking": "b1"})
+	webhooks.Emit(ctx, tenant.ID, kim.ID, models.WebhookEventBookingCancelled, map[string]string{"booking": "b2"})
+
+	if n := webhooks.deliverDue(ctx); n != 4 {
+		t.Fatalf("first pass delivered %d, want 4", n)
+	}
+	mineLog, _ := webhooks.ListDeliveries(ctx, mine.ID)
+	allLog, _ := webhooks.ListDeliveries(ctx, all.ID)
+	if len(mineLog) != 1 || len(allLog) != 3 {
+		t.Fatalf("deliveries: %d to Jo's webhook, %d tenant-wide; want 1 and 3", len(mineLog), len(allLog))
+	}
+
+	// Every post is signed with its webhook's secret and names its event
+	for i, r := range rec.posts {
+		secret := all.Secret
+		if r.URL.Path == "/jo" {
+			secret = mine.Secret
+		}
+		var event WebhookEvent
+		if err := json.Unmarshal(rec.bodies[i], &event); err != nil {
+			t.Fatalf("post %d body: %v", i, err)
+		}
+		if r.Header.Get("X-MeetWhen-Event") != string(event.Type) || r.Header.Get("X-MeetWhen-Delivery") == "" {
+			t.Errorf("post %d headers: %v", i, r.Header)
+		}
+		sig := r.Header.Get("X-MeetWhen-Signature")
+		ts := strings.TrimPrefix(strings.Split(sig, ",")[0], "t=")
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(ts + "." + string(rec.bodies[i])))
+		if !strings.HasSuffix(sig, ",v1="+hex.EncodeToString(mac.Sum(nil))) {
+			t.Errorf("post %d to %s: signature %q doesn't match", i, r.URL.Path, sig)
+		}
+	}
+
+	// A failing receiver gets a retry with backoff, then the delivery fails
+	rec.status = http.StatusBadGateway
+	webhooks.Emit(ctx, tenant.ID, jo.ID, models.WebhookEventBookingCancelled, map[string]string{"booking": "b3"})
+	if n := webhooks.deliverDue(ctx); n != 0 {
+		t.Fatalf("failing pass delivered %d", n)
+	}
+	mineLog, _ = webhooks.ListDeliveries(ctx, mine.ID)
+	d := mineLog[0]
+	if d.Status != models.WebhookDeliveryStatusPending || d.Attempts != 1 || d.ResponseStatus != http.StatusBadGateway ||
+		d.LastError != "502 Bad Gateway" {
+		t.Errorf("after one failure: %+v; want the status without the body", d)
+	}
+	if wait := time.Until(d.NextAttemptAt.Time); wait < 50*time.Second || wait > webhookBaseBackoff {
+		t.Errorf("retry in %v, want about %v", wait, webhookBaseBackoff)
+	}
+	if _, err := webhooks.Redeliver(ctx, tenant.ID, "", d.ID); err != ErrWebhookDeliveryNotFound {
+		t.Errorf("redeliver pending: err = %v, want ErrWebhookDeliveryNotFound", err)
+	}
+
+	if err := repos.WebhookDelivery.Retry(ctx, d.ID, webhookMaxAttempts-1, time.Now().UTC().Add(-time.Second), 502, "earlier"); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	webhooks.deliverDue(ctx)
+	if d, _ = repos.WebhookDelivery.GetByID(ctx, d.ID); d.Status != models.WebhookDeliveryStatusFailed || d.Attempts != webhookMaxAttempts {
+		t.Fatalf("after last attempt: status %q, %d attempts", d.Status, d.Attempts)
+	}
+
+	// Kim can't redeliver to Jo's webhook; Jo can, with the same event id
+	if _, err := webhooks.Redeliver(ctx, tenant.ID, kim.ID, d.ID); err != ErrWebhookDeliveryNotFound {
+		t.Errorf("redeliver by another host: err = %v, want ErrWebhookDeliveryNotFound", err)
+	}
+	rec.status = http.StatusNoContent
+	again, err := webhooks.Redeliver(ctx, tenant.ID, jo.ID, d.ID)
+	if err != nil {
+		t.Fatalf("redeliver: %v", err)
+	}
+	if again.ID == d.ID || again.EventID != d.EventID || again.Payload != d.Payload {
+		t.Errorf("redelivery = %+v, from %+v", again, d)
+	}
+	if n := webhooks.deliverDue(ctx); n != 1 {
+		t.Errorf("redelivery pass delivered %d, want 1", n)
+	}
+
+	// Turned off, a webhook drops what's queued and hears nothing new
+	if _, err := webhooks.UpdateWebhook(ctx, tenant.ID, jo.ID, mine.ID, WebhookInput{URL: mine.URL}); err != nil {
+		t.Fatalf("turn off: %v", err)
+	}
+	webhooks.Emit(ctx, tenant.ID, jo.ID, models.WebhookEventBookingCancelled, map[string]string{"booking": "b4"})
+	mineLog, _ = webhooks.ListDeliveries(ctx, mine.ID)
+	if mineLog[0].ID != again.ID {
+		t.Errorf("inactive webhook got a new delivery: %+v", mineLog[0])
+	}
+}
+
+func TestWebhook_PostRefusesPrivateAddresses(t *testing.T) {
+	rec := newWebhookReceiver(t)
+	webhooks := NewWebhookService(nil)
+
+	// The receiver listens on loopback, which the guarded client won't dial
+	// however the URL names it
+	sub := &models.WebhookSubscription{URL: rec.URL + "/hook", Secret: "whsec_x"}
+	d := &models.WebhookDelivery{ID: uuid.New().String(), EventType: models.WebhookEventBookingCreated, Payload: `{}`}
+	status, err := webhooks.post(context.Background(), sub, d)
+	if status != 0 || !errors.Is(err, ErrPrivateAddress) {
+		t.Errorf("post to loopback = %d, %v; want ErrPrivateAddress", status, err)
+	}
+	if len(rec.posts) != 0 {
+		t.Errorf("receiver got %d posts", len(rec.posts))
+	}
+}
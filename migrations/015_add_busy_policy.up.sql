@@ -0,0 +1,6 @@
+-- Per-calendar busy policy: which kinds of events block availability.
+-- Cancelled events never block; confirmed opaque events always do. The three
+-- flags below cover the cases hosts disagree on.
+ALTER TABLE provider_calendars ADD COLUMN busy_on_tentative BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE provider_calendars ADD COLUMN busy_on_free BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE provider_calendars ADD COLUMN busy_on_declined BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS busy_sync_states;
+DROP TABLE IF EXISTS busy_intervals;
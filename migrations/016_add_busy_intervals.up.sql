@@ -0,0 +1,33 @@
+-- Persistent busy-time cache. CalendarSyncService keeps busy_intervals in
+-- step with each polled provider calendar so availability can be computed
+-- without calling the provider on every booking-page view.
+--
+-- Rows store the provider's classification (busy/tentative/free/declined)
+-- rather than the outcome of the calendar's busy policy, so changing the
+-- policy takes effect without a resync. All-day events keep their dates as
+-- UTC midnights and are anchored to the host's timezone when read.
+CREATE TABLE busy_intervals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    calendar_id UUID NOT NULL REFERENCES provider_calendars(id) ON DELETE CASCADE,
+    source VARCHAR(1000) NOT NULL DEFAULT '',
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    is_all_day BOOLEAN NOT NULL DEFAULT FALSE,
+    availability VARCHAR(20) NOT NULL DEFAULT 'busy',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_busy_intervals_calendar_start ON busy_intervals(calendar_id, start_time);
+CREATE INDEX idx_busy_intervals_calendar_source ON busy_intervals(calendar_id, source);
+
+-- One row per cached calendar: the provider's incremental-sync cursor
+-- (Google syncToken, CalDAV sync-token / getctag), the window the cache
+-- covers, and when it was last confirmed current.
+CREATE TABLE busy_sync_states (
+    calendar_id UUID PRIMARY KEY REFERENCES provider_calendars(id) ON DELETE CASCADE,
+    sync_token TEXT NOT NULL DEFAULT '',
+    ctag VARCHAR(255) NOT NULL DEFAULT '',
+    window_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    window_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    synced_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,15 @@
+-- Google Calendar push-notification channels (events.watch), one live
+-- channel per polled Google provider calendar. id is the channel id we chose
+-- when registering; token is echoed back by Google on every notification and
+-- is checked before a notification is trusted.
+CREATE TABLE calendar_watch_channels (
+    id UUID PRIMARY KEY,
+    calendar_id UUID NOT NULL REFERENCES provider_calendars(id) ON DELETE CASCADE,
+    resource_id VARCHAR(255) NOT NULL DEFAULT '',
+    token VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_calendar_watch_channels_calendar ON calendar_watch_channels(calendar_id);
+CREATE INDEX idx_calendar_watch_channels_expires ON calendar_watch_channels(expires_at);
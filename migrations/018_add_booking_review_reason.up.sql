@@ -0,0 +1,4 @@
+-- Set when the host's calendar copy of a booking drifted (moved or deleted)
+-- in a way MeetWhen could not reconcile on its own. Non-empty means the
+-- booking is flagged for the host to review on the dashboard.
+ALTER TABLE bookings ADD COLUMN review_reason TEXT;
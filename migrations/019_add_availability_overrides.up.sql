@@ -0,0 +1,20 @@
+-- Date-specific availability. An override replaces a host's weekly working
+-- hours (and any template availability rules) on every date from start_date
+-- to end_date inclusive, read in the host's timezone. An empty intervals
+-- list means the host is off; otherwise only the listed HH:MM intervals are
+-- bookable. With template_id set, the override applies to that meeting type
+-- only and takes precedence over host-wide overrides on the same date.
+CREATE TABLE availability_overrides (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    host_id UUID NOT NULL REFERENCES hosts(id) ON DELETE CASCADE,
+    template_id UUID REFERENCES meeting_templates(id) ON DELETE CASCADE,
+    start_date VARCHAR(10) NOT NULL,
+    end_date VARCHAR(10) NOT NULL,
+    intervals JSONB NOT NULL DEFAULT '[]',
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_availability_overrides_host_dates ON availability_overrides(host_id, start_date, end_date);
@@ -0,0 +1,18 @@
+-- Public holidays. A host picks one bundled holiday set (see holidays/) by
+-- code; every holiday in it blocks the host's availability for that date,
+-- read in the host's timezone, unless the date is listed in
+-- host_holiday_exceptions.
+CREATE TABLE host_holiday_sets (
+    host_id UUID PRIMARY KEY REFERENCES hosts(id) ON DELETE CASCADE,
+    set_code VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Holidays the host has un-blocked and still takes bookings on.
+CREATE TABLE host_holiday_exceptions (
+    host_id UUID NOT NULL REFERENCES hosts(id) ON DELETE CASCADE,
+    holiday_date VARCHAR(10) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (host_id, holiday_date)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE hosts DROP COLUMN max_minutes_per_day;
+ALTER TABLE hosts DROP COLUMN max_bookings_per_week;
+ALTER TABLE hosts DROP COLUMN max_bookings_per_day;
+ALTER TABLE meeting_templates DROP COLUMN max_bookings_per_week;
+ALTER TABLE meeting_templates DROP COLUMN max_bookings_per_day;
@@ -0,0 +1,11 @@
+-- How a pooled template books its hosts. 'collective' needs every required
+-- host free and books them all. 'round_robin' offers any host's free time and
+-- assigns the booking to one of them, chosen by round_robin_strategy
+-- ('least_recently_booked', 'weighted' or 'maximize_availability').
+-- The assigned host is the booking's host_id.
+ALTER TABLE meeting_templates ADD COLUMN scheduling_type VARCHAR(20) NOT NULL DEFAULT 'collective';
+ALTER TABLE meeting_templates ADD COLUMN round_robin_strategy VARCHAR(30) NOT NULL DEFAULT 'least_recently_booked';
+
+-- Share of round-robin bookings under the 'weighted' strategy, relative to
+-- the template's other hosts.
+ALTER TABLE template_hosts ADD COLUMN weight INTEGER NOT NULL DEFAULT 1;
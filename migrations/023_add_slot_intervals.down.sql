@@ -0,0 +1,3 @@
+ALTER TABLE meeting_templates DROP COLUMN start_minutes;
+ALTER TABLE meeting_templates DROP COLUMN align_slots;
+ALTER TABLE meeting_templates DROP COLUMN slot_interval;
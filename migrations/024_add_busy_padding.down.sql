@@ -0,0 +1,6 @@
+ALTER TABLE hosts DROP COLUMN max_back_to_back_minutes;
+ALTER TABLE hosts DROP COLUMN min_gap_minutes;
+ALTER TABLE hosts DROP COLUMN calendar_buffer_after_minutes;
+ALTER TABLE hosts DROP COLUMN calendar_buffer_before_minutes;
+ALTER TABLE provider_calendars DROP COLUMN buffer_after_minutes;
+ALTER TABLE provider_calendars DROP COLUMN buffer_before_minutes;
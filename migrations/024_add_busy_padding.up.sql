@@ -0,0 +1,11 @@
+-- Padding around busy time, in minutes. Calendar buffers keep time free
+-- around events read from a provider calendar, per calendar and host-wide,
+-- with the larger applying. min_gap_minutes keeps that much free between any
+-- two meetings, and max_back_to_back_minutes caps a run of meetings with no
+-- break. 0 turns each off.
+ALTER TABLE provider_calendars ADD COLUMN buffer_before_minutes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE provider_calendars ADD COLUMN buffer_after_minutes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE hosts ADD COLUMN calendar_buffer_before_minutes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE hosts ADD COLUMN calendar_buffer_after_minutes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE hosts ADD COLUMN min_gap_minutes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE hosts ADD COLUMN max_back_to_back_minutes INTEGER NOT NULL DEFAULT 0;
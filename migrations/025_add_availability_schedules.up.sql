@@ -0,0 +1,20 @@
+-- Named availability schedules. A host keeps their working hours as the
+-- default and can add schedules such as "Summer hours", each with its own
+-- timezone and weekly HH:MM intervals ([{"day": 1, "start": "09:00",
+-- "end": "17:00"}, ...], day 0 = Sunday). A template with schedule_id set
+-- takes its hours from that schedule instead of the working hours.
+-- Overrides with schedule_id set are the schedule's own date overrides.
+CREATE TABLE availability_schedules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    host_id UUID NOT NULL REFERENCES hosts(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    timezone VARCHAR(100) NOT NULL DEFAULT 'UTC',
+    weekly_hours JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_availability_schedules_host ON availability_schedules(host_id);
+
+ALTER TABLE meeting_templates ADD COLUMN schedule_id UUID REFERENCES availability_schedules(id) ON DELETE SET NULL;
+ALTER TABLE availability_overrides ADD COLUMN schedule_id UUID REFERENCES availability_schedules(id) ON DELETE CASCADE;
@@ -0,0 +1 @@
+ALTER TABLE meeting_templates DROP COLUMN seats;
@@ -0,0 +1,5 @@
+-- Group meeting types. A template with seats above 1 keeps a slot open until
+-- that many invitees have booked it, each with their own booking, and the
+-- slot's confirmed invitees share one calendar event. 0 is one invitee per
+-- slot.
+ALTER TABLE meeting_templates ADD COLUMN seats INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,20 @@
+-- Outgoing emails, one row per message handed to the provider. provider is
+-- how it went out (smtp, mailgun); provider_message_id is Mailgun's
+-- Message-Id without the angle brackets, which its webhooks report
+-- delivered, bounced and complained events against. status is sent until a
+-- webhook says otherwise; status_detail keeps the provider's reason for a
+-- bounce.
+CREATE TABLE email_messages (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    provider VARCHAR(20) NOT NULL,
+    provider_message_id VARCHAR(255) NOT NULL DEFAULT '',
+    recipient VARCHAR(255) NOT NULL,
+    subject TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'sent',
+    status_detail TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_email_messages_provider_message ON email_messages(provider_message_id);
+CREATE INDEX idx_email_messages_recipient ON email_messages(recipient);
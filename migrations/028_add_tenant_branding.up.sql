@@ -0,0 +1,6 @@
+-- Tenant branding for HTML emails. Empty values fall back to the Meet When
+-- defaults: the tenant's name in place of a logo, the app's accent color and
+-- a footer naming the tenant. accent_color is a #rrggbb hex color.
+ALTER TABLE tenants ADD COLUMN logo_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE tenants ADD COLUMN accent_color VARCHAR(7) NOT NULL DEFAULT '';
+ALTER TABLE tenants ADD COLUMN email_footer TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,25 @@
+-- Emails waiting to go out. Senders add a row and the outbox worker sends
+-- the ones whose next_attempt_at has passed, deleting each once the
+-- provider accepts it (email_messages keeps the record of sent emails). A
+-- failed send is retried with exponential backoff; after the last attempt
+-- the row is dead and waits on the dashboard's failed emails page to be
+-- resent. tenant_id and host_id are who the email was sent for.
+CREATE TABLE email_outbox (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID REFERENCES tenants(id) ON DELETE CASCADE,
+    host_id UUID REFERENCES hosts(id) ON DELETE CASCADE,
+    recipient VARCHAR(255) NOT NULL,
+    subject TEXT NOT NULL DEFAULT '',
+    text_body TEXT NOT NULL DEFAULT '',
+    html_body TEXT NOT NULL DEFAULT '',
+    ics TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_email_outbox_due ON email_outbox(status, next_attempt_at);
+CREATE INDEX idx_email_outbox_tenant_status ON email_outbox(tenant_id, status);
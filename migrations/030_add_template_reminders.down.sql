@@ -0,0 +1,7 @@
+ALTER TABLE bookings ADD COLUMN reminder_sent BOOLEAN DEFAULT FALSE;
+UPDATE bookings SET reminder_sent = TRUE
+WHERE id IN (SELECT booking_id FROM booking_reminders WHERE reminder_key = 'invitee:email:1440');
+CREATE INDEX idx_bookings_reminder ON bookings(status, start_time, reminder_sent);
+
+DROP TABLE IF EXISTS booking_reminders;
+ALTER TABLE meeting_templates DROP COLUMN reminders;
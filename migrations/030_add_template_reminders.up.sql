@@ -0,0 +1,29 @@
+-- Reminders per meeting type, replacing the one reminder a day before each
+-- booking. reminders is a list of {offset_minutes, channel, recipient}: how
+-- long before the start to send it, "email" or "sms", and "invitee", "host"
+-- or "both". Existing meeting types keep the day-before email.
+ALTER TABLE meeting_templates ADD COLUMN reminders JSONB NOT NULL
+    DEFAULT '[{"offset_minutes":1440,"channel":"email","recipient":"invitee"}]';
+
+-- The reminders sent for each booking, one row per recipient of each
+-- reminder. reminder_key names the reminder by recipient, channel and
+-- offset, so changing a meeting type's reminders arms only the ones it adds
+-- or changes, and start_time is the start it was sent for, so rescheduling
+-- a booking arms its reminders again.
+CREATE TABLE booking_reminders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    reminder_key VARCHAR(100) NOT NULL,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (booking_id, reminder_key, start_time)
+);
+
+-- Carry over the day-before reminders already sent under the old flag
+INSERT INTO booking_reminders (booking_id, reminder_key, start_time, sent_at)
+SELECT id, 'invitee:email:1440', start_time, COALESCE(updated_at, NOW())
+FROM bookings
+WHERE reminder_sent = TRUE AND start_time > NOW();
+
+DROP INDEX IF EXISTS idx_bookings_reminder;
+ALTER TABLE bookings DROP COLUMN reminder_sent;
@@ -0,0 +1,7 @@
+-- ICS feed URLs are secrets, so they're now kept only on the connection,
+-- sealed like tokens. Sealing can push calendar_id past its old limit, and
+-- the feed's provider calendar gets a fixed id instead of the URL.
+ALTER TABLE calendar_connections ALTER COLUMN calendar_id TYPE TEXT;
+
+UPDATE provider_calendars SET provider_calendar_id = 'feed'
+WHERE connection_id IN (SELECT id FROM calendar_connections WHERE provider = 'ics_feed');
@@ -0,0 +1,3 @@
+ALTER TABLE provider_calendars DROP COLUMN busy_on_declined;
+ALTER TABLE provider_calendars DROP COLUMN busy_on_free;
+ALTER TABLE provider_calendars DROP COLUMN busy_on_tentative;
@@ -0,0 +1,4 @@
+-- Per-calendar busy policy. See migrations/015_add_busy_policy.up.sql.
+ALTER TABLE provider_calendars ADD COLUMN busy_on_tentative INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE provider_calendars ADD COLUMN busy_on_free INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE provider_calendars ADD COLUMN busy_on_declined INTEGER NOT NULL DEFAULT 0;
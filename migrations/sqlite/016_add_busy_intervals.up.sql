@@ -0,0 +1,23 @@
+-- Persistent busy-time cache. See migrations/016_add_busy_intervals.up.sql.
+CREATE TABLE busy_intervals (
+    id TEXT PRIMARY KEY,
+    calendar_id TEXT NOT NULL REFERENCES provider_calendars(id) ON DELETE CASCADE,
+    source TEXT NOT NULL DEFAULT '',
+    start_time TEXT NOT NULL,
+    end_time TEXT NOT NULL,
+    is_all_day INTEGER NOT NULL DEFAULT 0,
+    availability TEXT NOT NULL DEFAULT 'busy',
+    created_at TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+
+CREATE INDEX idx_busy_intervals_calendar_start ON busy_intervals(calendar_id, start_time);
+CREATE INDEX idx_busy_intervals_calendar_source ON busy_intervals(calendar_id, source);
+
+CREATE TABLE busy_sync_states (
+    calendar_id TEXT PRIMARY KEY REFERENCES provider_calendars(id) ON DELETE CASCADE,
+    sync_token TEXT NOT NULL DEFAULT '',
+    ctag TEXT NOT NULL DEFAULT '',
+    window_start TEXT NOT NULL,
+    window_end TEXT NOT NULL,
+    synced_at TEXT NOT NULL
+);
@@ -0,0 +1,13 @@
+-- Google Calendar push-notification channels. See
+-- migrations/017_add_calendar_watch_channels.up.sql.
+CREATE TABLE calendar_watch_channels (
+    id TEXT PRIMARY KEY,
+    calendar_id TEXT NOT NULL REFERENCES provider_calendars(id) ON DELETE CASCADE,
+    resource_id TEXT NOT NULL DEFAULT '',
+    token TEXT NOT NULL,
+    expires_at TEXT NOT NULL,
+    created_at TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+
+CREATE INDEX idx_calendar_watch_channels_calendar ON calendar_watch_channels(calendar_id);
+CREATE INDEX idx_calendar_watch_channels_expires ON calendar_watch_channels(expires_at);
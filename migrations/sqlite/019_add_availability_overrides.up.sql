@@ -0,0 +1,15 @@
+-- Date-specific availability. See migrations/019_add_availability_overrides.up.sql.
+CREATE TABLE availability_overrides (
+    id TEXT PRIMARY KEY,
+    host_id TEXT NOT NULL REFERENCES hosts(id) ON DELETE CASCADE,
+    template_id TEXT REFERENCES meeting_templates(id) ON DELETE CASCADE,
+    start_date TEXT NOT NULL,
+    end_date TEXT NOT NULL,
+    intervals TEXT NOT NULL DEFAULT '[]',
+    note TEXT NOT NULL DEFAULT '',
+    created_at TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_at TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_availability_overrides_host_dates ON availability_overrides(host_id, start_date, end_date);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS host_holiday_exceptions;
+DROP TABLE IF EXISTS host_holiday_sets;
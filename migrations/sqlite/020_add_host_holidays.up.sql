@@ -0,0 +1,14 @@
+-- Public holidays. See migrations/020_add_host_holidays.up.sql.
+CREATE TABLE host_holiday_sets (
+    host_id TEXT PRIMARY KEY REFERENCES hosts(id) ON DELETE CASCADE,
+    set_code TEXT NOT NULL,
+    created_at TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_at TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+
+CREATE TABLE host_holiday_exceptions (
+    host_id TEXT NOT NULL REFERENCES hosts(id) ON DELETE CASCADE,
+    holiday_date TEXT NOT NULL,
+    created_at TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    PRIMARY KEY (host_id, holiday_date)
+);
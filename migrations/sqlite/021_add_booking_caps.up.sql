@@ -0,0 +1,9 @@
+-- Booking caps, where 0 means no limit. Pending and confirmed bookings count, by
+-- start time, with days and Monday-to-Sunday weeks read in the host's
+-- timezone. Template caps count that meeting type only, and host caps count
+-- every booking the host owns.
+ALTER TABLE meeting_templates ADD COLUMN max_bookings_per_day INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE meeting_templates ADD COLUMN max_bookings_per_week INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE hosts ADD COLUMN max_bookings_per_day INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE hosts ADD COLUMN max_bookings_per_week INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE hosts ADD COLUMN max_minutes_per_day INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+ALTER TABLE template_hosts DROP COLUMN weight;
+ALTER TABLE meeting_templates DROP COLUMN round_robin_strategy;
+ALTER TABLE meeting_templates DROP COLUMN scheduling_type;
@@ -0,0 +1,8 @@
+-- Where a template's slots may start. slot_interval is the minutes between
+-- start times, where 0 keeps the original 15. align_slots puts starts on
+-- multiples of the interval from midnight rather than from the start of the
+-- working hours. start_minutes, when not empty, limits starts to those
+-- minutes past the hour.
+ALTER TABLE meeting_templates ADD COLUMN slot_interval INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE meeting_templates ADD COLUMN align_slots INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE meeting_templates ADD COLUMN start_minutes TEXT NOT NULL DEFAULT '[]';
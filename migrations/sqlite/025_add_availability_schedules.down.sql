@@ -0,0 +1,3 @@
+ALTER TABLE availability_overrides DROP COLUMN schedule_id;
+ALTER TABLE meeting_templates DROP COLUMN schedule_id;
+DROP TABLE IF EXISTS availability_schedules;
@@ -0,0 +1,15 @@
+-- Named availability schedules. See migrations/025_add_availability_schedules.up.sql.
+CREATE TABLE availability_schedules (
+    id TEXT PRIMARY KEY,
+    host_id TEXT NOT NULL REFERENCES hosts(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    weekly_hours TEXT NOT NULL DEFAULT '[]',
+    created_at TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_at TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+
+CREATE INDEX idx_availability_schedules_host ON availability_schedules(host_id);
+
+ALTER TABLE meeting_templates ADD COLUMN schedule_id TEXT REFERENCES availability_schedules(id) ON DELETE SET NULL;
+ALTER TABLE availability_overrides ADD COLUMN schedule_id TEXT REFERENCES availability_schedules(id) ON DELETE CASCADE;
@@ -0,0 +1,15 @@
+-- Outgoing emails. See migrations/027_add_email_messages.up.sql.
+CREATE TABLE email_messages (
+    id TEXT PRIMARY KEY,
+    provider TEXT NOT NULL,
+    provider_message_id TEXT NOT NULL DEFAULT '',
+    recipient TEXT NOT NULL,
+    subject TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'sent',
+    status_detail TEXT NOT NULL DEFAULT '',
+    created_at TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_at TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+
+CREATE INDEX idx_email_messages_provider_message ON email_messages(provider_message_id);
+CREATE INDEX idx_email_messages_recipient ON email_messages(recipient);
@@ -0,0 +1,3 @@
+ALTER TABLE tenants DROP COLUMN email_footer;
+ALTER TABLE tenants DROP COLUMN accent_color;
+ALTER TABLE tenants DROP COLUMN logo_url;
@@ -0,0 +1,4 @@
+-- Tenant branding for HTML emails. See migrations/028_add_tenant_branding.up.sql.
+ALTER TABLE tenants ADD COLUMN logo_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE tenants ADD COLUMN accent_color TEXT NOT NULL DEFAULT '';
+ALTER TABLE tenants ADD COLUMN email_footer TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,22 @@
+-- Reminders per meeting type and the ledger of reminders sent. See
+-- migrations/030_add_template_reminders.up.sql.
+ALTER TABLE meeting_templates ADD COLUMN reminders TEXT NOT NULL
+    DEFAULT '[{"offset_minutes":1440,"channel":"email","recipient":"invitee"}]';
+
+CREATE TABLE booking_reminders (
+    id TEXT PRIMARY KEY,
+    booking_id TEXT NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    reminder_key TEXT NOT NULL,
+    start_time TEXT NOT NULL,
+    sent_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    UNIQUE (booking_id, reminder_key, start_time)
+);
+
+INSERT INTO booking_reminders (id, booking_id, reminder_key, start_time, sent_at)
+SELECT lower(hex(randomblob(16))), id, 'invitee:email:1440', start_time,
+       COALESCE(updated_at, strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+FROM bookings
+WHERE reminder_sent = 1 AND start_time > strftime('%Y-%m-%dT%H:%M:%SZ', 'now');
+
+DROP INDEX IF EXISTS idx_bookings_reminder;
+ALTER TABLE bookings DROP COLUMN reminder_sent;
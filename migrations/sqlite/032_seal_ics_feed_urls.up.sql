@@ -0,0 +1,4 @@
+-- ICS feed provider calendars get a fixed id instead of the secret feed
+-- URL. See migrations/032_seal_ics_feed_urls.up.sql.
+UPDATE provider_calendars SET provider_calendar_id = 'feed'
+WHERE connection_id IN (SELECT id FROM calendar_connections WHERE provider = 'ics_feed');